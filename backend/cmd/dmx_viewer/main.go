@@ -32,5 +32,13 @@ func main() {
 		cancel()
 	}()
 
+	// サブコマンドが指定された場合はサーバーを起動しない
+	if len(os.Args) > 1 {
+		if err := app.RunCommand(ctx, cfg, appLogger, os.Args[1:]); err != nil {
+			appLogger.Fatal("Command failed: ", err)
+		}
+		return
+	}
+
 	app.Run(ctx, cfg, appLogger)
 }
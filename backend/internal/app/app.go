@@ -6,13 +6,17 @@ import (
 	"fmt"
 	"io/fs"
 	"net/http"
+	"path/filepath"
 	"time"
 
 	"github.com/nasshu2916/dmx_viewer/internal/config"
 	"github.com/nasshu2916/dmx_viewer/internal/di"
+	"github.com/nasshu2916/dmx_viewer/internal/domain/model"
+	"github.com/nasshu2916/dmx_viewer/internal/domain/repository"
 	"github.com/nasshu2916/dmx_viewer/internal/infrastructure"
 	"github.com/nasshu2916/dmx_viewer/internal/infrastructure/artnet"
 	metrics "github.com/nasshu2916/dmx_viewer/internal/infrastructure/metrics"
+	"github.com/nasshu2916/dmx_viewer/internal/infrastructure/sacn"
 	httpHandler "github.com/nasshu2916/dmx_viewer/internal/interface/handler/http"
 	"github.com/nasshu2916/dmx_viewer/internal/interface/handler/websocket"
 	"github.com/nasshu2916/dmx_viewer/internal/interface/router"
@@ -43,8 +47,104 @@ func Run(ctx context.Context, config *config.Config, logger *logger.Logger) {
 
 	artNetServer := artnet.NewServer(logger, &config.ArtNet)
 	artNetNodeRepo := infrastructure.NewArtNetNodeRepository()
-	artNetPacketHandler := usecase.NewArtNetPacketHandler(wsUseCase, artNetServer, &config.ArtNet, logger, artNetNodeRepo)
+	timeCodeRepo := infrastructure.NewTimeCodeRepository()
+	monitorUniverses, err := model.ParseUniverseList(config.ArtNet.MonitorUniverses)
+	if err != nil {
+		logger.Fatal("Invalid ARTNET_MONITOR_UNIVERSES: ", err)
+	}
+	if err := model.ValidateMonitoredUniverses(monitorUniverses); err != nil {
+		logger.Fatal("Invalid ARTNET_MONITOR_UNIVERSES: ", err)
+	}
+	defaultNodeSettings := model.NewNodeSettings(config.ArtNet.ShortName, config.ArtNet.LongName, monitorUniverses)
+	nodeSettingsRepo, err := infrastructure.NewNodeSettingsRepository(defaultNodeSettings, config.ArtNet.StateFile)
+	if err != nil {
+		logger.Fatal("Failed to load node settings: ", err)
+	}
+	nodeLivenessPolicy := model.NewNodeLivenessPolicy(
+		time.Duration(config.ArtNet.PollIntervalSeconds)*time.Second,
+		time.Duration(config.ArtNet.NodeExpireSeconds)*time.Second,
+	)
+	nodeLivenessUseCase := usecase.NewNodeLivenessUseCaseImpl(artNetNodeRepo, wsUseCase, nodeLivenessPolicy, logger)
+	sequenceTracker := usecase.NewSequenceTracker(wsUseCase, logger)
+	mergeMode, err := model.ParseMergeMode(config.ArtNet.MergeMode)
+	if err != nil || mergeMode == model.MergeModeSource {
+		logger.Fatal("Invalid ARTNET_MERGE_MODE (HTP or LTP): ", config.ArtNet.MergeMode)
+	}
+	historyPolicy := model.NewHistoryPolicy(
+		time.Duration(config.History.FullRateSeconds)*time.Second,
+		time.Duration(config.History.SecondMinutes)*time.Minute,
+		time.Duration(config.History.RetentionMinutes)*time.Minute,
+	)
+	historyRepo := infrastructure.NewUniverseHistoryRepository(historyPolicy)
+	historyUseCase := usecase.NewUniverseHistoryUseCaseImpl(historyRepo, historyPolicy)
+	liveUniverses := usecase.NewLiveUniverses()
+	universeObserver := usecase.UniverseObservers{liveUniverses}
+	if historyPolicy.Retention > 0 {
+		universeObserver = append(universeObserver, historyUseCase)
+	}
+	universeMerger := usecase.NewUniverseMerger(mergeMode, wsUseCase, universeObserver, logger)
+
+	// Art-Net と sACN の間のブリッジ
+	artNetToSACNRoutes, err := model.ParseBridgeRoutes(model.BridgeArtNetToSACN, config.Bridge.ArtNetToSACN, config.Bridge.ArtNetToSACNOffset)
+	if err != nil {
+		logger.Fatal("Invalid BRIDGE_ARTNET_TO_SACN: ", err)
+	}
+	sacnToArtNetRoutes, err := model.ParseBridgeRoutes(model.BridgeSACNToArtNet, config.Bridge.SACNToArtNet, config.Bridge.SACNToArtNetOffset)
+	if err != nil {
+		logger.Fatal("Invalid BRIDGE_SACN_TO_ARTNET: ", err)
+	}
+	if config.Bridge.SACNPriority < 0 || config.Bridge.SACNPriority > sacn.MaxPriority {
+		logger.Fatal("Invalid BRIDGE_SACN_PRIORITY (0-200): ", config.Bridge.SACNPriority)
+	}
+	bridgeCID, err := sacn.NewCID()
+	if err != nil {
+		logger.Fatal("Failed to generate sACN CID for bridge: ", err)
+	}
+	sacnSender := sacn.NewSender(logger, config.SACN.Interface)
+	protocolBridge := usecase.NewProtocolBridge(
+		append(artNetToSACNRoutes, sacnToArtNetRoutes...),
+		artNetServer,
+		artNetServer,
+		sacnSender,
+		usecase.SACNOutputOptions{CID: bridgeCID, SourceName: config.Bridge.SACNSourceName, Priority: uint8(config.Bridge.SACNPriority)},
+		config.Bridge.RefreshRate,
+		logger,
+	)
+
+	captureRepo := infrastructure.NewCaptureRepository(
+		config.Recorder.Dir,
+		int64(config.Recorder.MaxFileMB)*1024*1024,
+		time.Duration(config.Recorder.MaxFileMinutes)*time.Minute,
+	)
+	recorderUseCase := usecase.NewRecorderUseCaseImpl(captureRepo, logger)
+	// 受信したパケットをそのまま pcap ファイルに書き出す
+	trafficCaptureUseCase := usecase.NewTrafficCaptureUseCaseImpl(infrastructure.NewTrafficCaptureRepository(config.Recorder.Dir), artNetServer, logger)
+	artNetServer.SetDatagramObserver(trafficCaptureUseCase)
+	// 送信元ごとの値の差分を {protocol}/dmx_delta/{universe} トピックに配信する
+	dmxDeltaUseCase := usecase.NewDMXDeltaUseCaseImpl(wsUseCase, logger)
+	hub.RegisterSubscribeHandler(dmxDeltaUseCase)
+	frameObservers := usecase.FrameObservers{protocolBridge, recorderUseCase, dmxDeltaUseCase}
+
+	// オフラインレビューモードでは指定したキャプチャファイルのディレクトリから再生する
+	reviewMode := config.Review.File != ""
+	replayMode := config.Replay.PcapFile != ""
+	if reviewMode && replayMode {
+		logger.Fatal("REVIEW_FILE and REPLAY_PCAP cannot be used together")
+	}
+	var playbackRepo repository.CaptureRepository = captureRepo
+	if reviewMode {
+		playbackRepo = infrastructure.NewCaptureRepository(filepath.Dir(config.Review.File), 0, 0)
+	}
+	playbackUseCase := usecase.NewPlaybackUseCaseImpl(playbackRepo, artNetServer, artNetServer, wsUseCase, logger)
+	playbackUseCase.SetFrameObserver(dmxDeltaUseCase)
+	exportUseCase := usecase.NewExportUseCaseImpl(historyRepo, playbackRepo)
+
+	artNetPacketHandler := usecase.NewArtNetPacketHandler(wsUseCase, artNetServer, artNetServer, &config.ArtNet, logger, nodeLivenessUseCase, timeCodeRepo, nodeSettingsRepo, sequenceTracker, universeMerger, frameObservers)
 	artNetUseCase := usecase.NewArtNetUseCaseImpl(artNetPacketHandler, logger)
+	sacnSourceRepo := infrastructure.NewSACNSourceRepository()
+	sacnSourceDirectory := usecase.NewSACNSourceDirectoryImpl(sacnSourceRepo, wsUseCase, time.Duration(config.SACN.SourceExpireSeconds)*time.Second, logger)
+	nodeSettingsUseCase := usecase.NewNodeSettingsUseCaseImpl(nodeSettingsRepo, artNetPacketHandler, wsUseCase, logger)
+	snapshotUseCase := usecase.NewSnapshotUseCaseImpl(infrastructure.NewSnapshotRepository(config.Snapshot.Dir), liveUniverses, universeMerger, sacnSourceDirectory, nodeLivenessUseCase, wsUseCase, logger)
 
 	assetsSubFS, err := fs.Sub(assetsFS, "embed_static/assets")
 	if err != nil {
@@ -52,24 +152,86 @@ func Run(ctx context.Context, config *config.Config, logger *logger.Logger) {
 	}
 
 	go timeHandler.StartTimeSync(ctx)
-	go func() {
-		if err := artNetServer.Run(); err != nil {
-			logger.Error("ArtNet server stopped with error: ", err)
+	go nodeLivenessUseCase.StartSweeper(ctx)
+	go sequenceTracker.StartStatsBroadcast(ctx)
+	go historyUseCase.StartPruner(ctx)
+	go snapshotUseCase.StartDriftPublisher(ctx)
+	go protocolBridge.StartRefresh(ctx)
+	go recorderUseCase.StartFlusher(ctx)
+	go trafficCaptureUseCase.StartFlusher(ctx)
+	go playbackUseCase.StartPlayer(ctx)
+	go dmxDeltaUseCase.StartKeyframer(ctx)
+	if reviewMode {
+		// Art-Net / sACN は受信せず、キャプチャファイルの内容のみを配信する
+		// 配信するフレームは受信したフレームと同じくマージし、ライブの値・履歴にも反映する
+		// 無通信の送信元は再生位置で判定するため、マージのスイーパーは起動しない
+		sacnUseCase := usecase.NewSACNBridgeUseCaseImpl(wsUseCase, usecase.NewSACNSourceTracker(logger), sacnSourceDirectory, nil, universeObserver, nil, &config.SACN, logger)
+		playbackUseCase.SetFrameMerger(usecase.NewProtocolFrameMerger(universeMerger, sacnUseCase))
+		startReview(config.Review.File, hub, playbackUseCase, logger)
+	} else if replayMode {
+		// Art-Net / sACN は受信せず、パケットキャプチャの内容を受信したパケットとして処理する
+		sacnUseCase := usecase.NewSACNBridgeUseCaseImpl(wsUseCase, usecase.NewSACNSourceTracker(logger), sacnSourceDirectory, frameObservers, universeObserver, nil, &config.SACN, logger)
+		go sacnUseCase.StartSweeper(ctx)
+		go sacnSourceDirectory.StartSweeper(ctx)
+		go universeMerger.StartSweeper(ctx)
+		go replayPcap(ctx, config.Replay.PcapFile, usecase.NewPcapImportUseCaseImpl(nil, artNetPacketHandler, sacnUseCase, logger), logger)
+	} else {
+		if len(artNetToSACNRoutes) > 0 {
+			if err := sacnSender.Open(); err != nil {
+				logger.Error("Failed to start sACN sender for bridge: ", err)
+			}
+			defer sacnSender.Close()
 		}
-	}()
+		go universeMerger.StartSweeper(ctx)
+		go func() {
+			if err := artNetServer.Run(); err != nil {
+				logger.Error("ArtNet server stopped with error: ", err)
+			}
+		}()
 
-	// ArtNetパケットをWebSocketに転送する処理を開始
-	go artNetUseCase.StartPacketForwarding(ctx, artNetServer)
+		// ArtNetパケットをWebSocketに転送する処理を開始
+		go artNetUseCase.StartPacketForwarding(ctx, artNetServer)
+
+		if config.SACN.Enabled {
+			sacnReceiver := sacn.NewReceiver(logger, &config.SACN)
+			sacnTracker := usecase.NewSACNSourceTracker(logger)
+			for _, route := range sacnToArtNetRoutes {
+				sacnReceiver.AddUniverses(route.Input)
+			}
+			sacnUseCase := usecase.NewSACNBridgeUseCaseImpl(wsUseCase, sacnTracker, sacnSourceDirectory, frameObservers, universeObserver, sacnReceiver, &config.SACN, logger)
+			go sacnUseCase.StartSweeper(ctx)
+			go sacnSourceDirectory.StartSweeper(ctx)
+			go func() {
+				if err := sacnReceiver.Run(); err != nil {
+					logger.Error("sACN receiver stopped with error: ", err)
+				}
+			}()
+			go sacnUseCase.StartPacketForwarding(ctx, sacnReceiver)
+		} else if len(sacnToArtNetRoutes) > 0 {
+			logger.Warn("BRIDGE_SACN_TO_ARTNET is ignored because sACN is disabled")
+		}
+	}
 
 	staticHandler := httpHandler.NewStaticHandler(indexHtml, assetsSubFS, logger)
 	healthHandler := httpHandler.NewHealthHandler(artNetServer, logger)
+	timeCodeHandler := httpHandler.NewTimeCodeHandler(usecase.NewTimeCodeUseCaseImpl(timeCodeRepo), logger)
+	nodeSettingsHandler := httpHandler.NewNodeSettingsHandler(nodeSettingsUseCase, logger)
+	nodeHandler := httpHandler.NewNodeHandler(nodeLivenessUseCase, logger)
+	sacnSourceHandler := httpHandler.NewSACNSourceHandler(sacnSourceDirectory, logger)
+	mergeHandler := httpHandler.NewMergeHandler(universeMerger, logger)
+	historyHandler := httpHandler.NewHistoryHandler(historyUseCase, logger)
+	snapshotHandler := httpHandler.NewSnapshotHandler(snapshotUseCase, logger)
+	exportHandler := httpHandler.NewExportHandler(exportUseCase, logger)
+	recorderHandler := httpHandler.NewRecorderHandler(recorderUseCase, logger)
+	playbackHandler := httpHandler.NewPlaybackHandler(playbackUseCase, logger)
+	trafficCaptureHandler := httpHandler.NewTrafficCaptureHandler(trafficCaptureUseCase, logger)
 
 	// Prometheus レジストリ構築（プロセス/Go標準 + ArtNet カスタム）
-	reg := metrics.BuildRegistry(artNetServer)
+	reg := metrics.BuildRegistry(artNetServer, sequenceTracker, protocolBridge)
 	metricsHandler := httpHandler.NewMetricsHandlerWithRegistry(reg, logger)
 
 	httpTimeout := time.Duration(config.App.HTTPTimeoutSeconds) * time.Second
-	router := router.NewRouter(staticHandler, timeHandler, healthHandler, metricsHandler, wsHandler, logger, httpTimeout)
+	router := router.NewRouter(staticHandler, timeHandler, timeCodeHandler, nodeSettingsHandler, nodeHandler, sacnSourceHandler, mergeHandler, historyHandler, snapshotHandler, exportHandler, recorderHandler, playbackHandler, trafficCaptureHandler, healthHandler, metricsHandler, wsHandler, logger, httpTimeout)
 
 	server := &http.Server{
 		Addr:    fmt.Sprintf(":%s", config.App.Port),
@@ -93,4 +255,32 @@ func Run(ctx context.Context, config *config.Config, logger *logger.Logger) {
 	} else {
 		logger.Info("HTTP server shutdown gracefully")
 	}
+
+	// 記録中のキャプチャファイルを閉じる
+	if recorderUseCase.Status().Recording {
+		if _, err := recorderUseCase.Stop(); err != nil {
+			logger.Error("Failed to stop recording: ", err)
+		}
+	}
+	if trafficCaptureUseCase.Status().Capturing {
+		if _, err := trafficCaptureUseCase.Stop(); err != nil {
+			logger.Error("Failed to stop traffic capture: ", err)
+		}
+	}
+}
+
+// startReview オフラインレビューモードでキャプチャファイルを先頭で一時停止した状態で読み込み、
+// WebSocket から再生位置を操作できるようにする
+func startReview(file string, hub *websocket.Hub, playbackUseCase *usecase.PlaybackUseCaseImpl, logger *logger.Logger) {
+	opts := model.PlaybackOptions{File: filepath.Base(file), Speed: 1, DryRun: true}
+	playbackUseCase.EnableReviewMode()
+	hub.RegisterCommandHandler(websocket.ReviewCommandType, websocket.NewReviewCommandHandler(playbackUseCase, opts, logger))
+
+	if _, err := playbackUseCase.Start(opts); err != nil {
+		logger.Fatal("Failed to load capture file for review: ", err)
+	}
+	if _, err := playbackUseCase.Pause(); err != nil {
+		logger.Fatal("Failed to pause review playback: ", err)
+	}
+	logger.Info("Offline review mode started, Art-Net and sACN receivers are disabled", "file", file)
 }
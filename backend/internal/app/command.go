@@ -0,0 +1,181 @@
+package app
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/nasshu2916/dmx_viewer/internal/config"
+	"github.com/nasshu2916/dmx_viewer/internal/domain/model"
+	"github.com/nasshu2916/dmx_viewer/internal/infrastructure"
+	"github.com/nasshu2916/dmx_viewer/internal/infrastructure/export"
+	"github.com/nasshu2916/dmx_viewer/internal/infrastructure/pcap"
+	"github.com/nasshu2916/dmx_viewer/internal/usecase"
+	"github.com/nasshu2916/dmx_viewer/pkg/logger"
+)
+
+// RunCommand サーバーを起動せずにサブコマンドを実行する
+//
+//	dmx_viewer convert-pcap [-out DIR] FILE  パケットキャプチャをキャプチャファイルに変換する
+//	dmx_viewer export [-universe N] FILE     キャプチャファイルのチャンネルの値を CSV / NDJSON に書き出す
+func RunCommand(ctx context.Context, config *config.Config, logger *logger.Logger, args []string) error {
+	switch args[0] {
+	case "convert-pcap":
+		return convertPcap(config, logger, args[1:])
+	case "export":
+		return exportCapture(ctx, args[1:])
+	default:
+		return fmt.Errorf("unknown command %q", args[0])
+	}
+}
+
+// convertPcap pcap / pcapng の Art-Net / sACN のDMXデータをキャプチャファイルに変換する
+func convertPcap(config *config.Config, logger *logger.Logger, args []string) error {
+	flags := flag.NewFlagSet("convert-pcap", flag.ContinueOnError)
+	out := flags.String("out", config.Recorder.Dir, "output directory for capture files")
+	maxFileMB := flags.Int("max-file-mb", config.Recorder.MaxFileMB, "maximum size of a capture file in MB (0 for unlimited)")
+	maxFileMinutes := flags.Int("max-file-minutes", config.Recorder.MaxFileMinutes, "maximum duration of a capture file in minutes (0 for unlimited)")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: dmx_viewer convert-pcap [flags] FILE")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return fmt.Errorf("a pcap or pcapng file is required")
+	}
+
+	file, err := os.Open(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	reader, err := pcap.NewReader(file)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", flags.Arg(0), err)
+	}
+
+	repo := infrastructure.NewCaptureRepository(*out, int64(*maxFileMB)*1024*1024, time.Duration(*maxFileMinutes)*time.Minute)
+	result, err := usecase.NewPcapImportUseCaseImpl(repo, nil, nil, logger).Convert(reader)
+	if err != nil {
+		return fmt.Errorf("failed to convert %s: %w", flags.Arg(0), err)
+	}
+	logger.Info("Converted packet capture",
+		"datagrams", result.Datagrams,
+		"artnet", result.ArtNet,
+		"sacn", result.SACN,
+		"frames", result.Frames,
+		"skipped", result.Skipped,
+	)
+	for _, f := range result.Files {
+		fmt.Println(f.Path)
+	}
+	return nil
+}
+
+// exportCapture キャプチャファイルの選択したユニバース・チャンネルの値の時系列を CSV / NDJSON に書き出す
+func exportCapture(ctx context.Context, args []string) (err error) {
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	format := flags.String("format", string(model.ExportFormatCSV), "output format (csv or ndjson)")
+	mode := flags.String("mode", string(model.ExportModeChanges), "changes (a row when a value changes) or resample (a row every step)")
+	step := flags.String("step", "", "resample interval (e.g. 100ms)")
+	protocol := flags.String("protocol", model.ProtocolArtNet, "protocol (artnet or sacn)")
+	universe := flags.Uint("universe", 0, "universe to export")
+	channels := flags.String("channels", "", "channel range (e.g. 1-16, all channels if omitted)")
+	from := flags.String("from", "", "start time (unix milliseconds or RFC 3339)")
+	to := flags.String("to", "", "end time (unix milliseconds or RFC 3339)")
+	out := flags.String("out", "", "output file (stdout if omitted)")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: dmx_viewer export [flags] FILE")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return fmt.Errorf("a capture file is required")
+	}
+	if *universe > 0xFFFF {
+		return fmt.Errorf("invalid universe %d", *universe)
+	}
+
+	exportFormat, err := model.ParseExportFormat(*format)
+	if err != nil {
+		return err
+	}
+	opts := model.ExportOptions{Protocol: *protocol, Universe: uint16(*universe)}
+	if opts.Mode, err = model.ParseExportMode(*mode); err != nil {
+		return err
+	}
+	if opts.FirstChannel, opts.LastChannel, err = model.ParseChannelRange(*channels); err != nil {
+		return err
+	}
+	if opts.From, err = model.ParseTimeParam(*from); err != nil {
+		return fmt.Errorf("invalid from: %w", err)
+	}
+	if opts.To, err = model.ParseTimeParam(*to); err != nil {
+		return fmt.Errorf("invalid to: %w", err)
+	}
+	if opts.Step, err = model.ParseStepParam(*step); err != nil {
+		return fmt.Errorf("invalid step: %w", err)
+	}
+	if opts, err = usecase.NormalizeExportOptions(opts); err != nil {
+		return err
+	}
+
+	output := os.Stdout
+	if *out != "" {
+		if output, err = os.Create(*out); err != nil {
+			return err
+		}
+		defer func() {
+			if cerr := output.Close(); err == nil {
+				err = cerr
+			}
+		}()
+	}
+	writer, err := export.NewWriter(output, exportFormat, opts.FirstChannel, opts.LastChannel)
+	if err != nil {
+		return err
+	}
+
+	path := flags.Arg(0)
+	repo := infrastructure.NewCaptureRepository(filepath.Dir(path), 0, 0)
+	if err := usecase.NewExportUseCaseImpl(nil, repo).ExportCapture(ctx, filepath.Base(path), opts, writer); err != nil {
+		return fmt.Errorf("failed to export %s: %w", path, err)
+	}
+	return writer.Flush()
+}
+
+// replayPcap パケットキャプチャをキャプチャした時刻の間隔で受信したパケットとして処理する
+func replayPcap(ctx context.Context, path string, pcapImportUseCase *usecase.PcapImportUseCaseImpl, logger *logger.Logger) {
+	file, err := os.Open(path)
+	if err != nil {
+		logger.Error("Failed to open packet capture for replay: ", err)
+		return
+	}
+	defer file.Close()
+	reader, err := pcap.NewReader(file)
+	if err != nil {
+		logger.Error("Failed to read packet capture for replay: ", err)
+		return
+	}
+
+	logger.Info("Replaying packet capture, Art-Net and sACN receivers are disabled", "file", path)
+	result, err := pcapImportUseCase.Replay(ctx, reader)
+	if err != nil && ctx.Err() == nil {
+		logger.Error("Packet capture replay stopped with error: ", err)
+	}
+	logger.Info("Packet capture replay finished",
+		"datagrams", result.Datagrams,
+		"artnet", result.ArtNet,
+		"sacn", result.SACN,
+		"skipped", result.Skipped,
+	)
+}
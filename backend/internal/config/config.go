@@ -8,9 +8,16 @@ import (
 
 type (
 	Config struct {
-		App    App
-		ArtNet ArtNet
-		NTP    NTP
+		App      App
+		ArtNet   ArtNet
+		SACN     SACN
+		Bridge   Bridge
+		Recorder Recorder
+		History  History
+		Snapshot Snapshot
+		Review   Review
+		Replay   Replay
+		NTP      NTP
 	}
 
 	App struct {
@@ -21,10 +28,59 @@ type (
 
 	ArtNet struct {
 		LogLevel            string `env:"ARTNET_LOG_LEVEL" envDefault:"info"`
+		Interface           string `env:"ARTNET_INTERFACE" envDefault:""` // インターフェース名・CIDR・IPアドレス（空の場合は 2.x/10.x を優先して自動検出）
 		ShortName           string `env:"ARTNET_SHORT_NAME" envDefault:"DMX Viewer"`
 		LongName            string `env:"ARTNET_LONG_NAME" envDefault:"DMX Viewer Application"`
 		PollIntervalSeconds int    `env:"ARTNET_POLL_INTERVAL_SECONDS" envDefault:"5"`
 		ChannelBufferSize   int    `env:"ARTNET_CHANNEL_BUFFER_SIZE" envDefault:"1000"`
+		NodeExpireSeconds   int    `env:"ARTNET_NODE_EXPIRE_SECONDS" envDefault:"300"` // 応答のないノードを一覧から削除するまでの時間（0の場合は削除しない）
+		SyncEnabled         bool   `env:"ARTNET_SYNC_ENABLED" envDefault:"true"`
+		MergeMode           string `env:"ARTNET_MERGE_MODE" envDefault:"HTP"`     // 複数の送信元が同じユニバースを送信した場合の既定のマージモード（HTP/LTP）
+		StateFile           string `env:"ARTNET_STATE_FILE" envDefault:""`        // ArtAddressで変更された設定の保存先（空の場合は保存しない）
+		MonitorUniverses    string `env:"ARTNET_MONITOR_UNIVERSES" envDefault:""` // 仮想出力ポートとして公開するユニバース（例: "0-3,16"）
+	}
+
+	SACN struct {
+		Enabled             bool   `env:"SACN_ENABLED" envDefault:"true"`
+		Interface           string `env:"SACN_INTERFACE" envDefault:""`           // マルチキャストに参加するインターフェース（形式は ARTNET_INTERFACE と同じ）
+		Universes           string `env:"SACN_UNIVERSES" envDefault:""`           // 受信するユニバース（例: "1-4,10"）
+		JoinDiscovered      bool   `env:"SACN_JOIN_DISCOVERED" envDefault:"true"` // ユニバースディスカバリーで通知されたユニバースにも参加する
+		ChannelBufferSize   int    `env:"SACN_CHANNEL_BUFFER_SIZE" envDefault:"1000"`
+		SourceExpireSeconds int    `env:"SACN_SOURCE_EXPIRE_SECONDS" envDefault:"30"` // ユニバースディスカバリーが途絶えた送信元を一覧から削除するまでの時間
+	}
+
+	Bridge struct {
+		ArtNetToSACN       string `env:"BRIDGE_ARTNET_TO_SACN" envDefault:""`          // sACN に転送する Art-Net ユニバース（例: "0-3"。空の場合は転送しない）
+		ArtNetToSACNOffset int    `env:"BRIDGE_ARTNET_TO_SACN_OFFSET" envDefault:"1"`  // 出力する sACN ユニバース = Art-Net ユニバース + オフセット
+		SACNToArtNet       string `env:"BRIDGE_SACN_TO_ARTNET" envDefault:""`          // Art-Net に転送する sACN ユニバース（例: "1-4"。空の場合は転送しない）
+		SACNToArtNetOffset int    `env:"BRIDGE_SACN_TO_ARTNET_OFFSET" envDefault:"-1"` // 出力する Art-Net ユニバース = sACN ユニバース + オフセット
+		SACNPriority       int    `env:"BRIDGE_SACN_PRIORITY" envDefault:"100"`        // sACN で出力するときの優先度（0-200）
+		SACNSourceName     string `env:"BRIDGE_SACN_SOURCE_NAME" envDefault:"DMX Viewer Bridge"`
+		RefreshRate        int    `env:"BRIDGE_REFRESH_RATE" envDefault:"44"` // 入力が変化しない間も出力を再送する頻度（Hz）
+	}
+
+	Recorder struct {
+		Dir            string `env:"RECORDER_DIR" envDefault:"captures"`        // キャプチャファイルの保存先
+		MaxFileMB      int    `env:"RECORDER_MAX_FILE_MB" envDefault:"100"`     // 1ファイルの最大サイズ（0の場合は無制限）
+		MaxFileMinutes int    `env:"RECORDER_MAX_FILE_MINUTES" envDefault:"60"` // 1ファイルの最大記録時間（0の場合は無制限）
+	}
+
+	History struct {
+		FullRateSeconds  int `env:"HISTORY_FULL_RATE_SECONDS" envDefault:"60"` // 受信したフレームをそのまま保持する期間
+		SecondMinutes    int `env:"HISTORY_SECOND_MINUTES" envDefault:"10"`    // 1秒ごとに集計した履歴を保持する期間
+		RetentionMinutes int `env:"HISTORY_RETENTION_MINUTES" envDefault:"60"` // 10秒ごとに集計した履歴を保持する期間（0の場合は履歴を保持しない）
+	}
+
+	Snapshot struct {
+		Dir string `env:"SNAPSHOT_DIR" envDefault:"snapshots"` // スナップショットの保存先
+	}
+
+	Review struct {
+		File string `env:"REVIEW_FILE" envDefault:""` // オフラインレビューモードで表示するキャプチャファイル（指定した場合は Art-Net / sACN を受信しない）
+	}
+
+	Replay struct {
+		PcapFile string `env:"REPLAY_PCAP" envDefault:""` // キャプチャした時刻の間隔で受信したパケットとして処理する pcap / pcapng（指定した場合は Art-Net / sACN を受信しない）
 	}
 
 	NTP struct {
@@ -2,12 +2,16 @@ package model
 
 import (
 	"bytes"
+	"fmt"
 	"net"
 	"time"
 
 	"github.com/jsimonetti/go-artnet/packet"
 )
 
+// ArtNetNode ArtPollReplyで通知されたノード（BindIndex単位）の情報
+// 1台で4ポートを超えるゲートウェイは BindIndex ごとに別のArtPollReplyを送信するため、
+// IPAddress と BindIndex の組で1つのノードを表す
 type ArtNetNode struct {
 	IPAddress  net.IP
 	ShortName  string
@@ -15,19 +19,264 @@ type ArtNetNode struct {
 	NodeReport string
 	MacAddress net.HardwareAddr
 	LastSeen   time.Time
+	Liveness   NodeLiveness
+
+	BindIP    net.IP // ルートデバイスのIPアドレス
+	BindIndex uint8  // ルートデバイスは1（未対応ノードは0）
+
+	Firmware    uint16 // VersionInfo
+	UBEAVersion uint8
+	Oem         uint16
+	ESTACode    uint16 // ESTA製造者コード
+	Style       NodeStyle
+
+	NetSwitch uint8
+	SubSwitch uint8
+	NumPorts  uint8
+	Ports     []ArtNetNodePort
+
+	Status1 NodeStatus1
+	Status2 NodeStatus2
+}
+
+// ArtNetNodePort ノードの1ポート分の情報
+type ArtNetNodePort struct {
+	Index          int    // ページ内のポート番号（0-3）
+	Protocol       string // DMX512, MIDI, Avab, Colortran CMX, ADB 62.5, Art-Net, DALI
+	Input          bool   // Art-Netへの入力が可能か
+	Output         bool   // Art-Netからの出力が可能か
+	InputUniverse  uint16 // 入力ポートの15bit Port-Address
+	OutputUniverse uint16 // 出力ポートの15bit Port-Address
+	GoodInput      PortInputStatus
+	GoodOutput     PortOutputStatus
+	Healthy        bool // エラー・無効化・短絡が報告されていないか
+}
+
+// PortInputStatus GoodInput の各ビット
+type PortInputStatus struct {
+	DataReceived  bool // bit7
+	TestPackets   bool // bit6
+	SIP           bool // bit5
+	TextPackets   bool // bit4
+	Disabled      bool // bit3
+	ReceiveErrors bool // bit2
+}
+
+// PortOutputStatus GoodOutput の各ビット
+type PortOutputStatus struct {
+	DataTransmitted bool // bit7
+	TestPackets     bool // bit6
+	SIP             bool // bit5
+	TextPackets     bool // bit4
+	Merging         bool // bit3
+	ShortDetected   bool // bit2
+	MergeLTP        bool // bit1
+	SACN            bool // bit0: sACNを出力中（0はArt-Net）
+}
+
+// NodeStatus1 Status1 の各フィールド
+type NodeStatus1 struct {
+	Indicator       string // unknown, locate, mute, normal
+	PortAddressFrom string // unknown, front, network, unused
+	BootROM         bool
+	RDM             bool
+	UBEA            bool
+}
+
+// NodeStatus2 Status2 の各フィールド
+type NodeStatus2 struct {
+	WebConfig        bool // ブラウザから設定可能
+	DHCP             bool // DHCPでIPアドレスを取得している
+	DHCPCapable      bool
+	PortAddress15Bit bool // 15bit Port-Address に対応
+	SACNSwitchable   bool // Art-Net/sACNの切り替えに対応
+	Squawking        bool
 }
 
+// NodeStyle ノードの機器種別（Style コード）
+type NodeStyle uint8
+
+const (
+	StyleNode       NodeStyle = 0x00
+	StyleController NodeStyle = 0x01
+	StyleMedia      NodeStyle = 0x02
+	StyleRoute      NodeStyle = 0x03
+	StyleBackup     NodeStyle = 0x04
+	StyleConfig     NodeStyle = 0x05
+	StyleVisual     NodeStyle = 0x06
+)
+
+var nodeStyleNames = map[NodeStyle]string{
+	StyleNode:       "node",
+	StyleController: "controller",
+	StyleMedia:      "media",
+	StyleRoute:      "route",
+	StyleBackup:     "backup",
+	StyleConfig:     "config",
+	StyleVisual:     "visual",
+}
+
+func (s NodeStyle) String() string {
+	if name, ok := nodeStyleNames[s]; ok {
+		return name
+	}
+	return fmt.Sprintf("unknown(%d)", uint8(s))
+}
+
+// MarshalText JSONでは名前で出力する
+func (s NodeStyle) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+var portProtocolNames = [...]string{"DMX512", "MIDI", "Avab", "Colortran CMX", "ADB 62.5", "Art-Net", "DALI"}
+
 func NewArtNetNode(p *packet.ArtPollReplyPacket) *ArtNetNode {
 	shortName := string(bytes.Trim(p.ShortName[:], "\x00"))
 	longName := string(bytes.Trim(p.LongName[:], "\x00"))
 	nodeReport := string(bytes.Trim([]byte(string(p.NodeReport[:])), "\x00"))
 
-	return &ArtNetNode{
-		IPAddress:  net.IP(p.IPAddress[:]),
-		ShortName:  shortName,
-		LongName:   longName,
-		NodeReport: nodeReport,
-		MacAddress: net.HardwareAddr(p.Macaddress[:]),
-		LastSeen:   time.Now(),
+	node := &ArtNetNode{
+		IPAddress:   net.IP(append([]byte(nil), p.IPAddress[:]...)),
+		ShortName:   shortName,
+		LongName:    longName,
+		NodeReport:  nodeReport,
+		MacAddress:  net.HardwareAddr(append([]byte(nil), p.Macaddress[:]...)),
+		LastSeen:    time.Now(),
+		Liveness:    NodeOnline,
+		BindIP:      net.IP(append([]byte(nil), p.BindIP[:]...)),
+		BindIndex:   p.BindIndex,
+		Firmware:    p.VersionInfo,
+		UBEAVersion: p.UBEAVersion,
+		Oem:         p.Oem,
+		// ESTA製造者コードは下位バイトから送信される
+		ESTACode:  uint16(p.ESTAmanufacturer[1])<<8 | uint16(p.ESTAmanufacturer[0]),
+		Style:     NodeStyle(p.Style),
+		NetSwitch: p.NetSwitch & 0x7F,
+		SubSwitch: p.SubSwitch & 0x0F,
+		Status1:   decodeStatus1(uint8(p.Status1)),
+		Status2:   decodeStatus2(uint8(p.Status2)),
+	}
+
+	numPorts := p.NumPorts
+	if numPorts > PortsPerPage {
+		numPorts = PortsPerPage
+	}
+	node.NumPorts = uint8(numPorts)
+	node.Ports = make([]ArtNetNodePort, 0, numPorts)
+	for i := 0; i < int(numPorts); i++ {
+		node.Ports = append(node.Ports, node.decodePort(i, p))
+	}
+	return node
+}
+
+// Key リポジトリでノードを識別するキー（IPアドレスとBindIndexの組）
+func (n *ArtNetNode) Key() string {
+	return NodeKey(n.IPAddress, n.BindIndex)
+}
+
+// NodeKey IPアドレスとBindIndexからノードのキーを作成する
+// BindIndex 0（Bind非対応ノード）はルートデバイス（1）として扱う
+func NodeKey(ip net.IP, bindIndex uint8) string {
+	if bindIndex == 0 {
+		bindIndex = 1
+	}
+	return fmt.Sprintf("%s#%d", ip.String(), bindIndex)
+}
+
+// WithLiveness 状態を変更したノードのコピーを返す
+// リポジトリに保存済みのノードは配信中に参照されるため直接変更しない
+func (n *ArtNetNode) WithLiveness(liveness NodeLiveness) *ArtNetNode {
+	node := *n
+	node.Liveness = liveness
+	return &node
+}
+
+// OutputUniverses 出力ポートのユニバース一覧
+func (n *ArtNetNode) OutputUniverses() []uint16 {
+	universes := make([]uint16, 0, len(n.Ports))
+	for _, port := range n.Ports {
+		if port.Output {
+			universes = append(universes, port.OutputUniverse)
+		}
+	}
+	return universes
+}
+
+// InputUniverses 入力ポートのユニバース一覧
+func (n *ArtNetNode) InputUniverses() []uint16 {
+	universes := make([]uint16, 0, len(n.Ports))
+	for _, port := range n.Ports {
+		if port.Input {
+			universes = append(universes, port.InputUniverse)
+		}
+	}
+	return universes
+}
+
+func (n *ArtNetNode) decodePort(i int, p *packet.ArtPollReplyPacket) ArtNetNodePort {
+	portType := uint8(p.PortTypes[i])
+	goodIn := uint8(p.GoodInput[i])
+	goodOut := uint8(p.GoodOutput[i])
+	base := uint16(n.NetSwitch)<<8 | uint16(n.SubSwitch)<<4
+
+	protocol := "unknown"
+	if t := int(portType & 0x3F); t < len(portProtocolNames) {
+		protocol = portProtocolNames[t]
+	}
+
+	port := ArtNetNodePort{
+		Index:          i,
+		Protocol:       protocol,
+		Input:          portType&0x40 != 0,
+		Output:         portType&0x80 != 0,
+		InputUniverse:  base | uint16(p.SwIn[i]&0x0F),
+		OutputUniverse: base | uint16(p.SwOut[i]&0x0F),
+		GoodInput: PortInputStatus{
+			DataReceived:  goodIn&0x80 != 0,
+			TestPackets:   goodIn&0x40 != 0,
+			SIP:           goodIn&0x20 != 0,
+			TextPackets:   goodIn&0x10 != 0,
+			Disabled:      goodIn&0x08 != 0,
+			ReceiveErrors: goodIn&0x04 != 0,
+		},
+		GoodOutput: PortOutputStatus{
+			DataTransmitted: goodOut&0x80 != 0,
+			TestPackets:     goodOut&0x40 != 0,
+			SIP:             goodOut&0x20 != 0,
+			TextPackets:     goodOut&0x10 != 0,
+			Merging:         goodOut&0x08 != 0,
+			ShortDetected:   goodOut&0x04 != 0,
+			MergeLTP:        goodOut&0x02 != 0,
+			SACN:            goodOut&0x01 != 0,
+		},
+	}
+	port.Healthy = !(port.Input && (port.GoodInput.ReceiveErrors || port.GoodInput.Disabled)) &&
+		!(port.Output && port.GoodOutput.ShortDetected)
+	return port
+}
+
+// decodeStatus1 Status1 を各フィールドに分解する
+// go-artnet の Status1.Indicator() / PortAddr() はビットマスクが仕様と異なるため独自に解釈する
+func decodeStatus1(v uint8) NodeStatus1 {
+	indicators := [...]string{"unknown", "locate", "mute", "normal"}
+	portAddress := [...]string{"unknown", "front", "network", "unused"}
+	return NodeStatus1{
+		Indicator:       indicators[v>>6&0x03],
+		PortAddressFrom: portAddress[v>>4&0x03],
+		BootROM:         v&0x04 != 0,
+		RDM:             v&0x02 != 0,
+		UBEA:            v&0x01 != 0,
+	}
+}
+
+// decodeStatus2 Status2 を各フィールドに分解する
+func decodeStatus2(v uint8) NodeStatus2 {
+	return NodeStatus2{
+		WebConfig:        v&0x01 != 0,
+		DHCP:             v&0x02 != 0,
+		DHCPCapable:      v&0x04 != 0,
+		PortAddress15Bit: v&0x08 != 0,
+		SACNSwitchable:   v&0x10 != 0,
+		Squawking:        v&0x20 != 0,
 	}
 }
@@ -0,0 +1,110 @@
+package model
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/jsimonetti/go-artnet/packet"
+	"github.com/jsimonetti/go-artnet/packet/code"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestPollReply() *packet.ArtPollReplyPacket {
+	p := &packet.ArtPollReplyPacket{
+		IPAddress:        [4]byte{2, 0, 0, 20},
+		BindIP:           [4]byte{2, 0, 0, 20},
+		BindIndex:        2,
+		VersionInfo:      0x0103,
+		Oem:              0x2CA0,
+		ESTAmanufacturer: [2]byte{'L', 'A'},
+		NetSwitch:        0x01,
+		SubSwitch:        0x02,
+		NumPorts:         3,
+		PortTypes:        [4]code.PortType{0x80, 0xC0, 0x40, 0x80},
+		GoodInput:        [4]code.GoodInput{0x00, 0x80, 0x08, 0x00},
+		GoodOutput:       [4]code.GoodOutput{0x80, 0x84, 0x00, 0x00},
+		SwIn:             [4]uint8{0, 4, 5, 0},
+		SwOut:            [4]uint8{0, 3, 0, 7},
+		Style:            code.StNode,
+		Status1:          0xE2, // Indicator normal, Port-Address設定はネットワーク, RDM対応
+		Status2:          0x0E,
+		Macaddress:       [6]byte{0x02, 0x00, 0x00, 0x00, 0x00, 0x14},
+	}
+	copy(p.ShortName[:], "Gateway")
+	copy(p.LongName[:], "Test Gateway")
+	return p
+}
+
+func TestNewArtNetNode(t *testing.T) {
+	node := NewArtNetNode(newTestPollReply())
+
+	assert.Equal(t, "2.0.0.20", node.IPAddress.String())
+	assert.Equal(t, "Gateway", node.ShortName)
+	assert.Equal(t, "Test Gateway", node.LongName)
+	assert.Equal(t, "02:00:00:00:00:14", node.MacAddress.String())
+	assert.Equal(t, uint8(2), node.BindIndex)
+	assert.Equal(t, uint16(0x0103), node.Firmware)
+	assert.Equal(t, uint16(0x2CA0), node.Oem)
+	assert.Equal(t, uint16('A')<<8|uint16('L'), node.ESTACode)
+	assert.Equal(t, StyleNode, node.Style)
+	assert.Equal(t, NodeStatus1{Indicator: "normal", PortAddressFrom: "network", RDM: true}, node.Status1)
+	assert.Equal(t, NodeStatus2{DHCP: true, DHCPCapable: true, PortAddress15Bit: true}, node.Status2)
+
+	require.Len(t, node.Ports, 3)
+
+	assert.True(t, node.Ports[0].Output)
+	assert.False(t, node.Ports[0].Input)
+	assert.Equal(t, "DMX512", node.Ports[0].Protocol)
+	assert.Equal(t, uint16(0x120), node.Ports[0].OutputUniverse)
+	assert.True(t, node.Ports[0].GoodOutput.DataTransmitted)
+	assert.True(t, node.Ports[0].Healthy)
+
+	assert.True(t, node.Ports[1].Input)
+	assert.True(t, node.Ports[1].Output)
+	assert.Equal(t, uint16(0x124), node.Ports[1].InputUniverse)
+	assert.Equal(t, uint16(0x123), node.Ports[1].OutputUniverse)
+	assert.True(t, node.Ports[1].GoodInput.DataReceived)
+	assert.True(t, node.Ports[1].GoodOutput.ShortDetected)
+	assert.False(t, node.Ports[1].Healthy)
+
+	assert.True(t, node.Ports[2].GoodInput.Disabled)
+	assert.False(t, node.Ports[2].Healthy)
+
+	assert.Equal(t, []uint16{0x120, 0x123}, node.OutputUniverses())
+	assert.Equal(t, []uint16{0x124, 0x125}, node.InputUniverses())
+}
+
+func TestNewArtNetNode_ClampsNumPorts(t *testing.T) {
+	p := newTestPollReply()
+	p.NumPorts = 8
+
+	node := NewArtNetNode(p)
+	assert.Equal(t, uint8(PortsPerPage), node.NumPorts)
+	assert.Len(t, node.Ports, PortsPerPage)
+}
+
+func TestNodeKey(t *testing.T) {
+	ip := net.IPv4(2, 0, 0, 20)
+	assert.Equal(t, "2.0.0.20#1", NodeKey(ip, 0))
+	assert.Equal(t, "2.0.0.20#1", NodeKey(ip, 1))
+	assert.Equal(t, "2.0.0.20#3", NodeKey(ip, 3))
+
+	node := NewArtNetNode(newTestPollReply())
+	assert.Equal(t, "2.0.0.20#2", node.Key())
+}
+
+func TestNodeLivenessPolicy(t *testing.T) {
+	policy := NewNodeLivenessPolicy(5*time.Second, time.Minute)
+	lastSeen := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, NodeOnline, policy.Evaluate(lastSeen, lastSeen.Add(9*time.Second)))
+	assert.Equal(t, NodeStale, policy.Evaluate(lastSeen, lastSeen.Add(10*time.Second)))
+	assert.Equal(t, NodeOffline, policy.Evaluate(lastSeen, lastSeen.Add(15*time.Second)))
+	assert.False(t, policy.Expired(lastSeen, lastSeen.Add(59*time.Second)))
+	assert.True(t, policy.Expired(lastSeen, lastSeen.Add(time.Minute)))
+
+	policy.ExpireAfter = 0
+	assert.False(t, policy.Expired(lastSeen, lastSeen.Add(time.Hour)))
+}
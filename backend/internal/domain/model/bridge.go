@@ -0,0 +1,75 @@
+package model
+
+import "fmt"
+
+// BridgeDirection ブリッジで変換するプロトコルの向き
+type BridgeDirection string
+
+const (
+	BridgeArtNetToSACN BridgeDirection = "artnet-to-sacn"
+	BridgeSACNToArtNet BridgeDirection = "sacn-to-artnet"
+)
+
+// InputProtocol 入力側のプロトコル
+func (d BridgeDirection) InputProtocol() string {
+	if d == BridgeSACNToArtNet {
+		return ProtocolSACN
+	}
+	return ProtocolArtNet
+}
+
+// OutputProtocol 出力側のプロトコル
+func (d BridgeDirection) OutputProtocol() string {
+	if d == BridgeSACNToArtNet {
+		return ProtocolArtNet
+	}
+	return ProtocolSACN
+}
+
+// BridgeRoute 一方のプロトコルのユニバースをもう一方のプロトコルのユニバースに転送する経路
+type BridgeRoute struct {
+	Direction BridgeDirection `json:"Direction"`
+	Input     uint16          `json:"Input"`  // 入力ユニバース
+	Output    uint16          `json:"Output"` // 出力ユニバース
+}
+
+// BridgeRouteStats 経路ごとの転送状況
+type BridgeRouteStats struct {
+	BridgeRoute
+	Sources    int    `json:"Sources"`    // 入力ユニバースを送信している送信元の数
+	Active     bool   `json:"Active"`     // 出力中かどうか
+	FramesIn   uint64 `json:"FramesIn"`   // 受信した入力フレーム数
+	FramesOut  uint64 `json:"FramesOut"`  // 送信した出力フレーム数（キープアライブを含む）
+	KeepAlives uint64 `json:"KeepAlives"` // 入力がないため再送した出力フレーム数
+	SendErrors uint64 `json:"SendErrors"` // 送信に失敗した出力フレーム数
+}
+
+// ParseBridgeRoutes 入力ユニバースの指定（例: "0-3,16"）とオフセットから経路を作成する
+// 出力ユニバースは 入力ユニバース + offset とし、出力側のプロトコルの範囲外になる場合はエラーとする
+func ParseBridgeRoutes(direction BridgeDirection, spec string, offset int) ([]BridgeRoute, error) {
+	var inputs []uint16
+	var err error
+	if direction.InputProtocol() == ProtocolSACN {
+		inputs, err = ParseSACNUniverseList(spec)
+	} else {
+		inputs, err = ParseUniverseList(spec)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	minOutput, maxOutput := 0, MaxUniverse
+	if direction.OutputProtocol() == ProtocolSACN {
+		minOutput, maxOutput = 1, MaxSACNUniverse
+	}
+
+	routes := make([]BridgeRoute, 0, len(inputs))
+	for _, input := range inputs {
+		output := int(input) + offset
+		if output < minOutput || output > maxOutput {
+			return nil, fmt.Errorf("%s output universe %d for input %d out of range (%d-%d)", direction, output, input, minOutput, maxOutput)
+		}
+		routes = append(routes, BridgeRoute{Direction: direction, Input: input, Output: uint16(output)})
+	}
+	return routes, nil
+}
@@ -0,0 +1,38 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBridgeRoutes(t *testing.T) {
+	routes, err := ParseBridgeRoutes(BridgeArtNetToSACN, "0-1", 1)
+	require.NoError(t, err)
+	assert.Equal(t, []BridgeRoute{
+		{Direction: BridgeArtNetToSACN, Input: 0, Output: 1},
+		{Direction: BridgeArtNetToSACN, Input: 1, Output: 2},
+	}, routes)
+
+	routes, err = ParseBridgeRoutes(BridgeSACNToArtNet, "1,16", -1)
+	require.NoError(t, err)
+	assert.Equal(t, []BridgeRoute{
+		{Direction: BridgeSACNToArtNet, Input: 1, Output: 0},
+		{Direction: BridgeSACNToArtNet, Input: 16, Output: 15},
+	}, routes)
+
+	routes, err = ParseBridgeRoutes(BridgeArtNetToSACN, "", 1)
+	require.NoError(t, err)
+	assert.Empty(t, routes)
+
+	// sACN のユニバース0には出力できない
+	_, err = ParseBridgeRoutes(BridgeArtNetToSACN, "0", 0)
+	assert.Error(t, err)
+	// sACN のユニバース0は入力として指定できない
+	_, err = ParseBridgeRoutes(BridgeSACNToArtNet, "0", 1)
+	assert.Error(t, err)
+	// Art-Net の最大値を超える出力
+	_, err = ParseBridgeRoutes(BridgeSACNToArtNet, "32768", 0)
+	assert.Error(t, err)
+}
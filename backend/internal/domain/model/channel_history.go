@@ -0,0 +1,92 @@
+package model
+
+import "time"
+
+// 保持する履歴の解像度
+const (
+	HistorySecondResolution = 1 * time.Second
+	HistoryTenSecResolution = 10 * time.Second
+)
+
+// MaxHistoryPoints 1回の取得で返す履歴の最大件数
+const MaxHistoryPoints = 10000
+
+// HistoryPolicy ユニバースごとに保持する履歴の期間
+//
+// 直近 FullRate は受信したフレームをそのまま保持し、直近 Second は1秒、Retention までは10秒ごとに
+// 最小・最大・平均を集計して保持する。
+type HistoryPolicy struct {
+	FullRate  time.Duration
+	Second    time.Duration
+	Retention time.Duration
+}
+
+// NewHistoryPolicy 保持する期間を作成する（短い解像度の期間が長い解像度の期間を超えないように切り詰める）
+func NewHistoryPolicy(fullRate, second, retention time.Duration) HistoryPolicy {
+	second = min(second, retention)
+	fullRate = min(fullRate, second)
+	return HistoryPolicy{FullRate: fullRate, Second: second, Retention: retention}
+}
+
+// ChannelHistoryPoint 1チャンネルの履歴の1区間
+// 受信したフレームをそのまま返す場合は Min・Max・Avg が同じ値になる
+type ChannelHistoryPoint struct {
+	Time  time.Time `json:"Time"` // 区間の開始時刻
+	Min   uint8     `json:"Min"`
+	Max   uint8     `json:"Max"`
+	Avg   float64   `json:"Avg"`
+	Count uint32    `json:"-"` // 区間で受信したフレーム数（平均の再集計に使用する）
+}
+
+// ChannelRangeHistoryPoint 連続した複数チャンネルの履歴の1区間
+type ChannelRangeHistoryPoint struct {
+	Time  time.Time
+	Count uint32
+	Avg   []float64 // チャンネルごとの平均値
+}
+
+// ChannelHistoryQuery 取得する履歴の条件
+type ChannelHistoryQuery struct {
+	Protocol string // 空の場合は artnet
+	Universe uint16
+	Channel  int           // 1-512
+	From     time.Time     // ゼロ値の場合は To から保持期間だけ前
+	To       time.Time     // ゼロ値の場合は現在時刻
+	Step     time.Duration // 0の場合は保持している解像度のまま
+}
+
+// ChannelHistory 1チャンネルの履歴
+type ChannelHistory struct {
+	Protocol string                `json:"Protocol"`
+	Universe uint16                `json:"Universe"`
+	Channel  int                   `json:"Channel"` // 1-512
+	From     time.Time             `json:"From"`
+	To       time.Time             `json:"To"`
+	StepMs   int64                 `json:"StepMs"` // 0の場合は保持している解像度のまま
+	Points   []ChannelHistoryPoint `json:"Points"`
+}
+
+// DownsampleChannelHistory 古い順の履歴を step ごとの区間に集計し直す
+func DownsampleChannelHistory(points []ChannelHistoryPoint, step time.Duration) []ChannelHistoryPoint {
+	if step <= 0 || len(points) == 0 {
+		return points
+	}
+	result := make([]ChannelHistoryPoint, 0)
+	var current *ChannelHistoryPoint
+	for _, p := range points {
+		start := p.Time.Truncate(step)
+		if current == nil || !current.Time.Equal(start) {
+			result = append(result, ChannelHistoryPoint{Time: start, Min: p.Min, Max: p.Max, Avg: p.Avg, Count: p.Count})
+			current = &result[len(result)-1]
+			continue
+		}
+		current.Min = min(current.Min, p.Min)
+		current.Max = max(current.Max, p.Max)
+		total := current.Count + p.Count
+		if total > 0 {
+			current.Avg = (current.Avg*float64(current.Count) + p.Avg*float64(p.Count)) / float64(total)
+		}
+		current.Count = total
+	}
+	return result
+}
@@ -0,0 +1,30 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDownsampleChannelHistory(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	points := []ChannelHistoryPoint{
+		{Time: base, Min: 10, Max: 10, Avg: 10, Count: 1},
+		{Time: base.Add(500 * time.Millisecond), Min: 0, Max: 30, Avg: 20, Count: 3},
+		{Time: base.Add(2 * time.Second), Min: 200, Max: 255, Avg: 220, Count: 2},
+	}
+
+	got := DownsampleChannelHistory(points, 2*time.Second)
+	assert.Equal(t, []ChannelHistoryPoint{
+		{Time: base, Min: 0, Max: 30, Avg: 17.5, Count: 4},
+		{Time: base.Add(2 * time.Second), Min: 200, Max: 255, Avg: 220, Count: 2},
+	}, got)
+
+	assert.Equal(t, points, DownsampleChannelHistory(points, 0))
+}
+
+func TestNewHistoryPolicy(t *testing.T) {
+	policy := NewHistoryPolicy(time.Hour, 10*time.Minute, 5*time.Minute)
+	assert.Equal(t, HistoryPolicy{FullRate: 5 * time.Minute, Second: 5 * time.Minute, Retention: 5 * time.Minute}, policy)
+}
@@ -0,0 +1,43 @@
+package model
+
+// DMXDelta 送信元・ユニバースごとの値の差分（{protocol}/dmx_delta/{universe} トピックで配信する）
+//
+// キーフレームは全チャンネルの値（Data）、それ以外は前回から変化したチャンネルと値の組（Changes）を持つ。
+// Sequence は差分ごとに1ずつ増え、キーフレームはその時点の Sequence を持つ。
+// キーフレームより前に届いた差分は反映しない。
+// クライアントはキーフレームを受け取った後、Sequence が連続しない差分を受け取った場合は
+// 同じトピックを購読し直してキーフレームを要求する（キーフレームは要求したクライアントにのみ送信する）。
+type DMXDelta struct {
+	Protocol string
+	Universe uint16
+	Source   string // 送信元（Art-Net は送信元IP、sACN は CID）
+	Sequence uint32
+	Keyframe bool
+	Length   uint16
+	Data     *[512]uint8 `json:",omitempty"` // キーフレームのみ
+	Changes  [][2]uint16 `json:",omitempty"` // [チャンネル(1-512), 値]（キーフレーム以外）
+}
+
+// DiffDMX 変化したチャンネル（1-512）と変化後の値の組をチャンネル順に返す
+func DiffDMX(from, to *[512]uint8) [][2]uint16 {
+	var changes [][2]uint16
+	for ch := range to {
+		if from[ch] != to[ch] {
+			changes = append(changes, [2]uint16{uint16(ch + 1), uint16(to[ch])})
+		}
+	}
+	return changes
+}
+
+// Apply 差分を値に反映する（キーフレームの場合は全チャンネルを置き換える）
+func (d *DMXDelta) Apply(data *[512]uint8) {
+	if d.Keyframe && d.Data != nil {
+		*data = *d.Data
+		return
+	}
+	for _, change := range d.Changes {
+		if change[0] >= 1 && change[0] <= 512 {
+			data[change[0]-1] = uint8(change[1])
+		}
+	}
+}
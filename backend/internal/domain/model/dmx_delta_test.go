@@ -0,0 +1,30 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffDMX(t *testing.T) {
+	var from, to [512]uint8
+	assert.Empty(t, DiffDMX(&from, &to))
+
+	to[0], to[511] = 255, 7
+	from[10], to[10] = 3, 3
+	assert.Equal(t, [][2]uint16{{1, 255}, {512, 7}}, DiffDMX(&from, &to))
+}
+
+func TestDMXDelta_Apply(t *testing.T) {
+	var keyframeData [512]uint8
+	keyframeData[4] = 100
+
+	var data [512]uint8
+	data[0] = 1
+	(&DMXDelta{Keyframe: true, Data: &keyframeData}).Apply(&data)
+	assert.Equal(t, keyframeData, data)
+
+	(&DMXDelta{Changes: [][2]uint16{{5, 0}, {512, 9}, {0, 1}, {513, 1}}}).Apply(&data)
+	assert.Equal(t, uint8(0), data[4])
+	assert.Equal(t, uint8(9), data[511])
+}
@@ -0,0 +1,118 @@
+package model
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ExportFormat 書き出す形式
+type ExportFormat string
+
+const (
+	ExportFormatCSV    ExportFormat = "csv"
+	ExportFormatNDJSON ExportFormat = "ndjson"
+)
+
+// ParseExportFormat 書き出す形式を解析する（空の場合は CSV）
+func ParseExportFormat(s string) (ExportFormat, error) {
+	switch strings.ToLower(s) {
+	case "", string(ExportFormatCSV):
+		return ExportFormatCSV, nil
+	case string(ExportFormatNDJSON), "jsonl":
+		return ExportFormatNDJSON, nil
+	default:
+		return "", fmt.Errorf("unknown export format %q (csv or ndjson)", s)
+	}
+}
+
+// ExportMode 書き出す行の単位
+type ExportMode string
+
+const (
+	// ExportModeChanges 選択したチャンネルのいずれかの値が変化したときに1行を書き出す
+	ExportModeChanges ExportMode = "changes"
+	// ExportModeResample 一定間隔で1行を書き出す（キャプチャファイルは直前の値、履歴は区間の平均）
+	ExportModeResample ExportMode = "resample"
+)
+
+// ParseExportMode 書き出す行の単位を解析する（空の場合は変化したとき）
+func ParseExportMode(s string) (ExportMode, error) {
+	switch strings.ToLower(s) {
+	case "", string(ExportModeChanges):
+		return ExportModeChanges, nil
+	case string(ExportModeResample):
+		return ExportModeResample, nil
+	default:
+		return "", fmt.Errorf("unknown export mode %q (changes or resample)", s)
+	}
+}
+
+// ExportOptions 書き出すチャンネルと期間
+type ExportOptions struct {
+	Mode         ExportMode
+	Protocol     string // 空の場合は artnet
+	Universe     uint16
+	FirstChannel int           // 1-512（0の場合は1）
+	LastChannel  int           // 1-512（0の場合は512）
+	From         time.Time     // ゼロ値の場合は先頭から
+	To           time.Time     // ゼロ値の場合は末尾まで
+	Step         time.Duration // ExportModeResample の間隔
+}
+
+// ExportRow 書き出す1行
+type ExportRow struct {
+	Time    time.Time
+	Elapsed time.Duration // 最初の行からの経過時間
+	Source  string        // 送信元（履歴の場合はマージした出力を表す "merged"）
+	Values  []float64     // FirstChannel から LastChannel までの値
+}
+
+// ParseChannelRange "1-16"、"5" 形式のチャンネルの範囲を解析する（空の場合はすべてのチャンネル）
+func ParseChannelRange(s string) (int, int, error) {
+	if s == "" {
+		return 1, 512, nil
+	}
+	firstText, lastText, isRange := strings.Cut(s, "-")
+	first, err := strconv.Atoi(strings.TrimSpace(firstText))
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid channel %q", firstText)
+	}
+	last := first
+	if isRange {
+		if last, err = strconv.Atoi(strings.TrimSpace(lastText)); err != nil {
+			return 0, 0, fmt.Errorf("invalid channel %q", lastText)
+		}
+	}
+	if first < 1 || last > 512 || first > last {
+		return 0, 0, fmt.Errorf("invalid channel range %q (1-512)", s)
+	}
+	return first, last, nil
+}
+
+// ParseTimeParam UNIXミリ秒または RFC 3339 の時刻を解析する（空の場合はゼロ値）
+func ParseTimeParam(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if ms, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return time.UnixMilli(ms), nil
+	}
+	return time.Parse(time.RFC3339Nano, s)
+}
+
+// ParseStepParam "1s" などの時間またはミリ秒の間隔を解析する（空の場合は0）
+func ParseStepParam(s string) (time.Duration, error) {
+	if s == "" {
+		return 0, nil
+	}
+	if ms, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return time.Duration(ms) * time.Millisecond, nil
+	}
+	step, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("%q is neither a duration nor milliseconds", s)
+	}
+	return step, nil
+}
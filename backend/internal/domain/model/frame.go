@@ -0,0 +1,177 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"time"
+)
+
+const (
+	MaxUniverse = 0x7FFF
+	// MaxSACNUniverse sACN (E1.31) のユニバースの最大値（sACN のユニバースは1から始まる）
+	MaxSACNUniverse = 63999
+)
+
+// 受信したプロトコル
+const (
+	ProtocolArtNet = "artnet"
+	ProtocolSACN   = "sacn"
+)
+
+// StartCodeDMX 通常のレベルデータを示す START Code
+const StartCodeDMX uint8 = 0x00
+
+// DMXFrame 受信したプロトコルに依存しない1ユニバース分のDMXフレーム
+//
+// 各プロトコルのパケットからの変換は受信側（infrastructure/artnet, infrastructure/sacn）で行う。
+type DMXFrame struct {
+	Protocol        string      // 受信したプロトコル（artnet / sacn）
+	Universe        uint16      // ユニバース番号（Art-Net は15bitのポートアドレス、sACN は1-63999）
+	StartCode       uint8       // START Code
+	Length          uint16      // スロット数
+	Data            [512]uint8  // スロットデータ（START Code を除く）
+	SourceIP        net.IP      // 送信元IPアドレス
+	SourcePort      int         // 送信元ポート番号
+	SourceName      string      // 送信元名（sACN のみ）
+	SourceCID       string      // 送信元のCID（sACN のみ）
+	Priority        uint8       // 優先度（sACN のみ）
+	ChannelPriority *[512]uint8 // アドレスごとの優先度（sACN で START Code 0xDD を受信していない場合はnil）
+	Sequence        uint8       // シーケンス番号（0はシーケンス番号なし）
+	Physical        uint8       // 物理入力ポート（Art-Net のみ）
+	Synced          bool        // ArtSyncにより同期出力されたフレームかどうか
+	ReceivedAt      time.Time   // 受信時刻
+}
+
+// dmxFrameJSON WebSocketで配信するJSON表現（従来の ArtDMX 形式のフィールドを維持する）
+type dmxFrameJSON struct {
+	Sequence        uint8       `json:"Sequence"`
+	Physical        uint8       `json:"Physical"`
+	SubUni          uint8       `json:"SubUni"`
+	Net             uint8       `json:"Net"`
+	Length          uint16      `json:"Length"`
+	Data            [512]uint8  `json:"Data"`
+	SourceIP        net.IP      `json:"SourceIP"`
+	SourcePort      int         `json:"SourcePort"`
+	Synced          bool        `json:"Synced"`
+	Protocol        string      `json:"Protocol"`
+	Priority        uint8       `json:"Priority,omitempty"`
+	SourceName      string      `json:"SourceName,omitempty"`
+	ChannelPriority *[512]uint8 `json:"ChannelPriority,omitempty"`
+	Universe        uint16      `json:"Universe"`
+	StartCode       uint8       `json:"StartCode"`
+	SourceCID       string      `json:"SourceCID,omitempty"`
+	ReceivedAt      time.Time   `json:"ReceivedAt"`
+}
+
+// MarshalJSON Net / SubUni を含む従来のフィールドに加えて、プロトコル共通のフィールドを出力する
+func (d *DMXFrame) MarshalJSON() ([]byte, error) {
+	return json.Marshal(dmxFrameJSON{
+		Sequence:        d.Sequence,
+		Physical:        d.Physical,
+		SubUni:          uint8(d.Universe),
+		Net:             uint8(d.Universe >> 8),
+		Length:          d.Length,
+		Data:            d.Data,
+		SourceIP:        d.SourceIP,
+		SourcePort:      d.SourcePort,
+		Synced:          d.Synced,
+		Protocol:        d.Protocol,
+		Priority:        d.Priority,
+		SourceName:      d.SourceName,
+		ChannelPriority: d.ChannelPriority,
+		Universe:        d.Universe,
+		StartCode:       d.StartCode,
+		SourceCID:       d.SourceCID,
+		ReceivedAt:      d.ReceivedAt,
+	})
+}
+
+// Validate DMXFrameの妥当性を検証
+func (d *DMXFrame) Validate() error {
+	if d.Length > 512 {
+		return fmt.Errorf("length %d exceeds maximum DMX channels %d", d.Length, 512)
+	}
+
+	if d.Protocol == ProtocolSACN {
+		if d.Universe < 1 || d.Universe > MaxSACNUniverse {
+			return fmt.Errorf("sACN universe %d out of range (1-%d)", d.Universe, MaxSACNUniverse)
+		}
+		return nil
+	}
+	if d.Universe > MaxUniverse {
+		return fmt.Errorf("universe %d exceeds maximum %d", d.Universe, MaxUniverse)
+	}
+
+	return nil
+}
+
+// 指定チャンネルの値を取得（1-based）
+func (d *DMXFrame) GetChannelValue(channel int) (uint8, error) {
+	if channel < 1 || channel > 512 {
+		return 0, fmt.Errorf("channel %d out of range (1-512)", channel)
+	}
+
+	index := channel - 1
+	if uint16(index) >= d.Length {
+		return 0, nil // 範囲外は0を返す
+	}
+
+	return d.Data[index], nil
+}
+
+// 指定チャンネルの値を設定（1-based）
+func (d *DMXFrame) SetChannelValue(channel int, value uint8) error {
+	if channel < 1 || channel > 512 {
+		return fmt.Errorf("channel %d out of range (1-512)", channel)
+	}
+
+	index := channel - 1
+	d.Data[index] = value
+
+	// Lengthを必要に応じて拡張
+	if uint16(channel) > d.Length {
+		d.Length = uint16(channel)
+	}
+
+	return nil
+}
+
+// 指定範囲のチャンネル値を取得（1-based）
+func (d *DMXFrame) GetChannelRange(startChannel, endChannel int) ([]uint8, error) {
+	if startChannel < 1 || startChannel > 512 {
+		return nil, fmt.Errorf("start channel %d out of range (1-512)", startChannel)
+	}
+	if endChannel < 1 || endChannel > 512 {
+		return nil, fmt.Errorf("end channel %d out of range (1-512)", endChannel)
+	}
+	if startChannel > endChannel {
+		return nil, fmt.Errorf("start channel %d cannot be greater than end channel %d", startChannel, endChannel)
+	}
+
+	result := make([]uint8, endChannel-startChannel+1)
+	for i := startChannel; i <= endChannel; i++ {
+		value, _ := d.GetChannelValue(i)
+		result[i-startChannel] = value
+	}
+
+	return result, nil
+}
+
+// String DMXFrameの文字列表現
+func (d *DMXFrame) String() string {
+	return fmt.Sprintf("DMX[Universe:%d, Seq:%d, Length:%d]",
+		d.Universe, d.Sequence, d.Length)
+}
+
+// DMXFrameの深いコピーを作成
+func (d *DMXFrame) Clone() *DMXFrame {
+	clone := *d
+	clone.SourceIP = make(net.IP, len(d.SourceIP))
+	copy(clone.SourceIP, d.SourceIP)
+	if d.ChannelPriority != nil {
+		priorities := *d.ChannelPriority
+		clone.ChannelPriority = &priorities
+	}
+	return &clone
+}
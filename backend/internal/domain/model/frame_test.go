@@ -1,93 +1,77 @@
 package model
 
 import (
+	"encoding/json"
 	"net"
 	"testing"
+	"time"
 
-	"github.com/jsimonetti/go-artnet/packet"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
-func TestNewDMXData(t *testing.T) {
+func TestDMXFrame_MarshalJSON(t *testing.T) {
+	frame := &DMXFrame{
+		Protocol:   ProtocolArtNet,
+		Universe:   517, // 2*256 + 5
+		Sequence:   10,
+		Length:     3,
+		Data:       [512]byte{255, 128, 64},
+		SourceIP:   net.ParseIP("2.0.0.10"),
+		SourcePort: 6454,
+		ReceivedAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	b, err := json.Marshal(frame)
+	require.NoError(t, err)
+
+	var got map[string]any
+	require.NoError(t, json.Unmarshal(b, &got))
+	// 従来の ArtDMX 形式のフィールド
+	assert.Equal(t, float64(2), got["Net"])
+	assert.Equal(t, float64(5), got["SubUni"])
+	assert.Equal(t, float64(10), got["Sequence"])
+	assert.Equal(t, float64(3), got["Length"])
+	assert.Equal(t, "2.0.0.10", got["SourceIP"])
+	assert.Equal(t, float64(6454), got["SourcePort"])
+	assert.Equal(t, false, got["Synced"])
+	assert.Len(t, got["Data"], 512)
+	// プロトコル共通のフィールド
+	assert.Equal(t, "artnet", got["Protocol"])
+	assert.Equal(t, float64(517), got["Universe"])
+	assert.Equal(t, float64(0), got["StartCode"])
+	assert.Equal(t, "2024-01-01T00:00:00Z", got["ReceivedAt"])
+	assert.NotContains(t, got, "Priority")
+	assert.NotContains(t, got, "SourceCID")
+}
+
+func TestDMXFrame_Validate(t *testing.T) {
 	tests := []struct {
 		name    string
-		packet  *packet.ArtDMXPacket
+		frame   DMXFrame
 		wantErr bool
 	}{
-		{
-			name: "Valid DMX packet",
-			packet: &packet.ArtDMXPacket{
-				Sequence: 1,
-				Physical: 0,
-				SubUni:   5,
-				Net:      2,
-				Length:   100,
-				Data:     [512]byte{255, 128, 64, 32},
-			},
-			wantErr: false,
-		},
-		{
-			name:    "Nil packet",
-			packet:  nil,
-			wantErr: true,
-		},
-		{
-			name: "Invalid length",
-			packet: &packet.ArtDMXPacket{
-				Length: 513, // 最大値を超える
-			},
-			wantErr: true,
-		},
-	}
-
-	addr := &net.UDPAddr{
-		IP:   net.ParseIP("127.0.0.1"),
-		Port: 1234,
+		{name: "Art-Net universe", frame: DMXFrame{Protocol: ProtocolArtNet, Universe: MaxUniverse, Length: 512}},
+		{name: "Art-Net universe out of range", frame: DMXFrame{Protocol: ProtocolArtNet, Universe: MaxUniverse + 1}, wantErr: true},
+		{name: "sACN universe", frame: DMXFrame{Protocol: ProtocolSACN, Universe: MaxSACNUniverse}},
+		{name: "sACN universe 0", frame: DMXFrame{Protocol: ProtocolSACN, Universe: 0}, wantErr: true},
+		{name: "Invalid length", frame: DMXFrame{Length: 513}, wantErr: true},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			dmx, err := NewDMXData(addr, tt.packet)
+			err := tt.frame.Validate()
 			if tt.wantErr {
 				assert.Error(t, err)
-				assert.Nil(t, dmx)
 			} else {
 				assert.NoError(t, err)
-				assert.NotNil(t, dmx)
-				assert.Equal(t, tt.packet.Sequence, dmx.Sequence)
-				assert.Equal(t, tt.packet.Physical, dmx.Physical)
-				assert.Equal(t, tt.packet.SubUni, dmx.SubUni)
-				assert.Equal(t, tt.packet.Net, dmx.Net)
-				assert.Equal(t, tt.packet.Length, dmx.Length)
-				assert.Equal(t, tt.packet.Data, dmx.Data)
 			}
 		})
 	}
 }
 
-func TestDMXData_GetUniverse(t *testing.T) {
-	dmx := &DMXData{
-		Net:    2,
-		SubUni: 5,
-	}
-
-	expected := uint16(2<<8) | uint16(5) // 2*256 + 5 = 517
-	assert.Equal(t, expected, dmx.GetUniverse())
-}
-
-func TestDMXData_SetUniverse(t *testing.T) {
-	dmx := &DMXData{}
-	universe := uint16(517) // 2*256 + 5
-
-	dmx.SetUniverse(universe)
-
-	assert.Equal(t, uint8(2), dmx.Net)
-	assert.Equal(t, uint8(5), dmx.SubUni)
-}
-
-func TestDMXData_GetChannelValue(t *testing.T) {
-	dmx := &DMXData{
+func TestDMXFrame_GetChannelValue(t *testing.T) {
+	dmx := &DMXFrame{
 		Length: 10,
 		Data:   [512]byte{255, 128, 64, 32, 16, 8, 4, 2, 1, 0},
 	}
@@ -141,8 +125,8 @@ func TestDMXData_GetChannelValue(t *testing.T) {
 	}
 }
 
-func TestDMXData_SetChannelValue(t *testing.T) {
-	dmx := &DMXData{
+func TestDMXFrame_SetChannelValue(t *testing.T) {
+	dmx := &DMXFrame{
 		Length: 5,
 	}
 
@@ -165,8 +149,8 @@ func TestDMXData_SetChannelValue(t *testing.T) {
 	assert.Error(t, err)
 }
 
-func TestDMXData_GetChannelRange(t *testing.T) {
-	dmx := &DMXData{
+func TestDMXFrame_GetChannelRange(t *testing.T) {
+	dmx := &DMXFrame{
 		Length: 10,
 		Data:   [512]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10},
 	}
@@ -185,14 +169,14 @@ func TestDMXData_GetChannelRange(t *testing.T) {
 	assert.Error(t, err)
 }
 
-func TestDMXData_Clone(t *testing.T) {
-	original := &DMXData{
+func TestDMXFrame_Clone(t *testing.T) {
+	original := &DMXFrame{
 		Sequence: 1,
 		Physical: 2,
-		SubUni:   3,
-		Net:      4,
+		Universe: 0x0403,
 		Length:   10,
 		Data:     [512]byte{1, 2, 3, 4, 5},
+		SourceIP: net.ParseIP("2.0.0.10"),
 	}
 
 	clone := original.Clone()
@@ -200,8 +184,8 @@ func TestDMXData_Clone(t *testing.T) {
 	// 値が同じことを確認
 	assert.Equal(t, original.Sequence, clone.Sequence)
 	assert.Equal(t, original.Physical, clone.Physical)
-	assert.Equal(t, original.SubUni, clone.SubUni)
-	assert.Equal(t, original.Net, clone.Net)
+	assert.Equal(t, original.Universe, clone.Universe)
+	assert.Equal(t, original.SourceIP, clone.SourceIP)
 	assert.Equal(t, original.Length, clone.Length)
 	assert.Equal(t, original.Data, clone.Data)
 
@@ -211,14 +195,15 @@ func TestDMXData_Clone(t *testing.T) {
 	// 一方を変更しても他方に影響しないことを確認
 	clone.Sequence = 99
 	clone.Data[0] = 99
+	clone.SourceIP[len(clone.SourceIP)-1] = 99
 	assert.NotEqual(t, original.Sequence, clone.Sequence)
 	assert.NotEqual(t, original.Data[0], clone.Data[0])
+	assert.NotEqual(t, original.SourceIP, clone.SourceIP)
 }
 
-func TestDMXData_String(t *testing.T) {
-	dmx := &DMXData{
-		Net:      2,
-		SubUni:   5,
+func TestDMXFrame_String(t *testing.T) {
+	dmx := &DMXFrame{
+		Universe: 517,
 		Sequence: 10,
 		Length:   100,
 		Data:     [512]byte{255, 128, 64}, // 3つのアクティブチャンネル
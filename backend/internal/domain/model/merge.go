@@ -0,0 +1,115 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// MergeSourceTimeout この時間データを受信しなかった送信元はマージ対象から外す（Art-Net 仕様）
+const MergeSourceTimeout = 10 * time.Second
+
+// MergeModeSource 指定した送信元のデータのみを表示する
+const MergeModeSource MergeMode = "SOURCE"
+
+// ParseMergeMode 文字列からマージモードを解釈する（大文字小文字は区別しない）
+func ParseMergeMode(s string) (MergeMode, error) {
+	switch mode := MergeMode(strings.ToUpper(s)); mode {
+	case MergeModeHTP, MergeModeLTP, MergeModeSource:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("unknown merge mode %q", s)
+	}
+}
+
+// MergeInput マージ対象となる送信元ごとの最新フレーム
+type MergeInput struct {
+	SourceIP string
+	Frame    *DMXFrame
+	LastSeen time.Time
+}
+
+// MergedFrame 複数の送信元をマージした1ユニバース分のデータ
+type MergedFrame struct {
+	Universe uint16     `json:"Universe"`
+	Mode     MergeMode  `json:"Mode"`     // 実際に適用したマージモード
+	Sources  []string   `json:"Sources"`  // マージ対象となった送信元
+	Selected string     `json:"Selected"` // SOURCE/LTP で採用した送信元
+	Conflict bool       `json:"Conflict"` // 複数の送信元が同時に送信しているか
+	Length   uint16     `json:"Length"`
+	Data     [512]uint8 `json:"Data"`
+}
+
+// MergeFrames 送信元ごとのフレームを指定したモードでマージする
+// SOURCE で指定された送信元が存在しない場合は HTP でマージする
+func MergeFrames(universe uint16, inputs []MergeInput, mode MergeMode, selected string) *MergedFrame {
+	merged := &MergedFrame{
+		Universe: universe,
+		Mode:     mode,
+		Sources:  make([]string, 0, len(inputs)),
+		Conflict: len(inputs) > 1,
+	}
+	for _, in := range inputs {
+		merged.Sources = append(merged.Sources, in.SourceIP)
+	}
+	if len(inputs) == 0 {
+		return merged
+	}
+
+	if mode == MergeModeSource {
+		for _, in := range inputs {
+			if in.SourceIP == selected {
+				merged.copyFrom(in)
+				return merged
+			}
+		}
+		merged.Mode = MergeModeHTP
+	}
+
+	if merged.Mode == MergeModeLTP {
+		latest := inputs[0]
+		for _, in := range inputs[1:] {
+			if in.LastSeen.After(latest.LastSeen) {
+				latest = in
+			}
+		}
+		merged.copyFrom(latest)
+		return merged
+	}
+
+	// HTP: チャンネルごとに最大値を採用する
+	for _, in := range inputs {
+		if in.Frame.Length > merged.Length {
+			merged.Length = in.Frame.Length
+		}
+		for i := 0; i < int(in.Frame.Length); i++ {
+			if v := in.Frame.Data[i]; v > merged.Data[i] {
+				merged.Data[i] = v
+			}
+		}
+	}
+	return merged
+}
+
+func (m *MergedFrame) copyFrom(in MergeInput) {
+	m.Selected = in.SourceIP
+	m.Length = in.Frame.Length
+	m.Data = in.Frame.Data
+}
+
+// UniverseMergeState ユニバースのマージ設定と現在の送信元
+type UniverseMergeState struct {
+	Universe uint16    `json:"Universe"`
+	Mode     MergeMode `json:"Mode"`
+	Selected string    `json:"Selected"` // SOURCE モードで表示する送信元
+	Sources  []string  `json:"Sources"`
+	Conflict bool      `json:"Conflict"`
+}
+
+// UniverseConflict 複数の送信元が同じユニバースを送信している状態の通知
+type UniverseConflict struct {
+	Universe uint16    `json:"Universe"`
+	Sources  []string  `json:"Sources"`
+	Active   bool      `json:"Active"` // false の場合は競合が解消された
+	At       time.Time `json:"At"`
+}
@@ -0,0 +1,71 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newMergeInput(source string, lastSeen time.Time, values ...uint8) MergeInput {
+	frame := &DMXFrame{Length: uint16(len(values))}
+	copy(frame.Data[:], values)
+	return MergeInput{SourceIP: source, Frame: frame, LastSeen: lastSeen}
+}
+
+func TestMergeFrames(t *testing.T) {
+	now := time.Now()
+	inputs := []MergeInput{
+		newMergeInput("2.0.0.10", now, 255, 0, 100),
+		newMergeInput("2.0.0.11", now.Add(time.Millisecond), 10, 200, 50, 30),
+	}
+
+	tests := []struct {
+		name     string
+		mode     MergeMode
+		selected string
+		wantMode MergeMode
+		wantSel  string
+		want     []uint8
+	}{
+		{name: "HTP", mode: MergeModeHTP, wantMode: MergeModeHTP, want: []uint8{255, 200, 100, 30}},
+		{name: "LTP", mode: MergeModeLTP, wantMode: MergeModeLTP, wantSel: "2.0.0.11", want: []uint8{10, 200, 50, 30}},
+		{name: "Source", mode: MergeModeSource, selected: "2.0.0.10", wantMode: MergeModeSource, wantSel: "2.0.0.10", want: []uint8{255, 0, 100}},
+		{name: "Missing source falls back to HTP", mode: MergeModeSource, selected: "2.0.0.99", wantMode: MergeModeHTP, want: []uint8{255, 200, 100, 30}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			merged := MergeFrames(1, inputs, tt.mode, tt.selected)
+			assert.Equal(t, tt.wantMode, merged.Mode)
+			assert.Equal(t, tt.wantSel, merged.Selected)
+			assert.True(t, merged.Conflict)
+			assert.Equal(t, []string{"2.0.0.10", "2.0.0.11"}, merged.Sources)
+			assert.Equal(t, uint16(len(tt.want)), merged.Length)
+			assert.Equal(t, tt.want, merged.Data[:merged.Length])
+		})
+	}
+}
+
+func TestMergeFrames_SingleSource(t *testing.T) {
+	merged := MergeFrames(1, []MergeInput{newMergeInput("2.0.0.10", time.Now(), 1, 2)}, MergeModeHTP, "")
+	assert.False(t, merged.Conflict)
+	assert.Equal(t, []uint8{1, 2}, merged.Data[:merged.Length])
+
+	empty := MergeFrames(1, nil, MergeModeHTP, "")
+	assert.Empty(t, empty.Sources)
+	assert.Equal(t, uint16(0), empty.Length)
+}
+
+func TestParseMergeMode(t *testing.T) {
+	mode, err := ParseMergeMode("htp")
+	assert.NoError(t, err)
+	assert.Equal(t, MergeModeHTP, mode)
+
+	mode, err = ParseMergeMode("Source")
+	assert.NoError(t, err)
+	assert.Equal(t, MergeModeSource, mode)
+
+	_, err = ParseMergeMode("max")
+	assert.Error(t, err)
+}
@@ -0,0 +1,38 @@
+package model
+
+import "net"
+
+// NetworkInterface Art-Netの送受信に使用するネットワークインターフェース
+type NetworkInterface struct {
+	Name      string           `json:"Name"`
+	IP        net.IP           `json:"IP"`        // IPv4アドレス
+	Mask      net.IPMask       `json:"Mask"`      // サブネットマスク
+	MAC       net.HardwareAddr `json:"MAC"`       // MACアドレス
+	Broadcast net.IP           `json:"Broadcast"` // ディレクテッドブロードキャストアドレス
+}
+
+// NewNetworkInterface IPアドレスとマスクからブロードキャストアドレスを計算して作成
+func NewNetworkInterface(name string, ip net.IP, mask net.IPMask, mac net.HardwareAddr) *NetworkInterface {
+	ip4 := ip.To4()
+	broadcast := net.IPv4bcast
+	if ip4 != nil && len(mask) == net.IPv4len {
+		broadcast = make(net.IP, net.IPv4len)
+		for i := range ip4 {
+			broadcast[i] = ip4[i] | ^mask[i]
+		}
+	}
+
+	return &NetworkInterface{
+		Name:      name,
+		IP:        ip4,
+		Mask:      mask,
+		MAC:       mac,
+		Broadcast: broadcast,
+	}
+}
+
+// Contains 指定したIPアドレスがこのインターフェースのネットワークに含まれるかどうか
+func (n *NetworkInterface) Contains(ip net.IP) bool {
+	network := &net.IPNet{IP: n.IP.Mask(n.Mask), Mask: n.Mask}
+	return network.Contains(ip)
+}
@@ -0,0 +1,67 @@
+package model
+
+import (
+	"net"
+	"time"
+)
+
+// NodeLiveness ノードの生存状態
+type NodeLiveness string
+
+const (
+	NodeLivenessUnknown NodeLiveness = "unknown" // 未検出（新規ノードの遷移元）
+	NodeOnline          NodeLiveness = "online"
+	NodeStale           NodeLiveness = "stale"   // ArtPollReplyが途絶えつつある
+	NodeOffline         NodeLiveness = "offline" // 切断されたとみなす
+)
+
+// NodeLivenessPolicy LastSeen からノードの状態を判定するしきい値
+type NodeLivenessPolicy struct {
+	StaleAfter   time.Duration // この時間応答がなければ stale
+	OfflineAfter time.Duration // この時間応答がなければ offline
+	ExpireAfter  time.Duration // この時間応答がなければ一覧から削除（0の場合は削除しない）
+}
+
+// NewNodeLivenessPolicy ArtPollの送信間隔からポリシーを作成する
+// 1回分の応答の取りこぼしは許容し、2回で stale、3回で offline とする
+func NewNodeLivenessPolicy(pollInterval, expireAfter time.Duration) NodeLivenessPolicy {
+	return NodeLivenessPolicy{
+		StaleAfter:   2 * pollInterval,
+		OfflineAfter: 3 * pollInterval,
+		ExpireAfter:  expireAfter,
+	}
+}
+
+// Evaluate 最後に応答を受信した時刻から現在の状態を判定する
+func (p NodeLivenessPolicy) Evaluate(lastSeen, now time.Time) NodeLiveness {
+	elapsed := now.Sub(lastSeen)
+	switch {
+	case elapsed >= p.OfflineAfter:
+		return NodeOffline
+	case elapsed >= p.StaleAfter:
+		return NodeStale
+	default:
+		return NodeOnline
+	}
+}
+
+// Expired 一覧から削除すべきかどうか
+func (p NodeLivenessPolicy) Expired(lastSeen, now time.Time) bool {
+	return p.ExpireAfter > 0 && now.Sub(lastSeen) >= p.ExpireAfter
+}
+
+// NodeTransition ノードの状態遷移の記録
+type NodeTransition struct {
+	NodeKey   string
+	IPAddress net.IP
+	BindIndex uint8
+	From      NodeLiveness
+	To        NodeLiveness
+	At        time.Time
+}
+
+// NodeEvent node_online / node_offline として配信するイベント
+type NodeEvent struct {
+	Node       *ArtNetNode
+	Transition NodeTransition
+}
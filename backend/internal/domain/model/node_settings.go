@@ -0,0 +1,292 @@
+package model
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/jsimonetti/go-artnet/packet"
+)
+
+// ArtAddress のコマンド（Art-Net 4 仕様）
+const (
+	AcNone         uint8 = 0x00
+	AcCancelMerge  uint8 = 0x01
+	AcLedNormal    uint8 = 0x02
+	AcLedMute      uint8 = 0x03
+	AcLedLocate    uint8 = 0x04
+	AcResetRxFlags uint8 = 0x05
+	AcMergeLtp0    uint8 = 0x10 // 0x10-0x13: ポート0-3をLTPマージに設定
+	AcMergeHtp0    uint8 = 0x50 // 0x50-0x53: ポート0-3をHTPマージに設定
+	AcClearOp0     uint8 = 0x90 // 0x90-0x93: ポート0-3の出力バッファをクリア
+)
+
+// ArtAddress の各スイッチフィールドの特殊値
+const (
+	addressProgramBit = 0x80 // 最上位ビットが立っていれば値を書き込む
+	addressNoChange   = 0x7F // 変更しない
+	addressReset      = 0x00 // 既定値に戻す
+)
+
+const (
+	// PortsPerPage ArtPollReply 1ページあたりのポート数
+	PortsPerPage = 4
+	// MaxPortPages BindIndex で表現できる最大ページ数
+	MaxPortPages = 255
+)
+
+// IndicatorState ノードのインジケーター（LED）の状態
+type IndicatorState string
+
+const (
+	IndicatorNormal IndicatorState = "normal"
+	IndicatorMute   IndicatorState = "mute"
+	IndicatorLocate IndicatorState = "locate"
+)
+
+// MergeMode 出力ポートのマージモード
+type MergeMode string
+
+const (
+	MergeModeHTP MergeMode = "HTP"
+	MergeModeLTP MergeMode = "LTP"
+)
+
+// PortPage ArtPollReply 1ページ分（BindIndex 1つ分）のポート設定
+// 1ページ内のポートは Net と SubNet を共有する
+type PortPage struct {
+	NetSwitch uint8        `json:"NetSwitch"` // Port-Address の bit14-8
+	SubSwitch uint8        `json:"SubSwitch"` // Port-Address の bit7-4
+	NumPorts  uint8        `json:"NumPorts"`  // 有効な仮想出力ポート数（0-4）
+	SwIn      [4]uint8     `json:"SwIn"`      // 入力ポートごとの Port-Address bit3-0
+	SwOut     [4]uint8     `json:"SwOut"`     // 出力ポートごとの Port-Address bit3-0
+	MergeMode [4]MergeMode `json:"MergeMode"` // 出力ポートごとのマージモード
+}
+
+// NewPortPage 既定のマージモードでPortPageを作成
+func NewPortPage() PortPage {
+	return PortPage{
+		MergeMode: [4]MergeMode{MergeModeHTP, MergeModeHTP, MergeModeHTP, MergeModeHTP},
+	}
+}
+
+// OutputUniverse 指定した出力ポートの15bit Port-Addressを返す
+func (p *PortPage) OutputUniverse(port int) uint16 {
+	return uint16(p.NetSwitch&0x7F)<<8 | uint16(p.SubSwitch&0x0F)<<4 | uint16(p.SwOut[port]&0x0F)
+}
+
+// InputUniverse 指定した入力ポートの15bit Port-Addressを返す
+func (p *PortPage) InputUniverse(port int) uint16 {
+	return uint16(p.NetSwitch&0x7F)<<8 | uint16(p.SubSwitch&0x0F)<<4 | uint16(p.SwIn[port]&0x0F)
+}
+
+// NodeSettings このビューアがArt-Netノードとして公開する設定（ArtAddressで変更可能）
+type NodeSettings struct {
+	ShortName string     `json:"ShortName"`
+	LongName  string     `json:"LongName"`
+	Pages     []PortPage `json:"Pages"` // BindIndex 1 から順のポートページ（常に1ページ以上）
+
+	Indicator          IndicatorState `json:"Indicator"`
+	ProgrammedByRemote bool           `json:"ProgrammedByRemote"` // ArtAddressで設定が変更されたかどうか
+}
+
+// NewNodeSettings 名前とモニターするユニバースを指定して既定のNodeSettingsを作成
+func NewNodeSettings(shortName, longName string, universes []uint16) NodeSettings {
+	return NodeSettings{
+		ShortName: shortName,
+		LongName:  longName,
+		Pages:     BuildPortPages(universes),
+		Indicator: IndicatorNormal,
+	}
+}
+
+// Clone ページを含めた設定のコピーを作成
+func (s NodeSettings) Clone() NodeSettings {
+	s.Pages = append([]PortPage(nil), s.Pages...)
+	return s
+}
+
+// MonitoredUniverses 仮想出力ポートとして公開しているユニバースの一覧を返す
+func (s *NodeSettings) MonitoredUniverses() []uint16 {
+	universes := make([]uint16, 0, len(s.Pages)*PortsPerPage)
+	for i := range s.Pages {
+		page := &s.Pages[i]
+		for port := 0; port < int(page.NumPorts); port++ {
+			universes = append(universes, page.OutputUniverse(port))
+		}
+	}
+	return universes
+}
+
+// SetMonitoredUniverses モニターするユニバースを変更し、ポートページを再構成する
+func (s *NodeSettings) SetMonitoredUniverses(universes []uint16) error {
+	if err := ValidateMonitoredUniverses(universes); err != nil {
+		return err
+	}
+	s.Pages = BuildPortPages(universes)
+	return nil
+}
+
+// ValidateMonitoredUniverses モニターするユニバースがPort-Addressの範囲内で、ポートページが MaxPortPages 以下に収まるかを確認する
+func ValidateMonitoredUniverses(universes []uint16) error {
+	for _, universe := range universes {
+		if universe > MaxUniverse {
+			return fmt.Errorf("universe %d exceeds maximum %d", universe, MaxUniverse)
+		}
+	}
+	if pages := BuildPortPages(universes); len(pages) > MaxPortPages {
+		return fmt.Errorf("too many port pages %d (max %d)", len(pages), MaxPortPages)
+	}
+	return nil
+}
+
+// Validate ポートページの数とページごとのポート数がArtPollReplyで表現できる範囲かを確認する
+func (s *NodeSettings) Validate() error {
+	if len(s.Pages) == 0 || len(s.Pages) > MaxPortPages {
+		return fmt.Errorf("port pages must be between 1 and %d, got %d", MaxPortPages, len(s.Pages))
+	}
+	for i := range s.Pages {
+		if s.Pages[i].NumPorts > PortsPerPage {
+			return fmt.Errorf("port page %d has %d ports (max %d)", i+1, s.Pages[i].NumPorts, PortsPerPage)
+		}
+	}
+	return nil
+}
+
+// BuildPortPages ユニバースの一覧を、Net/SubNet が共通な4ポートずつのページに分割する
+// ユニバースが空の場合はポートを持たないページを1つ返す
+func BuildPortPages(universes []uint16) []PortPage {
+	sorted := make([]uint16, 0, len(universes))
+	for _, universe := range universes {
+		sorted = append(sorted, universe&MaxUniverse)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	pages := make([]PortPage, 0, len(sorted)/PortsPerPage+1)
+	for i, universe := range sorted {
+		if i > 0 && universe == sorted[i-1] {
+			continue
+		}
+
+		n := len(pages)
+		if n == 0 || pages[n-1].NumPorts == PortsPerPage || universe>>4 != pages[n-1].OutputUniverse(0)>>4 {
+			page := NewPortPage()
+			page.NetSwitch = uint8(universe >> 8)
+			page.SubSwitch = uint8(universe>>4) & 0x0F
+			pages = append(pages, page)
+			n++
+		}
+		page := &pages[n-1]
+		page.SwOut[page.NumPorts] = uint8(universe & 0x0F)
+		page.NumPorts++
+	}
+
+	if len(pages) == 0 {
+		pages = append(pages, NewPortPage())
+	}
+	return pages
+}
+
+// PageIndex ArtAddress等のBindIndexをページのインデックスに変換する（0 と 1 はルートデバイス）
+func PageIndex(bindIndex uint8) int {
+	if bindIndex == 0 {
+		return 0
+	}
+	return int(bindIndex) - 1
+}
+
+// ArtAddressResult ArtAddress適用後に呼び出し側で処理が必要なコマンド
+type ArtAddressResult struct {
+	Command     uint8 // 受信したコマンド
+	Page        int   // 対象となったページのインデックス
+	Unsupported bool  // 未対応のコマンドだったかどうか
+	ClearPorts  []int // 出力バッファのクリアを要求されたポート
+}
+
+// ApplyArtAddress ArtAddressパケットの内容を設定に適用する
+// defaults はフィールドに「既定値に戻す」が指定された場合に使用する
+func (s *NodeSettings) ApplyArtAddress(p *packet.ArtAddressPacket, defaults NodeSettings) (ArtAddressResult, error) {
+	if p == nil {
+		return ArtAddressResult{}, errors.New("packet cannot be nil")
+	}
+
+	pageIndex := PageIndex(p.BindIndex)
+	if pageIndex >= len(s.Pages) {
+		return ArtAddressResult{}, fmt.Errorf("bind index %d out of range (pages: %d)", p.BindIndex, len(s.Pages))
+	}
+
+	before := s.Clone()
+	page := &s.Pages[pageIndex]
+	defaultPage := NewPortPage()
+	if pageIndex < len(defaults.Pages) {
+		defaultPage = defaults.Pages[pageIndex]
+	}
+
+	// 名前はヌル文字列の場合は変更しない
+	if name := string(bytes.Trim(p.ShortName[:], "\x00")); name != "" {
+		s.ShortName = name
+	}
+	if name := string(bytes.Trim(p.LongName[:], "\x00")); name != "" {
+		s.LongName = name
+	}
+
+	page.NetSwitch = applySwitch(p.NetSwitch, page.NetSwitch, defaultPage.NetSwitch, 0x7F)
+	page.SubSwitch = applySwitch(p.SubSwitch, page.SubSwitch, defaultPage.SubSwitch, 0x0F)
+	for i := range page.SwIn {
+		page.SwIn[i] = applySwitch(p.SwIn[i], page.SwIn[i], defaultPage.SwIn[i], 0x0F)
+		page.SwOut[i] = applySwitch(p.SwOut[i], page.SwOut[i], defaultPage.SwOut[i], 0x0F)
+	}
+
+	result := ArtAddressResult{Command: p.Command, Page: pageIndex}
+	switch cmd := p.Command; {
+	case cmd == AcNone, cmd == AcCancelMerge, cmd == AcResetRxFlags:
+	case cmd == AcLedNormal:
+		s.Indicator = IndicatorNormal
+	case cmd == AcLedMute:
+		s.Indicator = IndicatorMute
+	case cmd == AcLedLocate:
+		s.Indicator = IndicatorLocate
+	case cmd >= AcMergeLtp0 && cmd <= AcMergeLtp0+3:
+		page.MergeMode[cmd-AcMergeLtp0] = MergeModeLTP
+	case cmd >= AcMergeHtp0 && cmd <= AcMergeHtp0+3:
+		page.MergeMode[cmd-AcMergeHtp0] = MergeModeHTP
+	case cmd >= AcClearOp0 && cmd <= AcClearOp0+3:
+		result.ClearPorts = append(result.ClearPorts, int(cmd-AcClearOp0))
+	default:
+		result.Unsupported = true
+	}
+
+	if s.addressChanged(&before) {
+		s.ProgrammedByRemote = true
+	}
+	return result, nil
+}
+
+// addressChanged 名前・Port-Addressのいずれかが変更されたかどうか
+func (s *NodeSettings) addressChanged(before *NodeSettings) bool {
+	if s.ShortName != before.ShortName || s.LongName != before.LongName || len(s.Pages) != len(before.Pages) {
+		return true
+	}
+	for i := range s.Pages {
+		a, b := &s.Pages[i], &before.Pages[i]
+		if a.NetSwitch != b.NetSwitch || a.SubSwitch != b.SubSwitch || a.SwIn != b.SwIn || a.SwOut != b.SwOut {
+			return true
+		}
+	}
+	return false
+}
+
+// applySwitch ArtAddressのスイッチフィールドの値を解釈して新しい値を返す
+func applySwitch(value, current, def, mask uint8) uint8 {
+	switch {
+	case value == addressNoChange:
+		return current
+	case value == addressReset:
+		return def
+	case value&addressProgramBit != 0:
+		return value & mask
+	default:
+		return current
+	}
+}
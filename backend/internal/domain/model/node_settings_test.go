@@ -0,0 +1,160 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/jsimonetti/go-artnet/packet"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNodeSettings_ApplyArtAddress(t *testing.T) {
+	defaults := NewNodeSettings("DMX Viewer", "DMX Viewer Application", nil)
+	settings := defaults.Clone()
+
+	p := &packet.ArtAddressPacket{
+		NetSwitch: 0x80 | 0x03,
+		SubSwitch: 0x80 | 0x02,
+		SwIn:      [4]uint8{0x7F, 0x7F, 0x7F, 0x7F},
+		SwOut:     [4]uint8{0x80 | 0x01, 0x7F, 0x80 | 0x0F, 0x7F},
+		Command:   AcLedLocate,
+	}
+	copy(p.ShortName[:], "Booth")
+	copy(p.LongName[:], "FOH Booth Viewer")
+
+	result, err := settings.ApplyArtAddress(p, defaults)
+	require.NoError(t, err)
+	assert.False(t, result.Unsupported)
+
+	assert.Equal(t, "Booth", settings.ShortName)
+	assert.Equal(t, "FOH Booth Viewer", settings.LongName)
+	assert.Equal(t, uint8(3), settings.Pages[0].NetSwitch)
+	assert.Equal(t, uint8(2), settings.Pages[0].SubSwitch)
+	assert.Equal(t, [4]uint8{1, 0, 15, 0}, settings.Pages[0].SwOut)
+	assert.Equal(t, IndicatorLocate, settings.Indicator)
+	assert.True(t, settings.ProgrammedByRemote)
+	assert.Equal(t, uint16(3<<8|2<<4|1), settings.Pages[0].OutputUniverse(0))
+	// 既定値は変更されない
+	assert.Equal(t, uint8(0), defaults.Pages[0].NetSwitch)
+
+	// ヌル文字列・0x7Fは変更なし、0x00は既定値に戻す
+	reset := &packet.ArtAddressPacket{
+		NetSwitch: 0x00,
+		SubSwitch: 0x7F,
+		SwIn:      [4]uint8{0x7F, 0x7F, 0x7F, 0x7F},
+		SwOut:     [4]uint8{0x00, 0x7F, 0x7F, 0x7F},
+		Command:   AcLedNormal,
+	}
+	_, err = settings.ApplyArtAddress(reset, defaults)
+	require.NoError(t, err)
+	assert.Equal(t, "Booth", settings.ShortName)
+	assert.Equal(t, uint8(0), settings.Pages[0].NetSwitch)
+	assert.Equal(t, uint8(2), settings.Pages[0].SubSwitch)
+	assert.Equal(t, [4]uint8{0, 0, 15, 0}, settings.Pages[0].SwOut)
+	assert.Equal(t, IndicatorNormal, settings.Indicator)
+}
+
+func TestNodeSettings_ApplyArtAddressCommands(t *testing.T) {
+	defaults := NewNodeSettings("a", "b", nil)
+	noChange := func(cmd uint8) *packet.ArtAddressPacket {
+		return &packet.ArtAddressPacket{
+			NetSwitch: 0x7F,
+			SubSwitch: 0x7F,
+			SwIn:      [4]uint8{0x7F, 0x7F, 0x7F, 0x7F},
+			SwOut:     [4]uint8{0x7F, 0x7F, 0x7F, 0x7F},
+			Command:   cmd,
+		}
+	}
+
+	settings := defaults.Clone()
+	result, err := settings.ApplyArtAddress(noChange(AcClearOp0+2), defaults)
+	require.NoError(t, err)
+	assert.Equal(t, []int{2}, result.ClearPorts)
+	assert.False(t, settings.ProgrammedByRemote)
+
+	_, err = settings.ApplyArtAddress(noChange(AcMergeLtp0+1), defaults)
+	require.NoError(t, err)
+	assert.Equal(t, MergeModeLTP, settings.Pages[0].MergeMode[1])
+
+	result, err = settings.ApplyArtAddress(noChange(0xEE), defaults)
+	require.NoError(t, err)
+	assert.True(t, result.Unsupported)
+
+	_, err = settings.ApplyArtAddress(nil, defaults)
+	assert.Error(t, err)
+}
+
+func TestNodeSettings_ApplyArtAddressBindIndex(t *testing.T) {
+	defaults := NewNodeSettings("a", "b", []uint16{0, 1, 2, 3, 4})
+	settings := defaults.Clone()
+	require.Len(t, settings.Pages, 2)
+
+	p := &packet.ArtAddressPacket{
+		BindIndex: 2,
+		NetSwitch: 0x7F,
+		SubSwitch: 0x80 | 0x05,
+		SwIn:      [4]uint8{0x7F, 0x7F, 0x7F, 0x7F},
+		SwOut:     [4]uint8{0x7F, 0x7F, 0x7F, 0x7F},
+	}
+	result, err := settings.ApplyArtAddress(p, defaults)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Page)
+	assert.Equal(t, []uint16{0, 1, 2, 3, 0x54}, settings.MonitoredUniverses())
+
+	p.BindIndex = 3
+	_, err = settings.ApplyArtAddress(p, defaults)
+	assert.Error(t, err)
+}
+
+func TestBuildPortPages(t *testing.T) {
+	pages := BuildPortPages([]uint16{17, 0, 1, 1, 2, 3, 4, 0x123})
+	require.Len(t, pages, 4)
+
+	assert.Equal(t, uint8(4), pages[0].NumPorts)
+	assert.Equal(t, [4]uint8{0, 1, 2, 3}, pages[0].SwOut)
+
+	assert.Equal(t, uint8(1), pages[1].NumPorts)
+	assert.Equal(t, uint16(4), pages[1].OutputUniverse(0))
+
+	// Net/SubNet が異なるユニバースは別ページになる
+	assert.Equal(t, uint8(1), pages[2].SubSwitch)
+	assert.Equal(t, uint16(17), pages[2].OutputUniverse(0))
+	assert.Equal(t, uint8(1), pages[3].NetSwitch)
+	assert.Equal(t, uint8(2), pages[3].SubSwitch)
+	assert.Equal(t, uint16(0x123), pages[3].OutputUniverse(0))
+
+	// 空の場合はポートなしのページが1つ
+	empty := BuildPortPages(nil)
+	require.Len(t, empty, 1)
+	assert.Equal(t, uint8(0), empty[0].NumPorts)
+}
+
+func TestNodeSettings_SetMonitoredUniverses(t *testing.T) {
+	settings := NewNodeSettings("a", "b", nil)
+	require.NoError(t, settings.SetMonitoredUniverses([]uint16{5, 6}))
+	assert.Equal(t, []uint16{5, 6}, settings.MonitoredUniverses())
+
+	assert.Error(t, settings.SetMonitoredUniverses([]uint16{MaxUniverse + 1}))
+
+	tooMany := make([]uint16, 0, (MaxPortPages+1)*PortsPerPage)
+	for u := 0; u < (MaxPortPages+1)*PortsPerPage; u++ {
+		tooMany = append(tooMany, uint16(u))
+	}
+	assert.Error(t, settings.SetMonitoredUniverses(tooMany))
+	assert.Error(t, ValidateMonitoredUniverses(tooMany))
+	assert.NoError(t, ValidateMonitoredUniverses(tooMany[:MaxPortPages*PortsPerPage]))
+}
+
+func TestNodeSettings_Validate(t *testing.T) {
+	settings := NewNodeSettings("a", "b", []uint16{0, 1})
+	assert.NoError(t, settings.Validate())
+
+	settings.Pages = nil
+	assert.Error(t, settings.Validate())
+
+	settings.Pages = make([]PortPage, MaxPortPages+1)
+	assert.Error(t, settings.Validate())
+
+	settings.Pages = []PortPage{{NumPorts: PortsPerPage + 1}}
+	assert.Error(t, settings.Validate())
+}
@@ -0,0 +1,80 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+// 再生速度の範囲
+const (
+	MinPlaybackSpeed = 0.1
+	MaxPlaybackSpeed = 16.0
+)
+
+// PlaybackState 再生の状態
+type PlaybackState string
+
+const (
+	PlaybackStateStopped PlaybackState = "stopped"
+	PlaybackStatePlaying PlaybackState = "playing"
+	PlaybackStatePaused  PlaybackState = "paused"
+)
+
+// UniverseRemap 再生時のユニバースの付け替え（記録したユニバース → 出力するユニバース）
+// 含まれないユニバースはそのまま出力する
+type UniverseRemap map[uint16]uint16
+
+// Apply 出力するユニバースを返す
+func (m UniverseRemap) Apply(universe uint16) uint16 {
+	if out, ok := m[universe]; ok {
+		return out
+	}
+	return universe
+}
+
+// PlaybackOptions キャプチャファイルの再生方法
+type PlaybackOptions struct {
+	File   string        `json:"File"`   // 保存先のディレクトリ内のファイル名
+	Speed  float64       `json:"Speed"`  // 再生速度（1 で記録時と同じ速さ）
+	Loop   bool          `json:"Loop"`   // 末尾に達したら先頭から繰り返す
+	DryRun bool          `json:"DryRun"` // ネットワークには送信せず WebSocket の表示のみを更新する
+	Remap  UniverseRemap `json:"Remap,omitempty"`
+	Start  time.Duration `json:"-"` // 再生を開始する位置
+}
+
+// Validate 再生方法が正しいか検証する
+func (o PlaybackOptions) Validate() error {
+	if o.File == "" {
+		return fmt.Errorf("capture file is required")
+	}
+	if o.Speed < MinPlaybackSpeed || o.Speed > MaxPlaybackSpeed {
+		return fmt.Errorf("speed %g is out of range (%g-%g)", o.Speed, MinPlaybackSpeed, MaxPlaybackSpeed)
+	}
+	if o.Start < 0 {
+		return fmt.Errorf("start position must not be negative")
+	}
+	for in, out := range o.Remap {
+		if out > MaxUniverse {
+			return fmt.Errorf("remapped universe %d -> %d exceeds maximum %d", in, out, MaxUniverse)
+		}
+	}
+	return nil
+}
+
+// PlaybackStatus 再生の状態
+type PlaybackStatus struct {
+	State      PlaybackState `json:"State"`
+	Review     bool          `json:"Review"` // オフラインレビューモード（ネットワークには送信しない）
+	File       string        `json:"File,omitempty"`
+	PositionMs int64         `json:"PositionMs"` // 現在の再生位置（記録開始からの経過時間）
+	DurationMs int64         `json:"DurationMs"` // キャプチャファイルの長さ
+	Speed      float64       `json:"Speed"`
+	Loop       bool          `json:"Loop"`
+	DryRun     bool          `json:"DryRun"`
+	Remap      UniverseRemap `json:"Remap,omitempty"`
+	Frames     int           `json:"Frames"`     // キャプチャファイルのフレーム数
+	FrameIndex int           `json:"FrameIndex"` // 再生位置のフレームの番号（0始まり。先頭のフレームより前は -1）
+	FramesSent uint64        `json:"FramesSent"` // 再生を開始してから出力したフレーム数
+	Loops      uint64        `json:"Loops"`      // 先頭に戻った回数
+	LastError  string        `json:"LastError,omitempty"`
+}
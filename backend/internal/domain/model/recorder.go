@@ -0,0 +1,55 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// RecordMode 記録するフレームの選び方
+type RecordMode string
+
+const (
+	// RecordModeAll 受信したすべてのフレームを記録する
+	RecordModeAll RecordMode = "all"
+	// RecordModeChanged 送信元・ユニバースごとに直前と内容が変化したフレームのみを記録する
+	RecordModeChanged RecordMode = "changed"
+)
+
+// ParseRecordMode 文字列から記録モードを解釈する（空の場合は all）
+func ParseRecordMode(s string) (RecordMode, error) {
+	switch mode := RecordMode(strings.ToLower(s)); mode {
+	case "":
+		return RecordModeAll, nil
+	case RecordModeAll, RecordModeChanged:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("unknown record mode %q", s)
+	}
+}
+
+// CaptureFile 記録したキャプチャファイル
+type CaptureFile struct {
+	Name      string    `json:"Name"` // 保存先のディレクトリ内のファイル名
+	Path      string    `json:"Path"`
+	Size      int64     `json:"Size"` // 書き込んだバイト数
+	StartedAt time.Time `json:"StartedAt"`
+}
+
+// CaptureRecord キャプチャファイルに記録した1フレーム
+type CaptureRecord struct {
+	Offset time.Duration // 記録開始からの経過時間
+	Frame  *DMXFrame
+}
+
+// RecorderStatus 記録の状態
+type RecorderStatus struct {
+	Recording bool          `json:"Recording"`
+	Mode      RecordMode    `json:"Mode"`
+	StartedAt time.Time     `json:"StartedAt"` // 最後に記録を開始した時刻
+	Frames    uint64        `json:"Frames"`    // 記録したフレーム数
+	Skipped   uint64        `json:"Skipped"`   // 変化がないため記録しなかったフレーム数
+	Dropped   uint64        `json:"Dropped"`   // 書き出しが追いつかず破棄したフレーム数
+	Files     []CaptureFile `json:"Files"`     // 最後の記録で作成したファイル（ローテーション順）
+	LastError string        `json:"LastError,omitempty"`
+}
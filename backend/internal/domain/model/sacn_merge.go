@@ -0,0 +1,83 @@
+package model
+
+import "time"
+
+// SACNMergeInput マージ対象となるsACN送信元1つ分の最新フレーム
+type SACNMergeInput struct {
+	CID      string
+	Frame    *DMXFrame
+	LastSeen time.Time
+}
+
+// SACNSourceInfo マージ結果に含まれる送信元の情報
+type SACNSourceInfo struct {
+	CID                string `json:"CID"`
+	SourceName         string `json:"SourceName"`
+	SourceIP           string `json:"SourceIP"`
+	Priority           uint8  `json:"Priority"`
+	PerAddressPriority bool   `json:"PerAddressPriority"` // アドレスごとの優先度（START Code 0xDD）を送信しているか
+}
+
+// SACNMergedFrame 優先度に従ってマージしたsACNユニバースのデータ
+type SACNMergedFrame struct {
+	Universe   uint16           `json:"Universe"`
+	Sources    []SACNSourceInfo `json:"Sources"`
+	Length     uint16           `json:"Length"`
+	Data       [512]uint8       `json:"Data"`
+	Priorities [512]uint8       `json:"Priorities"` // チャンネルごとの出力元の優先度
+	Winners    [512]int16       `json:"Winners"`    // チャンネルごとの出力元（Sources のインデックス。出力元がない場合は-1）
+}
+
+// channelPriority チャンネルの実効優先度と、送信元がそのチャンネルを出力しているかを返す
+//
+// アドレスごとの優先度を受信している場合はそれを使用し、0 のチャンネルは出力していないものとする。
+func (in SACNMergeInput) channelPriority(ch int) (uint8, bool) {
+	if ch >= int(in.Frame.Length) {
+		return 0, false
+	}
+	if in.Frame.ChannelPriority != nil {
+		p := in.Frame.ChannelPriority[ch]
+		return p, p > 0
+	}
+	return in.Frame.Priority, true
+}
+
+// MergeSACNFrames チャンネルごとに最も優先度の高い送信元のレベルを出力とする
+// 優先度が同じ送信元が複数ある場合はレベルの高い方（HTP）を出力とする。inputs の順序は出力元の順序として使用する
+func MergeSACNFrames(universe uint16, inputs []SACNMergeInput) *SACNMergedFrame {
+	merged := &SACNMergedFrame{
+		Universe: universe,
+		Sources:  make([]SACNSourceInfo, 0, len(inputs)),
+	}
+	for _, in := range inputs {
+		merged.Sources = append(merged.Sources, SACNSourceInfo{
+			CID:                in.CID,
+			SourceName:         in.Frame.SourceName,
+			SourceIP:           in.Frame.SourceIP.String(),
+			Priority:           in.Frame.Priority,
+			PerAddressPriority: in.Frame.ChannelPriority != nil,
+		})
+	}
+
+	for ch := 0; ch < len(merged.Data); ch++ {
+		merged.Winners[ch] = -1
+		for i, in := range inputs {
+			priority, ok := in.channelPriority(ch)
+			if !ok {
+				continue
+			}
+			level := in.Frame.Data[ch]
+			winner := merged.Winners[ch]
+			if winner >= 0 && (priority < merged.Priorities[ch] || (priority == merged.Priorities[ch] && level <= merged.Data[ch])) {
+				continue
+			}
+			merged.Winners[ch] = int16(i)
+			merged.Priorities[ch] = priority
+			merged.Data[ch] = level
+		}
+		if merged.Winners[ch] >= 0 {
+			merged.Length = uint16(ch + 1)
+		}
+	}
+	return merged
+}
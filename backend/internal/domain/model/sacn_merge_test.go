@@ -0,0 +1,95 @@
+package model
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newSACNMergeInput(cid string, priority uint8, channelPriority []uint8, values ...uint8) SACNMergeInput {
+	frame := &DMXFrame{Length: uint16(len(values)), Priority: priority, SourceName: cid, SourceIP: net.IPv4(10, 0, 0, 1), Protocol: ProtocolSACN}
+	copy(frame.Data[:], values)
+	if channelPriority != nil {
+		var priorities [512]uint8
+		copy(priorities[:], channelPriority)
+		frame.ChannelPriority = &priorities
+	}
+	return SACNMergeInput{CID: cid, Frame: frame}
+}
+
+func TestMergeSACNFrames(t *testing.T) {
+	tests := []struct {
+		name           string
+		inputs         []SACNMergeInput
+		wantData       []uint8
+		wantWinners    []int16
+		wantPriorities []uint8
+		wantLength     uint16
+	}{
+		{
+			name: "Higher universe priority wins",
+			inputs: []SACNMergeInput{
+				newSACNMergeInput("a", 100, nil, 255, 255),
+				newSACNMergeInput("b", 150, nil, 10, 20),
+			},
+			wantData:       []uint8{10, 20, 0},
+			wantWinners:    []int16{1, 1, -1},
+			wantPriorities: []uint8{150, 150, 0},
+			wantLength:     2,
+		},
+		{
+			name: "Equal priority merges HTP",
+			inputs: []SACNMergeInput{
+				newSACNMergeInput("a", 100, nil, 255, 0, 7),
+				newSACNMergeInput("b", 100, nil, 10, 20, 7),
+			},
+			wantData:       []uint8{255, 20, 7},
+			wantWinners:    []int16{0, 1, 0},
+			wantPriorities: []uint8{100, 100, 100},
+			wantLength:     3,
+		},
+		{
+			name: "Per-address priority overrides universe priority",
+			inputs: []SACNMergeInput{
+				newSACNMergeInput("a", 100, nil, 50, 50, 50),
+				newSACNMergeInput("b", 200, []uint8{0, 120, 80}, 1, 2, 3),
+			},
+			wantData:       []uint8{50, 2, 50},
+			wantWinners:    []int16{0, 1, 0},
+			wantPriorities: []uint8{100, 120, 100},
+			wantLength:     3,
+		},
+		{
+			name: "Per-address priority 0 does not source the channel",
+			inputs: []SACNMergeInput{
+				newSACNMergeInput("a", 100, []uint8{0, 100}, 9, 9),
+			},
+			wantData:       []uint8{0, 9, 0},
+			wantWinners:    []int16{-1, 0, -1},
+			wantPriorities: []uint8{0, 100, 0},
+			wantLength:     2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			merged := MergeSACNFrames(1, tt.inputs)
+			n := len(tt.wantData)
+			assert.Equal(t, tt.wantData, merged.Data[:n])
+			assert.Equal(t, tt.wantWinners, merged.Winners[:n])
+			assert.Equal(t, tt.wantPriorities, merged.Priorities[:n])
+			assert.Equal(t, tt.wantLength, merged.Length)
+			require.Len(t, merged.Sources, len(tt.inputs))
+		})
+	}
+}
+
+func TestMergeSACNFrames_NoSources(t *testing.T) {
+	merged := MergeSACNFrames(5, nil)
+	assert.Equal(t, uint16(5), merged.Universe)
+	assert.Empty(t, merged.Sources)
+	assert.Equal(t, uint16(0), merged.Length)
+	assert.Equal(t, int16(-1), merged.Winners[0])
+}
@@ -0,0 +1,66 @@
+package model
+
+import (
+	"net"
+	"sort"
+	"time"
+)
+
+// SACNSourceExpireAfter ユニバースディスカバリーの既定の期限（送信間隔10秒の3回分）
+const SACNSourceExpireAfter = 30 * time.Second
+
+// SACNSource ユニバースディスカバリーで通知されたsACN送信元
+type SACNSource struct {
+	CID        string    `json:"CID"`
+	SourceName string    `json:"SourceName"`
+	IPAddress  net.IP    `json:"IPAddress"`
+	Universes  []uint16  `json:"Universes"` // 送信中のユニバース（全ページの和集合を昇順で保持する）
+	LastSeen   time.Time `json:"LastSeen"`
+
+	pages map[uint8][]uint16 // ページ番号ごとのユニバース一覧
+}
+
+// WithDiscoveryPage ユニバースディスカバリーの1ページ分を反映した新しい SACNSource を返す
+// 元の値は変更しないため、リポジトリに保存済みの値に対して呼び出してよい
+func (s *SACNSource) WithDiscoveryPage(sourceName string, ip net.IP, page, lastPage uint8, universes []uint16, at time.Time) *SACNSource {
+	updated := &SACNSource{
+		CID:        s.CID,
+		SourceName: sourceName,
+		IPAddress:  ip,
+		LastSeen:   at,
+		pages:      make(map[uint8][]uint16, len(s.pages)+1),
+	}
+	// ページ数が変わった場合は範囲外のページを破棄する
+	for p, list := range s.pages {
+		if p <= lastPage {
+			updated.pages[p] = list
+		}
+	}
+	updated.pages[page] = append([]uint16(nil), universes...)
+
+	seen := make(map[uint16]struct{})
+	for _, list := range updated.pages {
+		for _, u := range list {
+			seen[u] = struct{}{}
+		}
+	}
+	updated.Universes = make([]uint16, 0, len(seen))
+	for u := range seen {
+		updated.Universes = append(updated.Universes, u)
+	}
+	sort.Slice(updated.Universes, func(i, j int) bool { return updated.Universes[i] < updated.Universes[j] })
+	return updated
+}
+
+// SameAnnouncement 送信元名・IPアドレス・ユニバース一覧が同じか
+func (s *SACNSource) SameAnnouncement(other *SACNSource) bool {
+	if s.SourceName != other.SourceName || !s.IPAddress.Equal(other.IPAddress) || len(s.Universes) != len(other.Universes) {
+		return false
+	}
+	for i := range s.Universes {
+		if s.Universes[i] != other.Universes[i] {
+			return false
+		}
+	}
+	return true
+}
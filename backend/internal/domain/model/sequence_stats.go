@@ -0,0 +1,65 @@
+package model
+
+import "time"
+
+// sequenceWindow 前回より先のシーケンス番号とみなす範囲（これより後ろは順序入れ替わり）
+// シーケンス番号は 1-255 を循環するため、半周分を前方とする
+const sequenceWindow = 127
+
+// SequenceStats 送信元・ユニバースごとのArtDMXシーケンス番号の統計
+type SequenceStats struct {
+	SourceIP     string
+	Universe     uint16
+	Received     uint64  // 受信したパケット数
+	Lost         uint64  // 欠落したと推定されるパケット数
+	Reordered    uint64  // 遅れて到着したパケット数
+	Duplicates   uint64  // 直前と同じシーケンス番号のパケット数
+	Unsequenced  uint64  // シーケンス番号 0（無効）のパケット数
+	LastSequence uint8   // 最後に受信したシーケンス番号
+	LossPercent  float64 // 欠落率（%）
+	LastSeen     time.Time
+}
+
+// Observe 受信したシーケンス番号を統計に反映する
+// シーケンス番号 0 は送信元がシーケンス番号を使用していないことを示すため検査しない
+func (s *SequenceStats) Observe(sequence uint8, at time.Time) {
+	s.Received++
+	s.LastSeen = at
+
+	if sequence == 0 {
+		s.Unsequenced++
+		s.LastSequence = 0
+		s.updateLossPercent()
+		return
+	}
+	if s.LastSequence == 0 {
+		s.LastSequence = sequence
+		s.updateLossPercent()
+		return
+	}
+
+	distance := (int(sequence) - int(s.LastSequence) + 255) % 255
+	switch {
+	case distance == 0:
+		s.Duplicates++
+	case distance <= sequenceWindow:
+		s.Lost += uint64(distance - 1)
+		s.LastSequence = sequence
+	default:
+		// 欠落として数えたパケットが遅れて到着した
+		s.Reordered++
+		if s.Lost > 0 {
+			s.Lost--
+		}
+	}
+	s.updateLossPercent()
+}
+
+func (s *SequenceStats) updateLossPercent() {
+	expected := s.Received - s.Unsequenced - s.Duplicates + s.Lost
+	if expected == 0 {
+		s.LossPercent = 0
+		return
+	}
+	s.LossPercent = float64(s.Lost) / float64(expected) * 100
+}
@@ -0,0 +1,62 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func observeSequences(stats *SequenceStats, sequences ...uint8) {
+	now := time.Now()
+	for _, seq := range sequences {
+		stats.Observe(seq, now)
+	}
+}
+
+func TestSequenceStats_Observe(t *testing.T) {
+	tests := []struct {
+		name       string
+		sequences  []uint8
+		lost       uint64
+		reordered  uint64
+		duplicates uint64
+		unseq      uint64
+		last       uint8
+	}{
+		{name: "In order", sequences: []uint8{1, 2, 3, 4}, last: 4},
+		{name: "Wrap around skips zero", sequences: []uint8{254, 255, 1, 2}, last: 2},
+		{name: "Gap", sequences: []uint8{1, 2, 5}, lost: 2, last: 5},
+		{name: "Gap across wrap", sequences: []uint8{254, 2}, lost: 2, last: 2},
+		{name: "Duplicate", sequences: []uint8{1, 2, 2, 3}, duplicates: 1, last: 3},
+		{name: "Reorder fills gap", sequences: []uint8{1, 3, 2, 4}, reordered: 1, last: 4},
+		{name: "Reorder across wrap", sequences: []uint8{254, 1, 255, 2}, reordered: 1, last: 2},
+		{name: "Disabled", sequences: []uint8{0, 0, 0}, unseq: 3},
+		{name: "Disabled resets tracking", sequences: []uint8{10, 0, 50, 51}, unseq: 1, last: 51},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stats := &SequenceStats{}
+			observeSequences(stats, tt.sequences...)
+			assert.Equal(t, uint64(len(tt.sequences)), stats.Received)
+			assert.Equal(t, tt.lost, stats.Lost, "lost")
+			assert.Equal(t, tt.reordered, stats.Reordered, "reordered")
+			assert.Equal(t, tt.duplicates, stats.Duplicates, "duplicates")
+			assert.Equal(t, tt.unseq, stats.Unsequenced, "unsequenced")
+			assert.Equal(t, tt.last, stats.LastSequence)
+		})
+	}
+}
+
+func TestSequenceStats_LossPercent(t *testing.T) {
+	stats := &SequenceStats{}
+	// 1-10 のうち 3 つを欠落
+	observeSequences(stats, 1, 2, 4, 5, 6, 8, 10)
+	assert.Equal(t, uint64(3), stats.Lost)
+	assert.InDelta(t, 30.0, stats.LossPercent, 0.001)
+
+	stats = &SequenceStats{}
+	observeSequences(stats, 0, 0)
+	assert.Equal(t, 0.0, stats.LossPercent)
+}
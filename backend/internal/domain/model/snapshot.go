@@ -0,0 +1,151 @@
+package model
+
+import (
+	"errors"
+	"regexp"
+	"sort"
+	"time"
+)
+
+// SnapshotLiveName 差分の比較対象として現在の出力を表す名前
+const SnapshotLiveName = "live"
+
+var snapshotNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9 _.-]{0,63}$`)
+
+// ValidateSnapshotName スナップショット名として使用できるか確認する
+// ファイル名として保存するため、英数字で始まる英数字・空白・"_"・"."・"-" の64文字以内とする
+func ValidateSnapshotName(name string) error {
+	if name == SnapshotLiveName {
+		return errors.New(`"live" is reserved`)
+	}
+	if !snapshotNamePattern.MatchString(name) {
+		return errors.New("name must start with a letter or digit and contain up to 64 letters, digits, spaces, '_', '.' or '-'")
+	}
+	return nil
+}
+
+// SnapshotUniverse スナップショットに含める1ユニバース分の出力（マージ後の値）
+type SnapshotUniverse struct {
+	Protocol string     `json:"Protocol"`
+	Universe uint16     `json:"Universe"`
+	Data     [512]uint8 `json:"Data"`
+}
+
+// Snapshot 名前を付けて保存したすべてのユニバースの出力と送信元・ノードの一覧
+type Snapshot struct {
+	Name          string               `json:"Name"`
+	CreatedAt     time.Time            `json:"CreatedAt"`
+	Universes     []SnapshotUniverse   `json:"Universes"`     // プロトコル、ユニバースの順
+	ArtNetSources []UniverseMergeState `json:"ArtNetSources"` // Art-Net のユニバースごとの送信元
+	SACNSources   []*SACNSource        `json:"SACNSources"`
+	Nodes         []*ArtNetNode        `json:"Nodes"`
+}
+
+// SortUniverses ユニバースをプロトコル、ユニバースの順に並べる
+func (s *Snapshot) SortUniverses() {
+	sort.Slice(s.Universes, func(i, j int) bool {
+		a, b := s.Universes[i], s.Universes[j]
+		if a.Protocol != b.Protocol {
+			return a.Protocol < b.Protocol
+		}
+		return a.Universe < b.Universe
+	})
+}
+
+// SnapshotInfo スナップショットの一覧に表示する情報
+type SnapshotInfo struct {
+	Name      string    `json:"Name"`
+	CreatedAt time.Time `json:"CreatedAt"`
+	Universes int       `json:"Universes"`
+	Pinned    bool      `json:"Pinned"`
+}
+
+// ChannelDiff 値が異なるチャンネル
+type ChannelDiff struct {
+	Protocol string `json:"Protocol"`
+	Universe uint16 `json:"Universe"`
+	Channel  int    `json:"Channel"` // 1-512
+	From     uint8  `json:"From"`
+	To       uint8  `json:"To"`
+}
+
+// UniverseKey プロトコルとユニバースの組
+type UniverseKey struct {
+	Protocol string `json:"Protocol"`
+	Universe uint16 `json:"Universe"`
+}
+
+// SnapshotDiff 2つのスナップショット（または現在の出力）の差分
+type SnapshotDiff struct {
+	From       string        `json:"From"`
+	To         string        `json:"To"` // 現在の出力と比較した場合は "live"
+	Changes    []ChannelDiff `json:"Changes"`
+	OnlyInFrom []UniverseKey `json:"OnlyInFrom"` // From にのみ含まれるユニバース
+	OnlyInTo   []UniverseKey `json:"OnlyInTo"`   // To にのみ含まれるユニバース
+}
+
+// DiffSnapshots 2つのスナップショットの出力を比較する
+// 一方にのみ含まれるユニバースは、もう一方の値をすべて0として比較する
+func DiffSnapshots(from, to *Snapshot) SnapshotDiff {
+	diff := SnapshotDiff{
+		From:       from.Name,
+		To:         to.Name,
+		Changes:    []ChannelDiff{},
+		OnlyInFrom: []UniverseKey{},
+		OnlyInTo:   []UniverseKey{},
+	}
+
+	fromData := make(map[UniverseKey]*[512]uint8, len(from.Universes))
+	for i := range from.Universes {
+		u := &from.Universes[i]
+		fromData[UniverseKey{Protocol: u.Protocol, Universe: u.Universe}] = &u.Data
+	}
+	toData := make(map[UniverseKey]*[512]uint8, len(to.Universes))
+	for i := range to.Universes {
+		u := &to.Universes[i]
+		toData[UniverseKey{Protocol: u.Protocol, Universe: u.Universe}] = &u.Data
+	}
+
+	keys := make([]UniverseKey, 0, len(fromData)+len(toData))
+	for key := range fromData {
+		keys = append(keys, key)
+		if _, ok := toData[key]; !ok {
+			diff.OnlyInFrom = append(diff.OnlyInFrom, key)
+		}
+	}
+	for key := range toData {
+		if _, ok := fromData[key]; !ok {
+			keys = append(keys, key)
+			diff.OnlyInTo = append(diff.OnlyInTo, key)
+		}
+	}
+	sortUniverseKeys(keys)
+	sortUniverseKeys(diff.OnlyInFrom)
+	sortUniverseKeys(diff.OnlyInTo)
+
+	var zero [512]uint8
+	for _, key := range keys {
+		a, b := fromData[key], toData[key]
+		if a == nil {
+			a = &zero
+		}
+		if b == nil {
+			b = &zero
+		}
+		for ch := range a {
+			if a[ch] != b[ch] {
+				diff.Changes = append(diff.Changes, ChannelDiff{Protocol: key.Protocol, Universe: key.Universe, Channel: ch + 1, From: a[ch], To: b[ch]})
+			}
+		}
+	}
+	return diff
+}
+
+func sortUniverseKeys(keys []UniverseKey) {
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].Protocol != keys[j].Protocol {
+			return keys[i].Protocol < keys[j].Protocol
+		}
+		return keys[i].Universe < keys[j].Universe
+	})
+}
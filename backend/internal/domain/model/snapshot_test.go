@@ -0,0 +1,49 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newSnapshotUniverse(protocol string, universe uint16, values ...uint8) SnapshotUniverse {
+	u := SnapshotUniverse{Protocol: protocol, Universe: universe}
+	copy(u.Data[:], values)
+	return u
+}
+
+func TestDiffSnapshots(t *testing.T) {
+	from := &Snapshot{Name: "cue 12", Universes: []SnapshotUniverse{
+		newSnapshotUniverse(ProtocolArtNet, 0, 255, 0, 10),
+		newSnapshotUniverse(ProtocolArtNet, 1, 5),
+		newSnapshotUniverse(ProtocolSACN, 1, 1),
+	}}
+	to := &Snapshot{Name: "cue 13", Universes: []SnapshotUniverse{
+		newSnapshotUniverse(ProtocolSACN, 1, 1),
+		newSnapshotUniverse(ProtocolArtNet, 0, 255, 20, 0),
+		newSnapshotUniverse(ProtocolArtNet, 2, 0, 7),
+	}}
+
+	diff := DiffSnapshots(from, to)
+	assert.Equal(t, "cue 12", diff.From)
+	assert.Equal(t, "cue 13", diff.To)
+	assert.Equal(t, []ChannelDiff{
+		{Protocol: ProtocolArtNet, Universe: 0, Channel: 2, From: 0, To: 20},
+		{Protocol: ProtocolArtNet, Universe: 0, Channel: 3, From: 10, To: 0},
+		{Protocol: ProtocolArtNet, Universe: 1, Channel: 1, From: 5, To: 0},
+		{Protocol: ProtocolArtNet, Universe: 2, Channel: 2, From: 0, To: 7},
+	}, diff.Changes)
+	assert.Equal(t, []UniverseKey{{Protocol: ProtocolArtNet, Universe: 1}}, diff.OnlyInFrom)
+	assert.Equal(t, []UniverseKey{{Protocol: ProtocolArtNet, Universe: 2}}, diff.OnlyInTo)
+
+	assert.Empty(t, DiffSnapshots(from, from).Changes)
+}
+
+func TestValidateSnapshotName(t *testing.T) {
+	for _, name := range []string{"cue 12", "Act1_scene-2.v3", "0"} {
+		assert.NoError(t, ValidateSnapshotName(name), name)
+	}
+	for _, name := range []string{"", "live", ".hidden", "../etc", "a/b", " cue", "cue*"} {
+		assert.Error(t, ValidateSnapshotName(name), name)
+	}
+}
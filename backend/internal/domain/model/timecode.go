@@ -0,0 +1,131 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/jsimonetti/go-artnet/packet"
+)
+
+// TimeCodeType タイムコードの種別
+type TimeCodeType uint8
+
+const (
+	TimeCodeFilm  TimeCodeType = iota // 24fps
+	TimeCodeEBU                       // 25fps
+	TimeCodeDF                        // 29.97fps (ドロップフレーム)
+	TimeCodeSMPTE                     // 30fps
+)
+
+func (t TimeCodeType) String() string {
+	switch t {
+	case TimeCodeFilm:
+		return "Film"
+	case TimeCodeEBU:
+		return "EBU"
+	case TimeCodeDF:
+		return "DF"
+	case TimeCodeSMPTE:
+		return "SMPTE"
+	default:
+		return "Unknown"
+	}
+}
+
+// FrameRate 種別ごとのフレームレートを返す
+func (t TimeCodeType) FrameRate() float64 {
+	switch t {
+	case TimeCodeFilm:
+		return 24
+	case TimeCodeEBU:
+		return 25
+	case TimeCodeDF:
+		return 29.97
+	case TimeCodeSMPTE:
+		return 30
+	default:
+		return 0
+	}
+}
+
+// MarshalText JSONでは種別名として出力する
+func (t TimeCodeType) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+// TimeCode ArtTimeCodeで受信したタイムコードを表すドメインモデル
+type TimeCode struct {
+	Hours      uint8        `json:"Hours"`
+	Minutes    uint8        `json:"Minutes"`
+	Seconds    uint8        `json:"Seconds"`
+	Frames     uint8        `json:"Frames"`
+	Type       TimeCodeType `json:"Type"`
+	SourceIP   net.IP       `json:"SourceIP"`   // 送信元IPアドレス
+	ReceivedAt time.Time    `json:"ReceivedAt"` // 受信時刻
+}
+
+// NewTimeCode ArtTimeCodePacketからTimeCodeを作成
+func NewTimeCode(srcAddr net.Addr, p *packet.ArtTimeCodePacket) (*TimeCode, error) {
+	if p == nil {
+		return nil, errors.New("packet cannot be nil")
+	}
+
+	tc := &TimeCode{
+		Hours:      p.Hours,
+		Minutes:    p.Minutes,
+		Seconds:    p.Seconds,
+		Frames:     p.Frames,
+		Type:       TimeCodeType(p.Type),
+		ReceivedAt: time.Now(),
+	}
+	if addr, ok := srcAddr.(*net.UDPAddr); ok {
+		tc.SourceIP = addr.IP
+	}
+
+	if err := tc.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid timecode: %w", err)
+	}
+
+	return tc, nil
+}
+
+// Validate TimeCodeの妥当性を検証
+func (t *TimeCode) Validate() error {
+	if t.Type > TimeCodeSMPTE {
+		return fmt.Errorf("unknown timecode type %d", t.Type)
+	}
+	if t.Hours > 23 {
+		return fmt.Errorf("hours %d out of range (0-23)", t.Hours)
+	}
+	if t.Minutes > 59 {
+		return fmt.Errorf("minutes %d out of range (0-59)", t.Minutes)
+	}
+	if t.Seconds > 59 {
+		return fmt.Errorf("seconds %d out of range (0-59)", t.Seconds)
+	}
+	if maxFrames := uint8(t.Type.FrameRate() + 0.5); t.Frames >= maxFrames {
+		return fmt.Errorf("frames %d out of range for %s (0-%d)", t.Frames, t.Type, maxFrames-1)
+	}
+	return nil
+}
+
+// String HH:MM:SS:FF 形式の文字列表現（ドロップフレームは HH:MM:SS;FF）
+func (t *TimeCode) String() string {
+	sep := ":"
+	if t.Type == TimeCodeDF {
+		sep = ";"
+	}
+	return fmt.Sprintf("%02d:%02d:%02d%s%02d", t.Hours, t.Minutes, t.Seconds, sep, t.Frames)
+}
+
+// Duration タイムコードの先頭からの経過時間を返す
+func (t *TimeCode) Duration() time.Duration {
+	seconds := time.Duration(t.Hours)*time.Hour + time.Duration(t.Minutes)*time.Minute + time.Duration(t.Seconds)*time.Second
+	rate := t.Type.FrameRate()
+	if rate == 0 {
+		return seconds
+	}
+	return seconds + time.Duration(float64(t.Frames)/rate*float64(time.Second))
+}
@@ -0,0 +1,80 @@
+package model
+
+import (
+	"encoding/json"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/jsimonetti/go-artnet/packet"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTimeCode(t *testing.T) {
+	addr := &net.UDPAddr{IP: net.ParseIP("192.168.1.10"), Port: 6454}
+
+	tests := []struct {
+		name    string
+		packet  *packet.ArtTimeCodePacket
+		want    string
+		wantErr bool
+	}{
+		{
+			name:   "SMPTE",
+			packet: &packet.ArtTimeCodePacket{Hours: 1, Minutes: 2, Seconds: 3, Frames: 29, Type: 3},
+			want:   "01:02:03:29",
+		},
+		{
+			name:   "Drop frame",
+			packet: &packet.ArtTimeCodePacket{Hours: 10, Minutes: 0, Seconds: 59, Frames: 4, Type: 2},
+			want:   "10:00:59;04",
+		},
+		{
+			name:    "Frames exceed film rate",
+			packet:  &packet.ArtTimeCodePacket{Frames: 24, Type: 0},
+			wantErr: true,
+		},
+		{
+			name:    "Invalid type",
+			packet:  &packet.ArtTimeCodePacket{Type: 4},
+			wantErr: true,
+		},
+		{
+			name:    "Invalid minutes",
+			packet:  &packet.ArtTimeCodePacket{Minutes: 60, Type: 1},
+			wantErr: true,
+		},
+		{
+			name:    "Nil packet",
+			packet:  nil,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tc, err := NewTimeCode(addr, tt.packet)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Nil(t, tc)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, tc.String())
+			assert.True(t, tc.SourceIP.Equal(addr.IP))
+		})
+	}
+}
+
+func TestTimeCode_Duration(t *testing.T) {
+	tc := &TimeCode{Hours: 1, Minutes: 1, Seconds: 1, Frames: 12, Type: TimeCodeFilm}
+	assert.Equal(t, time.Hour+time.Minute+time.Second+500*time.Millisecond, tc.Duration())
+}
+
+func TestTimeCode_JSONType(t *testing.T) {
+	tc := &TimeCode{Type: TimeCodeEBU}
+	b, err := json.Marshal(tc)
+	require.NoError(t, err)
+	assert.Contains(t, string(b), `"Type":"EBU"`)
+}
@@ -0,0 +1,35 @@
+package model
+
+import "time"
+
+// 受信したパケットを書き出す時間
+const (
+	DefaultTrafficCaptureDuration = 60 * time.Second
+	MaxTrafficCaptureDuration     = 1 * time.Hour
+)
+
+// TrafficCaptureFilter 書き出すパケットの条件（指定しない条件はすべて一致とする）
+type TrafficCaptureFilter struct {
+	SourceIPs []string `json:"SourceIPs,omitempty"` // 送信元IPアドレス
+	OpCodes   []string `json:"OpCodes,omitempty"`   // Art-Net のオペコード（"0x5000"、"ArtDmx" など）
+	Universes string   `json:"Universes,omitempty"` // ユニバース（例: "0-3,16"。ユニバースを含まないパケットは書き出さない）
+}
+
+// TrafficCaptureOptions 受信したパケットの書き出し方法
+type TrafficCaptureOptions struct {
+	Filter      TrafficCaptureFilter
+	MaxDuration time.Duration // 経過すると自動的に停止する
+}
+
+// TrafficCaptureStatus 受信したパケットの書き出しの状態
+type TrafficCaptureStatus struct {
+	Capturing bool                 `json:"Capturing"`
+	Filter    TrafficCaptureFilter `json:"Filter"`
+	StartedAt time.Time            `json:"StartedAt"` // 最後に書き出しを開始した時刻
+	StopsAt   time.Time            `json:"StopsAt"`   // 最大の時間に達して停止する時刻
+	Packets   uint64               `json:"Packets"`   // 書き出したパケット数
+	Filtered  uint64               `json:"Filtered"`  // 条件に一致せず書き出さなかったパケット数
+	Dropped   uint64               `json:"Dropped"`   // 書き出しが追いつかず破棄したパケット数
+	File      *CaptureFile         `json:"File,omitempty"`
+	LastError string               `json:"LastError,omitempty"`
+}
@@ -2,16 +2,37 @@ package model
 
 import (
 	"net"
+	"time"
 
 	"github.com/jsimonetti/go-artnet/packet"
 )
 
 type ReceivedData struct {
-	Data []byte
-	Addr net.Addr
+	Data       []byte
+	Addr       net.Addr
+	ReceivedAt time.Time // ソケットから読み取った時刻
 }
 
 type ReceivedArtPacket struct {
-	Packet packet.ArtNetPacket
-	Addr   net.Addr
+	Packet     packet.ArtNetPacket
+	Addr       net.Addr
+	ReceivedAt time.Time
+}
+
+// CapturedDatagram パケットキャプチャ（pcap / pcapng）から取り出したUDPデータグラム
+type CapturedDatagram struct {
+	Timestamp time.Time // キャプチャした時刻
+	Src       *net.UDPAddr
+	Dst       *net.UDPAddr
+	Payload   []byte
+}
+
+// PcapImportResult パケットキャプチャの変換・再生の結果
+type PcapImportResult struct {
+	Datagrams int           `json:"Datagrams"` // Art-Net / sACN のポートのデータグラム数
+	ArtNet    int           `json:"ArtNet"`    // Art-Net のパケット数
+	SACN      int           `json:"SACN"`      // sACN のパケット数
+	Frames    int           `json:"Frames"`    // 変換したDMXフレーム数（再生の場合は処理したパケット数）
+	Skipped   int           `json:"Skipped"`   // 解析できなかった・対象外のパケット数
+	Files     []CaptureFile `json:"Files,omitempty"`
 }
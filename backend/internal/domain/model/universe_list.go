@@ -0,0 +1,68 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ParseUniverseList "0,1,16-19" のようなカンマ区切りのユニバース指定を解析する
+// 範囲は両端を含む。結果は昇順で重複を含まない
+func ParseUniverseList(s string) ([]uint16, error) {
+	return parseUniverseList(s, 0, MaxUniverse)
+}
+
+// ParseSACNUniverseList sACN のユニバース指定（1-63999）を解析する
+func ParseSACNUniverseList(s string) ([]uint16, error) {
+	return parseUniverseList(s, 1, MaxSACNUniverse)
+}
+
+func parseUniverseList(s string, min, max uint16) ([]uint16, error) {
+	seen := make(map[uint16]struct{})
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		start, end := part, part
+		if i := strings.Index(part, "-"); i >= 0 {
+			start, end = strings.TrimSpace(part[:i]), strings.TrimSpace(part[i+1:])
+		}
+
+		from, err := parseUniverse(start, min, max)
+		if err != nil {
+			return nil, err
+		}
+		to, err := parseUniverse(end, min, max)
+		if err != nil {
+			return nil, err
+		}
+		if from > to {
+			return nil, fmt.Errorf("invalid universe range %q", part)
+		}
+
+		for u := uint32(from); u <= uint32(to); u++ {
+			seen[uint16(u)] = struct{}{}
+		}
+	}
+
+	universes := make([]uint16, 0, len(seen))
+	for u := range seen {
+		universes = append(universes, u)
+	}
+	sort.Slice(universes, func(i, j int) bool { return universes[i] < universes[j] })
+	return universes, nil
+}
+
+func parseUniverse(s string, min, max uint16) (uint16, error) {
+	v, err := strconv.ParseUint(s, 10, 16)
+	if err != nil {
+		return 0, fmt.Errorf("invalid universe %q: %w", s, err)
+	}
+	if v < uint64(min) || v > uint64(max) {
+		return 0, fmt.Errorf("universe %d out of range (%d-%d)", v, min, max)
+	}
+	return uint16(v), nil
+}
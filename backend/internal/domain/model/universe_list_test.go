@@ -0,0 +1,35 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseUniverseList(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    []uint16
+		wantErr bool
+	}{
+		{name: "Empty", input: "", want: []uint16{}},
+		{name: "Single", input: "5", want: []uint16{5}},
+		{name: "List and range", input: "16-18, 0,1,17", want: []uint16{0, 1, 16, 17, 18}},
+		{name: "Reversed range", input: "5-3", wantErr: true},
+		{name: "Not a number", input: "a", wantErr: true},
+		{name: "Exceeds maximum", input: "32768", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseUniverseList(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
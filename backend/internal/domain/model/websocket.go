@@ -1,6 +1,11 @@
 package model
 
-import "time"
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
 
 type WebSocketMessage struct {
 	Type      string      `json:"Type"`
@@ -15,3 +20,84 @@ func NewWebSocketMessage(messageType string, data interface{}) *WebSocketMessage
 		Timestamp: time.Now().UnixMilli(),
 	}
 }
+
+// DMXPacketTopic 受信したすべてのユニバースのDMXフレームを配信するトピック
+const DMXPacketTopic = "artnet/dmx_packet"
+
+// ユニバースごとのトピック（"{protocol}/{kind}/{universe}"）の種類
+const (
+	dmxTopicKind      = "dmx"       // 受信したDMXフレーム
+	dmxDeltaTopicKind = "dmx_delta" // 送信元ごとの値の差分（DMXDelta）
+)
+
+// DMXUniverseTopic 1ユニバース分のDMXフレームを配信するトピック（"artnet/dmx/1"、"sacn/dmx/1" など）
+func DMXUniverseTopic(protocol string, universe uint16) string {
+	return protocol + "/" + dmxTopicKind + "/" + strconv.FormatUint(uint64(universe), 10)
+}
+
+// DMXDeltaTopic 1ユニバース分の値の差分を配信するトピック（"artnet/dmx_delta/1"、"sacn/dmx_delta/1" など）
+func DMXDeltaTopic(protocol string, universe uint16) string {
+	return protocol + "/" + dmxDeltaTopicKind + "/" + strconv.FormatUint(uint64(universe), 10)
+}
+
+// ParseDMXDeltaTopic 値の差分を配信するトピックからプロトコルとユニバースを取得する
+func ParseDMXDeltaTopic(topic string) (string, uint16, bool) {
+	protocol, kind, spec, ok := splitUniverseTopic(topic)
+	if !ok || kind != dmxDeltaTopicKind {
+		return "", 0, false
+	}
+	universe, err := strconv.ParseUint(spec, 10, 16)
+	if err != nil {
+		return "", 0, false
+	}
+	return protocol, uint16(universe), true
+}
+
+// MaxExpandedTopics 1つのトピックの指定から展開できるユニバースの最大数
+const MaxExpandedTopics = 512
+
+// ExpandTopic ユニバースの指定を含むトピック（"artnet/dmx/0-3,8" など）をユニバースごとのトピックに展開する
+// ユニバースごとのトピック以外はそのまま返す。MaxExpandedTopics を超えるユニバースを指定した場合はエラーを返す
+func ExpandTopic(topic string) ([]string, error) {
+	protocol, kind, spec, ok := splitUniverseTopic(topic)
+	if !ok {
+		return []string{topic}, nil
+	}
+	var universes []uint16
+	var err error
+	if protocol == ProtocolSACN {
+		universes, err = ParseSACNUniverseList(spec)
+	} else {
+		universes, err = ParseUniverseList(spec)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid topic %q: %w", topic, err)
+	}
+	if len(universes) == 0 {
+		return nil, fmt.Errorf("invalid topic %q: no universe", topic)
+	}
+	if len(universes) > MaxExpandedTopics {
+		return nil, fmt.Errorf("invalid topic %q: %d universes exceed the maximum of %d", topic, len(universes), MaxExpandedTopics)
+	}
+
+	topics := make([]string, len(universes))
+	for i, u := range universes {
+		topics[i] = protocol + "/" + kind + "/" + strconv.FormatUint(uint64(u), 10)
+	}
+	return topics, nil
+}
+
+// splitUniverseTopic ユニバースごとのトピックをプロトコル・種類・ユニバースの指定に分ける
+func splitUniverseTopic(topic string) (protocol, kind, spec string, ok bool) {
+	parts := strings.SplitN(topic, "/", 3)
+	if len(parts) != 3 {
+		return "", "", "", false
+	}
+	if parts[0] != ProtocolArtNet && parts[0] != ProtocolSACN {
+		return "", "", "", false
+	}
+	if parts[1] != dmxTopicKind && parts[1] != dmxDeltaTopicKind {
+		return "", "", "", false
+	}
+	return parts[0], parts[1], parts[2], true
+}
@@ -0,0 +1,64 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDMXUniverseTopic(t *testing.T) {
+	assert.Equal(t, "artnet/dmx/0", DMXUniverseTopic(ProtocolArtNet, 0))
+	assert.Equal(t, "sacn/dmx/63999", DMXUniverseTopic(ProtocolSACN, 63999))
+}
+
+func TestParseDMXDeltaTopic(t *testing.T) {
+	protocol, universe, ok := ParseDMXDeltaTopic(DMXDeltaTopic(ProtocolSACN, 7))
+	assert.True(t, ok)
+	assert.Equal(t, ProtocolSACN, protocol)
+	assert.Equal(t, uint16(7), universe)
+
+	for _, topic := range []string{"artnet/dmx/1", "artnet/dmx_delta/1-2", "artnet/dmx_delta/", "dmx_delta/1", "other/dmx_delta/1"} {
+		_, _, ok := ParseDMXDeltaTopic(topic)
+		assert.False(t, ok, topic)
+	}
+}
+
+func TestExpandTopic(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    []string
+		wantErr bool
+	}{
+		{name: "Other topic", input: "artnet/nodes", want: []string{"artnet/nodes"}},
+		{name: "Firehose", input: DMXPacketTopic, want: []string{DMXPacketTopic}},
+		{name: "Single universe", input: "artnet/dmx/3", want: []string{"artnet/dmx/3"}},
+		{name: "Set and range", input: "artnet/dmx/8,0-2", want: []string{"artnet/dmx/0", "artnet/dmx/1", "artnet/dmx/2", "artnet/dmx/8"}},
+		{name: "sACN", input: "sacn/dmx/1-2", want: []string{"sacn/dmx/1", "sacn/dmx/2"}},
+		{name: "Delta", input: "artnet/dmx_delta/4,6", want: []string{"artnet/dmx_delta/4", "artnet/dmx_delta/6"}},
+		{name: "sACN universe 0", input: "sacn/dmx/0", wantErr: true},
+		{name: "Out of range", input: "artnet/dmx/32768", wantErr: true},
+		{name: "Too many universes", input: "artnet/dmx/0-512", wantErr: true},
+		{name: "Whole Art-Net range", input: "artnet/dmx/0-32767", wantErr: true},
+		{name: "Empty", input: "artnet/dmx/", wantErr: true},
+		{name: "Not a number", input: "artnet/dmx/x", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ExpandTopic(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestExpandTopic_MaxExpandedTopics(t *testing.T) {
+	got, err := ExpandTopic("artnet/dmx/0-511")
+	assert.NoError(t, err)
+	assert.Len(t, got, MaxExpandedTopics)
+}
@@ -5,4 +5,12 @@ import "github.com/nasshu2916/dmx_viewer/internal/domain/model"
 type ArtNetNodeRepository interface {
 	Save(node *model.ArtNetNode)
 	All() []*model.ArtNetNode
+	// キー（IPアドレスとBindIndexの組）でノードを取得する
+	Get(key string) (*model.ArtNetNode, bool)
+	// ノードを一覧から削除する（状態遷移の履歴は残すが、保持するノード数を超えると古いものから削除される）
+	Delete(key string)
+	// 状態遷移を履歴に追加する
+	AddTransition(transition model.NodeTransition)
+	// ノードの状態遷移の履歴を古い順に取得する（履歴がない場合はfalse）
+	Transitions(key string) ([]model.NodeTransition, bool)
 }
@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/nasshu2916/dmx_viewer/internal/domain/model"
+)
+
+// CaptureRepository 受信したDMXフレームをキャプチャファイルに追記・読み込みするリポジトリ
+type CaptureRepository interface {
+	// 新しいキャプチャファイルを作成して記録を開始する
+	Open(startedAt time.Time) error
+	// フレームを追記する（サイズ・時間の上限に達した場合は次のファイルに切り替える）
+	Append(frame *model.DMXFrame) error
+	// バッファの内容をディスクに書き出す
+	Flush() error
+	// 記録を終了してファイルを閉じる
+	Close() error
+	// 最後の記録で作成したファイルの一覧
+	Files() []model.CaptureFile
+	// 保存先のディレクトリにあるキャプチャファイルの一覧（記録開始時刻の昇順）
+	List() ([]model.CaptureFile, error)
+	// キャプチャファイルの情報を取得する（存在しない場合は fs.ErrNotExist を返す）
+	Get(name string) (model.CaptureFile, error)
+	// キャプチャファイルのすべてのレコードを読み込む（存在しない場合は fs.ErrNotExist を返す）
+	Load(name string) (model.CaptureFile, []*model.CaptureRecord, error)
+	// キャプチャファイルを先頭から1レコードずつ読み込む（存在しない場合は fs.ErrNotExist を返す）
+	Stream(name string) (model.CaptureFile, CaptureRecordReader, error)
+}
+
+// CaptureRecordReader キャプチャファイルのレコードを先頭から順に読み込む
+type CaptureRecordReader interface {
+	// 次のレコードを読み込む（末尾では io.EOF、書き込み途中で終了したレコードは io.ErrUnexpectedEOF を返す）
+	Next() (*model.CaptureRecord, error)
+	Close() error
+}
@@ -0,0 +1,14 @@
+package repository
+
+import "github.com/nasshu2916/dmx_viewer/internal/domain/model"
+
+// NodeSettingsRepository このビューア自身のArt-Netノード設定を保持するリポジトリ
+type NodeSettingsRepository interface {
+	// 現在の設定を取得する
+	Get() model.NodeSettings
+	// 起動時の既定の設定を取得する
+	Defaults() model.NodeSettings
+	// 現在の設定を update で変更して保存し、変更後の設定を返す（永続化が有効な場合はファイルにも書き込む）
+	// 取得から保存までを排他的に行う。update がエラーを返した場合は変更しない
+	Update(update func(settings *model.NodeSettings) error) (model.NodeSettings, error)
+}
@@ -0,0 +1,12 @@
+package repository
+
+import "github.com/nasshu2916/dmx_viewer/internal/domain/model"
+
+type SACNSourceRepository interface {
+	Save(source *model.SACNSource)
+	All() []*model.SACNSource
+	// CIDで送信元を取得する
+	Get(cid string) (*model.SACNSource, bool)
+	// 送信元を一覧から削除する
+	Delete(cid string)
+}
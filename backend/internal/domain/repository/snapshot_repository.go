@@ -0,0 +1,15 @@
+package repository
+
+import "github.com/nasshu2916/dmx_viewer/internal/domain/model"
+
+// SnapshotRepository スナップショットを永続化する
+type SnapshotRepository interface {
+	// スナップショットを保存する（同じ名前のスナップショットが存在する場合は fs.ErrExist）
+	Create(snapshot *model.Snapshot) error
+	// 保存済みのスナップショットを作成日時の順に取得する
+	List() ([]model.SnapshotInfo, error)
+	// スナップショットを取得する（存在しない場合は fs.ErrNotExist）
+	Get(name string) (*model.Snapshot, error)
+	// スナップショットを削除する（存在しない場合は fs.ErrNotExist）
+	Delete(name string) error
+}
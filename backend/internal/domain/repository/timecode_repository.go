@@ -0,0 +1,8 @@
+package repository
+
+import "github.com/nasshu2916/dmx_viewer/internal/domain/model"
+
+type TimeCodeRepository interface {
+	Save(timeCode *model.TimeCode)
+	Latest() (*model.TimeCode, bool)
+}
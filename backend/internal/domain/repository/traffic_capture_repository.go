@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"net"
+	"time"
+
+	"github.com/nasshu2916/dmx_viewer/internal/domain/model"
+)
+
+// TrafficCaptureRepository 受信したUDPデータグラムを pcap ファイルに書き出すリポジトリ
+type TrafficCaptureRepository interface {
+	// 新しい pcap ファイルを作成する
+	// broadcast は受信したインターフェースのディレクテッドブロードキャストアドレス（不明な場合は nil）
+	Create(startedAt time.Time, broadcast net.IP) (model.CaptureFile, error)
+	// データグラムを追記する
+	Write(datagram *model.CapturedDatagram) error
+	// バッファの内容をディスクに書き出す
+	Flush() error
+	// ファイルを閉じる（閉じたファイルの情報を返す）
+	Close() (model.CaptureFile, error)
+	// 保存先のディレクトリにある pcap ファイルの一覧（作成した時刻の昇順）
+	List() ([]model.CaptureFile, error)
+	// pcap ファイルの情報を取得する（存在しない場合は fs.ErrNotExist を返す）
+	Get(name string) (model.CaptureFile, error)
+}
@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/nasshu2916/dmx_viewer/internal/domain/model"
+)
+
+// UniverseHistoryRepository ユニバースごとの出力の履歴を解像度を落としながら保持する
+type UniverseHistoryRepository interface {
+	// 受信したユニバースの出力を追加する
+	Append(protocol string, universe uint16, at time.Time, data *[512]uint8)
+	// 1チャンネル（0始まり）の [from, to) の履歴を古い順に取得する
+	Query(protocol string, universe uint16, channel int, from, to time.Time) []model.ChannelHistoryPoint
+	// 連続した複数チャンネル（0始まり、first から last まで）の [from, to) の平均値の履歴を古い順に取得する
+	QueryRange(protocol string, universe uint16, first, last int, from, to time.Time) []model.ChannelRangeHistoryPoint
+	// 保持する期間を過ぎた履歴を削除する
+	Prune(now time.Time)
+}
@@ -5,6 +5,11 @@ type WebSocketRepository interface {
 	// 特定のトピックにメッセージをブロードキャストする
 	BroadcastToTopic(topic string, message []byte) error
 
+	// 複数のトピックにメッセージをブロードキャストする（すべてのクライアントへの送信を終えてから戻る）
+	// text と binary は、その表現を使うクライアントが購読している場合にのみ1回だけ呼ばれる。
+	// バイナリ表現を選択したクライアントには binary を、それ以外のクライアント（binary が nil または nil を返した場合は全員）には text を送信する
+	BroadcastToTopics(topics []string, text, binary func() []byte) error
+
 	// 全クライアントにメッセージをブロードキャストする
 	BroadcastToAll(message []byte) error
 }
@@ -0,0 +1,11 @@
+//go:build linux
+
+package artnet
+
+import "syscall"
+
+// bindToDevice ソケットを指定したインターフェースに束縛する（SO_BINDTODEVICE）
+// 特定のIPアドレスにbindするとブロードキャストを受信できなくなるため、デバイス単位で束縛する
+func bindToDevice(fd uintptr, name string) error {
+	return syscall.SetsockoptString(int(fd), syscall.SOL_SOCKET, syscall.SO_BINDTODEVICE, name)
+}
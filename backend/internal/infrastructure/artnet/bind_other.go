@@ -0,0 +1,8 @@
+//go:build !linux
+
+package artnet
+
+// bindToDevice このプラットフォームではインターフェースへの束縛に対応していない
+func bindToDevice(_ uintptr, _ string) error {
+	return ErrBindToDeviceUnsupported
+}
@@ -24,6 +24,8 @@ var (
 	ErrSendChannelTimeout       = errors.New("send channel write timeout")
 	ErrConnectionNotEstablished = errors.New("connection is not established")
 	ErrServerNotRunning         = errors.New("server is not running")
+	ErrInterfaceNotFound        = errors.New("network interface not found")
+	ErrBindToDeviceUnsupported  = errors.New("binding to a network device is not supported on this platform")
 )
 
 // ChannelType チャンネルタイプ
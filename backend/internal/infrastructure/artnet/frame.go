@@ -0,0 +1,76 @@
+package artnet
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/jsimonetti/go-artnet/packet"
+	"github.com/nasshu2916/dmx_viewer/internal/domain/model"
+)
+
+// NewDMXFrame ArtDMXパケットからプロトコル共通の DMXFrame を作成する
+func NewDMXFrame(srcAddr net.Addr, p *packet.ArtDMXPacket, receivedAt time.Time) (*model.DMXFrame, error) {
+	if p == nil {
+		return nil, errors.New("packet cannot be nil")
+	}
+
+	frame := &model.DMXFrame{
+		Protocol:   model.ProtocolArtNet,
+		Universe:   uint16(p.Net)<<8 | uint16(p.SubUni),
+		StartCode:  model.StartCodeDMX,
+		Length:     p.Length,
+		Data:       p.Data,
+		Sequence:   p.Sequence,
+		Physical:   p.Physical,
+		ReceivedAt: receivedAt,
+	}
+	if addr, ok := srcAddr.(*net.UDPAddr); ok {
+		frame.SourceIP = addr.IP
+		frame.SourcePort = addr.Port
+	}
+
+	if err := frame.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid DMX data: %w", err)
+	}
+
+	return frame, nil
+}
+
+// artDMXHeaderSize ArtDMX のデータより前の部分のサイズ（最後の2バイトが Length）
+const artDMXHeaderSize = 18
+
+// MarshalDMXFrame DMXFrame を ArtDMX パケットにエンコードする
+// go-artnet は常に512チャンネルで送信するため、フレームのチャンネル数に合わせて切り詰める
+func MarshalDMXFrame(frame *model.DMXFrame) ([]byte, error) {
+	if frame.Universe > model.MaxUniverse {
+		return nil, fmt.Errorf("universe %d exceeds Art-Net maximum %d", frame.Universe, model.MaxUniverse)
+	}
+	p := &packet.ArtDMXPacket{
+		Sequence: frame.Sequence,
+		Physical: frame.Physical,
+		SubUni:   uint8(frame.Universe),
+		Net:      uint8(frame.Universe >> 8),
+		Data:     frame.Data,
+	}
+	data, err := p.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	length := dmxLength(frame.Length)
+	binary.BigEndian.PutUint16(data[artDMXHeaderSize-2:artDMXHeaderSize], length)
+	return data[:artDMXHeaderSize+int(length)], nil
+}
+
+// dmxLength ArtDMX の Length は 2-512 の偶数とする
+func dmxLength(length uint16) uint16 {
+	if length < 2 {
+		return 2
+	}
+	if length%2 == 1 {
+		length++
+	}
+	return length
+}
@@ -0,0 +1,92 @@
+package artnet
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/jsimonetti/go-artnet/packet"
+	"github.com/nasshu2916/dmx_viewer/internal/domain/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewDMXFrame(t *testing.T) {
+	tests := []struct {
+		name    string
+		packet  *packet.ArtDMXPacket
+		wantErr bool
+	}{
+		{
+			name: "Valid DMX packet",
+			packet: &packet.ArtDMXPacket{
+				Sequence: 1,
+				Physical: 0,
+				SubUni:   5,
+				Net:      2,
+				Length:   100,
+				Data:     [512]byte{255, 128, 64, 32},
+			},
+			wantErr: false,
+		},
+		{
+			name:    "Nil packet",
+			packet:  nil,
+			wantErr: true,
+		},
+		{
+			name: "Invalid length",
+			packet: &packet.ArtDMXPacket{
+				Length: 513, // 最大値を超える
+			},
+			wantErr: true,
+		},
+	}
+
+	addr := &net.UDPAddr{
+		IP:   net.ParseIP("127.0.0.1"),
+		Port: 1234,
+	}
+	receivedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			frame, err := NewDMXFrame(addr, tt.packet, receivedAt)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Nil(t, frame)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, frame)
+				assert.Equal(t, model.ProtocolArtNet, frame.Protocol)
+				assert.Equal(t, uint16(517), frame.Universe) // 2*256 + 5
+				assert.Equal(t, tt.packet.Sequence, frame.Sequence)
+				assert.Equal(t, tt.packet.Physical, frame.Physical)
+				assert.Equal(t, tt.packet.Length, frame.Length)
+				assert.Equal(t, tt.packet.Data, frame.Data)
+				assert.Equal(t, 1234, frame.SourcePort)
+				assert.Equal(t, receivedAt, frame.ReceivedAt)
+			}
+		})
+	}
+}
+
+func TestMarshalDMXFrame(t *testing.T) {
+	frame := &model.DMXFrame{Universe: 517, Sequence: 9, Physical: 1, Length: 3, Data: [512]byte{10, 20, 30}}
+
+	data, err := MarshalDMXFrame(frame)
+	assert.NoError(t, err)
+	// Length は偶数に切り上げ、データはその長さで切り詰める
+	assert.Len(t, data, 18+4)
+
+	var p packet.ArtDMXPacket
+	assert.NoError(t, p.UnmarshalBinary(data))
+	assert.Equal(t, uint8(9), p.Sequence)
+	assert.Equal(t, uint8(1), p.Physical)
+	assert.Equal(t, uint8(5), p.SubUni)
+	assert.Equal(t, uint8(2), p.Net)
+	assert.Equal(t, uint16(4), p.Length)
+	assert.Equal(t, []byte{10, 20, 30, 0}, p.Data[:4])
+
+	_, err = MarshalDMXFrame(&model.DMXFrame{Universe: model.MaxUniverse + 1})
+	assert.Error(t, err)
+}
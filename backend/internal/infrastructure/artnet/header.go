@@ -0,0 +1,62 @@
+package artnet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/jsimonetti/go-artnet/packet/code"
+)
+
+// artNetID Art-Net パケットの先頭8バイト
+var artNetID = []byte("Art-Net\x00")
+
+// Header パケット全体を解析せずに読み取った Art-Net ヘッダーの内容
+type Header struct {
+	OpCode      code.OpCode
+	Universe    uint16 // 15bitのポートアドレス（HasUniverse の場合のみ）
+	HasUniverse bool   // ArtDmx / ArtNzs のようにユニバースを含むパケット
+}
+
+// PeekHeader UDPペイロードから Art-Net のオペコードとユニバースを読み取る（Art-Net でない場合は false）
+func PeekHeader(b []byte) (Header, bool) {
+	if len(b) < 10 || !bytes.Equal(b[:8], artNetID) {
+		return Header{}, false
+	}
+	h := Header{OpCode: code.OpCode(binary.LittleEndian.Uint16(b[8:10]))}
+	switch h.OpCode {
+	case code.OpDMX, code.OpNzs:
+		// ProtVer(2) Sequence(1) Physical/StartCode(1) SubUni(1) Net(1)
+		if len(b) >= 16 {
+			h.Universe = uint16(b[15]&0x7F)<<8 | uint16(b[14])
+			h.HasUniverse = true
+		}
+	}
+	return h, true
+}
+
+// ParseOpCode オペコードを数値（"0x5000"）または名前（"OpOutput"、"ArtDmx"、"dmx" など）から解析する
+func ParseOpCode(s string) (code.OpCode, error) {
+	s = strings.TrimSpace(s)
+	if v, err := strconv.ParseUint(s, 0, 16); err == nil {
+		return code.OpCode(v), nil
+	}
+
+	name := strings.ToLower(s)
+	name = strings.TrimPrefix(name, "op")
+	name = strings.TrimPrefix(name, "art")
+	if name == "dmx" {
+		// ArtDmx のオペコードの名前は OpOutput
+		return code.OpDMX, nil
+	}
+	// オペコードは上位8bitのみを使用する
+	for v := 0; v <= 0xFF00; v += 0x100 {
+		op := code.OpCode(v)
+		if code.ValidOp(op) && strings.ToLower(op.String()) == "op"+name {
+			return op, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown Art-Net opcode %q", s)
+}
@@ -0,0 +1,60 @@
+package artnet
+
+import (
+	"testing"
+
+	"github.com/jsimonetti/go-artnet/packet"
+	"github.com/jsimonetti/go-artnet/packet/code"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPeekHeader(t *testing.T) {
+	dmx, err := (&packet.ArtDMXPacket{Net: 0x12, SubUni: 0x34, Length: 2}).MarshalBinary()
+	require.NoError(t, err)
+	poll, err := packet.NewArtPollPacket().MarshalBinary()
+	require.NoError(t, err)
+
+	h, ok := PeekHeader(dmx)
+	require.True(t, ok)
+	assert.Equal(t, code.OpDMX, h.OpCode)
+	assert.True(t, h.HasUniverse)
+	assert.Equal(t, uint16(0x1234), h.Universe)
+
+	h, ok = PeekHeader(poll)
+	require.True(t, ok)
+	assert.Equal(t, code.OpPoll, h.OpCode)
+	assert.False(t, h.HasUniverse)
+
+	_, ok = PeekHeader([]byte("not art-net"))
+	assert.False(t, ok)
+}
+
+func TestParseOpCode(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    code.OpCode
+		wantErr bool
+	}{
+		{in: "0x5000", want: code.OpDMX},
+		{in: "20992", want: code.OpSync},
+		{in: "ArtDmx", want: code.OpDMX},
+		{in: "OpPoll", want: code.OpPoll},
+		{in: "artpollreply", want: code.OpPollReply},
+		{in: "TimeCode", want: code.OpTimeCode},
+		{in: "nzs", want: code.OpNzs},
+		{in: "unknown", wantErr: true},
+		{in: "0x10000", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseOpCode(tt.in)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
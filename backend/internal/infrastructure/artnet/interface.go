@@ -0,0 +1,108 @@
+package artnet
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/nasshu2916/dmx_viewer/internal/domain/model"
+)
+
+// Art-Net で一般的に使用されるネットワーク（自動検出時に優先する順）
+var preferredArtNetNetworks = []*net.IPNet{
+	{IP: net.IPv4(2, 0, 0, 0).To4(), Mask: net.CIDRMask(8, 32)},
+	{IP: net.IPv4(10, 0, 0, 0).To4(), Mask: net.CIDRMask(8, 32)},
+}
+
+// SelectInterface 設定値に従ってArt-Netで使用するネットワークインターフェースを選択する
+// spec にはインターフェース名・CIDR・IPアドレスのいずれかを指定できる
+// 空の場合は 2.x.x.x, 10.x.x.x, その他の順で自動検出する
+func SelectInterface(spec string) (*model.NetworkInterface, error) {
+	candidates, err := listInterfaces()
+	if err != nil {
+		return nil, err
+	}
+	return selectInterface(spec, candidates)
+}
+
+// listInterfaces 稼働中のIPv4アドレスを持つインターフェースを列挙する
+func listInterfaces() ([]*model.NetworkInterface, error) {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list network interfaces: %w", err)
+	}
+
+	var candidates []*model.NetworkInterface
+	for _, iface := range ifaces {
+		if iface.Flags&net.FlagUp == 0 {
+			continue
+		}
+		addrs, err := iface.Addrs()
+		if err != nil {
+			continue
+		}
+		for _, addr := range addrs {
+			ipNet, ok := addr.(*net.IPNet)
+			if !ok || ipNet.IP.To4() == nil {
+				continue
+			}
+			mask := ipNet.Mask
+			if len(mask) == net.IPv6len {
+				mask = mask[12:]
+			}
+			candidates = append(candidates, model.NewNetworkInterface(iface.Name, ipNet.IP, mask, iface.HardwareAddr))
+		}
+	}
+	return candidates, nil
+}
+
+// selectInterface 候補の中から設定値に一致するインターフェースを選択する
+func selectInterface(spec string, candidates []*model.NetworkInterface) (*model.NetworkInterface, error) {
+	if spec == "" {
+		return autoDetectInterface(candidates)
+	}
+
+	if _, network, err := net.ParseCIDR(spec); err == nil {
+		for _, c := range candidates {
+			if network.Contains(c.IP) {
+				return c, nil
+			}
+		}
+		return nil, fmt.Errorf("%w: no interface in %s", ErrInterfaceNotFound, spec)
+	}
+
+	if ip := net.ParseIP(spec); ip != nil {
+		for _, c := range candidates {
+			if c.IP.Equal(ip) {
+				return c, nil
+			}
+		}
+		return nil, fmt.Errorf("%w: no interface with address %s", ErrInterfaceNotFound, spec)
+	}
+
+	for _, c := range candidates {
+		if c.Name == spec {
+			return c, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrInterfaceNotFound, spec)
+}
+
+// autoDetectInterface Art-Netのネットワーク（2.x, 10.x）を優先してインターフェースを選択する
+func autoDetectInterface(candidates []*model.NetworkInterface) (*model.NetworkInterface, error) {
+	for _, network := range preferredArtNetNetworks {
+		for _, c := range candidates {
+			if network.Contains(c.IP) {
+				return c, nil
+			}
+		}
+	}
+	for _, c := range candidates {
+		if !c.IP.IsLoopback() {
+			return c, nil
+		}
+	}
+	if len(candidates) > 0 {
+		return candidates[0], nil
+	}
+	return nil, ErrInterfaceNotFound
+}
@@ -0,0 +1,66 @@
+package artnet
+
+import (
+	"net"
+	"testing"
+
+	"github.com/nasshu2916/dmx_viewer/internal/domain/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testInterfaces() []*model.NetworkInterface {
+	return []*model.NetworkInterface{
+		model.NewNetworkInterface("lo", net.IPv4(127, 0, 0, 1), net.CIDRMask(8, 32), nil),
+		model.NewNetworkInterface("eth0", net.IPv4(192, 168, 1, 20), net.CIDRMask(24, 32), nil),
+		model.NewNetworkInterface("eth1", net.IPv4(10, 1, 2, 3), net.CIDRMask(8, 32), nil),
+		model.NewNetworkInterface("eth2", net.IPv4(2, 0, 0, 10), net.CIDRMask(8, 32), nil),
+	}
+}
+
+func TestSelectInterface(t *testing.T) {
+	tests := []struct {
+		name     string
+		spec     string
+		want     string
+		wantErr  bool
+		override []*model.NetworkInterface
+	}{
+		{name: "Auto detect prefers 2.x", spec: "", want: "eth2"},
+		{name: "Auto detect falls back to 10.x", spec: "", want: "eth1", override: testInterfaces()[:3]},
+		{name: "Auto detect falls back to non-loopback", spec: "", want: "eth0", override: testInterfaces()[:2]},
+		{name: "Auto detect uses loopback when isolated", spec: "", want: "lo", override: testInterfaces()[:1]},
+		{name: "By name", spec: "eth0", want: "eth0"},
+		{name: "By CIDR", spec: "192.168.1.0/24", want: "eth0"},
+		{name: "By address", spec: "10.1.2.3", want: "eth1"},
+		{name: "Unknown name", spec: "wlan0", wantErr: true},
+		{name: "Unknown CIDR", spec: "172.16.0.0/12", wantErr: true},
+		{name: "No interfaces", spec: "", wantErr: true, override: []*model.NetworkInterface{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			candidates := testInterfaces()
+			if tt.override != nil {
+				candidates = tt.override
+			}
+			iface, err := selectInterface(tt.spec, candidates)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInterfaceNotFound)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, iface.Name)
+		})
+	}
+}
+
+func TestNewNetworkInterface_Broadcast(t *testing.T) {
+	iface := model.NewNetworkInterface("eth0", net.IPv4(192, 168, 1, 20), net.CIDRMask(24, 32), nil)
+	assert.Equal(t, "192.168.1.255", iface.Broadcast.String())
+
+	iface = model.NewNetworkInterface("eth1", net.IPv4(2, 3, 4, 5), net.CIDRMask(8, 32), nil)
+	assert.Equal(t, "2.255.255.255", iface.Broadcast.String())
+	assert.True(t, iface.Contains(net.IPv4(2, 100, 0, 1)))
+	assert.False(t, iface.Contains(net.IPv4(10, 0, 0, 1)))
+}
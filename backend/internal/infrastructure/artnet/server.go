@@ -1,13 +1,17 @@
 package artnet
 
 import (
+	"context"
 	"fmt"
 	"net"
+	"sync/atomic"
+	"syscall"
 	"time"
 
 	"github.com/nasshu2916/dmx_viewer/internal/config"
 	"github.com/nasshu2916/dmx_viewer/internal/domain/model"
 	"github.com/nasshu2916/dmx_viewer/pkg/logger"
+	"golang.org/x/net/ipv4"
 )
 
 // SendPacket 送信するパケットの情報
@@ -16,11 +20,20 @@ type SendPacket struct {
 	Addr net.Addr
 }
 
+// DatagramObserver 受信したUDPデータグラムを解析する前にそのまま受け取るインターフェース
+// 受信処理のゴルーチンから呼ばれるため、実装はすぐに戻る必要がある。ペイロードは受信チャネルと共有するため変更しない
+type DatagramObserver interface {
+	ObserveDatagram(datagram *model.CapturedDatagram)
+}
+
 type Server struct {
 	conn               net.PacketConn
+	packetConn         *ipv4.PacketConn // 宛先アドレスを取得するための制御メッセージ付きの受信（有効にできない場合はnil）
+	datagramObserver   DatagramObserver
 	logger             *logger.Logger
 	config             *config.ArtNet
-	ipAddress          string
+	ipAddress          string                                 // 選択したインターフェースのIPアドレス
+	iface              atomic.Pointer[model.NetworkInterface] // 送受信に使用するネットワークインターフェース
 	port               int
 	done               chan bool
 	receivedChan       chan model.ReceivedData // 受信したArtNetパケットを送信するチャネル
@@ -57,18 +70,47 @@ func NewServer(logger *logger.Logger, cfg *config.ArtNet) *Server {
 }
 
 func (s *Server) Run() error {
-	addr := fmt.Sprintf("%s:%d", s.ipAddress, s.port)
-	udpAddr, err := net.ResolveUDPAddr("udp", addr)
+	iface, err := SelectInterface(s.config.Interface)
 	if err != nil {
-		return fmt.Errorf("failed to resolve UDP address %s: %w", addr, err)
+		return fmt.Errorf("ArtNet server startup failed: %w", err)
+	}
+	s.iface.Store(iface)
+	s.ipAddress = iface.IP.String()
+
+	// ブロードキャストを受信するため全アドレスで待ち受け、インターフェースへはデバイス単位で束縛する
+	addr := fmt.Sprintf(":%d", s.port)
+	var bindErr error
+	listenConfig := net.ListenConfig{
+		Control: func(_, _ string, c syscall.RawConn) error {
+			return c.Control(func(fd uintptr) {
+				bindErr = bindToDevice(fd, iface.Name)
+			})
+		},
 	}
-	conn, err := net.ListenUDP("udp", udpAddr)
+	conn, err := listenConfig.ListenPacket(context.Background(), "udp4", addr)
 	if err != nil {
 		return fmt.Errorf("ArtNet server startup failed: %w", err)
 	}
+	if bindErr != nil {
+		s.logger.Warn("Failed to bind ArtNet socket to interface, receiving on all interfaces", "interface", iface.Name, "error", bindErr)
+	}
 	s.conn = conn
 
-	s.logger.Info("ArtNet server started", "address", addr, "channelBufferSize", s.channelBufferSize)
+	// ブロードキャストとユニキャストを区別するため受信したパケットの宛先アドレスを取得する
+	packetConn := ipv4.NewPacketConn(conn)
+	if err := packetConn.SetControlMessage(ipv4.FlagDst, true); err != nil {
+		s.logger.Warn("Failed to enable destination address on ArtNet socket", "error", err)
+	} else {
+		s.packetConn = packetConn
+	}
+
+	s.logger.Info("ArtNet server started",
+		"address", addr,
+		"interface", iface.Name,
+		"ip", s.ipAddress,
+		"broadcast", iface.Broadcast.String(),
+		"mac", iface.MAC.String(),
+		"channelBufferSize", s.channelBufferSize)
 	pollInterval := time.Duration(s.config.PollIntervalSeconds) * time.Second
 
 	// ArtPollパケットを定期送信するゴルーチンを開始
@@ -102,6 +144,24 @@ func (s *Server) Run() error {
 	return nil
 }
 
+// SetDatagramObserver 受信したUDPデータグラムを受け取るオブザーバーを設定する（Run の前に呼ぶ）
+func (s *Server) SetDatagramObserver(observer DatagramObserver) {
+	s.datagramObserver = observer
+}
+
+// LocalInterface 送受信に使用しているネットワークインターフェースを返す（起動前はnil）
+func (s *Server) LocalInterface() *model.NetworkInterface {
+	return s.iface.Load()
+}
+
+// BroadcastAddr 選択したインターフェースのディレクテッドブロードキャストアドレスを返す
+func (s *Server) BroadcastAddr() *net.UDPAddr {
+	if iface := s.iface.Load(); iface != nil {
+		return &net.UDPAddr{IP: iface.Broadcast, Port: s.port}
+	}
+	return &net.UDPAddr{IP: net.IPv4bcast, Port: s.port}
+}
+
 // IsRunning returns true if the UDP listener is established.
 // It can be used as a readiness signal for HTTP readiness checks.
 func (s *Server) IsRunning() bool {
@@ -1,13 +1,25 @@
 package artnet
 
 import (
+	"net"
 	"testing"
 
 	"github.com/nasshu2916/dmx_viewer/internal/config"
+	"github.com/nasshu2916/dmx_viewer/internal/domain/model"
 	"github.com/nasshu2916/dmx_viewer/pkg/logger"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/ipv4"
 )
 
+type recordingDatagramObserver struct {
+	datagrams []*model.CapturedDatagram
+}
+
+func (o *recordingDatagramObserver) ObserveDatagram(datagram *model.CapturedDatagram) {
+	o.datagrams = append(o.datagrams, datagram)
+}
+
 func TestServer_ChannelBuffering(t *testing.T) {
 	logger := logger.NewLogger("test")
 	cfg := &config.ArtNet{
@@ -78,3 +90,34 @@ func TestServer_ChannelCapacity(t *testing.T) {
 	receivedChan := server.ReceivedChan()
 	assert.Equal(t, 2, cap(receivedChan))
 }
+
+func TestServer_DatagramObserver(t *testing.T) {
+	server := NewServer(logger.NewLogger("fatal"), &config.ArtNet{ChannelBufferSize: 10})
+	observer := &recordingDatagramObserver{}
+	server.SetDatagramObserver(observer)
+
+	conn, err := net.ListenPacket("udp4", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer conn.Close()
+	server.conn = conn
+	server.packetConn = ipv4.NewPacketConn(conn)
+	require.NoError(t, server.packetConn.SetControlMessage(ipv4.FlagDst, true))
+
+	sender, err := net.ListenPacket("udp4", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer sender.Close()
+	_, err = sender.WriteTo([]byte("Art-Net\x00"), conn.LocalAddr())
+	require.NoError(t, err)
+
+	require.NoError(t, server.processIncomingPackets(make([]byte, DefaultMaxPacketSize)))
+	require.Len(t, observer.datagrams, 1)
+	d := observer.datagrams[0]
+	assert.Equal(t, sender.LocalAddr().String(), d.Src.String())
+	assert.Equal(t, "127.0.0.1", d.Dst.IP.String())
+	assert.Equal(t, DefaultPort, d.Dst.Port)
+	assert.Equal(t, []byte("Art-Net\x00"), d.Payload)
+
+	// 受信チャネルにも渡す
+	received := <-server.ReceivedChan()
+	assert.Equal(t, d.Payload, received.Data)
+}
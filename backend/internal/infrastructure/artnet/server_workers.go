@@ -33,7 +33,7 @@ func (s *Server) runReceiver() {
 // processIncomingPackets 受信パケットを処理
 func (s *Server) processIncomingPackets(buffer []byte) error {
 	s.conn.SetReadDeadline(time.Now().Add(DefaultReadTimeout))
-	n, receivedAddr, err := s.conn.ReadFrom(buffer)
+	n, receivedAddr, dstIP, err := s.readFrom(buffer)
 	if err != nil {
 		if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
 			// タイムアウトの場合、done チャンネルをチェックしてから続行
@@ -57,13 +57,53 @@ func (s *Server) processIncomingPackets(buffer []byte) error {
 	s.recordReceivedPacket()
 
 	receivedPacket := model.ReceivedData{
-		Data: data,
-		Addr: receivedAddr,
+		Data:       data,
+		Addr:       receivedAddr,
+		ReceivedAt: time.Now(),
+	}
+
+	if s.datagramObserver != nil {
+		s.observeDatagram(receivedPacket, dstIP)
 	}
 
 	return s.sendToReceiveChannel(receivedPacket)
 }
 
+// readFrom パケットを受信する（宛先アドレスを取得できない場合は dstIP は nil）
+func (s *Server) readFrom(buffer []byte) (int, net.Addr, net.IP, error) {
+	if s.packetConn == nil {
+		n, addr, err := s.conn.ReadFrom(buffer)
+		return n, addr, nil, err
+	}
+	n, cm, addr, err := s.packetConn.ReadFrom(buffer)
+	if cm != nil {
+		return n, addr, cm.Dst, err
+	}
+	return n, addr, nil, err
+}
+
+// observeDatagram 受信したデータグラムをオブザーバーに渡す
+// 宛先アドレスを取得できない場合は選択したインターフェースのアドレス宛てとする
+func (s *Server) observeDatagram(received model.ReceivedData, dstIP net.IP) {
+	src, ok := received.Addr.(*net.UDPAddr)
+	if !ok {
+		return
+	}
+	if dstIP == nil {
+		if iface := s.iface.Load(); iface != nil {
+			dstIP = iface.IP
+		} else {
+			dstIP = net.IPv4zero
+		}
+	}
+	s.datagramObserver.ObserveDatagram(&model.CapturedDatagram{
+		Timestamp: received.ReceivedAt,
+		Src:       src,
+		Dst:       &net.UDPAddr{IP: dstIP, Port: s.port},
+		Payload:   received.Data,
+	})
+}
+
 // sendToReceiveChannel 受信チャンネルにパケットを送信
 func (s *Server) sendToReceiveChannel(packet model.ReceivedData) error {
 	select {
@@ -132,7 +172,7 @@ func (s *Server) runPollSender(pollTicker *time.Ticker) {
 		return
 	}
 
-	broadcastAddr := &net.UDPAddr{IP: net.IPv4bcast, Port: s.port}
+	broadcastAddr := s.BroadcastAddr()
 
 	for {
 		select {
@@ -1,29 +1,42 @@
 package infrastructure
 
 import (
+	"bytes"
+	"sort"
 	"sync"
+	"time"
 
 	"github.com/nasshu2916/dmx_viewer/internal/domain/model"
 )
 
+// maxNodeTransitions ノードごとに保持する状態遷移の最大件数
+const maxNodeTransitions = 100
+
+// maxTransitionHistories 状態遷移の履歴を保持するノードの最大数
+// 超えた場合は一覧から削除したノードのうち、最後の状態遷移が最も古いノードの履歴から削除する
+const maxTransitionHistories = 1024
+
 type ArtNetNodeRepositoryImpl struct {
-	mu    sync.RWMutex
-	nodes map[string]*model.ArtNetNode
+	mu          sync.RWMutex
+	nodes       map[string]*model.ArtNetNode // IPアドレスとBindIndexの組をキーとする
+	transitions map[string][]model.NodeTransition
 }
 
 func NewArtNetNodeRepository() *ArtNetNodeRepositoryImpl {
 	return &ArtNetNodeRepositoryImpl{
-		nodes: make(map[string]*model.ArtNetNode),
+		nodes:       make(map[string]*model.ArtNetNode),
+		transitions: make(map[string][]model.NodeTransition),
 	}
 }
 
 func (r *ArtNetNodeRepositoryImpl) Save(node *model.ArtNetNode) {
-	ip := node.IPAddress.String()
+	key := node.Key()
 	r.mu.Lock()
-	r.nodes[ip] = node
+	r.nodes[key] = node
 	r.mu.Unlock()
 }
 
+// All IPアドレス、BindIndexの順に並べたノードの一覧を返す
 func (r *ArtNetNodeRepositoryImpl) All() []*model.ArtNetNode {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
@@ -31,5 +44,62 @@ func (r *ArtNetNodeRepositoryImpl) All() []*model.ArtNetNode {
 	for _, n := range r.nodes {
 		result = append(result, n)
 	}
+	sort.Slice(result, func(i, j int) bool {
+		if c := bytes.Compare(result[i].IPAddress.To16(), result[j].IPAddress.To16()); c != 0 {
+			return c < 0
+		}
+		return result[i].BindIndex < result[j].BindIndex
+	})
 	return result
 }
+
+func (r *ArtNetNodeRepositoryImpl) Get(key string) (*model.ArtNetNode, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	node, ok := r.nodes[key]
+	return node, ok
+}
+
+func (r *ArtNetNodeRepositoryImpl) Delete(key string) {
+	r.mu.Lock()
+	delete(r.nodes, key)
+	r.mu.Unlock()
+}
+
+func (r *ArtNetNodeRepositoryImpl) AddTransition(transition model.NodeTransition) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	history := append(r.transitions[transition.NodeKey], transition)
+	if len(history) > maxNodeTransitions {
+		history = append([]model.NodeTransition(nil), history[len(history)-maxNodeTransitions:]...)
+	}
+	r.transitions[transition.NodeKey] = history
+	if len(r.transitions) > maxTransitionHistories {
+		r.evictTransitions()
+	}
+}
+
+// evictTransitions 一覧から削除したノードのうち、最後の状態遷移が最も古いノードの履歴を削除する
+func (r *ArtNetNodeRepositoryImpl) evictTransitions() {
+	var oldestKey string
+	var oldest time.Time
+	for key, history := range r.transitions {
+		if _, ok := r.nodes[key]; ok {
+			continue
+		}
+		last := history[len(history)-1].At
+		if oldestKey == "" || last.Before(oldest) {
+			oldestKey, oldest = key, last
+		}
+	}
+	if oldestKey != "" {
+		delete(r.transitions, oldestKey)
+	}
+}
+
+func (r *ArtNetNodeRepositoryImpl) Transitions(key string) ([]model.NodeTransition, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	history, ok := r.transitions[key]
+	return append([]model.NodeTransition(nil), history...), ok
+}
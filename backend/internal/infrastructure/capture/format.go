@@ -0,0 +1,227 @@
+// Package capture 受信したDMXフレームを記録するキャプチャファイル（.dmxcap）の読み書きを行う
+//
+// キャプチャファイルは追記のみで書き込むバイナリ形式で、すべての数値はビッグエンディアンとする。
+//
+// ファイルヘッダー（32バイト）
+//
+//	offset size
+//	0      8    マジックナンバー "DMXVCAP\x00"
+//	8      2    フォーマットのバージョン（2。バージョン1はアドレスごとの優先度を含まない）
+//	10     2    ファイルヘッダーの長さ（32。読み込み時は残りを読み飛ばす）
+//	12     8    記録を開始した時刻（Unix時刻のナノ秒）
+//	20     12   予約（0）
+//
+// レコード（ファイルヘッダーの後に続く）
+//
+//	offset size
+//	0      4    レコード長 N（この4バイトを除く。CRC を含む）
+//	4      1    レコードの種類（1 = DMXフレーム）
+//	5      1    プロトコル（1 = Art-Net, 2 = sACN）
+//	6      1    フラグ（bit0: ArtSync による同期出力、bit1: アドレスごとの優先度あり）
+//	7      1    START Code
+//	8      8    タイムスタンプ（記録開始からの経過時間のナノ秒。ファイル内で単調増加する）
+//	16     2    ユニバース
+//	18     16   送信元IPアドレス（IPv4 は IPv4-mapped IPv6 形式）
+//	34     2    送信元ポート
+//	36     16   送信元のCID（sACN のみ。Art-Net は0）
+//	52     1    シーケンス番号
+//	53     1    優先度（sACN のみ）
+//	54     1    物理入力ポート（Art-Net のみ）
+//	55     1    予約（0）
+//	56     2    スロット数 L（0-512）
+//	58     L    スロットデータ
+//	58+L   P    アドレスごとの優先度（sACN の START Code 0xDD。フラグの bit1 が1の場合は512バイト、それ以外は0バイト）
+//	58+L+P 4    CRC-32（IEEE）。offset 4 から 58+L+P までのバイト列に対する値
+//
+// レコードは書き込みの途中で異常終了しても、レコード長と CRC により末尾の不完全なレコードを検出できる。
+// 読み込み時は不完全なレコード以降を無視する。
+package capture
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"net"
+	"time"
+
+	"github.com/nasshu2916/dmx_viewer/internal/domain/model"
+	"github.com/nasshu2916/dmx_viewer/internal/infrastructure/sacn"
+)
+
+// FileExtension キャプチャファイルの拡張子
+const FileExtension = ".dmxcap"
+
+// Version 現在のフォーマットのバージョン
+const Version = 2
+
+// minVersion 読み込めるもっとも古いフォーマットのバージョン
+const minVersion = 1
+
+const (
+	fileHeaderSize    = 32
+	recordHeaderSize  = 58 // レコード長からスロット数までのサイズ
+	crcSize           = 4
+	priorityBlockSize = 512
+	maxRecordLength   = recordHeaderSize - 4 + 512 + priorityBlockSize + crcSize
+)
+
+// レコードの種類
+const (
+	recordTypeDMXFrame uint8 = 1
+)
+
+// プロトコル
+const (
+	protocolArtNet uint8 = 1
+	protocolSACN   uint8 = 2
+)
+
+// フラグ
+const (
+	flagSynced          uint8 = 0x01
+	flagChannelPriority uint8 = 0x02
+)
+
+var magic = [8]byte{'D', 'M', 'X', 'V', 'C', 'A', 'P', 0}
+
+// エラー定義
+var (
+	ErrInvalidHeader      = errors.New("invalid capture file header")
+	ErrUnsupportedVersion = errors.New("unsupported capture file version")
+	ErrCorruptRecord      = errors.New("corrupt capture record")
+)
+
+// Header キャプチャファイルのヘッダー
+type Header struct {
+	Version   uint16
+	StartedAt time.Time // 記録を開始した時刻
+}
+
+func encodeHeader(h Header) []byte {
+	b := make([]byte, fileHeaderSize)
+	copy(b[0:8], magic[:])
+	binary.BigEndian.PutUint16(b[8:10], h.Version)
+	binary.BigEndian.PutUint16(b[10:12], fileHeaderSize)
+	binary.BigEndian.PutUint64(b[12:20], uint64(h.StartedAt.UnixNano()))
+	return b
+}
+
+func decodeHeader(b []byte) (Header, int, error) {
+	if len(b) < 12 || [8]byte(b[0:8]) != magic {
+		return Header{}, 0, ErrInvalidHeader
+	}
+	h := Header{Version: binary.BigEndian.Uint16(b[8:10])}
+	if h.Version < minVersion || h.Version > Version {
+		return Header{}, 0, fmt.Errorf("%w: %d", ErrUnsupportedVersion, h.Version)
+	}
+	size := int(binary.BigEndian.Uint16(b[10:12]))
+	if size < 20 {
+		return Header{}, 0, ErrInvalidHeader
+	}
+	return h, size, nil
+}
+
+// encodeRecord DMXフレームをレコードにエンコードする
+func encodeRecord(offset time.Duration, frame *model.DMXFrame) ([]byte, error) {
+	if frame.Length > 512 {
+		return nil, fmt.Errorf("%w: length %d exceeds 512", ErrCorruptRecord, frame.Length)
+	}
+	length := int(frame.Length)
+	priorities := 0
+	if frame.ChannelPriority != nil {
+		priorities = priorityBlockSize
+	}
+	b := make([]byte, recordHeaderSize+length+priorities+crcSize)
+	binary.BigEndian.PutUint32(b[0:4], uint32(len(b)-4))
+	b[4] = recordTypeDMXFrame
+	b[5] = protocolArtNet
+	if frame.Protocol == model.ProtocolSACN {
+		b[5] = protocolSACN
+	}
+	if frame.Synced {
+		b[6] |= flagSynced
+	}
+	if frame.ChannelPriority != nil {
+		b[6] |= flagChannelPriority
+	}
+	b[7] = frame.StartCode
+	binary.BigEndian.PutUint64(b[8:16], uint64(offset))
+	binary.BigEndian.PutUint16(b[16:18], frame.Universe)
+	if ip := frame.SourceIP.To16(); ip != nil {
+		copy(b[18:34], ip)
+	}
+	binary.BigEndian.PutUint16(b[34:36], uint16(frame.SourcePort))
+	if cid, err := sacn.ParseCID(frame.SourceCID); err == nil {
+		copy(b[36:52], cid[:])
+	}
+	b[52] = frame.Sequence
+	b[53] = frame.Priority
+	b[54] = frame.Physical
+	binary.BigEndian.PutUint16(b[56:58], frame.Length)
+	copy(b[recordHeaderSize:], frame.Data[:length])
+	if frame.ChannelPriority != nil {
+		copy(b[recordHeaderSize+length:], frame.ChannelPriority[:])
+	}
+	end := recordHeaderSize + length + priorities
+	binary.BigEndian.PutUint32(b[end:], crc32.ChecksumIEEE(b[4:end]))
+	return b, nil
+}
+
+// decodeRecord レコード長を除いたレコードをデコードする（startedAt は受信時刻の復元に使用する）
+func decodeRecord(b []byte, startedAt time.Time) (*model.CaptureRecord, error) {
+	if len(b) < recordHeaderSize-4+crcSize {
+		return nil, fmt.Errorf("%w: record too short", ErrCorruptRecord)
+	}
+	body, sum := b[:len(b)-crcSize], binary.BigEndian.Uint32(b[len(b)-crcSize:])
+	if crc32.ChecksumIEEE(body) != sum {
+		return nil, fmt.Errorf("%w: checksum mismatch", ErrCorruptRecord)
+	}
+	if body[0] != recordTypeDMXFrame {
+		return nil, fmt.Errorf("%w: unknown record type %d", ErrCorruptRecord, body[0])
+	}
+
+	// 以降の offset はレコード長の4バイトを除いた位置
+	length := int(binary.BigEndian.Uint16(body[52:54]))
+	priorities := 0
+	if body[2]&flagChannelPriority != 0 {
+		priorities = priorityBlockSize
+	}
+	if length > 512 || len(body) != recordHeaderSize-4+length+priorities {
+		return nil, fmt.Errorf("%w: invalid slot count %d", ErrCorruptRecord, length)
+	}
+	offset := time.Duration(binary.BigEndian.Uint64(body[4:12]))
+	frame := &model.DMXFrame{
+		Protocol:   model.ProtocolArtNet,
+		StartCode:  body[3],
+		Synced:     body[2]&flagSynced != 0,
+		Universe:   binary.BigEndian.Uint16(body[12:14]),
+		SourceIP:   sourceIP(body[14:30]),
+		SourcePort: int(binary.BigEndian.Uint16(body[30:32])),
+		Sequence:   body[48],
+		Priority:   body[49],
+		Physical:   body[50],
+		Length:     uint16(length),
+		ReceivedAt: startedAt.Add(offset),
+	}
+	if body[1] == protocolSACN {
+		frame.Protocol = model.ProtocolSACN
+		frame.SourceCID = sacn.FormatCID([16]byte(body[32:48]))
+	}
+	copy(frame.Data[:], body[recordHeaderSize-4:recordHeaderSize-4+length])
+	if priorities > 0 {
+		var channelPriority [512]uint8
+		copy(channelPriority[:], body[recordHeaderSize-4+length:])
+		frame.ChannelPriority = &channelPriority
+	}
+	return &model.CaptureRecord{Offset: offset, Frame: frame}, nil
+}
+
+func sourceIP(b []byte) net.IP {
+	ip := make(net.IP, net.IPv6len)
+	copy(ip, b)
+	if v4 := ip.To4(); v4 != nil {
+		return v4
+	}
+	return ip
+}
@@ -0,0 +1,138 @@
+package capture
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/nasshu2916/dmx_viewer/internal/domain/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriterReader_RoundTrip(t *testing.T) {
+	startedAt := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	artNetFrame := &model.DMXFrame{
+		Protocol:   model.ProtocolArtNet,
+		Universe:   0x0203,
+		Length:     4,
+		Data:       [512]uint8{1, 2, 3, 4},
+		SourceIP:   net.IPv4(2, 0, 0, 10),
+		SourcePort: 6454,
+		Sequence:   7,
+		Physical:   1,
+		Synced:     true,
+		ReceivedAt: startedAt.Add(10 * time.Millisecond),
+	}
+	sacnFrame := &model.DMXFrame{
+		Protocol:   model.ProtocolSACN,
+		Universe:   40000,
+		Length:     512,
+		SourceIP:   net.ParseIP("fe80::1"),
+		SourcePort: 5568,
+		SourceCID:  "01020304-0506-0708-090a-0b0c0d0e0f10",
+		Priority:   150,
+		// 前のフレームより前の受信時刻は前のフレームと同じ時刻として記録する
+		ReceivedAt: startedAt.Add(5 * time.Millisecond),
+	}
+	sacnFrame.Data[511] = 255
+	var priorities [512]uint8
+	priorities[0], priorities[511] = 200, 50
+	sacnFrame.ChannelPriority = &priorities
+
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, startedAt)
+	require.NoError(t, err)
+	require.NoError(t, w.WriteFrame(artNetFrame))
+	require.NoError(t, w.WriteFrame(sacnFrame))
+	assert.Equal(t, int64(buf.Len()), w.Written())
+
+	r, err := NewReader(bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+	assert.Equal(t, uint16(Version), r.Header().Version)
+	assert.True(t, startedAt.Equal(r.Header().StartedAt))
+
+	first, err := r.Next()
+	require.NoError(t, err)
+	assert.Equal(t, 10*time.Millisecond, first.Offset)
+	assert.Equal(t, model.ProtocolArtNet, first.Frame.Protocol)
+	assert.Equal(t, uint16(0x0203), first.Frame.Universe)
+	assert.Equal(t, []uint8{1, 2, 3, 4}, first.Frame.Data[:first.Frame.Length])
+	assert.Equal(t, "2.0.0.10", first.Frame.SourceIP.String())
+	assert.Equal(t, 6454, first.Frame.SourcePort)
+	assert.Equal(t, uint8(7), first.Frame.Sequence)
+	assert.Equal(t, uint8(1), first.Frame.Physical)
+	assert.True(t, first.Frame.Synced)
+	assert.Nil(t, first.Frame.ChannelPriority)
+	assert.Empty(t, first.Frame.SourceCID)
+	assert.True(t, artNetFrame.ReceivedAt.Equal(first.Frame.ReceivedAt))
+
+	second, err := r.Next()
+	require.NoError(t, err)
+	assert.Equal(t, 10*time.Millisecond, second.Offset)
+	assert.Equal(t, model.ProtocolSACN, second.Frame.Protocol)
+	assert.Equal(t, uint16(40000), second.Frame.Universe)
+	assert.Equal(t, uint16(512), second.Frame.Length)
+	assert.Equal(t, uint8(255), second.Frame.Data[511])
+	assert.Equal(t, "fe80::1", second.Frame.SourceIP.String())
+	assert.Equal(t, sacnFrame.SourceCID, second.Frame.SourceCID)
+	assert.Equal(t, uint8(150), second.Frame.Priority)
+	require.NotNil(t, second.Frame.ChannelPriority)
+	assert.Equal(t, priorities, *second.Frame.ChannelPriority)
+
+	_, err = r.Next()
+	assert.ErrorIs(t, err, io.EOF)
+}
+
+func TestReader_ReadsVersion1(t *testing.T) {
+	startedAt := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	frame := &model.DMXFrame{Protocol: model.ProtocolArtNet, Universe: 1, Length: 2, Data: [512]uint8{10, 20}, ReceivedAt: startedAt}
+	record, err := encodeRecord(0, frame)
+	require.NoError(t, err)
+	file := append(encodeHeader(Header{Version: 1, StartedAt: startedAt}), record...)
+
+	r, err := NewReader(bytes.NewReader(file))
+	require.NoError(t, err)
+	assert.Equal(t, uint16(1), r.Header().Version)
+	records, err := r.ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, []uint8{10, 20}, records[0].Frame.Data[:2])
+
+	_, err = NewReader(bytes.NewReader(encodeHeader(Header{Version: Version + 1, StartedAt: startedAt})))
+	assert.ErrorIs(t, err, ErrUnsupportedVersion)
+}
+
+func TestReader_TruncatedAndCorruptRecords(t *testing.T) {
+	startedAt := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, startedAt)
+	require.NoError(t, err)
+	for i := 0; i < 3; i++ {
+		frame := &model.DMXFrame{Protocol: model.ProtocolArtNet, Universe: uint16(i), Length: 2, ReceivedAt: startedAt}
+		require.NoError(t, w.WriteFrame(frame))
+	}
+	data := buf.Bytes()
+
+	// 書き込み途中で終了した末尾のレコードは無視する
+	r, err := NewReader(bytes.NewReader(data[:len(data)-3]))
+	require.NoError(t, err)
+	records, err := r.ReadAll()
+	require.NoError(t, err)
+	assert.Len(t, records, 2)
+
+	// 内容が壊れたレコードは検出する
+	corrupt := append([]byte(nil), data...)
+	corrupt[len(corrupt)-5] ^= 0xff
+	r, err = NewReader(bytes.NewReader(corrupt))
+	require.NoError(t, err)
+	records, err = r.ReadAll()
+	assert.True(t, errors.Is(err, ErrCorruptRecord))
+	assert.Len(t, records, 2)
+
+	_, err = NewReader(bytes.NewReader([]byte("not a capture file")))
+	assert.ErrorIs(t, err, ErrInvalidHeader)
+}
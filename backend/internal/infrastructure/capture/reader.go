@@ -0,0 +1,80 @@
+package capture
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/nasshu2916/dmx_viewer/internal/domain/model"
+)
+
+// Reader キャプチャファイルからレコードを順に読み込む
+type Reader struct {
+	r      *bufio.Reader
+	header Header
+}
+
+// NewReader ファイルヘッダーを読み込み、Readerを作成する
+func NewReader(r io.Reader) (*Reader, error) {
+	br := bufio.NewReader(r)
+	prefix := make([]byte, 20)
+	if _, err := io.ReadFull(br, prefix); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidHeader, err)
+	}
+	header, size, err := decodeHeader(prefix)
+	if err != nil {
+		return nil, err
+	}
+	header.StartedAt = time.Unix(0, int64(binary.BigEndian.Uint64(prefix[12:20])))
+	if _, err := br.Discard(size - len(prefix)); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidHeader, err)
+	}
+	return &Reader{r: br, header: header}, nil
+}
+
+// Header ファイルヘッダー
+func (r *Reader) Header() Header {
+	return r.header
+}
+
+// Next 次のレコードを読み込む
+// ファイルの終端では io.EOF を返す。書き込み途中で終了した不完全なレコードは io.ErrUnexpectedEOF、
+// 内容が壊れたレコードは ErrCorruptRecord を返す（いずれの場合もそれ以降は読み込めない）
+func (r *Reader) Next() (*model.CaptureRecord, error) {
+	var lengthBuf [4]byte
+	if _, err := io.ReadFull(r.r, lengthBuf[:]); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, io.EOF
+		}
+		return nil, io.ErrUnexpectedEOF
+	}
+	length := binary.BigEndian.Uint32(lengthBuf[:])
+	if length > maxRecordLength {
+		return nil, fmt.Errorf("%w: record length %d", ErrCorruptRecord, length)
+	}
+
+	b := make([]byte, length)
+	if _, err := io.ReadFull(r.r, b); err != nil {
+		return nil, io.ErrUnexpectedEOF
+	}
+	return decodeRecord(b, r.header.StartedAt)
+}
+
+// ReadAll 読み込めるすべてのレコードを返す
+// 末尾の不完全なレコード（異常終了時に書き込み途中だったもの）は無視する
+func (r *Reader) ReadAll() ([]*model.CaptureRecord, error) {
+	var records []*model.CaptureRecord
+	for {
+		record, err := r.Next()
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return records, nil
+		}
+		if err != nil {
+			return records, err
+		}
+		records = append(records, record)
+	}
+}
@@ -0,0 +1,61 @@
+package capture
+
+import (
+	"io"
+	"time"
+
+	"github.com/nasshu2916/dmx_viewer/internal/domain/model"
+)
+
+// Writer キャプチャファイルの形式でDMXフレームを書き込む
+type Writer struct {
+	w         io.Writer
+	startedAt time.Time
+	last      time.Duration
+	written   int64
+}
+
+// NewWriter ファイルヘッダーを書き込み、Writerを作成する
+// startedAt は単調時計の値を含む時刻（time.Now の戻り値）を指定する
+func NewWriter(w io.Writer, startedAt time.Time) (*Writer, error) {
+	header := encodeHeader(Header{Version: Version, StartedAt: startedAt})
+	n, err := w.Write(header)
+	if err != nil {
+		return nil, err
+	}
+	return &Writer{w: w, startedAt: startedAt, written: int64(n)}, nil
+}
+
+// WriteFrame フレームを受信時刻のレコードとして書き込む
+// タイムスタンプがファイル内で単調増加するよう、前のレコードより前の時刻は前のレコードと同じ時刻とする
+func (w *Writer) WriteFrame(frame *model.DMXFrame) error {
+	offset := frame.ReceivedAt.Sub(w.startedAt)
+	if frame.ReceivedAt.IsZero() {
+		offset = time.Since(w.startedAt)
+	}
+	if offset < w.last {
+		offset = w.last
+	}
+
+	b, err := encodeRecord(offset, frame)
+	if err != nil {
+		return err
+	}
+	n, err := w.w.Write(b)
+	w.written += int64(n)
+	if err != nil {
+		return err
+	}
+	w.last = offset
+	return nil
+}
+
+// StartedAt 記録を開始した時刻
+func (w *Writer) StartedAt() time.Time {
+	return w.startedAt
+}
+
+// Written ファイルヘッダーを含む書き込んだバイト数
+func (w *Writer) Written() int64 {
+	return w.written
+}
@@ -0,0 +1,285 @@
+package infrastructure
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/nasshu2916/dmx_viewer/internal/domain/model"
+	"github.com/nasshu2916/dmx_viewer/internal/domain/repository"
+	"github.com/nasshu2916/dmx_viewer/internal/infrastructure/capture"
+)
+
+// ErrCaptureNotOpen 記録を開始していない
+var ErrCaptureNotOpen = errors.New("capture file is not open")
+
+// CaptureRepositoryImpl キャプチャファイルをディレクトリに作成し、サイズ・時間の上限でローテーションする
+type CaptureRepositoryImpl struct {
+	mu          sync.Mutex
+	dir         string
+	maxBytes    int64         // 1ファイルの最大サイズ（0の場合は無制限）
+	maxDuration time.Duration // 1ファイルの最大記録時間（0の場合は無制限）
+	file        *os.File
+	buf         *bufio.Writer
+	writer      *capture.Writer
+	files       []model.CaptureFile
+	now         func() time.Time
+}
+
+// NewCaptureRepository 保存先のディレクトリとローテーションの上限を指定して作成する
+func NewCaptureRepository(dir string, maxBytes int64, maxDuration time.Duration) *CaptureRepositoryImpl {
+	return &CaptureRepositoryImpl{
+		dir:         dir,
+		maxBytes:    maxBytes,
+		maxDuration: maxDuration,
+		now:         time.Now,
+	}
+}
+
+func (r *CaptureRepositoryImpl) Open(startedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.closeLocked(); err != nil {
+		return err
+	}
+	r.files = nil
+	return r.openLocked(startedAt)
+}
+
+func (r *CaptureRepositoryImpl) Append(frame *model.DMXFrame) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.writer == nil {
+		return ErrCaptureNotOpen
+	}
+	if r.shouldRotateLocked(frame) {
+		if err := r.closeLocked(); err != nil {
+			return err
+		}
+		// 変換したキャプチャなど過去の時刻のフレームでも記録開始からの経過時間が正しくなるよう、フレームの時刻から始める
+		startedAt := frame.ReceivedAt
+		if startedAt.IsZero() {
+			startedAt = r.now()
+		}
+		if err := r.openLocked(startedAt); err != nil {
+			return err
+		}
+	}
+	err := r.writer.WriteFrame(frame)
+	r.files[len(r.files)-1].Size = r.writer.Written()
+	return err
+}
+
+func (r *CaptureRepositoryImpl) Flush() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.flushLocked()
+}
+
+func (r *CaptureRepositoryImpl) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.closeLocked()
+}
+
+func (r *CaptureRepositoryImpl) Files() []model.CaptureFile {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]model.CaptureFile(nil), r.files...)
+}
+
+func (r *CaptureRepositoryImpl) List() ([]model.CaptureFile, error) {
+	entries, err := os.ReadDir(r.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return []model.CaptureFile{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read capture directory %s: %w", r.dir, err)
+	}
+
+	files := make([]model.CaptureFile, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != capture.FileExtension {
+			continue
+		}
+		file, err := r.stat(entry.Name())
+		if err != nil {
+			// 読み込めないファイルは一覧に含めない
+			continue
+		}
+		files = append(files, file)
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].StartedAt.Before(files[j].StartedAt) })
+	return files, nil
+}
+
+func (r *CaptureRepositoryImpl) Get(name string) (model.CaptureFile, error) {
+	if _, err := r.path(name); err != nil {
+		return model.CaptureFile{}, err
+	}
+	return r.stat(name)
+}
+
+func (r *CaptureRepositoryImpl) Load(name string) (model.CaptureFile, []*model.CaptureRecord, error) {
+	path, err := r.path(name)
+	if err != nil {
+		return model.CaptureFile{}, nil, err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return model.CaptureFile{}, nil, err
+	}
+	defer f.Close()
+
+	reader, err := capture.NewReader(f)
+	if err != nil {
+		return model.CaptureFile{}, nil, fmt.Errorf("failed to read capture file %s: %w", name, err)
+	}
+	records, err := reader.ReadAll()
+	if err != nil {
+		return model.CaptureFile{}, nil, fmt.Errorf("failed to read capture file %s: %w", name, err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return model.CaptureFile{}, nil, err
+	}
+	file := model.CaptureFile{Name: name, Path: path, Size: info.Size(), StartedAt: reader.Header().StartedAt}
+	return file, records, nil
+}
+
+// captureRecordReader 開いたキャプチャファイルとそのReader
+type captureRecordReader struct {
+	*capture.Reader
+	file *os.File
+}
+
+func (r *captureRecordReader) Close() error {
+	return r.file.Close()
+}
+
+func (r *CaptureRepositoryImpl) Stream(name string) (model.CaptureFile, repository.CaptureRecordReader, error) {
+	path, err := r.path(name)
+	if err != nil {
+		return model.CaptureFile{}, nil, err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return model.CaptureFile{}, nil, err
+	}
+	reader, err := capture.NewReader(bufio.NewReader(f))
+	if err != nil {
+		f.Close()
+		return model.CaptureFile{}, nil, fmt.Errorf("failed to read capture file %s: %w", name, err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return model.CaptureFile{}, nil, err
+	}
+	file := model.CaptureFile{Name: name, Path: path, Size: info.Size(), StartedAt: reader.Header().StartedAt}
+	return file, &captureRecordReader{Reader: reader, file: f}, nil
+}
+
+// stat ファイルヘッダーを読み込んでキャプチャファイルの情報を取得する
+func (r *CaptureRepositoryImpl) stat(name string) (model.CaptureFile, error) {
+	path := filepath.Join(r.dir, name)
+	f, err := os.Open(path)
+	if err != nil {
+		return model.CaptureFile{}, err
+	}
+	defer f.Close()
+
+	reader, err := capture.NewReader(f)
+	if err != nil {
+		return model.CaptureFile{}, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return model.CaptureFile{}, err
+	}
+	return model.CaptureFile{Name: name, Path: path, Size: info.Size(), StartedAt: reader.Header().StartedAt}, nil
+}
+
+// path 保存先のディレクトリ内のファイル名からパスを作成する（ディレクトリの外を指す名前は存在しないものとして扱う）
+func (r *CaptureRepositoryImpl) path(name string) (string, error) {
+	if name == "" || name != filepath.Base(name) || filepath.Ext(name) != capture.FileExtension {
+		return "", fmt.Errorf("invalid capture file name %q: %w", name, fs.ErrNotExist)
+	}
+	return filepath.Join(r.dir, name), nil
+}
+
+// shouldRotateLocked 現在のファイルがサイズ・時間の上限に達しているか
+func (r *CaptureRepositoryImpl) shouldRotateLocked(frame *model.DMXFrame) bool {
+	if r.maxBytes > 0 && r.writer.Written() >= r.maxBytes {
+		return true
+	}
+	if r.maxDuration > 0 && frame.ReceivedAt.Sub(r.writer.StartedAt()) >= r.maxDuration {
+		return true
+	}
+	return false
+}
+
+// openLocked 新しいキャプチャファイルを作成してファイルヘッダーを書き込む
+func (r *CaptureRepositoryImpl) openLocked(startedAt time.Time) error {
+	if err := os.MkdirAll(r.dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create capture directory %s: %w", r.dir, err)
+	}
+
+	base := "capture-" + startedAt.Format("20060102-150405.000")
+	var file *os.File
+	var err error
+	for i := 0; ; i++ {
+		name := base + capture.FileExtension
+		if i > 0 {
+			name = fmt.Sprintf("%s-%d%s", base, i, capture.FileExtension)
+		}
+		file, err = os.OpenFile(filepath.Join(r.dir, name), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+		if !errors.Is(err, os.ErrExist) {
+			break
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create capture file: %w", err)
+	}
+
+	buf := bufio.NewWriter(file)
+	writer, err := capture.NewWriter(buf, startedAt)
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write capture header: %w", err)
+	}
+	r.file, r.buf, r.writer = file, buf, writer
+	r.files = append(r.files, model.CaptureFile{Name: filepath.Base(file.Name()), Path: file.Name(), Size: writer.Written(), StartedAt: startedAt})
+	return nil
+}
+
+// flushLocked バッファの内容を書き込み、異常終了時にも失われないようディスクに同期する
+func (r *CaptureRepositoryImpl) flushLocked() error {
+	if r.buf == nil {
+		return nil
+	}
+	if err := r.buf.Flush(); err != nil {
+		return err
+	}
+	return r.file.Sync()
+}
+
+func (r *CaptureRepositoryImpl) closeLocked() error {
+	if r.file == nil {
+		return nil
+	}
+	err := r.flushLocked()
+	if closeErr := r.file.Close(); err == nil {
+		err = closeErr
+	}
+	r.file, r.buf, r.writer = nil, nil, nil
+	return err
+}
@@ -0,0 +1,157 @@
+// Package export チャンネルの値の時系列を CSV / NDJSON（JSON Lines）形式で書き出す
+//
+// どちらの形式も1行に1時刻分の選択したチャンネルの値を書き出す。
+// CSV は先頭にヘッダー行（time,elapsed,source,ch1,ch2,...）を書き込む。
+package export
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/nasshu2916/dmx_viewer/internal/domain/model"
+)
+
+// Writer 行を指定した形式で書き出す
+//
+// ヘッダーは最初の行を書き込むとき（行がない場合は Flush）に書き込むため、
+// Started が false の間は出力先に何も書き込んでいない。
+type Writer struct {
+	format       model.ExportFormat
+	firstChannel int
+	lastChannel  int
+	buf          *bufio.Writer
+	csv          *csv.Writer
+	started      bool
+	record       []string
+	line         []byte
+}
+
+// NewWriter 選択したチャンネル（1-512）の値を書き出すWriterを作成する
+func NewWriter(w io.Writer, format model.ExportFormat, firstChannel, lastChannel int) (*Writer, error) {
+	if firstChannel < 1 || lastChannel > 512 || firstChannel > lastChannel {
+		return nil, fmt.Errorf("invalid channel range %d-%d", firstChannel, lastChannel)
+	}
+	writer := &Writer{format: format, firstChannel: firstChannel, lastChannel: lastChannel}
+	switch format {
+	case model.ExportFormatCSV:
+		writer.csv = csv.NewWriter(w)
+	case model.ExportFormatNDJSON:
+		writer.buf = bufio.NewWriter(w)
+	default:
+		return nil, fmt.Errorf("unknown export format %q", format)
+	}
+	return writer, nil
+}
+
+// ContentType 形式に対応する Content-Type
+func ContentType(format model.ExportFormat) string {
+	if format == model.ExportFormatNDJSON {
+		return "application/x-ndjson"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// FileExtension 形式に対応する拡張子
+func FileExtension(format model.ExportFormat) string {
+	if format == model.ExportFormatNDJSON {
+		return ".ndjson"
+	}
+	return ".csv"
+}
+
+// Started ヘッダーまたは行を書き込んだか
+func (w *Writer) Started() bool {
+	return w.started
+}
+
+// WriteRow 1行を書き込む（値の数は選択したチャンネルの数と一致する必要がある）
+func (w *Writer) WriteRow(row *model.ExportRow) error {
+	if len(row.Values) != w.lastChannel-w.firstChannel+1 {
+		return fmt.Errorf("row has %d values, want %d", len(row.Values), w.lastChannel-w.firstChannel+1)
+	}
+	if err := w.start(); err != nil {
+		return err
+	}
+	if w.csv != nil {
+		return w.writeCSV(row)
+	}
+	return w.writeNDJSON(row)
+}
+
+// Flush バッファの内容を出力先に書き込む
+func (w *Writer) Flush() error {
+	if err := w.start(); err != nil {
+		return err
+	}
+	if w.csv != nil {
+		w.csv.Flush()
+		return w.csv.Error()
+	}
+	return w.buf.Flush()
+}
+
+func (w *Writer) start() error {
+	if w.started {
+		return nil
+	}
+	w.started = true
+	if w.csv == nil {
+		return nil
+	}
+	header := []string{"time", "elapsed", "source"}
+	for ch := w.firstChannel; ch <= w.lastChannel; ch++ {
+		header = append(header, "ch"+strconv.Itoa(ch))
+	}
+	return w.csv.Write(header)
+}
+
+func (w *Writer) writeCSV(row *model.ExportRow) error {
+	w.record = append(w.record[:0],
+		row.Time.Format(time.RFC3339Nano),
+		formatSeconds(row.Elapsed),
+		row.Source,
+	)
+	for _, v := range row.Values {
+		w.record = append(w.record, strconv.FormatFloat(v, 'f', -1, 64))
+	}
+	return w.csv.Write(w.record)
+}
+
+// writeNDJSON {"Time":"...","Elapsed":0.5,"Source":"...","Values":{"1":255,...}} の形式で書き込む
+// Values のキーはチャンネル番号の順とする
+func (w *Writer) writeNDJSON(row *model.ExportRow) error {
+	source, err := json.Marshal(row.Source)
+	if err != nil {
+		return err
+	}
+	b := append(w.line[:0], `{"Time":"`...)
+	b = row.Time.AppendFormat(b, time.RFC3339Nano)
+	b = append(b, `","Elapsed":`...)
+	b = append(b, formatSeconds(row.Elapsed)...)
+	b = append(b, `,"Source":`...)
+	b = append(b, source...)
+	b = append(b, `,"Values":{`...)
+	for i, v := range row.Values {
+		if i > 0 {
+			b = append(b, ',')
+		}
+		b = append(b, '"')
+		b = strconv.AppendInt(b, int64(w.firstChannel+i), 10)
+		b = append(b, `":`...)
+		b = strconv.AppendFloat(b, v, 'f', -1, 64)
+	}
+	b = append(b, "}}\n"...)
+	w.line = b
+	_, err = w.buf.Write(b)
+	return err
+}
+
+// formatSeconds 経過時間を秒で表す
+func formatSeconds(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'f', -1, 64)
+}
@@ -0,0 +1,74 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nasshu2916/dmx_viewer/internal/domain/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testRows = []*model.ExportRow{
+	{Time: time.Date(2024, 5, 1, 19, 30, 0, 0, time.UTC), Source: "2.0.0.10", Values: []float64{255, 0}},
+	{Time: time.Date(2024, 5, 1, 19, 30, 0, 500000000, time.UTC), Elapsed: 500 * time.Millisecond, Source: "merged", Values: []float64{127.5, 3}},
+}
+
+func TestWriter_CSV(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, model.ExportFormatCSV, 10, 11)
+	require.NoError(t, err)
+	for _, row := range testRows {
+		require.NoError(t, w.WriteRow(row))
+	}
+	require.NoError(t, w.Flush())
+
+	assert.Equal(t, strings.Join([]string{
+		"time,elapsed,source,ch10,ch11",
+		"2024-05-01T19:30:00Z,0,2.0.0.10,255,0",
+		"2024-05-01T19:30:00.5Z,0.5,merged,127.5,3",
+		"",
+	}, "\n"), buf.String())
+}
+
+func TestWriter_NDJSON(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, model.ExportFormatNDJSON, 10, 11)
+	require.NoError(t, err)
+	for _, row := range testRows {
+		require.NoError(t, w.WriteRow(row))
+	}
+	require.NoError(t, w.Flush())
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	require.Len(t, lines, 2)
+	assert.Equal(t, `{"Time":"2024-05-01T19:30:00Z","Elapsed":0,"Source":"2.0.0.10","Values":{"10":255,"11":0}}`, lines[0])
+	var decoded struct {
+		Time    time.Time
+		Elapsed float64
+		Source  string
+		Values  map[string]float64
+	}
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &decoded))
+	assert.Equal(t, 0.5, decoded.Elapsed)
+	assert.Equal(t, map[string]float64{"10": 127.5, "11": 3}, decoded.Values)
+}
+
+func TestWriter_HeaderOnly(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, model.ExportFormatCSV, 1, 1)
+	require.NoError(t, err)
+	assert.False(t, w.Started())
+	require.NoError(t, w.Flush())
+	assert.True(t, w.Started())
+	assert.Equal(t, "time,elapsed,source,ch1\n", buf.String())
+
+	assert.Error(t, w.WriteRow(&model.ExportRow{Values: []float64{1, 2}}))
+	_, err = NewWriter(&buf, model.ExportFormatCSV, 0, 1)
+	assert.Error(t, err)
+	_, err = NewWriter(&buf, "xml", 1, 1)
+	assert.Error(t, err)
+}
@@ -0,0 +1,87 @@
+package metrics
+
+import (
+	"strconv"
+
+	"github.com/nasshu2916/dmx_viewer/internal/domain/model"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// BridgeStatsProvider Art-Net と sACN の間の転送状況を提供するインターフェース
+type BridgeStatsProvider interface {
+	BridgeStats() []model.BridgeRouteStats
+}
+
+// BridgeMetricsCollector はプロトコル間ブリッジの転送状況を direction/input/output ラベル付きで公開する Collector
+type BridgeMetricsCollector struct {
+	provider BridgeStatsProvider
+
+	framesInDesc   *prometheus.Desc
+	framesOutDesc  *prometheus.Desc
+	keepAlivesDesc *prometheus.Desc
+	sendErrorsDesc *prometheus.Desc
+	sourcesDesc    *prometheus.Desc
+	activeDesc     *prometheus.Desc
+}
+
+func NewBridgeMetricsCollector(provider BridgeStatsProvider) *BridgeMetricsCollector {
+	labels := []string{"direction", "input", "output"}
+	return &BridgeMetricsCollector{
+		provider: provider,
+		framesInDesc: prometheus.NewDesc(
+			"dmx_bridge_input_frames_total",
+			"Number of DMX frames received on the bridge input universe",
+			labels, nil,
+		),
+		framesOutDesc: prometheus.NewDesc(
+			"dmx_bridge_output_frames_total",
+			"Number of DMX frames sent on the bridge output universe (including keep-alive refreshes)",
+			labels, nil,
+		),
+		keepAlivesDesc: prometheus.NewDesc(
+			"dmx_bridge_keepalive_frames_total",
+			"Number of DMX frames re-sent on the bridge output universe while the input was unchanged",
+			labels, nil,
+		),
+		sendErrorsDesc: prometheus.NewDesc(
+			"dmx_bridge_send_errors_total",
+			"Number of bridge output frames that failed to send",
+			labels, nil,
+		),
+		sourcesDesc: prometheus.NewDesc(
+			"dmx_bridge_input_sources",
+			"Number of sources currently sending the bridge input universe",
+			labels, nil,
+		),
+		activeDesc: prometheus.NewDesc(
+			"dmx_bridge_route_active",
+			"1 if the bridge route is currently outputting, else 0",
+			labels, nil,
+		),
+	}
+}
+
+func (c *BridgeMetricsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.framesInDesc
+	ch <- c.framesOutDesc
+	ch <- c.keepAlivesDesc
+	ch <- c.sendErrorsDesc
+	ch <- c.sourcesDesc
+	ch <- c.activeDesc
+}
+
+func (c *BridgeMetricsCollector) Collect(ch chan<- prometheus.Metric) {
+	for _, stats := range c.provider.BridgeStats() {
+		direction, input, output := string(stats.Direction), strconv.Itoa(int(stats.Input)), strconv.Itoa(int(stats.Output))
+		active := 0.0
+		if stats.Active {
+			active = 1
+		}
+		ch <- prometheus.MustNewConstMetric(c.framesInDesc, prometheus.CounterValue, float64(stats.FramesIn), direction, input, output)
+		ch <- prometheus.MustNewConstMetric(c.framesOutDesc, prometheus.CounterValue, float64(stats.FramesOut), direction, input, output)
+		ch <- prometheus.MustNewConstMetric(c.keepAlivesDesc, prometheus.CounterValue, float64(stats.KeepAlives), direction, input, output)
+		ch <- prometheus.MustNewConstMetric(c.sendErrorsDesc, prometheus.CounterValue, float64(stats.SendErrors), direction, input, output)
+		ch <- prometheus.MustNewConstMetric(c.sourcesDesc, prometheus.GaugeValue, float64(stats.Sources), direction, input, output)
+		ch <- prometheus.MustNewConstMetric(c.activeDesc, prometheus.GaugeValue, active, direction, input, output)
+	}
+}
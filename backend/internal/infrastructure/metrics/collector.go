@@ -140,7 +140,7 @@ func (c *ArtNetMetricsCollector) Collect(ch chan<- prometheus.Metric) {
 }
 
 // BuildRegistry は専用の Registry を作成し、標準 Collector と ArtNet Collector を登録して返す
-func BuildRegistry(server *artnet.Server) *prometheus.Registry {
+func BuildRegistry(server *artnet.Server, sequenceStats SequenceStatsProvider, bridgeStats BridgeStatsProvider) *prometheus.Registry {
 	reg := prometheus.NewRegistry()
 	// 標準Collector
 	_ = reg.Register(collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}))
@@ -148,5 +148,7 @@ func BuildRegistry(server *artnet.Server) *prometheus.Registry {
 	_ = reg.Register(collectors.NewBuildInfoCollector())
 	// カスタムCollector
 	_ = reg.Register(NewArtNetMetricsCollector(server))
+	_ = reg.Register(NewSequenceMetricsCollector(sequenceStats))
+	_ = reg.Register(NewBridgeMetricsCollector(bridgeStats))
 	return reg
 }
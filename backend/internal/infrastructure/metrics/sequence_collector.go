@@ -0,0 +1,83 @@
+package metrics
+
+import (
+	"strconv"
+
+	"github.com/nasshu2916/dmx_viewer/internal/domain/model"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// SequenceStatsProvider 送信元・ユニバースごとのシーケンス統計を提供するインターフェース
+type SequenceStatsProvider interface {
+	Snapshot() []model.SequenceStats
+}
+
+// SequenceMetricsCollector は ArtDMX のシーケンス統計を source/universe ラベル付きで公開する Collector
+type SequenceMetricsCollector struct {
+	provider SequenceStatsProvider
+
+	receivedDesc    *prometheus.Desc
+	lostDesc        *prometheus.Desc
+	reorderedDesc   *prometheus.Desc
+	duplicatesDesc  *prometheus.Desc
+	unsequencedDesc *prometheus.Desc
+	lossPercentDesc *prometheus.Desc
+}
+
+func NewSequenceMetricsCollector(provider SequenceStatsProvider) *SequenceMetricsCollector {
+	labels := []string{"source", "universe"}
+	return &SequenceMetricsCollector{
+		provider: provider,
+		receivedDesc: prometheus.NewDesc(
+			"dmx_artnet_dmx_packets_total",
+			"Number of ArtDMX packets received per source and universe",
+			labels, nil,
+		),
+		lostDesc: prometheus.NewDesc(
+			"dmx_artnet_dmx_lost_packets_total",
+			"Estimated number of ArtDMX packets lost (sequence gaps) per source and universe",
+			labels, nil,
+		),
+		reorderedDesc: prometheus.NewDesc(
+			"dmx_artnet_dmx_reordered_packets_total",
+			"Number of ArtDMX packets received out of order per source and universe",
+			labels, nil,
+		),
+		duplicatesDesc: prometheus.NewDesc(
+			"dmx_artnet_dmx_duplicate_packets_total",
+			"Number of ArtDMX packets with a repeated sequence number per source and universe",
+			labels, nil,
+		),
+		unsequencedDesc: prometheus.NewDesc(
+			"dmx_artnet_dmx_unsequenced_packets_total",
+			"Number of ArtDMX packets with sequence disabled (0) per source and universe",
+			labels, nil,
+		),
+		lossPercentDesc: prometheus.NewDesc(
+			"dmx_artnet_dmx_loss_percent",
+			"Estimated ArtDMX packet loss percent per source and universe",
+			labels, nil,
+		),
+	}
+}
+
+func (c *SequenceMetricsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.receivedDesc
+	ch <- c.lostDesc
+	ch <- c.reorderedDesc
+	ch <- c.duplicatesDesc
+	ch <- c.unsequencedDesc
+	ch <- c.lossPercentDesc
+}
+
+func (c *SequenceMetricsCollector) Collect(ch chan<- prometheus.Metric) {
+	for _, stats := range c.provider.Snapshot() {
+		source, universe := stats.SourceIP, strconv.Itoa(int(stats.Universe))
+		ch <- prometheus.MustNewConstMetric(c.receivedDesc, prometheus.CounterValue, float64(stats.Received), source, universe)
+		ch <- prometheus.MustNewConstMetric(c.lostDesc, prometheus.CounterValue, float64(stats.Lost), source, universe)
+		ch <- prometheus.MustNewConstMetric(c.reorderedDesc, prometheus.CounterValue, float64(stats.Reordered), source, universe)
+		ch <- prometheus.MustNewConstMetric(c.duplicatesDesc, prometheus.CounterValue, float64(stats.Duplicates), source, universe)
+		ch <- prometheus.MustNewConstMetric(c.unsequencedDesc, prometheus.CounterValue, float64(stats.Unsequenced), source, universe)
+		ch <- prometheus.MustNewConstMetric(c.lossPercentDesc, prometheus.GaugeValue, stats.LossPercent, source, universe)
+	}
+}
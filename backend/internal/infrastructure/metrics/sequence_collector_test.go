@@ -0,0 +1,58 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/nasshu2916/dmx_viewer/internal/domain/model"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type staticSequenceStats []model.SequenceStats
+
+func (s staticSequenceStats) Snapshot() []model.SequenceStats {
+	return s
+}
+
+func TestSequenceCollector_ExportsPerSourceMetrics(t *testing.T) {
+	provider := staticSequenceStats{
+		{SourceIP: "2.0.0.10", Universe: 1, Received: 90, Lost: 10, Reordered: 2, Duplicates: 1, LossPercent: 10},
+		{SourceIP: "2.0.0.11", Universe: 5, Received: 50},
+	}
+
+	reg := prometheus.NewRegistry()
+	require.NoError(t, reg.Register(NewSequenceMetricsCollector(provider)))
+
+	mfs, err := reg.Gather()
+	require.NoError(t, err)
+
+	values := map[string]float64{}
+	for _, mf := range mfs {
+		for _, m := range mf.Metric {
+			var source, universe string
+			for _, label := range m.Label {
+				switch label.GetName() {
+				case "source":
+					source = label.GetValue()
+				case "universe":
+					universe = label.GetValue()
+				}
+			}
+			key := mf.GetName() + "{" + source + "/" + universe + "}"
+			if m.Counter != nil {
+				values[key] = m.Counter.GetValue()
+			} else if m.Gauge != nil {
+				values[key] = m.Gauge.GetValue()
+			}
+		}
+	}
+
+	assert.Equal(t, 90.0, values["dmx_artnet_dmx_packets_total{2.0.0.10/1}"])
+	assert.Equal(t, 10.0, values["dmx_artnet_dmx_lost_packets_total{2.0.0.10/1}"])
+	assert.Equal(t, 2.0, values["dmx_artnet_dmx_reordered_packets_total{2.0.0.10/1}"])
+	assert.Equal(t, 1.0, values["dmx_artnet_dmx_duplicate_packets_total{2.0.0.10/1}"])
+	assert.Equal(t, 10.0, values["dmx_artnet_dmx_loss_percent{2.0.0.10/1}"])
+	assert.Equal(t, 50.0, values["dmx_artnet_dmx_packets_total{2.0.0.11/5}"])
+	assert.Equal(t, 0.0, values["dmx_artnet_dmx_loss_percent{2.0.0.11/5}"])
+}
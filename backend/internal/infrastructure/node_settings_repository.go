@@ -0,0 +1,107 @@
+package infrastructure
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/nasshu2916/dmx_viewer/internal/domain/model"
+)
+
+// NodeSettingsRepositoryImpl ノード設定をメモリ上に保持し、必要に応じてJSONファイルに永続化する
+type NodeSettingsRepositoryImpl struct {
+	mu       sync.RWMutex
+	settings model.NodeSettings
+	defaults model.NodeSettings
+	path     string // 永続化先のファイルパス（空の場合は永続化しない）
+}
+
+// NewNodeSettingsRepository 既定の設定を指定して作成する
+// path が指定され、ファイルが存在する場合は保存済みの設定を読み込む
+func NewNodeSettingsRepository(defaults model.NodeSettings, path string) (*NodeSettingsRepositoryImpl, error) {
+	r := &NodeSettingsRepositoryImpl{
+		settings: defaults.Clone(),
+		defaults: defaults.Clone(),
+		path:     path,
+	}
+	if path == "" {
+		return r, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return r, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read node settings %s: %w", path, err)
+	}
+
+	loaded := defaults.Clone()
+	if err := json.Unmarshal(data, &loaded); err != nil {
+		return nil, fmt.Errorf("failed to parse node settings %s: %w", path, err)
+	}
+	// インジケーターの状態は再起動時に通常状態へ戻す
+	loaded.Indicator = model.IndicatorNormal
+	if len(loaded.Pages) == 0 {
+		loaded.Pages = []model.PortPage{model.NewPortPage()}
+	}
+	if err := loaded.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid node settings %s: %w", path, err)
+	}
+	r.settings = loaded
+	return r, nil
+}
+
+func (r *NodeSettingsRepositoryImpl) Get() model.NodeSettings {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.settings.Clone()
+}
+
+func (r *NodeSettingsRepositoryImpl) Defaults() model.NodeSettings {
+	return r.defaults.Clone()
+}
+
+func (r *NodeSettingsRepositoryImpl) Update(update func(settings *model.NodeSettings) error) (model.NodeSettings, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	settings := r.settings.Clone()
+	if err := update(&settings); err != nil {
+		return r.settings.Clone(), err
+	}
+	r.settings = settings.Clone()
+	if r.path == "" {
+		return settings, nil
+	}
+	return settings, r.writeFile(settings)
+}
+
+// writeFile 一時ファイルに書き込んでからリネームし、書き込み途中の状態が残らないようにする
+func (r *NodeSettingsRepositoryImpl) writeFile(settings model.NodeSettings) error {
+	data, err := json.MarshalIndent(settings, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(r.path), filepath.Base(r.path)+".tmp*")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file for node settings: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write node settings: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close node settings: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), r.path); err != nil {
+		return fmt.Errorf("failed to save node settings %s: %w", r.path, err)
+	}
+	return nil
+}
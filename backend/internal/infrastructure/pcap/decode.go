@@ -0,0 +1,166 @@
+package pcap
+
+import (
+	"encoding/binary"
+	"net"
+)
+
+// リンク層の種類（https://www.tcpdump.org/linktypes.html）
+const (
+	linkTypeNull      uint32 = 0   // BSD ループバック
+	linkTypeEthernet  uint32 = 1   // Ethernet
+	linkTypeRawDLT    uint32 = 12  // IPv4 / IPv6（一部のOSの DLT_RAW）
+	linkTypeRawDLTAlt uint32 = 14  // IPv4 / IPv6（OpenBSD の DLT_RAW）
+	linkTypeRaw       uint32 = 101 // IPv4 / IPv6
+	linkTypeLinuxSLL  uint32 = 113 // Linux cooked capture v1
+	linkTypeLinuxSLL2 uint32 = 276 // Linux cooked capture v2
+)
+
+// EtherType
+const (
+	etherTypeIPv4   uint16 = 0x0800
+	etherTypeIPv6   uint16 = 0x86DD
+	etherTypeVLAN   uint16 = 0x8100 // 802.1Q
+	etherTypeQinQ   uint16 = 0x88A8 // 802.1ad
+	etherTypeQinQv1 uint16 = 0x9100 // 旧来の QinQ
+)
+
+const (
+	ipProtocolUDP  = 17
+	udpHeaderSize  = 8
+	ipv6HeaderSize = 40
+)
+
+// udpDatagram リンク層から取り出したUDPデータグラム
+type udpDatagram struct {
+	src, dst *net.UDPAddr
+	payload  []byte
+}
+
+// decodeLink リンク層のフレームからUDPデータグラムを取り出す（UDP以外の場合は false）
+func decodeLink(linkType uint32, b []byte) (udpDatagram, bool) {
+	switch linkType {
+	case linkTypeEthernet:
+		return decodeEthernet(b)
+	case linkTypeRaw, linkTypeRawDLT, linkTypeRawDLTAlt:
+		return decodeIP(b)
+	case linkTypeLinuxSLL:
+		if len(b) < 16 {
+			return udpDatagram{}, false
+		}
+		return decodeEtherType(binary.BigEndian.Uint16(b[14:16]), b[16:])
+	case linkTypeLinuxSLL2:
+		if len(b) < 20 {
+			return udpDatagram{}, false
+		}
+		return decodeEtherType(binary.BigEndian.Uint16(b[0:2]), b[20:])
+	case linkTypeNull:
+		// アドレスファミリーはキャプチャしたホストのバイトオーダーのため、IPヘッダーのバージョンで判定する
+		if len(b) < 4 {
+			return udpDatagram{}, false
+		}
+		return decodeIP(b[4:])
+	default:
+		return udpDatagram{}, false
+	}
+}
+
+// decodeEthernet Ethernet フレームを解析する（802.1Q / 802.1ad の VLAN タグは読み飛ばす）
+func decodeEthernet(b []byte) (udpDatagram, bool) {
+	if len(b) < 14 {
+		return udpDatagram{}, false
+	}
+	etherType := binary.BigEndian.Uint16(b[12:14])
+	b = b[14:]
+	for etherType == etherTypeVLAN || etherType == etherTypeQinQ || etherType == etherTypeQinQv1 {
+		if len(b) < 4 {
+			return udpDatagram{}, false
+		}
+		etherType = binary.BigEndian.Uint16(b[2:4])
+		b = b[4:]
+	}
+	return decodeEtherType(etherType, b)
+}
+
+func decodeEtherType(etherType uint16, b []byte) (udpDatagram, bool) {
+	switch etherType {
+	case etherTypeIPv4:
+		return decodeIPv4(b)
+	case etherTypeIPv6:
+		return decodeIPv6(b)
+	default:
+		return udpDatagram{}, false
+	}
+}
+
+// decodeIP IPヘッダーのバージョンで IPv4 / IPv6 を判定して解析する
+func decodeIP(b []byte) (udpDatagram, bool) {
+	if len(b) == 0 {
+		return udpDatagram{}, false
+	}
+	switch b[0] >> 4 {
+	case 4:
+		return decodeIPv4(b)
+	case 6:
+		return decodeIPv6(b)
+	default:
+		return udpDatagram{}, false
+	}
+}
+
+// decodeIPv4 IPv4 パケットを解析する（フラグメント化されたパケットは扱わない）
+func decodeIPv4(b []byte) (udpDatagram, bool) {
+	if len(b) < 20 || b[0]>>4 != 4 {
+		return udpDatagram{}, false
+	}
+	headerLen := int(b[0]&0x0F) * 4
+	totalLen := int(binary.BigEndian.Uint16(b[2:4]))
+	if headerLen < 20 || totalLen < headerLen || len(b) < headerLen {
+		return udpDatagram{}, false
+	}
+	flagsOffset := binary.BigEndian.Uint16(b[6:8])
+	if flagsOffset&0x2000 != 0 || flagsOffset&0x1FFF != 0 {
+		return udpDatagram{}, false
+	}
+	if b[9] != ipProtocolUDP {
+		return udpDatagram{}, false
+	}
+	// Ethernet のパディングを除くため全長で切り詰める
+	if totalLen < len(b) {
+		b = b[:totalLen]
+	}
+	src := net.IP(append([]byte(nil), b[12:16]...))
+	dst := net.IP(append([]byte(nil), b[16:20]...))
+	return decodeUDP(src, dst, b[headerLen:])
+}
+
+// decodeIPv6 IPv6 パケットを解析する（拡張ヘッダーを含むパケットは扱わない）
+func decodeIPv6(b []byte) (udpDatagram, bool) {
+	if len(b) < ipv6HeaderSize || b[0]>>4 != 6 || b[6] != ipProtocolUDP {
+		return udpDatagram{}, false
+	}
+	payloadLen := int(binary.BigEndian.Uint16(b[4:6]))
+	src := net.IP(append([]byte(nil), b[8:24]...))
+	dst := net.IP(append([]byte(nil), b[24:40]...))
+	b = b[ipv6HeaderSize:]
+	if payloadLen < len(b) {
+		b = b[:payloadLen]
+	}
+	return decodeUDP(src, dst, b)
+}
+
+func decodeUDP(src, dst net.IP, b []byte) (udpDatagram, bool) {
+	if len(b) < udpHeaderSize {
+		return udpDatagram{}, false
+	}
+	length := int(binary.BigEndian.Uint16(b[4:6]))
+	// 切り詰めてキャプチャされたデータグラムは扱わない
+	if length < udpHeaderSize || length > len(b) {
+		return udpDatagram{}, false
+	}
+	return udpDatagram{
+		src:     &net.UDPAddr{IP: src, Port: int(binary.BigEndian.Uint16(b[0:2]))},
+		dst:     &net.UDPAddr{IP: dst, Port: int(binary.BigEndian.Uint16(b[2:4]))},
+		payload: append([]byte(nil), b[udpHeaderSize:length]...),
+	}, true
+}
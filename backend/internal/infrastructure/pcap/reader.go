@@ -0,0 +1,302 @@
+// Package pcap Wireshark / tcpdump のパケットキャプチャ（pcap / pcapng）からUDPデータグラムを取り出す、
+// または受信したUDPデータグラムを pcap 形式で書き出す
+//
+// 標準ライブラリのみで実装しており、対応するリンク層は Ethernet（802.1Q / 802.1ad の VLAN タグを含む）、
+// Raw IP、Linux cooked capture（v1 / v2）、BSD ループバックとする。
+// IPv4 / IPv6 のUDPのみを扱い、フラグメント化されたパケットや IPv6 の拡張ヘッダーを含むパケットは読み飛ばす。
+package pcap
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/bits"
+	"time"
+
+	"github.com/nasshu2916/dmx_viewer/internal/domain/model"
+)
+
+// pcap のマジックナンバー（ファイル先頭をビッグエンディアンで読んだ値）
+const (
+	magicMicroBE uint32 = 0xA1B2C3D4
+	magicMicroLE uint32 = 0xD4C3B2A1
+	magicNanoBE  uint32 = 0xA1B23C4D
+	magicNanoLE  uint32 = 0x4D3CB2A1
+)
+
+// pcapng のブロックの種類
+const (
+	blockSectionHeader   uint32 = 0x0A0D0D0A
+	blockInterface       uint32 = 0x00000001
+	blockPacket          uint32 = 0x00000002 // 旧形式
+	blockSimplePacket    uint32 = 0x00000003
+	blockEnhancedPacket  uint32 = 0x00000006
+	byteOrderMagic       uint32 = 0x1A2B3C4D
+	optionEndOfOpt       uint16 = 0
+	optionIfTsResol      uint16 = 9
+	optionIfTsOffset     uint16 = 14
+	maxBlockLength              = 16 * 1024 * 1024
+	pcapRecordHeaderSize        = 16
+	pcapFileHeaderSize          = 24
+)
+
+// エラー定義
+var (
+	ErrUnknownFormat = errors.New("not a pcap or pcapng file")
+	ErrCorruptFile   = errors.New("corrupt packet capture")
+)
+
+// iface pcapng のインターフェースごとのリンク層とタイムスタンプの単位
+type iface struct {
+	linkType  uint32
+	unitsPerS uint64 // 1秒あたりのタイムスタンプの単位数
+	offset    int64  // タイムスタンプに加える秒数
+}
+
+// Reader パケットキャプチャからUDPデータグラムを順に読み込む
+type Reader struct {
+	r       *bufio.Reader
+	order   binary.ByteOrder
+	ng      bool
+	ifaces  []iface // pcap の場合は1つ
+	skipped int
+}
+
+// NewReader ファイルの形式（pcap / pcapng）を判定してReaderを作成する
+func NewReader(r io.Reader) (*Reader, error) {
+	br := bufio.NewReader(r)
+	head, err := br.Peek(4)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnknownFormat, err)
+	}
+	reader := &Reader{r: br}
+
+	switch binary.BigEndian.Uint32(head) {
+	case blockSectionHeader:
+		reader.ng = true
+		if _, err := reader.readBlock(); err != nil {
+			return nil, err
+		}
+		return reader, nil
+	case magicMicroBE, magicNanoBE:
+		reader.order = binary.BigEndian
+	case magicMicroLE, magicNanoLE:
+		reader.order = binary.LittleEndian
+	default:
+		return nil, ErrUnknownFormat
+	}
+
+	header := make([]byte, pcapFileHeaderSize)
+	if _, err := io.ReadFull(br, header); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrCorruptFile, err)
+	}
+	units := uint64(1e6)
+	if magic := reader.order.Uint32(header[0:4]); magic == magicNanoBE {
+		units = 1e9
+	}
+	// 上位16bitは FCS の長さなどに使われるため下位16bitをリンク層の種類とする
+	linkType := reader.order.Uint32(header[20:24]) & 0xFFFF
+	reader.ifaces = []iface{{linkType: linkType, unitsPerS: units}}
+	return reader, nil
+}
+
+// Skipped UDP以外・未対応のリンク層などのため読み飛ばしたパケット数
+func (r *Reader) Skipped() int {
+	return r.skipped
+}
+
+// Next 次のUDPデータグラムを読み込む（ファイルの終端では io.EOF を返す）
+// 途中で途切れたファイルの場合は io.ErrUnexpectedEOF を返す
+func (r *Reader) Next() (*model.CapturedDatagram, error) {
+	for {
+		linkType, timestamp, data, err := r.nextPacket()
+		if err != nil {
+			return nil, err
+		}
+		datagram, ok := decodeLink(linkType, data)
+		if !ok {
+			r.skipped++
+			continue
+		}
+		return &model.CapturedDatagram{
+			Timestamp: timestamp,
+			Src:       datagram.src,
+			Dst:       datagram.dst,
+			Payload:   datagram.payload,
+		}, nil
+	}
+}
+
+// nextPacket 次のパケットのリンク層のフレームを読み込む
+func (r *Reader) nextPacket() (uint32, time.Time, []byte, error) {
+	if !r.ng {
+		return r.nextPcapRecord()
+	}
+	for {
+		packet, err := r.readBlock()
+		if err != nil {
+			return 0, time.Time{}, nil, err
+		}
+		if packet != nil {
+			return packet.linkType, packet.timestamp, packet.data, nil
+		}
+	}
+}
+
+func (r *Reader) nextPcapRecord() (uint32, time.Time, []byte, error) {
+	header := make([]byte, pcapRecordHeaderSize)
+	if _, err := io.ReadFull(r.r, header); err != nil {
+		if errors.Is(err, io.EOF) {
+			return 0, time.Time{}, nil, io.EOF
+		}
+		return 0, time.Time{}, nil, io.ErrUnexpectedEOF
+	}
+	length := r.order.Uint32(header[8:12])
+	if length > maxBlockLength {
+		return 0, time.Time{}, nil, fmt.Errorf("%w: record length %d", ErrCorruptFile, length)
+	}
+	data := make([]byte, length)
+	if _, err := io.ReadFull(r.r, data); err != nil {
+		return 0, time.Time{}, nil, io.ErrUnexpectedEOF
+	}
+	ifc := r.ifaces[0]
+	sec, frac := r.order.Uint32(header[0:4]), r.order.Uint32(header[4:8])
+	timestamp := time.Unix(int64(sec), int64(frac)*int64(time.Second)/int64(ifc.unitsPerS))
+	return ifc.linkType, timestamp, data, nil
+}
+
+// ngPacket pcapng のパケットブロックの内容
+type ngPacket struct {
+	linkType  uint32
+	timestamp time.Time
+	data      []byte
+}
+
+// readBlock pcapng のブロックを1つ読み込む（パケット以外のブロックの場合は nil を返す）
+func (r *Reader) readBlock() (*ngPacket, error) {
+	head, err := r.r.Peek(12)
+	if err != nil {
+		if errors.Is(err, io.EOF) && len(head) == 0 {
+			return nil, io.EOF
+		}
+		return nil, io.ErrUnexpectedEOF
+	}
+
+	// セクションヘッダーでバイトオーダーが変わる
+	if binary.BigEndian.Uint32(head[0:4]) == blockSectionHeader {
+		switch {
+		case binary.BigEndian.Uint32(head[8:12]) == byteOrderMagic:
+			r.order = binary.BigEndian
+		case binary.LittleEndian.Uint32(head[8:12]) == byteOrderMagic:
+			r.order = binary.LittleEndian
+		default:
+			return nil, fmt.Errorf("%w: invalid byte-order magic", ErrCorruptFile)
+		}
+		// インターフェースの番号はセクションごとに振り直される
+		r.ifaces = nil
+	} else if r.order == nil {
+		return nil, ErrUnknownFormat
+	}
+
+	blockType := r.order.Uint32(head[0:4])
+	length := r.order.Uint32(head[4:8])
+	if length < 12 || length%4 != 0 || length > maxBlockLength {
+		return nil, fmt.Errorf("%w: block length %d", ErrCorruptFile, length)
+	}
+	block := make([]byte, length)
+	if _, err := io.ReadFull(r.r, block); err != nil {
+		return nil, io.ErrUnexpectedEOF
+	}
+	body := block[8 : length-4]
+
+	switch blockType {
+	case blockInterface:
+		return nil, r.readInterface(body)
+	case blockEnhancedPacket:
+		if len(body) < 20 {
+			return nil, fmt.Errorf("%w: short enhanced packet block", ErrCorruptFile)
+		}
+		return r.packet(r.order.Uint32(body[0:4]), body[4:12], r.order.Uint32(body[12:16]), body[20:])
+	case blockPacket:
+		if len(body) < 20 {
+			return nil, fmt.Errorf("%w: short packet block", ErrCorruptFile)
+		}
+		return r.packet(uint32(r.order.Uint16(body[0:2])), body[4:12], r.order.Uint32(body[12:16]), body[20:])
+	case blockSimplePacket:
+		// タイムスタンプがないため元のタイミングを再現できない
+		r.skipped++
+		return nil, nil
+	default:
+		return nil, nil
+	}
+}
+
+// readInterface インターフェース記述ブロックからリンク層とタイムスタンプの単位を読み込む
+func (r *Reader) readInterface(body []byte) error {
+	if len(body) < 8 {
+		return fmt.Errorf("%w: short interface description block", ErrCorruptFile)
+	}
+	ifc := iface{linkType: uint32(r.order.Uint16(body[0:2])), unitsPerS: 1e6}
+
+	options := body[8:]
+	for len(options) >= 4 {
+		code, length := r.order.Uint16(options[0:2]), int(r.order.Uint16(options[2:4]))
+		if code == optionEndOfOpt || 4+length > len(options) {
+			break
+		}
+		value := options[4 : 4+length]
+		switch {
+		case code == optionIfTsResol && length >= 1:
+			ifc.unitsPerS = tsResolution(value[0])
+		case code == optionIfTsOffset && length >= 8:
+			ifc.offset = int64(r.order.Uint64(value))
+		}
+		// オプションの値は4バイト境界に揃えられている
+		next := 4 + (length+3)/4*4
+		if next > len(options) {
+			break
+		}
+		options = options[next:]
+	}
+	r.ifaces = append(r.ifaces, ifc)
+	return nil
+}
+
+// tsResolution if_tsresol から1秒あたりのタイムスタンプの単位数を求める
+func tsResolution(v uint8) uint64 {
+	exp := uint64(v & 0x7F)
+	if v&0x80 != 0 {
+		if exp > 63 {
+			return math.MaxUint64
+		}
+		return 1 << exp
+	}
+	units := uint64(1)
+	for i := uint64(0); i < exp && units <= math.MaxUint64/10; i++ {
+		units *= 10
+	}
+	return units
+}
+
+func (r *Reader) packet(ifaceID uint32, ts []byte, capturedLen uint32, data []byte) (*ngPacket, error) {
+	if int(ifaceID) >= len(r.ifaces) {
+		return nil, fmt.Errorf("%w: unknown interface %d", ErrCorruptFile, ifaceID)
+	}
+	if int(capturedLen) > len(data) {
+		return nil, fmt.Errorf("%w: captured length %d exceeds block", ErrCorruptFile, capturedLen)
+	}
+	ifc := r.ifaces[ifaceID]
+	units := uint64(r.order.Uint32(ts[0:4]))<<32 | uint64(r.order.Uint32(ts[4:8]))
+	sec := units / ifc.unitsPerS
+	// 1秒未満の部分をナノ秒に変換する（単位が細かい場合に桁あふれしないよう128bitで計算する）
+	hi, lo := bits.Mul64(units%ifc.unitsPerS, uint64(time.Second))
+	nsec, _ := bits.Div64(hi, lo, ifc.unitsPerS)
+	return &ngPacket{
+		linkType:  ifc.linkType,
+		timestamp: time.Unix(int64(sec)+ifc.offset, int64(nsec)),
+		data:      data[:capturedLen],
+	}, nil
+}
@@ -0,0 +1,288 @@
+package pcap
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// udpIPv4 IPv4 のUDPパケットを作成する
+func udpIPv4(src, dst string, srcPort, dstPort uint16, payload []byte) []byte {
+	udp := make([]byte, udpHeaderSize+len(payload))
+	binary.BigEndian.PutUint16(udp[0:2], srcPort)
+	binary.BigEndian.PutUint16(udp[2:4], dstPort)
+	binary.BigEndian.PutUint16(udp[4:6], uint16(len(udp)))
+	copy(udp[udpHeaderSize:], payload)
+
+	ip := make([]byte, 20, 20+len(udp))
+	ip[0] = 0x45
+	binary.BigEndian.PutUint16(ip[2:4], uint16(20+len(udp)))
+	ip[8] = 64
+	ip[9] = ipProtocolUDP
+	copy(ip[12:16], net.ParseIP(src).To4())
+	copy(ip[16:20], net.ParseIP(dst).To4())
+	return append(ip, udp...)
+}
+
+// udpIPv6 IPv6 のUDPパケットを作成する
+func udpIPv6(src, dst string, srcPort, dstPort uint16, payload []byte) []byte {
+	udp := make([]byte, udpHeaderSize+len(payload))
+	binary.BigEndian.PutUint16(udp[0:2], srcPort)
+	binary.BigEndian.PutUint16(udp[2:4], dstPort)
+	binary.BigEndian.PutUint16(udp[4:6], uint16(len(udp)))
+	copy(udp[udpHeaderSize:], payload)
+
+	ip := make([]byte, ipv6HeaderSize, ipv6HeaderSize+len(udp))
+	ip[0] = 0x60
+	binary.BigEndian.PutUint16(ip[4:6], uint16(len(udp)))
+	ip[6] = ipProtocolUDP
+	copy(ip[8:24], net.ParseIP(src).To16())
+	copy(ip[24:40], net.ParseIP(dst).To16())
+	return append(ip, udp...)
+}
+
+// ethernet Ethernet フレームを作成する（vlans を指定した場合は 802.1Q タグを付ける）
+func ethernet(etherType uint16, payload []byte, vlans ...uint16) []byte {
+	frame := make([]byte, 12)
+	for _, vlan := range vlans {
+		frame = binary.BigEndian.AppendUint16(frame, etherTypeVLAN)
+		frame = binary.BigEndian.AppendUint16(frame, vlan)
+	}
+	frame = binary.BigEndian.AppendUint16(frame, etherType)
+	frame = append(frame, payload...)
+	// 最小フレーム長に満たない場合のパディング
+	for len(frame) < 60 {
+		frame = append(frame, 0)
+	}
+	return frame
+}
+
+// byteOrder テスト用のファイルを組み立てるためのバイトオーダー
+type byteOrder interface {
+	binary.ByteOrder
+	binary.AppendByteOrder
+}
+
+type testPacket struct {
+	ts   time.Time
+	data []byte
+}
+
+// pcapFile pcap 形式のファイルを作成する
+func pcapFile(order byteOrder, nano bool, linkType uint32, packets ...testPacket) []byte {
+	var buf bytes.Buffer
+	magic := magicMicroBE
+	if nano {
+		magic = magicNanoBE
+	}
+	header := make([]byte, pcapFileHeaderSize)
+	order.PutUint32(header[0:4], magic)
+	order.PutUint16(header[4:6], 2)
+	order.PutUint16(header[6:8], 4)
+	order.PutUint32(header[16:20], 65535)
+	order.PutUint32(header[20:24], linkType)
+	buf.Write(header)
+	for _, p := range packets {
+		record := make([]byte, pcapRecordHeaderSize)
+		order.PutUint32(record[0:4], uint32(p.ts.Unix()))
+		frac := uint32(p.ts.Nanosecond() / 1000)
+		if nano {
+			frac = uint32(p.ts.Nanosecond())
+		}
+		order.PutUint32(record[4:8], frac)
+		order.PutUint32(record[8:12], uint32(len(p.data)))
+		order.PutUint32(record[12:16], uint32(len(p.data)))
+		buf.Write(record)
+		buf.Write(p.data)
+	}
+	return buf.Bytes()
+}
+
+func ngBlock(order byteOrder, blockType uint32, body []byte) []byte {
+	for len(body)%4 != 0 {
+		body = append(body, 0)
+	}
+	b := make([]byte, 8, 12+len(body))
+	order.PutUint32(b[0:4], blockType)
+	order.PutUint32(b[4:8], uint32(12+len(body)))
+	b = append(b, body...)
+	return order.AppendUint32(b, uint32(12+len(body)))
+}
+
+// pcapngFile pcapng 形式のファイルを作成する（タイムスタンプの単位はナノ秒）
+func pcapngFile(order byteOrder, linkType uint16, packets ...testPacket) []byte {
+	var buf bytes.Buffer
+	shb := order.AppendUint32(nil, byteOrderMagic)
+	shb = order.AppendUint16(shb, 1)
+	shb = order.AppendUint16(shb, 0)
+	shb = order.AppendUint64(shb, 0xFFFFFFFFFFFFFFFF)
+	buf.Write(ngBlock(order, blockSectionHeader, shb))
+
+	idb := order.AppendUint16(nil, linkType)
+	idb = order.AppendUint16(idb, 0)
+	idb = order.AppendUint32(idb, 0)
+	idb = order.AppendUint16(idb, optionIfTsResol)
+	idb = order.AppendUint16(idb, 1)
+	idb = append(idb, 9, 0, 0, 0)
+	idb = order.AppendUint16(idb, optionEndOfOpt)
+	idb = order.AppendUint16(idb, 0)
+	buf.Write(ngBlock(order, blockInterface, idb))
+
+	// パケット以外のブロックは読み飛ばす
+	buf.Write(ngBlock(order, 0x00000005, make([]byte, 8)))
+
+	for _, p := range packets {
+		ts := uint64(p.ts.UnixNano())
+		epb := order.AppendUint32(nil, 0)
+		epb = order.AppendUint32(epb, uint32(ts>>32))
+		epb = order.AppendUint32(epb, uint32(ts))
+		epb = order.AppendUint32(epb, uint32(len(p.data)))
+		epb = order.AppendUint32(epb, uint32(len(p.data)))
+		epb = append(epb, p.data...)
+		buf.Write(ngBlock(order, blockEnhancedPacket, epb))
+	}
+	return buf.Bytes()
+}
+
+func readAll(t *testing.T, data []byte) (*Reader, []*testDatagram) {
+	t.Helper()
+	r, err := NewReader(bytes.NewReader(data))
+	require.NoError(t, err)
+	var datagrams []*testDatagram
+	for {
+		d, err := r.Next()
+		if errors.Is(err, io.EOF) {
+			return r, datagrams
+		}
+		require.NoError(t, err)
+		datagrams = append(datagrams, &testDatagram{ts: d.Timestamp, src: d.Src.String(), dst: d.Dst.String(), payload: d.Payload})
+	}
+}
+
+type testDatagram struct {
+	ts       time.Time
+	src, dst string
+	payload  []byte
+}
+
+func TestReader_Pcap(t *testing.T) {
+	start := time.Date(2024, 5, 1, 19, 30, 0, 123456000, time.UTC)
+	packets := []testPacket{
+		{ts: start, data: ethernet(etherTypeIPv4, udpIPv4("2.0.0.10", "2.255.255.255", 6454, 6454, []byte("Art-Net\x00")))},
+		// 802.1Q タグ付き（QinQ）
+		{ts: start.Add(25 * time.Millisecond), data: ethernet(etherTypeIPv4, udpIPv4("10.0.0.5", "239.255.0.1", 50000, 5568, []byte{1, 2, 3}), 100, 200)},
+		// UDP 以外は読み飛ばす
+		{ts: start.Add(30 * time.Millisecond), data: ethernet(0x0806, make([]byte, 28))},
+		{ts: start.Add(40 * time.Millisecond), data: ethernet(etherTypeIPv6, udpIPv6("fe80::1", "ff18::83:0:1", 50001, 5568, []byte{9}))},
+	}
+
+	for _, tt := range []struct {
+		name  string
+		order byteOrder
+		nano  bool
+	}{
+		{name: "little endian microseconds", order: binary.LittleEndian},
+		{name: "big endian nanoseconds", order: binary.BigEndian, nano: true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			r, datagrams := readAll(t, pcapFile(tt.order, tt.nano, linkTypeEthernet, packets...))
+			require.Len(t, datagrams, 3)
+			assert.Equal(t, 1, r.Skipped())
+
+			assert.True(t, start.Equal(datagrams[0].ts))
+			assert.Equal(t, "2.0.0.10:6454", datagrams[0].src)
+			assert.Equal(t, "2.255.255.255:6454", datagrams[0].dst)
+			// Ethernet のパディングは含まない
+			assert.Equal(t, []byte("Art-Net\x00"), datagrams[0].payload)
+
+			assert.True(t, start.Add(25*time.Millisecond).Equal(datagrams[1].ts))
+			assert.Equal(t, "239.255.0.1:5568", datagrams[1].dst)
+			assert.Equal(t, []byte{1, 2, 3}, datagrams[1].payload)
+
+			assert.Equal(t, "[ff18::83:0:1]:5568", datagrams[2].dst)
+		})
+	}
+}
+
+func TestReader_Pcapng(t *testing.T) {
+	start := time.Date(2024, 5, 1, 19, 30, 0, 123456789, time.UTC)
+	packets := []testPacket{
+		{ts: start, data: ethernet(etherTypeIPv4, udpIPv4("2.0.0.10", "2.0.0.255", 6454, 6454, []byte{1}), 10)},
+		{ts: start.Add(time.Second), data: ethernet(etherTypeIPv4, udpIPv4("2.0.0.11", "2.0.0.255", 6454, 6454, []byte{2}))},
+	}
+
+	for _, order := range []byteOrder{binary.LittleEndian, binary.BigEndian} {
+		t.Run(order.String(), func(t *testing.T) {
+			_, datagrams := readAll(t, pcapngFile(order, uint16(linkTypeEthernet), packets...))
+			require.Len(t, datagrams, 2)
+			// if_tsresol によりナノ秒の精度で読み込む
+			assert.True(t, start.Equal(datagrams[0].ts), datagrams[0].ts)
+			assert.Equal(t, "2.0.0.10:6454", datagrams[0].src)
+			assert.Equal(t, []byte{2}, datagrams[1].payload)
+		})
+	}
+}
+
+func TestReader_LinkTypes(t *testing.T) {
+	ts := time.Unix(1700000000, 0)
+	ipPacket := udpIPv4("2.0.0.10", "2.0.0.255", 6454, 6454, []byte{7})
+
+	sll := make([]byte, 16)
+	binary.BigEndian.PutUint16(sll[14:16], etherTypeIPv4)
+	sll2 := make([]byte, 20)
+	binary.BigEndian.PutUint16(sll2[0:2], etherTypeIPv4)
+
+	tests := []struct {
+		name     string
+		linkType uint32
+		data     []byte
+	}{
+		{name: "raw", linkType: linkTypeRaw, data: ipPacket},
+		{name: "linux sll", linkType: linkTypeLinuxSLL, data: append(sll, ipPacket...)},
+		{name: "linux sll2", linkType: linkTypeLinuxSLL2, data: append(sll2, ipPacket...)},
+		{name: "null", linkType: linkTypeNull, data: append([]byte{2, 0, 0, 0}, ipPacket...)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, datagrams := readAll(t, pcapFile(binary.LittleEndian, false, tt.linkType, testPacket{ts: ts, data: tt.data}))
+			require.Len(t, datagrams, 1)
+			assert.Equal(t, []byte{7}, datagrams[0].payload)
+		})
+	}
+}
+
+func TestReader_Errors(t *testing.T) {
+	_, err := NewReader(bytes.NewReader([]byte("not a capture file")))
+	assert.ErrorIs(t, err, ErrUnknownFormat)
+
+	// 途中で途切れたファイル
+	data := pcapFile(binary.LittleEndian, false, linkTypeEthernet,
+		testPacket{ts: time.Unix(1, 0), data: ethernet(etherTypeIPv4, udpIPv4("2.0.0.10", "2.0.0.255", 6454, 6454, []byte{1}))},
+		testPacket{ts: time.Unix(2, 0), data: ethernet(etherTypeIPv4, udpIPv4("2.0.0.10", "2.0.0.255", 6454, 6454, []byte{2}))},
+	)
+	r, err := NewReader(bytes.NewReader(data[:len(data)-10]))
+	require.NoError(t, err)
+	_, err = r.Next()
+	require.NoError(t, err)
+	_, err = r.Next()
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+
+	// 切り詰めてキャプチャされたデータグラムは読み飛ばす
+	truncated := ethernet(etherTypeIPv4, udpIPv4("2.0.0.10", "2.0.0.255", 6454, 6454, make([]byte, 100)))[:60]
+	r, datagrams := readAll(t, pcapFile(binary.LittleEndian, false, linkTypeEthernet, testPacket{ts: time.Unix(1, 0), data: truncated}))
+	assert.Empty(t, datagrams)
+	assert.Equal(t, 1, r.Skipped())
+}
+
+func TestTsResolution(t *testing.T) {
+	assert.Equal(t, uint64(1e6), tsResolution(6))
+	assert.Equal(t, uint64(1e9), tsResolution(9))
+	assert.Equal(t, uint64(1024), tsResolution(0x80|10))
+}
@@ -0,0 +1,168 @@
+package pcap
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+
+	"github.com/nasshu2916/dmx_viewer/internal/domain/model"
+)
+
+// FileExtension 書き出すパケットキャプチャの拡張子
+const FileExtension = ".pcap"
+
+const (
+	ethernetHeaderSize = 14
+	ipv4HeaderSize     = 20
+	maxSnapLen         = 65535
+	ipv4TTL            = 64
+)
+
+// Writer UDPデータグラムに Ethernet / IP / UDP ヘッダーを付けて pcap 形式（ナノ秒精度）で書き込む
+//
+// 受信したソケットからはリンク層の情報を得られないため、MACアドレスはIPアドレスから作成した
+// ローカル管理アドレス（02:00:IPv4アドレス）とし、ブロードキャスト・マルチキャストは対応するアドレスとする。
+type Writer struct {
+	w         io.Writer
+	broadcast net.IP // 受信したインターフェースのディレクテッドブロードキャストアドレス（不明な場合は nil）
+	written   int64
+	ipID      uint16
+}
+
+// NewWriter ファイルヘッダーを書き込んでWriterを作成する
+// broadcast には受信したインターフェースのディレクテッドブロードキャストアドレスを指定する（不明な場合は nil）
+func NewWriter(w io.Writer, broadcast net.IP) (*Writer, error) {
+	header := make([]byte, pcapFileHeaderSize)
+	binary.LittleEndian.PutUint32(header[0:4], magicNanoBE)
+	binary.LittleEndian.PutUint16(header[4:6], 2)
+	binary.LittleEndian.PutUint16(header[6:8], 4)
+	binary.LittleEndian.PutUint32(header[16:20], maxSnapLen)
+	binary.LittleEndian.PutUint32(header[20:24], linkTypeEthernet)
+	n, err := w.Write(header)
+	return &Writer{w: w, broadcast: broadcast, written: int64(n)}, err
+}
+
+// Written 書き込んだバイト数（ファイルヘッダーを含む）
+func (w *Writer) Written() int64 {
+	return w.written
+}
+
+// WriteDatagram データグラムを1パケットとして書き込む
+func (w *Writer) WriteDatagram(d *model.CapturedDatagram) error {
+	if d.Src == nil || d.Dst == nil {
+		return fmt.Errorf("datagram source and destination are required")
+	}
+	frame, err := w.frame(d)
+	if err != nil {
+		return err
+	}
+	if len(frame) > maxSnapLen {
+		return fmt.Errorf("datagram too large: %d bytes frame exceeds %d bytes", len(frame), maxSnapLen)
+	}
+
+	record := make([]byte, pcapRecordHeaderSize, pcapRecordHeaderSize+len(frame))
+	ts := d.Timestamp.UnixNano()
+	binary.LittleEndian.PutUint32(record[0:4], uint32(ts/1e9))
+	binary.LittleEndian.PutUint32(record[4:8], uint32(ts%1e9))
+	binary.LittleEndian.PutUint32(record[8:12], uint32(len(frame)))
+	binary.LittleEndian.PutUint32(record[12:16], uint32(len(frame)))
+	n, err := w.w.Write(append(record, frame...))
+	w.written += int64(n)
+	return err
+}
+
+// frame Ethernet フレームを組み立てる
+func (w *Writer) frame(d *model.CapturedDatagram) ([]byte, error) {
+	src4, dst4 := d.Src.IP.To4(), d.Dst.IP.To4()
+	if (src4 == nil) != (dst4 == nil) {
+		return nil, fmt.Errorf("mixed IPv4 and IPv6 addresses: %s -> %s", d.Src, d.Dst)
+	}
+
+	udp := make([]byte, udpHeaderSize, udpHeaderSize+len(d.Payload))
+	binary.BigEndian.PutUint16(udp[0:2], uint16(d.Src.Port))
+	binary.BigEndian.PutUint16(udp[2:4], uint16(d.Dst.Port))
+	binary.BigEndian.PutUint16(udp[4:6], uint16(udpHeaderSize+len(d.Payload)))
+	udp = append(udp, d.Payload...)
+
+	frame := make([]byte, ethernetHeaderSize, ethernetHeaderSize+ipv6HeaderSize+len(udp))
+	copy(frame[0:6], macAddress(d.Dst.IP, w.broadcast))
+	copy(frame[6:12], macAddress(d.Src.IP, w.broadcast))
+
+	if src4 != nil {
+		binary.BigEndian.PutUint16(frame[12:14], etherTypeIPv4)
+		ip := make([]byte, ipv4HeaderSize)
+		ip[0] = 0x45
+		binary.BigEndian.PutUint16(ip[2:4], uint16(ipv4HeaderSize+len(udp)))
+		binary.BigEndian.PutUint16(ip[4:6], w.ipID)
+		w.ipID++
+		binary.BigEndian.PutUint16(ip[6:8], 0x4000) // Don't Fragment
+		ip[8] = ipv4TTL
+		ip[9] = ipProtocolUDP
+		copy(ip[12:16], src4)
+		copy(ip[16:20], dst4)
+		binary.BigEndian.PutUint16(ip[10:12], checksum(ip))
+		// IPv4 では UDP のチェックサムを省略できる（0）
+		frame = append(frame, ip...)
+	} else {
+		binary.BigEndian.PutUint16(frame[12:14], etherTypeIPv6)
+		ip := make([]byte, ipv6HeaderSize)
+		ip[0] = 0x60
+		binary.BigEndian.PutUint16(ip[4:6], uint16(len(udp)))
+		ip[6] = ipProtocolUDP
+		ip[7] = ipv4TTL
+		copy(ip[8:24], d.Src.IP.To16())
+		copy(ip[24:40], d.Dst.IP.To16())
+		// IPv6 では UDP のチェックサムを省略できないため疑似ヘッダーから計算する
+		pseudo := make([]byte, 0, 40+len(udp))
+		pseudo = append(pseudo, ip[8:40]...)
+		pseudo = binary.BigEndian.AppendUint32(pseudo, uint32(len(udp)))
+		pseudo = append(pseudo, 0, 0, 0, ipProtocolUDP)
+		sum := checksum(append(pseudo, udp...))
+		if sum == 0 {
+			sum = 0xFFFF
+		}
+		binary.BigEndian.PutUint16(udp[6:8], sum)
+		frame = append(frame, ip...)
+	}
+	return append(frame, udp...), nil
+}
+
+// macAddress IPアドレスに対応する Ethernet のアドレスを返す
+// broadcast はインターフェースのディレクテッドブロードキャストアドレス（不明な場合は nil）
+func macAddress(ip, broadcast net.IP) net.HardwareAddr {
+	if ip4 := ip.To4(); ip4 != nil {
+		switch {
+		case ip4.Equal(net.IPv4bcast) || (broadcast != nil && ip4.Equal(broadcast)):
+			return net.HardwareAddr{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF}
+		case ip4.IsMulticast():
+			return net.HardwareAddr{0x01, 0x00, 0x5E, ip4[1] & 0x7F, ip4[2], ip4[3]}
+		default:
+			return net.HardwareAddr{0x02, 0x00, ip4[0], ip4[1], ip4[2], ip4[3]}
+		}
+	}
+	ip16 := ip.To16()
+	if ip16 != nil && ip16.IsMulticast() {
+		return net.HardwareAddr{0x33, 0x33, ip16[12], ip16[13], ip16[14], ip16[15]}
+	}
+	mac := net.HardwareAddr{0x02, 0x00, 0, 0, 0, 0}
+	if ip16 != nil {
+		copy(mac[2:], ip16[12:16])
+	}
+	return mac
+}
+
+// checksum インターネットチェックサム（RFC 1071）
+func checksum(b []byte) uint16 {
+	var sum uint32
+	for i := 0; i+1 < len(b); i += 2 {
+		sum += uint32(binary.BigEndian.Uint16(b[i : i+2]))
+	}
+	if len(b)%2 == 1 {
+		sum += uint32(b[len(b)-1]) << 8
+	}
+	for sum > 0xFFFF {
+		sum = sum>>16 + sum&0xFFFF
+	}
+	return ^uint16(sum)
+}
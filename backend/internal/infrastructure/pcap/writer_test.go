@@ -0,0 +1,85 @@
+package pcap
+
+import (
+	"bytes"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/nasshu2916/dmx_viewer/internal/domain/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriter_RoundTrip(t *testing.T) {
+	start := time.Date(2024, 5, 1, 19, 30, 0, 123456789, time.UTC)
+	datagrams := []*model.CapturedDatagram{
+		{
+			Timestamp: start,
+			Src:       &net.UDPAddr{IP: net.IPv4(2, 0, 0, 10), Port: 6454},
+			Dst:       &net.UDPAddr{IP: net.IPv4(2, 255, 255, 255), Port: 6454},
+			Payload:   []byte("Art-Net\x00"),
+		},
+		{
+			Timestamp: start.Add(25 * time.Millisecond),
+			Src:       &net.UDPAddr{IP: net.ParseIP("fe80::1"), Port: 50000},
+			Dst:       &net.UDPAddr{IP: net.ParseIP("ff18::83:0:1"), Port: 5568},
+			Payload:   []byte{1, 2, 3},
+		},
+	}
+
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, net.IPv4(2, 255, 255, 255))
+	require.NoError(t, err)
+	for _, d := range datagrams {
+		require.NoError(t, w.WriteDatagram(d))
+	}
+	assert.Equal(t, int64(buf.Len()), w.Written())
+
+	_, got := readAll(t, buf.Bytes())
+	require.Len(t, got, 2)
+	for i, d := range datagrams {
+		assert.True(t, d.Timestamp.Equal(got[i].ts), got[i].ts)
+		assert.Equal(t, d.Src.String(), got[i].src)
+		assert.Equal(t, d.Dst.String(), got[i].dst)
+		assert.Equal(t, d.Payload, got[i].payload)
+	}
+
+	// IPv4 ヘッダーのチェックサムが正しい
+	frame := buf.Bytes()[pcapFileHeaderSize+pcapRecordHeaderSize:]
+	assert.Equal(t, uint16(0), checksum(frame[ethernetHeaderSize:ethernetHeaderSize+ipv4HeaderSize]))
+	assert.Equal(t, net.HardwareAddr{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF}, net.HardwareAddr(frame[0:6]))
+	assert.Equal(t, net.HardwareAddr{0x02, 0x00, 2, 0, 0, 10}, net.HardwareAddr(frame[6:12]))
+}
+
+func TestWriter_Errors(t *testing.T) {
+	w, err := NewWriter(&bytes.Buffer{}, nil)
+	require.NoError(t, err)
+
+	err = w.WriteDatagram(&model.CapturedDatagram{Src: &net.UDPAddr{IP: net.IPv4(2, 0, 0, 10)}})
+	assert.Error(t, err)
+	err = w.WriteDatagram(&model.CapturedDatagram{
+		Src: &net.UDPAddr{IP: net.IPv4(2, 0, 0, 10)},
+		Dst: &net.UDPAddr{IP: net.ParseIP("fe80::1")},
+	})
+	assert.Error(t, err)
+	// ヘッダーを付けたフレームの長さを報告する
+	err = w.WriteDatagram(&model.CapturedDatagram{
+		Src:     &net.UDPAddr{IP: net.IPv4(2, 0, 0, 10)},
+		Dst:     &net.UDPAddr{IP: net.IPv4(2, 0, 0, 11)},
+		Payload: make([]byte, 65500),
+	})
+	assert.EqualError(t, err, "datagram too large: 65542 bytes frame exceeds 65535 bytes")
+}
+
+func TestMacAddress(t *testing.T) {
+	broadcast := net.IPv4(10, 255, 255, 255)
+	assert.Equal(t, "01:00:5e:7f:00:01", macAddress(net.IPv4(239, 255, 0, 1), broadcast).String())
+	assert.Equal(t, "02:00:0a:00:00:05", macAddress(net.IPv4(10, 0, 0, 5), broadcast).String())
+	assert.Equal(t, "33:33:00:00:00:01", macAddress(net.ParseIP("ff18::83:0:1"), broadcast).String())
+	assert.Equal(t, "ff:ff:ff:ff:ff:ff", macAddress(net.IPv4(10, 255, 255, 255), broadcast).String())
+	assert.Equal(t, "ff:ff:ff:ff:ff:ff", macAddress(net.IPv4bcast, nil).String())
+	// インターフェースのブロードキャストアドレスでなければ末尾が255でもホストのアドレスとする
+	assert.Equal(t, "02:00:0a:00:01:ff", macAddress(net.IPv4(10, 0, 1, 255), broadcast).String())
+	assert.Equal(t, "02:00:02:ff:ff:ff", macAddress(net.IPv4(2, 255, 255, 255), nil).String())
+}
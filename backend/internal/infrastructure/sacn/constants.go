@@ -0,0 +1,64 @@
+package sacn
+
+import (
+	"errors"
+	"time"
+)
+
+// 定数定義
+const (
+	DefaultPort              = 5568
+	DefaultChannelBufferSize = 1000
+	DefaultReadTimeout       = 500 * time.Millisecond
+	DefaultMaxPacketSize     = 1500
+
+	// MinUniverse / MaxUniverse データを送信できるユニバースの範囲
+	MinUniverse = 1
+	MaxUniverse = 63999
+	// DiscoveryUniverse ユニバースディスカバリーパケットを送信するユニバース
+	DiscoveryUniverse = 64214
+
+	// SourceLossTimeout この時間データを受信しなかった送信元は切断されたとみなす
+	SourceLossTimeout = 2500 * time.Millisecond
+	// DiscoveryInterval 送信元がユニバースディスカバリーを送信する間隔
+	DiscoveryInterval = 10 * time.Second
+
+	// DefaultPriority 優先度の既定値（0-200）
+	DefaultPriority = 100
+	MaxPriority     = 200
+)
+
+// Options フィールドのビット
+const (
+	OptionPreviewData      uint8 = 0x80
+	OptionStreamTerminated uint8 = 0x40
+	OptionForceSync        uint8 = 0x20
+)
+
+// START Code
+const (
+	StartCodeDMX                uint8 = 0x00
+	StartCodePerAddressPriority uint8 = 0xDD
+)
+
+// 各レイヤーの Vector
+const (
+	vectorRootE131Data     uint32 = 0x00000004
+	vectorRootE131Extended uint32 = 0x00000008
+
+	vectorE131DataPacket        uint32 = 0x00000002
+	vectorE131ExtendedSync      uint32 = 0x00000001
+	vectorE131ExtendedDiscovery uint32 = 0x00000002
+
+	vectorDMPSetProperty        uint8  = 0x02
+	vectorUniverseDiscoveryList uint32 = 0x00000001
+)
+
+// エラー定義
+var (
+	ErrInvalidPacket      = errors.New("invalid E1.31 packet")
+	ErrUnsupportedVector  = errors.New("unsupported E1.31 vector")
+	ErrReceiverNotRunning = errors.New("sACN receiver is not running")
+	ErrUniverseOutOfRange = errors.New("sACN universe out of range")
+	ErrSenderNotOpen      = errors.New("sACN sender is not open")
+)
@@ -0,0 +1,58 @@
+package sacn
+
+import (
+	"encoding/hex"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/nasshu2916/dmx_viewer/internal/domain/model"
+)
+
+// NewDMXFrame データパケットからプロトコル共通の DMXFrame を作成する
+func NewDMXFrame(srcAddr net.Addr, p *DataPacket, receivedAt time.Time) (*model.DMXFrame, error) {
+	if len(p.Data) > 512 {
+		return nil, fmt.Errorf("invalid DMX data: length %d exceeds maximum DMX channels %d", len(p.Data), 512)
+	}
+
+	frame := &model.DMXFrame{
+		Protocol:   model.ProtocolSACN,
+		Universe:   p.Universe,
+		StartCode:  p.StartCode,
+		Length:     uint16(len(p.Data)),
+		SourceName: p.SourceName,
+		SourceCID:  FormatCID(p.CID),
+		Priority:   p.Priority,
+		Sequence:   p.Sequence,
+		ReceivedAt: receivedAt,
+	}
+	copy(frame.Data[:], p.Data)
+	if addr, ok := srcAddr.(*net.UDPAddr); ok {
+		frame.SourceIP = addr.IP
+		frame.SourcePort = addr.Port
+	}
+
+	if err := frame.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid DMX data: %w", err)
+	}
+
+	return frame, nil
+}
+
+// FormatCID CID を UUID の文字列表現にする
+func FormatCID(cid [16]byte) string {
+	s := hex.EncodeToString(cid[:])
+	return s[0:8] + "-" + s[8:12] + "-" + s[12:16] + "-" + s[16:20] + "-" + s[20:32]
+}
+
+// ParseCID UUID の文字列表現の CID を16バイトに変換する
+func ParseCID(s string) ([16]byte, error) {
+	var cid [16]byte
+	b, err := hex.DecodeString(strings.ReplaceAll(s, "-", ""))
+	if err != nil || len(b) != len(cid) {
+		return cid, fmt.Errorf("invalid CID %q", s)
+	}
+	copy(cid[:], b)
+	return cid, nil
+}
@@ -0,0 +1,259 @@
+package sacn
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"net"
+)
+
+// ANSI E1.31 (sACN) パケットのエンコード・デコード
+//
+// すべてのパケットは Root Layer で始まり、Root Layer の Vector によって
+// データパケット（Framing Layer + DMP Layer）か、拡張パケット（同期・ユニバースディスカバリー）かが決まる。
+// 多バイト値はすべてビッグエンディアン。
+
+// acnPacketIdentifier Root Layer の ACN Packet Identifier
+var acnPacketIdentifier = [12]byte{'A', 'S', 'C', '-', 'E', '1', '.', '1', '7', 0x00, 0x00, 0x00}
+
+const (
+	rootLayerSize      = 38  // Root Layer（CIDまで）
+	dataHeaderSize     = 126 // START Code までのヘッダー長
+	syncPacketSize     = 49
+	discoveryHeaderEnd = 120 // ユニバース一覧の開始位置
+	maxDiscoveryList   = 512 // 1ページあたりの最大ユニバース数
+)
+
+// Packet デコードしたE1.31パケット（*DataPacket, *SyncPacket, *DiscoveryPacket のいずれか）
+type Packet interface {
+	SenderCID() [16]byte
+}
+
+// DataPacket E1.31 データパケット
+type DataPacket struct {
+	CID         [16]byte
+	SourceName  string
+	Priority    uint8
+	SyncAddress uint16
+	Sequence    uint8
+	Options     uint8
+	Universe    uint16
+	StartCode   uint8
+	Data        []byte // START Code を除くスロットデータ（最大512）
+}
+
+// SyncPacket E1.31 同期パケット
+type SyncPacket struct {
+	CID         [16]byte
+	Sequence    uint8
+	SyncAddress uint16
+}
+
+// DiscoveryPacket E1.31 ユニバースディスカバリーパケット
+type DiscoveryPacket struct {
+	CID        [16]byte
+	SourceName string
+	Page       uint8
+	LastPage   uint8
+	Universes  []uint16
+}
+
+func (p *DataPacket) SenderCID() [16]byte      { return p.CID }
+func (p *SyncPacket) SenderCID() [16]byte      { return p.CID }
+func (p *DiscoveryPacket) SenderCID() [16]byte { return p.CID }
+
+// StreamTerminated 送信元が送信を終了したことを示すか
+func (p *DataPacket) StreamTerminated() bool {
+	return p.Options&OptionStreamTerminated != 0
+}
+
+// Preview 表示確認用のデータ（本番出力には使用しない）か
+func (p *DataPacket) Preview() bool {
+	return p.Options&OptionPreviewData != 0
+}
+
+// Unmarshal 受信したバイト列をE1.31パケットとしてデコードする
+func Unmarshal(b []byte) (Packet, error) {
+	// Root Layer と Framing Layer の PDU 長・ベクターまで（44バイト）を読み込む
+	if len(b) < rootLayerSize+6 {
+		return nil, fmt.Errorf("%w: too short (%d bytes)", ErrInvalidPacket, len(b))
+	}
+	if binary.BigEndian.Uint16(b[0:2]) != 0x0010 || binary.BigEndian.Uint16(b[2:4]) != 0x0000 {
+		return nil, fmt.Errorf("%w: bad preamble", ErrInvalidPacket)
+	}
+	if !bytes.Equal(b[4:16], acnPacketIdentifier[:]) {
+		return nil, fmt.Errorf("%w: bad ACN packet identifier", ErrInvalidPacket)
+	}
+	// Root Layer の PDU 長より後ろのパディングは無視する
+	length := pduLength(b[16:18])
+	if length < rootLayerSize-16+6 || length > len(b)-16 {
+		return nil, fmt.Errorf("%w: root layer length mismatch", ErrInvalidPacket)
+	}
+	b = b[:16+length]
+
+	var cid [16]byte
+	copy(cid[:], b[22:38])
+	framingVector := binary.BigEndian.Uint32(b[40:44])
+
+	switch binary.BigEndian.Uint32(b[18:22]) {
+	case vectorRootE131Data:
+		if framingVector != vectorE131DataPacket {
+			return nil, fmt.Errorf("%w: framing vector 0x%08x", ErrUnsupportedVector, framingVector)
+		}
+		return unmarshalData(cid, b)
+	case vectorRootE131Extended:
+		switch framingVector {
+		case vectorE131ExtendedSync:
+			return unmarshalSync(cid, b)
+		case vectorE131ExtendedDiscovery:
+			return unmarshalDiscovery(cid, b)
+		}
+		return nil, fmt.Errorf("%w: extended framing vector 0x%08x", ErrUnsupportedVector, framingVector)
+	default:
+		return nil, fmt.Errorf("%w: root vector 0x%08x", ErrUnsupportedVector, binary.BigEndian.Uint32(b[18:22]))
+	}
+}
+
+func unmarshalData(cid [16]byte, b []byte) (*DataPacket, error) {
+	if len(b) < dataHeaderSize {
+		return nil, fmt.Errorf("%w: data packet too short (%d bytes)", ErrInvalidPacket, len(b))
+	}
+	if b[117] != vectorDMPSetProperty || b[118] != 0xA1 {
+		return nil, fmt.Errorf("%w: bad DMP layer", ErrInvalidPacket)
+	}
+	count := int(binary.BigEndian.Uint16(b[123:125]))
+	if count < 1 || count > 513 || dataHeaderSize-1+count > len(b) {
+		return nil, fmt.Errorf("%w: bad property value count %d", ErrInvalidPacket, count)
+	}
+
+	p := &DataPacket{
+		CID:         cid,
+		SourceName:  decodeString(b[44:108]),
+		Priority:    b[108],
+		SyncAddress: binary.BigEndian.Uint16(b[109:111]),
+		Sequence:    b[111],
+		Options:     b[112],
+		Universe:    binary.BigEndian.Uint16(b[113:115]),
+		StartCode:   b[125],
+		Data:        append([]byte(nil), b[dataHeaderSize:dataHeaderSize-1+count]...),
+	}
+	if p.Priority > MaxPriority {
+		return nil, fmt.Errorf("%w: priority %d", ErrInvalidPacket, p.Priority)
+	}
+	if p.Universe < MinUniverse || p.Universe > MaxUniverse {
+		return nil, fmt.Errorf("%w: %d", ErrUniverseOutOfRange, p.Universe)
+	}
+	return p, nil
+}
+
+func unmarshalSync(cid [16]byte, b []byte) (*SyncPacket, error) {
+	if len(b) < syncPacketSize {
+		return nil, fmt.Errorf("%w: sync packet too short (%d bytes)", ErrInvalidPacket, len(b))
+	}
+	return &SyncPacket{
+		CID:         cid,
+		Sequence:    b[44],
+		SyncAddress: binary.BigEndian.Uint16(b[45:47]),
+	}, nil
+}
+
+func unmarshalDiscovery(cid [16]byte, b []byte) (*DiscoveryPacket, error) {
+	if len(b) < discoveryHeaderEnd || (len(b)-discoveryHeaderEnd)%2 != 0 {
+		return nil, fmt.Errorf("%w: bad discovery packet length %d", ErrInvalidPacket, len(b))
+	}
+	if binary.BigEndian.Uint32(b[114:118]) != vectorUniverseDiscoveryList {
+		return nil, fmt.Errorf("%w: bad universe discovery layer", ErrInvalidPacket)
+	}
+
+	p := &DiscoveryPacket{
+		CID:        cid,
+		SourceName: decodeString(b[44:108]),
+		Page:       b[118],
+		LastPage:   b[119],
+		Universes:  make([]uint16, 0, (len(b)-discoveryHeaderEnd)/2),
+	}
+	for i := discoveryHeaderEnd; i+1 < len(b); i += 2 {
+		p.Universes = append(p.Universes, binary.BigEndian.Uint16(b[i:i+2]))
+	}
+	return p, nil
+}
+
+// MarshalBinary データパケットをエンコードする
+func (p *DataPacket) MarshalBinary() ([]byte, error) {
+	if len(p.Data) > 512 {
+		return nil, fmt.Errorf("%w: %d slots exceeds 512", ErrInvalidPacket, len(p.Data))
+	}
+	b := make([]byte, dataHeaderSize+len(p.Data))
+	writeRootLayer(b, vectorRootE131Data, p.CID)
+
+	putPDUHeader(b[38:40], len(b)-38)
+	binary.BigEndian.PutUint32(b[40:44], vectorE131DataPacket)
+	copy(b[44:107], p.SourceName) // 終端のヌル文字を残す
+	b[108] = p.Priority
+	binary.BigEndian.PutUint16(b[109:111], p.SyncAddress)
+	b[111] = p.Sequence
+	b[112] = p.Options
+	binary.BigEndian.PutUint16(b[113:115], p.Universe)
+
+	putPDUHeader(b[115:117], len(b)-115)
+	b[117] = vectorDMPSetProperty
+	b[118] = 0xA1
+	binary.BigEndian.PutUint16(b[119:121], 0x0000) // First Property Address
+	binary.BigEndian.PutUint16(b[121:123], 0x0001) // Address Increment
+	binary.BigEndian.PutUint16(b[123:125], uint16(len(p.Data)+1))
+	b[125] = p.StartCode
+	copy(b[dataHeaderSize:], p.Data)
+	return b, nil
+}
+
+// MarshalBinary ユニバースディスカバリーパケットをエンコードする
+func (p *DiscoveryPacket) MarshalBinary() ([]byte, error) {
+	if len(p.Universes) > maxDiscoveryList {
+		return nil, fmt.Errorf("%w: %d universes exceeds %d per page", ErrInvalidPacket, len(p.Universes), maxDiscoveryList)
+	}
+	b := make([]byte, discoveryHeaderEnd+2*len(p.Universes))
+	writeRootLayer(b, vectorRootE131Extended, p.CID)
+
+	putPDUHeader(b[38:40], len(b)-38)
+	binary.BigEndian.PutUint32(b[40:44], vectorE131ExtendedDiscovery)
+	copy(b[44:107], p.SourceName) // 終端のヌル文字を残す
+
+	putPDUHeader(b[112:114], len(b)-112)
+	binary.BigEndian.PutUint32(b[114:118], vectorUniverseDiscoveryList)
+	b[118] = p.Page
+	b[119] = p.LastPage
+	for i, universe := range p.Universes {
+		binary.BigEndian.PutUint16(b[discoveryHeaderEnd+2*i:], universe)
+	}
+	return b, nil
+}
+
+// MulticastAddr ユニバースのマルチキャストグループアドレス（239.255.{上位}.{下位}）を返す
+func MulticastAddr(universe uint16) net.IP {
+	return net.IPv4(239, 255, byte(universe>>8), byte(universe))
+}
+
+func writeRootLayer(b []byte, vector uint32, cid [16]byte) {
+	binary.BigEndian.PutUint16(b[0:2], 0x0010)
+	binary.BigEndian.PutUint16(b[2:4], 0x0000)
+	copy(b[4:16], acnPacketIdentifier[:])
+	putPDUHeader(b[16:18], len(b)-16)
+	binary.BigEndian.PutUint32(b[18:22], vector)
+	copy(b[22:38], cid[:])
+}
+
+// putPDUHeader Flags (0x7) と PDU長（下位12bit）を書き込む
+func putPDUHeader(b []byte, length int) {
+	binary.BigEndian.PutUint16(b, 0x7000|uint16(length&0x0FFF))
+}
+
+func pduLength(b []byte) int {
+	return int(binary.BigEndian.Uint16(b) & 0x0FFF)
+}
+
+func decodeString(b []byte) string {
+	if i := bytes.IndexByte(b, 0); i >= 0 {
+		b = b[:i]
+	}
+	return string(b)
+}
@@ -0,0 +1,134 @@
+package sacn
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/nasshu2916/dmx_viewer/internal/domain/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testCID = [16]byte{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0a, 0x0b, 0x0c, 0x0d, 0x0e, 0x0f, 0x10}
+
+func TestDataPacket_RoundTrip(t *testing.T) {
+	want := &DataPacket{
+		CID:         testCID,
+		SourceName:  "Console A",
+		Priority:    150,
+		SyncAddress: 0,
+		Sequence:    42,
+		Options:     OptionPreviewData,
+		Universe:    1234,
+		StartCode:   StartCodeDMX,
+		Data:        []byte{0, 127, 255},
+	}
+
+	b, err := want.MarshalBinary()
+	require.NoError(t, err)
+	assert.Len(t, b, dataHeaderSize+3)
+
+	got, err := Unmarshal(b)
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+	assert.True(t, got.(*DataPacket).Preview())
+	assert.False(t, got.(*DataPacket).StreamTerminated())
+}
+
+func TestUnmarshal_IgnoresPadding(t *testing.T) {
+	p := &DataPacket{CID: testCID, Priority: DefaultPriority, Universe: 1, Data: []byte{10, 20}}
+	b, err := p.MarshalBinary()
+	require.NoError(t, err)
+
+	got, err := Unmarshal(append(b, 0, 0, 0, 0))
+	require.NoError(t, err)
+	assert.Equal(t, []byte{10, 20}, got.(*DataPacket).Data)
+}
+
+// truncateRootLayer Root Layer の PDU 長を合わせてパケットを n バイトに切り詰める
+func truncateRootLayer(b []byte, n int) []byte {
+	length := n - 16
+	b[16], b[17] = 0x70|byte(length>>8), byte(length)
+	return b[:n]
+}
+
+func TestUnmarshal_Invalid(t *testing.T) {
+	valid, err := (&DataPacket{CID: testCID, Priority: DefaultPriority, Universe: 1, Data: []byte{1}}).MarshalBinary()
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		modify  func(b []byte) []byte
+		wantErr error
+	}{
+		{name: "Too short", modify: func(b []byte) []byte { return b[:20] }, wantErr: ErrInvalidPacket},
+		{name: "Truncated before framing vector (42 bytes)", modify: func(b []byte) []byte { return truncateRootLayer(b, 42) }, wantErr: ErrInvalidPacket},
+		{name: "Truncated before framing vector (43 bytes)", modify: func(b []byte) []byte { return truncateRootLayer(b, 43) }, wantErr: ErrInvalidPacket},
+		{name: "Bad identifier", modify: func(b []byte) []byte { b[4] = 'X'; return b }, wantErr: ErrInvalidPacket},
+		{name: "Unknown root vector", modify: func(b []byte) []byte { b[21] = 0x05; return b }, wantErr: ErrUnsupportedVector},
+		{name: "Priority out of range", modify: func(b []byte) []byte { b[108] = 201; return b }, wantErr: ErrInvalidPacket},
+		{name: "Universe 0", modify: func(b []byte) []byte { b[113], b[114] = 0, 0; return b }, wantErr: ErrUniverseOutOfRange},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := tt.modify(append([]byte(nil), valid...))
+			_, err := Unmarshal(b)
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestDiscoveryPacket_RoundTrip(t *testing.T) {
+	want := &DiscoveryPacket{
+		CID:        testCID,
+		SourceName: "Console A",
+		Page:       0,
+		LastPage:   1,
+		Universes:  []uint16{1, 2, 100},
+	}
+
+	b, err := want.MarshalBinary()
+	require.NoError(t, err)
+
+	got, err := Unmarshal(b)
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+}
+
+func TestUnmarshal_SyncPacket(t *testing.T) {
+	b := make([]byte, syncPacketSize)
+	writeRootLayer(b, vectorRootE131Extended, testCID)
+	putPDUHeader(b[38:40], len(b)-38)
+	b[43] = byte(vectorE131ExtendedSync)
+	b[44] = 7
+	b[45], b[46] = 0x00, 0x05
+
+	got, err := Unmarshal(b)
+	require.NoError(t, err)
+	assert.Equal(t, &SyncPacket{CID: testCID, Sequence: 7, SyncAddress: 5}, got)
+}
+
+func TestMulticastAddr(t *testing.T) {
+	assert.True(t, MulticastAddr(1).Equal(net.IPv4(239, 255, 0, 1)))
+	assert.True(t, MulticastAddr(DiscoveryUniverse).Equal(net.IPv4(239, 255, 250, 214)))
+}
+
+func TestNewDMXFrame(t *testing.T) {
+	p := &DataPacket{CID: testCID, SourceName: "Console A", Priority: 150, Sequence: 3, Universe: 40000, Data: []byte{1, 2, 3}}
+	addr := &net.UDPAddr{IP: net.IPv4(10, 0, 0, 5), Port: DefaultPort}
+	receivedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	frame, err := NewDMXFrame(addr, p, receivedAt)
+	require.NoError(t, err)
+	assert.Equal(t, model.ProtocolSACN, frame.Protocol)
+	assert.Equal(t, uint16(40000), frame.Universe)
+	assert.Equal(t, uint16(3), frame.Length)
+	assert.Equal(t, []byte{1, 2, 3}, frame.Data[:3])
+	assert.Equal(t, "Console A", frame.SourceName)
+	assert.Equal(t, "01020304-0506-0708-090a-0b0c0d0e0f10", frame.SourceCID)
+	assert.Equal(t, uint8(150), frame.Priority)
+	assert.Equal(t, receivedAt, frame.ReceivedAt)
+	assert.True(t, frame.SourceIP.Equal(net.IPv4(10, 0, 0, 5)))
+}
@@ -0,0 +1,242 @@
+package sacn
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/nasshu2916/dmx_viewer/internal/config"
+	"github.com/nasshu2916/dmx_viewer/internal/domain/model"
+	"github.com/nasshu2916/dmx_viewer/internal/infrastructure/artnet"
+	"github.com/nasshu2916/dmx_viewer/pkg/logger"
+	"golang.org/x/net/ipv4"
+)
+
+// Receiver E1.31 パケットを受信し、指定したユニバースのマルチキャストグループに参加する
+type Receiver struct {
+	logger            *logger.Logger
+	config            *config.SACN
+	conn              net.PacketConn
+	packetConn        *ipv4.PacketConn
+	netIface          *net.Interface
+	iface             atomic.Pointer[model.NetworkInterface]
+	port              int
+	running           atomic.Bool
+	done              chan struct{}
+	receivedChan      chan model.ReceivedData // 受信したsACNパケットを送信するチャネル
+	channelBufferSize int
+	droppedPackets    int64
+
+	mu             sync.Mutex
+	joined         map[uint16]struct{} // 参加中のユニバース
+	extraUniverses []uint16            // 設定以外に起動時に参加するユニバース
+}
+
+func NewReceiver(logger *logger.Logger, cfg *config.SACN) *Receiver {
+	channelBufferSize := cfg.ChannelBufferSize
+	if channelBufferSize <= 0 {
+		channelBufferSize = DefaultChannelBufferSize
+	}
+
+	return &Receiver{
+		logger:            logger,
+		config:            cfg,
+		port:              DefaultPort,
+		done:              make(chan struct{}),
+		receivedChan:      make(chan model.ReceivedData, channelBufferSize),
+		channelBufferSize: channelBufferSize,
+		joined:            make(map[uint16]struct{}),
+	}
+}
+
+// Run 受信を開始し、Stop が呼ばれるまでブロックする
+func (r *Receiver) Run() error {
+	iface, err := artnet.SelectInterface(r.config.Interface)
+	if err != nil {
+		return fmt.Errorf("sACN receiver startup failed: %w", err)
+	}
+	netIface, err := net.InterfaceByName(iface.Name)
+	if err != nil {
+		return fmt.Errorf("sACN receiver startup failed: %w", err)
+	}
+
+	universes, err := model.ParseSACNUniverseList(r.config.Universes)
+	if err != nil {
+		return fmt.Errorf("sACN receiver startup failed: %w", err)
+	}
+	r.mu.Lock()
+	universes = append(universes, r.extraUniverses...)
+	r.mu.Unlock()
+
+	// ユニキャストと複数のマルチキャストグループを受信するため全アドレスで待ち受ける
+	addr := fmt.Sprintf(":%d", r.port)
+	conn, err := (&net.ListenConfig{}).ListenPacket(context.Background(), "udp4", addr)
+	if err != nil {
+		return fmt.Errorf("sACN receiver startup failed: %w", err)
+	}
+	r.conn = conn
+	r.packetConn = ipv4.NewPacketConn(conn)
+	r.netIface = netIface
+	r.iface.Store(iface)
+	r.running.Store(true)
+
+	// ユニバースディスカバリーは常に受信する
+	if err := r.joinGroup(DiscoveryUniverse); err != nil {
+		r.logger.Warn("Failed to join sACN universe discovery group", "error", err)
+	}
+	for _, universe := range universes {
+		if err := r.JoinUniverse(universe); err != nil {
+			r.logger.Warn("Failed to join sACN multicast group", "universe", universe, "error", err)
+		}
+	}
+
+	r.logger.Info("sACN receiver started",
+		"address", addr,
+		"interface", iface.Name,
+		"ip", iface.IP.String(),
+		"universes", universes,
+		"channelBufferSize", r.channelBufferSize)
+
+	go r.runReceiver()
+
+	defer func() {
+		r.running.Store(false)
+		if r.conn != nil {
+			r.conn.Close()
+			r.logger.Info("sACN receiver connection closed")
+		}
+		close(r.receivedChan)
+	}()
+
+	<-r.done
+	return nil
+}
+
+func (r *Receiver) Stop() {
+	select {
+	case <-r.done:
+	default:
+		close(r.done)
+	}
+}
+
+// IsRunning 受信ソケットが確立しているか
+func (r *Receiver) IsRunning() bool {
+	return r.running.Load()
+}
+
+func (r *Receiver) ReceivedChan() <-chan model.ReceivedData {
+	return r.receivedChan
+}
+
+// LocalInterface 受信に使用しているネットワークインターフェースを返す（起動前はnil）
+func (r *Receiver) LocalInterface() *model.NetworkInterface {
+	return r.iface.Load()
+}
+
+// AddUniverses 起動時に参加するユニバースを追加する（Run の前に呼ぶ）
+func (r *Receiver) AddUniverses(universes ...uint16) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.extraUniverses = append(r.extraUniverses, universes...)
+}
+
+// JoinUniverse ユニバースのマルチキャストグループに参加する（参加済みの場合は何もしない）
+func (r *Receiver) JoinUniverse(universe uint16) error {
+	if universe < MinUniverse || universe > MaxUniverse {
+		return fmt.Errorf("%w: %d", ErrUniverseOutOfRange, universe)
+	}
+	return r.joinGroup(universe)
+}
+
+// LeaveUniverse ユニバースのマルチキャストグループから離脱する
+func (r *Receiver) LeaveUniverse(universe uint16) error {
+	if !r.IsRunning() {
+		return ErrReceiverNotRunning
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.joined[universe]; !ok {
+		return nil
+	}
+	if err := r.packetConn.LeaveGroup(r.netIface, &net.UDPAddr{IP: MulticastAddr(universe)}); err != nil {
+		return err
+	}
+	delete(r.joined, universe)
+	return nil
+}
+
+// JoinedUniverses 参加中のユニバースを昇順で返す（ディスカバリー用のユニバースは含まない）
+func (r *Receiver) JoinedUniverses() []uint16 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	universes := make([]uint16, 0, len(r.joined))
+	for universe := range r.joined {
+		if universe != DiscoveryUniverse {
+			universes = append(universes, universe)
+		}
+	}
+	sort.Slice(universes, func(i, j int) bool { return universes[i] < universes[j] })
+	return universes
+}
+
+func (r *Receiver) joinGroup(universe uint16) error {
+	if !r.IsRunning() {
+		return ErrReceiverNotRunning
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.joined[universe]; ok {
+		return nil
+	}
+	if err := r.packetConn.JoinGroup(r.netIface, &net.UDPAddr{IP: MulticastAddr(universe)}); err != nil {
+		return err
+	}
+	r.joined[universe] = struct{}{}
+	r.logger.Debug("Joined sACN multicast group", "universe", universe, "group", MulticastAddr(universe).String())
+	return nil
+}
+
+// runReceiver 受信処理を行うゴルーチン
+func (r *Receiver) runReceiver() {
+	panicHandler := artnet.NewPanicHandler(r.logger, "sacn receiver")
+	defer panicHandler.Handle()
+
+	buffer := make([]byte, DefaultMaxPacketSize)
+	for {
+		select {
+		case <-r.done:
+			r.logger.Debug("sACN receiver stopped")
+			return
+		default:
+		}
+
+		r.conn.SetReadDeadline(time.Now().Add(DefaultReadTimeout))
+		n, addr, err := r.conn.ReadFrom(buffer)
+		if err != nil {
+			if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
+				continue
+			}
+			select {
+			case <-r.done:
+				return
+			default:
+			}
+			r.logger.Warn("Error reading from sACN, continuing to receive", "error", err)
+			continue
+		}
+
+		data := make([]byte, n)
+		copy(data, buffer[:n])
+		received := model.ReceivedData{Data: data, Addr: addr, ReceivedAt: time.Now()}
+		select {
+		case r.receivedChan <- received:
+		default:
+			artnet.DropPacketWithLog(r.logger, &r.droppedPackets, artnet.ReceiveChannel, len(r.receivedChan), r.channelBufferSize, addr.String())
+		}
+	}
+}
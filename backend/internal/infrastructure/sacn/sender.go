@@ -0,0 +1,107 @@
+package sacn
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"net"
+	"sync"
+
+	"github.com/nasshu2916/dmx_viewer/internal/infrastructure/artnet"
+	"github.com/nasshu2916/dmx_viewer/pkg/logger"
+	"golang.org/x/net/ipv4"
+)
+
+// Sender E1.31 データパケットをユニバースのマルチキャストグループに送信する
+type Sender struct {
+	logger        *logger.Logger
+	interfaceSpec string
+
+	mu         sync.Mutex
+	conn       net.PacketConn
+	packetConn *ipv4.PacketConn
+}
+
+// NewSender Senderの新しいインスタンスを作成（interfaceSpec の形式は SACN_INTERFACE と同じ）
+func NewSender(logger *logger.Logger, interfaceSpec string) *Sender {
+	return &Sender{
+		logger:        logger,
+		interfaceSpec: interfaceSpec,
+	}
+}
+
+// Open 送信用のソケットを作成し、マルチキャストの送信インターフェースを設定する
+func (s *Sender) Open() error {
+	iface, err := artnet.SelectInterface(s.interfaceSpec)
+	if err != nil {
+		return fmt.Errorf("sACN sender startup failed: %w", err)
+	}
+	netIface, err := net.InterfaceByName(iface.Name)
+	if err != nil {
+		return fmt.Errorf("sACN sender startup failed: %w", err)
+	}
+
+	conn, err := (&net.ListenConfig{}).ListenPacket(context.Background(), "udp4", net.JoinHostPort(iface.IP.String(), "0"))
+	if err != nil {
+		return fmt.Errorf("sACN sender startup failed: %w", err)
+	}
+	packetConn := ipv4.NewPacketConn(conn)
+	if err := packetConn.SetMulticastInterface(netIface); err != nil {
+		conn.Close()
+		return fmt.Errorf("sACN sender startup failed: %w", err)
+	}
+	// 同じホストの受信機でも送信内容を確認できるようにする
+	if err := packetConn.SetMulticastLoopback(true); err != nil {
+		s.logger.Warn("Failed to enable sACN multicast loopback", "error", err)
+	}
+
+	s.mu.Lock()
+	s.conn = conn
+	s.packetConn = packetConn
+	s.mu.Unlock()
+
+	s.logger.Info("sACN sender started", "interface", iface.Name, "ip", iface.IP.String())
+	return nil
+}
+
+// Send データパケットをユニバースのマルチキャストグループに送信する
+func (s *Sender) Send(p *DataPacket) error {
+	b, err := p.MarshalBinary()
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	conn := s.conn
+	s.mu.Unlock()
+	if conn == nil {
+		return ErrSenderNotOpen
+	}
+
+	_, err = conn.WriteTo(b, &net.UDPAddr{IP: MulticastAddr(p.Universe), Port: DefaultPort})
+	return err
+}
+
+// Close 送信用のソケットを閉じる
+func (s *Sender) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	s.packetConn = nil
+	return err
+}
+
+// NewCID 送信元を識別するCID（UUID version 4）を生成する
+func NewCID() ([16]byte, error) {
+	var cid [16]byte
+	if _, err := rand.Read(cid[:]); err != nil {
+		return cid, err
+	}
+	cid[6] = cid[6]&0x0f | 0x40
+	cid[8] = cid[8]&0x3f | 0x80
+	return cid, nil
+}
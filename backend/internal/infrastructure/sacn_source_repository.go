@@ -0,0 +1,56 @@
+package infrastructure
+
+import (
+	"bytes"
+	"sort"
+	"sync"
+
+	"github.com/nasshu2916/dmx_viewer/internal/domain/model"
+)
+
+type SACNSourceRepositoryImpl struct {
+	mu      sync.RWMutex
+	sources map[string]*model.SACNSource // CIDをキーとする
+}
+
+func NewSACNSourceRepository() *SACNSourceRepositoryImpl {
+	return &SACNSourceRepositoryImpl{
+		sources: make(map[string]*model.SACNSource),
+	}
+}
+
+func (r *SACNSourceRepositoryImpl) Save(source *model.SACNSource) {
+	r.mu.Lock()
+	r.sources[source.CID] = source
+	r.mu.Unlock()
+}
+
+// All IPアドレス、CIDの順に並べた送信元の一覧を返す
+func (r *SACNSourceRepositoryImpl) All() []*model.SACNSource {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	result := make([]*model.SACNSource, 0, len(r.sources))
+	for _, s := range r.sources {
+		result = append(result, s)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if c := bytes.Compare(result[i].IPAddress.To16(), result[j].IPAddress.To16()); c != 0 {
+			return c < 0
+		}
+		return result[i].CID < result[j].CID
+	})
+	return result
+}
+
+func (r *SACNSourceRepositoryImpl) Get(cid string) (*model.SACNSource, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	source, ok := r.sources[cid]
+	return source, ok
+}
+
+func (r *SACNSourceRepositoryImpl) Delete(cid string) {
+	r.mu.Lock()
+	delete(r.sources, cid)
+	r.mu.Unlock()
+}
@@ -0,0 +1,135 @@
+package infrastructure
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/nasshu2916/dmx_viewer/internal/domain/model"
+)
+
+// snapshotFileExtension スナップショットを保存するファイルの拡張子
+const snapshotFileExtension = ".json"
+
+// SnapshotRepositoryImpl スナップショットを1件ずつJSONファイルとしてディレクトリに保存する
+type SnapshotRepositoryImpl struct {
+	mu  sync.Mutex
+	dir string
+}
+
+func NewSnapshotRepository(dir string) *SnapshotRepositoryImpl {
+	return &SnapshotRepositoryImpl{dir: dir}
+}
+
+// Create 一時ファイルに書き込んでから保存先の名前にリンクし、書き込み途中の状態が残らないようにする
+// リンクは保存先が存在する場合に失敗するため、同じ名前で同時に作成しても上書きしない
+func (r *SnapshotRepositoryImpl) Create(snapshot *model.Snapshot) error {
+	path, err := r.path(snapshot.Name)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := os.MkdirAll(r.dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create snapshot directory %s: %w", r.dir, err)
+	}
+	tmp, err := os.CreateTemp(r.dir, ".snapshot.tmp*")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file for snapshot: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close snapshot: %w", err)
+	}
+	if err := os.Link(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to save snapshot %s: %w", path, err)
+	}
+	return nil
+}
+
+func (r *SnapshotRepositoryImpl) List() ([]model.SnapshotInfo, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entries, err := os.ReadDir(r.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return []model.SnapshotInfo{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot directory %s: %w", r.dir, err)
+	}
+
+	snapshots := make([]model.SnapshotInfo, 0, len(entries))
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), snapshotFileExtension)
+		if entry.IsDir() || !ok || model.ValidateSnapshotName(name) != nil {
+			continue
+		}
+		snapshot, err := r.read(filepath.Join(r.dir, entry.Name()))
+		if err != nil {
+			continue
+		}
+		snapshots = append(snapshots, model.SnapshotInfo{Name: snapshot.Name, CreatedAt: snapshot.CreatedAt, Universes: len(snapshot.Universes)})
+	}
+	sort.Slice(snapshots, func(i, j int) bool { return snapshots[i].CreatedAt.Before(snapshots[j].CreatedAt) })
+	return snapshots, nil
+}
+
+func (r *SnapshotRepositoryImpl) Get(name string) (*model.Snapshot, error) {
+	path, err := r.path(name)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.read(path)
+}
+
+func (r *SnapshotRepositoryImpl) Delete(name string) error {
+	path, err := r.path(name)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return os.Remove(path)
+}
+
+// path スナップショット名から保存先のパスを求める（使用できない名前は fs.ErrNotExist）
+func (r *SnapshotRepositoryImpl) path(name string) (string, error) {
+	if err := model.ValidateSnapshotName(name); err != nil {
+		return "", fmt.Errorf("invalid snapshot name %q: %w", name, fs.ErrNotExist)
+	}
+	return filepath.Join(r.dir, name+snapshotFileExtension), nil
+}
+
+func (r *SnapshotRepositoryImpl) read(path string) (*model.Snapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var snapshot model.Snapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, fmt.Errorf("failed to parse snapshot %s: %w", path, err)
+	}
+	return &snapshot, nil
+}
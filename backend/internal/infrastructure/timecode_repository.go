@@ -0,0 +1,28 @@
+package infrastructure
+
+import (
+	"sync"
+
+	"github.com/nasshu2916/dmx_viewer/internal/domain/model"
+)
+
+type TimeCodeRepositoryImpl struct {
+	mu     sync.RWMutex
+	latest *model.TimeCode
+}
+
+func NewTimeCodeRepository() *TimeCodeRepositoryImpl {
+	return &TimeCodeRepositoryImpl{}
+}
+
+func (r *TimeCodeRepositoryImpl) Save(timeCode *model.TimeCode) {
+	r.mu.Lock()
+	r.latest = timeCode
+	r.mu.Unlock()
+}
+
+func (r *TimeCodeRepositoryImpl) Latest() (*model.TimeCode, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.latest, r.latest != nil
+}
@@ -0,0 +1,170 @@
+package infrastructure
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/nasshu2916/dmx_viewer/internal/domain/model"
+	"github.com/nasshu2916/dmx_viewer/internal/infrastructure/pcap"
+)
+
+// ErrTrafficCaptureNotOpen 書き出しを開始していない
+var ErrTrafficCaptureNotOpen = errors.New("traffic capture file is not open")
+
+// TrafficCaptureRepositoryImpl 受信したUDPデータグラムを pcap ファイルとしてディレクトリに作成する
+type TrafficCaptureRepositoryImpl struct {
+	mu     sync.Mutex
+	dir    string
+	file   *os.File
+	buf    *bufio.Writer
+	writer *pcap.Writer
+	info   model.CaptureFile
+}
+
+// NewTrafficCaptureRepository 保存先のディレクトリを指定して作成する
+func NewTrafficCaptureRepository(dir string) *TrafficCaptureRepositoryImpl {
+	return &TrafficCaptureRepositoryImpl{dir: dir}
+}
+
+func (r *TrafficCaptureRepositoryImpl) Create(startedAt time.Time, broadcast net.IP) (model.CaptureFile, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, err := r.closeLocked(); err != nil {
+		return model.CaptureFile{}, err
+	}
+	if err := os.MkdirAll(r.dir, 0o755); err != nil {
+		return model.CaptureFile{}, fmt.Errorf("failed to create capture directory %s: %w", r.dir, err)
+	}
+
+	base := "traffic-" + startedAt.Format("20060102-150405.000")
+	var file *os.File
+	var err error
+	for i := 0; ; i++ {
+		name := base + pcap.FileExtension
+		if i > 0 {
+			name = fmt.Sprintf("%s-%d%s", base, i, pcap.FileExtension)
+		}
+		file, err = os.OpenFile(filepath.Join(r.dir, name), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+		if !errors.Is(err, os.ErrExist) {
+			break
+		}
+	}
+	if err != nil {
+		return model.CaptureFile{}, fmt.Errorf("failed to create pcap file: %w", err)
+	}
+
+	buf := bufio.NewWriter(file)
+	writer, err := pcap.NewWriter(buf, broadcast)
+	if err != nil {
+		file.Close()
+		return model.CaptureFile{}, fmt.Errorf("failed to write pcap header: %w", err)
+	}
+	r.file, r.buf, r.writer = file, buf, writer
+	r.info = model.CaptureFile{Name: filepath.Base(file.Name()), Path: file.Name(), Size: writer.Written(), StartedAt: startedAt}
+	return r.info, nil
+}
+
+func (r *TrafficCaptureRepositoryImpl) Write(datagram *model.CapturedDatagram) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.writer == nil {
+		return ErrTrafficCaptureNotOpen
+	}
+	err := r.writer.WriteDatagram(datagram)
+	r.info.Size = r.writer.Written()
+	return err
+}
+
+func (r *TrafficCaptureRepositoryImpl) Flush() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.buf == nil {
+		return nil
+	}
+	return r.buf.Flush()
+}
+
+func (r *TrafficCaptureRepositoryImpl) Close() (model.CaptureFile, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.closeLocked()
+}
+
+func (r *TrafficCaptureRepositoryImpl) List() ([]model.CaptureFile, error) {
+	entries, err := os.ReadDir(r.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return []model.CaptureFile{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read capture directory %s: %w", r.dir, err)
+	}
+
+	files := make([]model.CaptureFile, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != pcap.FileExtension {
+			continue
+		}
+		file, err := r.stat(entry.Name())
+		if err != nil {
+			continue
+		}
+		files = append(files, file)
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].StartedAt.Before(files[j].StartedAt) })
+	return files, nil
+}
+
+func (r *TrafficCaptureRepositoryImpl) Get(name string) (model.CaptureFile, error) {
+	if name == "" || name != filepath.Base(name) || filepath.Ext(name) != pcap.FileExtension {
+		return model.CaptureFile{}, fmt.Errorf("invalid pcap file name %q: %w", name, fs.ErrNotExist)
+	}
+	return r.stat(name)
+}
+
+// stat 先頭のパケットの時刻を作成した時刻としてファイルの情報を取得する
+// パケットを含まないファイルは更新時刻とする
+func (r *TrafficCaptureRepositoryImpl) stat(name string) (model.CaptureFile, error) {
+	path := filepath.Join(r.dir, name)
+	f, err := os.Open(path)
+	if err != nil {
+		return model.CaptureFile{}, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return model.CaptureFile{}, err
+	}
+	file := model.CaptureFile{Name: name, Path: path, Size: info.Size(), StartedAt: info.ModTime()}
+	reader, err := pcap.NewReader(f)
+	if err != nil {
+		return model.CaptureFile{}, err
+	}
+	if datagram, err := reader.Next(); err == nil {
+		file.StartedAt = datagram.Timestamp
+	}
+	return file, nil
+}
+
+func (r *TrafficCaptureRepositoryImpl) closeLocked() (model.CaptureFile, error) {
+	if r.file == nil {
+		return r.info, nil
+	}
+	err := r.buf.Flush()
+	if closeErr := r.file.Close(); err == nil {
+		err = closeErr
+	}
+	r.file, r.buf, r.writer = nil, nil, nil
+	return r.info, err
+}
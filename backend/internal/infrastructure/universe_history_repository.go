@@ -0,0 +1,229 @@
+package infrastructure
+
+import (
+	"sync"
+	"time"
+
+	"github.com/nasshu2916/dmx_viewer/internal/domain/model"
+)
+
+type universeHistoryKey struct {
+	protocol string
+	universe uint16
+}
+
+// historySample 受信したフレームをそのまま保持する履歴
+type historySample struct {
+	at   time.Time
+	data [512]uint8
+}
+
+// historyBucket 一定時間ごとに集計したチャンネルごとの最小・最大・合計
+type historyBucket struct {
+	start time.Time
+	count uint32
+	min   [512]uint8
+	max   [512]uint8
+	sum   [512]uint32
+}
+
+func (b *historyBucket) add(data *[512]uint8) {
+	for ch, v := range data {
+		if b.count == 0 || v < b.min[ch] {
+			b.min[ch] = v
+		}
+		if v > b.max[ch] {
+			b.max[ch] = v
+		}
+		b.sum[ch] += uint32(v)
+	}
+	b.count++
+}
+
+func (b *historyBucket) point(ch int) model.ChannelHistoryPoint {
+	return model.ChannelHistoryPoint{
+		Time:  b.start,
+		Min:   b.min[ch],
+		Max:   b.max[ch],
+		Avg:   float64(b.sum[ch]) / float64(b.count),
+		Count: b.count,
+	}
+}
+
+// universeHistory 1ユニバース分の履歴（いずれも古い順）
+type universeHistory struct {
+	raw     []historySample
+	seconds []*historyBucket
+	tens    []*historyBucket
+}
+
+type UniverseHistoryRepositoryImpl struct {
+	mu        sync.RWMutex
+	policy    model.HistoryPolicy
+	histories map[universeHistoryKey]*universeHistory
+}
+
+func NewUniverseHistoryRepository(policy model.HistoryPolicy) *UniverseHistoryRepositoryImpl {
+	return &UniverseHistoryRepositoryImpl{
+		policy:    policy,
+		histories: make(map[universeHistoryKey]*universeHistory),
+	}
+}
+
+func (r *UniverseHistoryRepositoryImpl) Append(protocol string, universe uint16, at time.Time, data *[512]uint8) {
+	key := universeHistoryKey{protocol: protocol, universe: universe}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	h, ok := r.histories[key]
+	if !ok {
+		h = &universeHistory{}
+		r.histories[key] = h
+	}
+	if r.policy.FullRate > 0 {
+		h.raw = append(h.raw, historySample{at: at, data: *data})
+	}
+	if r.policy.Second > 0 {
+		h.seconds = addToBucket(h.seconds, at, model.HistorySecondResolution, data)
+	}
+	h.tens = addToBucket(h.tens, at, model.HistoryTenSecResolution, data)
+}
+
+// addToBucket 時刻を含む区間に集計する（時刻が戻った場合は最新の区間に含める）
+func addToBucket(buckets []*historyBucket, at time.Time, resolution time.Duration, data *[512]uint8) []*historyBucket {
+	start := at.Truncate(resolution)
+	if n := len(buckets); n > 0 && !start.After(buckets[n-1].start) {
+		buckets[n-1].add(data)
+		return buckets
+	}
+	bucket := &historyBucket{start: start}
+	bucket.add(data)
+	return append(buckets, bucket)
+}
+
+func (r *UniverseHistoryRepositoryImpl) Query(protocol string, universe uint16, channel int, from, to time.Time) []model.ChannelHistoryPoint {
+	points := make([]model.ChannelHistoryPoint, 0)
+	r.each(protocol, universe, from, to, func(at time.Time, bucket *historyBucket, sample *[512]uint8) {
+		if bucket != nil {
+			points = append(points, bucket.point(channel))
+			return
+		}
+		v := sample[channel]
+		points = append(points, model.ChannelHistoryPoint{Time: at, Min: v, Max: v, Avg: float64(v), Count: 1})
+	})
+	return points
+}
+
+func (r *UniverseHistoryRepositoryImpl) QueryRange(protocol string, universe uint16, first, last int, from, to time.Time) []model.ChannelRangeHistoryPoint {
+	points := make([]model.ChannelRangeHistoryPoint, 0)
+	r.each(protocol, universe, from, to, func(at time.Time, bucket *historyBucket, sample *[512]uint8) {
+		point := model.ChannelRangeHistoryPoint{Time: at, Count: 1, Avg: make([]float64, last-first+1)}
+		if bucket != nil {
+			point.Count = bucket.count
+		}
+		for ch := first; ch <= last; ch++ {
+			if bucket != nil {
+				point.Avg[ch-first] = float64(bucket.sum[ch]) / float64(bucket.count)
+			} else {
+				point.Avg[ch-first] = float64(sample[ch])
+			}
+		}
+		points = append(points, point)
+	})
+	return points
+}
+
+// each 解像度の高い履歴を優先してつなぎ合わせた [from, to) の履歴を古い順に visit に渡す
+// 集計した区間は bucket、受信したフレームそのままの履歴は sample に渡す
+//
+// 解像度の低い区間と重複しないよう、高い解像度の履歴は低い解像度の区間の境界から使用する。
+func (r *UniverseHistoryRepositoryImpl) each(protocol string, universe uint16, from, to time.Time, visit func(at time.Time, bucket *historyBucket, sample *[512]uint8)) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	h, ok := r.histories[universeHistoryKey{protocol: protocol, universe: universe}]
+	if !ok {
+		return
+	}
+
+	var secondsFrom, rawFrom time.Time // ゼロ値の場合はその解像度の履歴がない
+	if len(h.seconds) > 0 {
+		secondsFrom = ceilTime(h.seconds[0].start, model.HistoryTenSecResolution)
+	}
+	if len(h.raw) > 0 {
+		rawFrom = ceilTime(h.raw[0].at, model.HistorySecondResolution)
+	}
+
+	for _, b := range h.tens {
+		if !secondsFrom.IsZero() && !b.start.Before(secondsFrom) {
+			break
+		}
+		if inRange(b.start, model.HistoryTenSecResolution, from, to) {
+			visit(b.start, b, nil)
+		}
+	}
+	for _, b := range h.seconds {
+		if b.start.Before(secondsFrom) {
+			continue
+		}
+		if !rawFrom.IsZero() && !b.start.Before(rawFrom) {
+			break
+		}
+		if inRange(b.start, model.HistorySecondResolution, from, to) {
+			visit(b.start, b, nil)
+		}
+	}
+	for i := range h.raw {
+		s := &h.raw[i]
+		if s.at.Before(rawFrom) || s.at.Before(from) {
+			continue
+		}
+		if !s.at.Before(to) {
+			break
+		}
+		visit(s.at, nil, &s.data)
+	}
+}
+
+// Prune 解像度ごとの保持期間を過ぎた履歴を削除する
+func (r *UniverseHistoryRepositoryImpl) Prune(now time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for key, h := range r.histories {
+		i := 0
+		for i < len(h.raw) && h.raw[i].at.Before(now.Add(-r.policy.FullRate)) {
+			i++
+		}
+		h.raw = h.raw[i:]
+		h.seconds = pruneBuckets(h.seconds, model.HistorySecondResolution, now.Add(-r.policy.Second))
+		h.tens = pruneBuckets(h.tens, model.HistoryTenSecResolution, now.Add(-r.policy.Retention))
+		if len(h.raw) == 0 && len(h.seconds) == 0 && len(h.tens) == 0 {
+			delete(r.histories, key)
+		}
+	}
+}
+
+// pruneBuckets 終了時刻が threshold 以前の区間を削除する
+func pruneBuckets(buckets []*historyBucket, resolution time.Duration, threshold time.Time) []*historyBucket {
+	i := 0
+	for i < len(buckets) && !buckets[i].start.Add(resolution).After(threshold) {
+		i++
+	}
+	return buckets[i:]
+}
+
+// inRange 区間 [start, start+resolution) が [from, to) と重なるか
+func inRange(start time.Time, resolution time.Duration, from, to time.Time) bool {
+	return start.Add(resolution).After(from) && start.Before(to)
+}
+
+// ceilTime 時刻を resolution の倍数に切り上げる
+func ceilTime(t time.Time, resolution time.Duration) time.Time {
+	truncated := t.Truncate(resolution)
+	if truncated.Equal(t) {
+		return t
+	}
+	return truncated.Add(resolution)
+}
@@ -23,6 +23,15 @@ func (r *WebSocketRepositoryImpl) BroadcastToTopic(topic string, message []byte)
 	return nil
 }
 
+func (r *WebSocketRepositoryImpl) BroadcastToTopics(topics []string, text, binary func() []byte) error {
+	subscribeTopics := make([]websocket.SubscribeTopic, len(topics))
+	for i, topic := range topics {
+		subscribeTopics[i] = websocket.SubscribeTopic(topic)
+	}
+	r.hub.BroadcastEncoded(subscribeTopics, text, binary)
+	return nil
+}
+
 func (r *WebSocketRepositoryImpl) BroadcastToAll(message []byte) error {
 	r.hub.BroadcastMessage(websocket.AllSubscribedTopic, message)
 	return nil
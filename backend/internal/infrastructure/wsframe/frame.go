@@ -0,0 +1,148 @@
+// Package wsframe WebSocket で配信するDMXフレームのバイナリ表現の読み書きを行う
+//
+// バイナリ表現はDMXフレームのみに使用し、それ以外のメッセージは JSON のテキストメッセージで配信する。
+// すべての数値はビッグエンディアンとする。
+//
+//	offset size
+//	0      1    メッセージの種類（1 = DMXフレーム）
+//	1      1    ヘッダーの長さ H（Art-Net は38、sACN は54。読み込み時はスロットデータの位置として使用する）
+//	2      1    プロトコル（1 = Art-Net, 2 = sACN）
+//	3      1    フラグ（bit0: ArtSync による同期出力）
+//	4      2    ユニバース
+//	6      1    シーケンス番号
+//	7      1    START Code
+//	8      8    受信時刻（Unix時刻のマイクロ秒）
+//	16     16   送信元IPアドレス（IPv4 は IPv4-mapped IPv6 形式）
+//	32     2    送信元ポート
+//	34     1    優先度（sACN のみ）
+//	35     1    物理入力ポート（Art-Net のみ）
+//	36     2    スロット数 L（0-512）
+//	38     16   送信元のCID（sACN のみ）
+//	H      L    スロットデータ
+//
+// 送信元名とアドレスごとの優先度は含まない（sacn/sources トピックで取得する）。
+package wsframe
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/nasshu2916/dmx_viewer/internal/domain/model"
+	"github.com/nasshu2916/dmx_viewer/internal/infrastructure/sacn"
+)
+
+// メッセージの種類
+const (
+	MessageTypeDMXFrame uint8 = 1
+)
+
+const (
+	artNetHeaderSize = 38
+	sacnHeaderSize   = artNetHeaderSize + 16
+)
+
+// プロトコル
+const (
+	protocolArtNet uint8 = 1
+	protocolSACN   uint8 = 2
+)
+
+// フラグ
+const (
+	flagSynced uint8 = 0x01
+)
+
+var ErrInvalidFrame = errors.New("invalid binary DMX frame")
+
+// MarshalDMXFrame DMXフレームをバイナリ表現にエンコードする
+func MarshalDMXFrame(frame *model.DMXFrame) ([]byte, error) {
+	if frame.Length > 512 {
+		return nil, fmt.Errorf("%w: length %d exceeds 512", ErrInvalidFrame, frame.Length)
+	}
+	headerSize := artNetHeaderSize
+	if frame.Protocol == model.ProtocolSACN {
+		headerSize = sacnHeaderSize
+	}
+	length := int(frame.Length)
+	b := make([]byte, headerSize+length)
+	b[0] = MessageTypeDMXFrame
+	b[1] = uint8(headerSize)
+	b[2] = protocolArtNet
+	if frame.Protocol == model.ProtocolSACN {
+		b[2] = protocolSACN
+	}
+	if frame.Synced {
+		b[3] |= flagSynced
+	}
+	binary.BigEndian.PutUint16(b[4:6], frame.Universe)
+	b[6] = frame.Sequence
+	b[7] = frame.StartCode
+	if !frame.ReceivedAt.IsZero() {
+		binary.BigEndian.PutUint64(b[8:16], uint64(frame.ReceivedAt.UnixMicro()))
+	}
+	if ip := frame.SourceIP.To16(); ip != nil {
+		copy(b[16:32], ip)
+	}
+	binary.BigEndian.PutUint16(b[32:34], uint16(frame.SourcePort))
+	b[34] = frame.Priority
+	b[35] = frame.Physical
+	binary.BigEndian.PutUint16(b[36:38], frame.Length)
+	if headerSize == sacnHeaderSize {
+		if cid, err := sacn.ParseCID(frame.SourceCID); err == nil {
+			copy(b[38:54], cid[:])
+		}
+	}
+	copy(b[headerSize:], frame.Data[:length])
+	return b, nil
+}
+
+// UnmarshalDMXFrame バイナリ表現からDMXフレームをデコードする
+func UnmarshalDMXFrame(b []byte) (*model.DMXFrame, error) {
+	if len(b) < artNetHeaderSize {
+		return nil, fmt.Errorf("%w: message too short", ErrInvalidFrame)
+	}
+	if b[0] != MessageTypeDMXFrame {
+		return nil, fmt.Errorf("%w: unknown message type %d", ErrInvalidFrame, b[0])
+	}
+	headerSize := int(b[1])
+	length := int(binary.BigEndian.Uint16(b[36:38]))
+	if headerSize < artNetHeaderSize || length > 512 || len(b) != headerSize+length {
+		return nil, fmt.Errorf("%w: invalid header size %d or slot count %d", ErrInvalidFrame, headerSize, length)
+	}
+
+	frame := &model.DMXFrame{
+		Protocol:   model.ProtocolArtNet,
+		Synced:     b[3]&flagSynced != 0,
+		Universe:   binary.BigEndian.Uint16(b[4:6]),
+		Sequence:   b[6],
+		StartCode:  b[7],
+		SourceIP:   sourceIP(b[16:32]),
+		SourcePort: int(binary.BigEndian.Uint16(b[32:34])),
+		Priority:   b[34],
+		Physical:   b[35],
+		Length:     uint16(length),
+	}
+	if us := int64(binary.BigEndian.Uint64(b[8:16])); us != 0 {
+		frame.ReceivedAt = time.UnixMicro(us)
+	}
+	if b[2] == protocolSACN {
+		frame.Protocol = model.ProtocolSACN
+		if headerSize >= sacnHeaderSize {
+			frame.SourceCID = sacn.FormatCID([16]byte(b[38:54]))
+		}
+	}
+	copy(frame.Data[:], b[headerSize:])
+	return frame, nil
+}
+
+func sourceIP(b []byte) net.IP {
+	ip := make(net.IP, net.IPv6len)
+	copy(ip, b)
+	if v4 := ip.To4(); v4 != nil {
+		return v4
+	}
+	return ip
+}
@@ -0,0 +1,173 @@
+package wsframe
+
+import (
+	"encoding/json"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/nasshu2916/dmx_viewer/internal/domain/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarshalUnmarshalDMXFrame(t *testing.T) {
+	receivedAt := time.Date(2024, 1, 1, 12, 0, 0, 123456000, time.UTC)
+	artNetFrame := &model.DMXFrame{
+		Protocol:   model.ProtocolArtNet,
+		Universe:   0x0203,
+		Length:     4,
+		Data:       [512]uint8{1, 2, 3, 4},
+		SourceIP:   net.IPv4(2, 0, 0, 10),
+		SourcePort: 6454,
+		Sequence:   7,
+		Physical:   1,
+		Synced:     true,
+		ReceivedAt: receivedAt,
+	}
+	b, err := MarshalDMXFrame(artNetFrame)
+	require.NoError(t, err)
+	assert.Len(t, b, artNetHeaderSize+4)
+
+	got, err := UnmarshalDMXFrame(b)
+	require.NoError(t, err)
+	assert.Equal(t, model.ProtocolArtNet, got.Protocol)
+	assert.Equal(t, uint16(0x0203), got.Universe)
+	assert.Equal(t, []uint8{1, 2, 3, 4}, got.Data[:got.Length])
+	assert.Equal(t, "2.0.0.10", got.SourceIP.String())
+	assert.Equal(t, 6454, got.SourcePort)
+	assert.Equal(t, uint8(7), got.Sequence)
+	assert.Equal(t, uint8(1), got.Physical)
+	assert.True(t, got.Synced)
+	assert.Empty(t, got.SourceCID)
+	assert.True(t, receivedAt.Equal(got.ReceivedAt))
+
+	sacnFrame := &model.DMXFrame{
+		Protocol:   model.ProtocolSACN,
+		Universe:   40000,
+		Length:     512,
+		SourceIP:   net.ParseIP("fe80::1"),
+		SourcePort: 5568,
+		SourceCID:  "01020304-0506-0708-090a-0b0c0d0e0f10",
+		Priority:   150,
+	}
+	sacnFrame.Data[511] = 255
+	b, err = MarshalDMXFrame(sacnFrame)
+	require.NoError(t, err)
+	assert.Len(t, b, sacnHeaderSize+512)
+
+	got, err = UnmarshalDMXFrame(b)
+	require.NoError(t, err)
+	assert.Equal(t, model.ProtocolSACN, got.Protocol)
+	assert.Equal(t, uint16(40000), got.Universe)
+	assert.Equal(t, sacnFrame.Data, got.Data)
+	assert.Equal(t, "fe80::1", got.SourceIP.String())
+	assert.Equal(t, sacnFrame.SourceCID, got.SourceCID)
+	assert.Equal(t, uint8(150), got.Priority)
+	assert.True(t, got.ReceivedAt.IsZero())
+}
+
+func TestMarshalDMXFrame_InvalidLength(t *testing.T) {
+	_, err := MarshalDMXFrame(&model.DMXFrame{Length: 513})
+	assert.ErrorIs(t, err, ErrInvalidFrame)
+}
+
+func TestUnmarshalDMXFrame_Invalid(t *testing.T) {
+	valid, err := MarshalDMXFrame(&model.DMXFrame{Protocol: model.ProtocolArtNet, Length: 2})
+	require.NoError(t, err)
+
+	unknownType := append([]byte(nil), valid...)
+	unknownType[0] = 9
+	shortHeader := append([]byte(nil), valid...)
+	shortHeader[1] = 10
+
+	for name, b := range map[string][]byte{
+		"Too short":    valid[:artNetHeaderSize-1],
+		"Truncated":    valid[:len(valid)-1],
+		"Unknown type": unknownType,
+		"Short header": shortHeader,
+	} {
+		_, err := UnmarshalDMXFrame(b)
+		assert.ErrorIs(t, err, ErrInvalidFrame, name)
+	}
+}
+
+func benchmarkFrame() *model.DMXFrame {
+	frame := &model.DMXFrame{
+		Protocol:   model.ProtocolArtNet,
+		Universe:   1,
+		Length:     512,
+		SourceIP:   net.IPv4(2, 0, 0, 10),
+		SourcePort: 6454,
+		Sequence:   42,
+		ReceivedAt: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+	}
+	for i := range frame.Data {
+		frame.Data[i] = uint8(i * 7)
+	}
+	return frame
+}
+
+// BenchmarkEncodeDMXFrame_JSON 従来の JSON のテキストメッセージ（artnet_dmx_packet）へのエンコード
+func BenchmarkEncodeDMXFrame_JSON(b *testing.B) {
+	frame := benchmarkFrame()
+	var size int
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		data, err := json.Marshal(model.NewWebSocketMessage("artnet_dmx_packet", frame))
+		if err != nil {
+			b.Fatal(err)
+		}
+		size = len(data)
+	}
+	b.ReportMetric(float64(size), "bytes/frame")
+}
+
+// BenchmarkEncodeDMXFrame_Binary バイナリ表現へのエンコード
+func BenchmarkEncodeDMXFrame_Binary(b *testing.B) {
+	frame := benchmarkFrame()
+	var size int
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		data, err := MarshalDMXFrame(frame)
+		if err != nil {
+			b.Fatal(err)
+		}
+		size = len(data)
+	}
+	b.ReportMetric(float64(size), "bytes/frame")
+}
+
+// BenchmarkDecodeDMXFrame_JSON JSON のテキストメッセージからのデコード（クライアント側の負荷の目安）
+func BenchmarkDecodeDMXFrame_JSON(b *testing.B) {
+	data, err := json.Marshal(model.NewWebSocketMessage("artnet_dmx_packet", benchmarkFrame()))
+	require.NoError(b, err)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var msg struct {
+			Type string
+			Data struct {
+				Data [512]uint8
+			}
+		}
+		if err := json.Unmarshal(data, &msg); err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.ReportMetric(float64(len(data)), "bytes/frame")
+}
+
+// BenchmarkDecodeDMXFrame_Binary バイナリ表現からのデコード
+func BenchmarkDecodeDMXFrame_Binary(b *testing.B) {
+	data, err := MarshalDMXFrame(benchmarkFrame())
+	require.NoError(b, err)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := UnmarshalDMXFrame(data); err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.ReportMetric(float64(len(data)), "bytes/frame")
+}
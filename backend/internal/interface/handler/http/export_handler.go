@@ -0,0 +1,167 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/nasshu2916/dmx_viewer/internal/domain/model"
+	"github.com/nasshu2916/dmx_viewer/internal/infrastructure/export"
+	"github.com/nasshu2916/dmx_viewer/internal/interface/httpctx"
+	"github.com/nasshu2916/dmx_viewer/internal/usecase"
+	"github.com/nasshu2916/dmx_viewer/pkg/logger"
+)
+
+type ExportHandler struct {
+	exportUseCase usecase.ExportUseCase
+	logger        *logger.Logger
+}
+
+func NewExportHandler(exportUseCase usecase.ExportUseCase, logger *logger.Logger) *ExportHandler {
+	return &ExportHandler{
+		exportUseCase: exportUseCase,
+		logger:        logger,
+	}
+}
+
+// /api/export — チャンネルの値の時系列を CSV / NDJSON でダウンロード
+//
+// クエリパラメータ:
+//   - source: history（既定、サーバーが保持している履歴）または capture（file で指定したキャプチャファイル）
+//   - format: csv（既定）または ndjson
+//   - mode: changes（既定、値が変化したとき）または resample（step ごと）
+//   - protocol, universe: 書き出すユニバース（protocol の既定は artnet）
+//   - channels: チャンネルの範囲（"1-16" など。省略時はすべて）
+//   - from, to: UNIXミリ秒または RFC 3339
+//   - step: resample の間隔（"100ms" などの時間、または ミリ秒）
+func (h *ExportHandler) GetExport(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("export handler: GetExport",
+		"request_id", r.Header.Get("X-Request-Id"),
+		"real_ip", httpctx.RealIP(r.Context()),
+		"method", r.Method,
+		"path", r.URL.Path,
+	)
+
+	query := r.URL.Query()
+	format, err := model.ParseExportFormat(query.Get("format"))
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	opts, err := parseExportOptions(query.Get)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if opts, err = usecase.NormalizeExportOptions(opts); err != nil {
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	source := query.Get("source")
+	var name string
+	switch source {
+	case "", "history":
+		name = fmt.Sprintf("history-%s-%d", opts.Protocol, opts.Universe)
+	case "capture":
+		if query.Get("file") == "" {
+			writeJSONError(w, http.StatusBadRequest, "file is required for capture source")
+			return
+		}
+		name = fmt.Sprintf("%s-%d", strings.TrimSuffix(query.Get("file"), path.Ext(query.Get("file"))), opts.Universe)
+	default:
+		writeJSONError(w, http.StatusBadRequest, fmt.Sprintf("unknown source %q (history or capture)", source))
+		return
+	}
+
+	writer, err := export.NewWriter(w, format, opts.FirstChannel, opts.LastChannel)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	rows := &exportResponseWriter{w: w, writer: writer, format: format, name: name}
+	if source == "capture" {
+		err = h.exportUseCase.ExportCapture(r.Context(), query.Get("file"), opts, rows)
+	} else {
+		err = h.exportUseCase.ExportHistory(r.Context(), opts, rows)
+	}
+	if err == nil {
+		rows.writeHeader()
+		err = writer.Flush()
+	}
+	if err == nil {
+		return
+	}
+
+	// 書き出しを開始した後はステータスコードを変更できないため、書き出した行までを送信して終了する
+	if writer.Started() {
+		h.logger.Warn("Export stopped", "error", err)
+		_ = writer.Flush()
+		return
+	}
+	switch {
+	case errors.Is(err, usecase.ErrInvalidExportOptions):
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, usecase.ErrCaptureNotFound):
+		writeJSONError(w, http.StatusNotFound, err.Error())
+	default:
+		h.logger.Error("Failed to export", "error", err)
+		writeJSONError(w, http.StatusInternalServerError, err.Error())
+	}
+}
+
+// parseExportOptions クエリパラメータから書き出す条件を作成する
+func parseExportOptions(get func(string) string) (model.ExportOptions, error) {
+	var opts model.ExportOptions
+	var err error
+	if opts.Mode, err = model.ParseExportMode(get("mode")); err != nil {
+		return opts, err
+	}
+	opts.Protocol = get("protocol")
+	if s := get("universe"); s != "" {
+		universe, err := strconv.ParseUint(s, 10, 16)
+		if err != nil {
+			return opts, fmt.Errorf("invalid universe %q", s)
+		}
+		opts.Universe = uint16(universe)
+	}
+	if opts.FirstChannel, opts.LastChannel, err = model.ParseChannelRange(get("channels")); err != nil {
+		return opts, err
+	}
+	if opts.From, err = model.ParseTimeParam(get("from")); err != nil {
+		return opts, fmt.Errorf("invalid from: %w", err)
+	}
+	if opts.To, err = model.ParseTimeParam(get("to")); err != nil {
+		return opts, fmt.Errorf("invalid to: %w", err)
+	}
+	if opts.Step, err = model.ParseStepParam(get("step")); err != nil {
+		return opts, fmt.Errorf("invalid step: %w", err)
+	}
+	return opts, nil
+}
+
+// exportResponseWriter 最初の行を書き出すときにレスポンスヘッダーを設定する
+type exportResponseWriter struct {
+	w             http.ResponseWriter
+	writer        *export.Writer
+	format        model.ExportFormat
+	name          string
+	headerWritten bool
+}
+
+func (rw *exportResponseWriter) WriteRow(row *model.ExportRow) error {
+	rw.writeHeader()
+	return rw.writer.WriteRow(row)
+}
+
+func (rw *exportResponseWriter) writeHeader() {
+	if rw.headerWritten {
+		return
+	}
+	rw.headerWritten = true
+	rw.w.Header().Set("Content-Type", export.ContentType(rw.format))
+	rw.w.Header().Set("Content-Disposition", `attachment; filename="`+rw.name+export.FileExtension(rw.format)+`"`)
+}
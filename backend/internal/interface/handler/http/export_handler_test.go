@@ -0,0 +1,107 @@
+package http_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/nasshu2916/dmx_viewer/internal/domain/model"
+	internalHttp "github.com/nasshu2916/dmx_viewer/internal/interface/handler/http"
+	"github.com/nasshu2916/dmx_viewer/internal/usecase"
+	"github.com/nasshu2916/dmx_viewer/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockExportUseCase struct {
+	mock.Mock
+}
+
+func (m *MockExportUseCase) ExportHistory(ctx context.Context, opts model.ExportOptions, w usecase.ExportRowWriter) error {
+	args := m.Called(opts, w)
+	return args.Error(0)
+}
+
+func (m *MockExportUseCase) ExportCapture(ctx context.Context, name string, opts model.ExportOptions, w usecase.ExportRowWriter) error {
+	args := m.Called(name, opts, w)
+	return args.Error(0)
+}
+
+func writeExportRow(values ...float64) func(args mock.Arguments) {
+	return func(args mock.Arguments) {
+		w := args.Get(len(args) - 1).(usecase.ExportRowWriter)
+		_ = w.WriteRow(&model.ExportRow{Time: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), Source: "merged", Values: values})
+	}
+}
+
+func TestExportHandler_GetExport_History(t *testing.T) {
+	mockUseCase := new(MockExportUseCase)
+	opts := model.ExportOptions{Mode: model.ExportModeChanges, Protocol: model.ProtocolArtNet, Universe: 1, FirstChannel: 1, LastChannel: 2}
+	mockUseCase.On("ExportHistory", opts, mock.Anything).Run(writeExportRow(10, 255)).Return(nil)
+
+	handler := internalHttp.NewExportHandler(mockUseCase, logger.NewLogger("error"))
+	req := httptest.NewRequest(http.MethodGet, "/api/export?universe=1&channels=1-2", nil)
+	rec := httptest.NewRecorder()
+	handler.GetExport(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "text/csv; charset=utf-8", rec.Header().Get("Content-Type"))
+	assert.Equal(t, `attachment; filename="history-artnet-1.csv"`, rec.Header().Get("Content-Disposition"))
+	assert.Equal(t, "time,elapsed,source,ch1,ch2\n2024-01-01T00:00:00Z,0,merged,10,255\n", rec.Body.String())
+	mockUseCase.AssertExpectations(t)
+}
+
+func TestExportHandler_GetExport_Capture(t *testing.T) {
+	mockUseCase := new(MockExportUseCase)
+	opts := model.ExportOptions{Mode: model.ExportModeResample, Protocol: model.ProtocolSACN, Universe: 3, FirstChannel: 5, LastChannel: 5, Step: 100 * time.Millisecond}
+	mockUseCase.On("ExportCapture", "show.dmxcap", opts, mock.Anything).Run(writeExportRow(128)).Return(nil)
+
+	handler := internalHttp.NewExportHandler(mockUseCase, logger.NewLogger("error"))
+	req := httptest.NewRequest(http.MethodGet, "/api/export?source=capture&file=show.dmxcap&format=ndjson&mode=resample&step=100ms&protocol=sacn&universe=3&channels=5", nil)
+	rec := httptest.NewRecorder()
+	handler.GetExport(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/x-ndjson", rec.Header().Get("Content-Type"))
+	assert.Equal(t, `{"Time":"2024-01-01T00:00:00Z","Elapsed":0,"Source":"merged","Values":{"5":128}}`+"\n", rec.Body.String())
+	mockUseCase.AssertExpectations(t)
+}
+
+func TestExportHandler_GetExport_Errors(t *testing.T) {
+	mockUseCase := new(MockExportUseCase)
+	mockUseCase.On("ExportCapture", "missing.dmxcap", mock.Anything, mock.Anything).
+		Return(fmt.Errorf("%w: missing.dmxcap", usecase.ErrCaptureNotFound))
+	mockUseCase.On("ExportCapture", "broken.dmxcap", mock.Anything, mock.Anything).Return(errors.New("broken"))
+	mockUseCase.On("ExportCapture", "partial.dmxcap", mock.Anything, mock.Anything).Run(writeExportRow(1)).Return(errors.New("broken"))
+
+	handler := internalHttp.NewExportHandler(mockUseCase, logger.NewLogger("fatal"))
+	tests := []struct {
+		path string
+		want int
+	}{
+		{path: "/api/export?format=xml", want: http.StatusBadRequest},
+		{path: "/api/export?mode=sometimes", want: http.StatusBadRequest},
+		{path: "/api/export?mode=resample", want: http.StatusBadRequest},
+		{path: "/api/export?universe=x", want: http.StatusBadRequest},
+		{path: "/api/export?channels=10-1", want: http.StatusBadRequest},
+		{path: "/api/export?from=yesterday", want: http.StatusBadRequest},
+		{path: "/api/export?protocol=sacn&universe=0", want: http.StatusBadRequest},
+		{path: "/api/export?source=other", want: http.StatusBadRequest},
+		{path: "/api/export?source=capture", want: http.StatusBadRequest},
+		{path: "/api/export?source=capture&file=missing.dmxcap", want: http.StatusNotFound},
+		{path: "/api/export?source=capture&file=broken.dmxcap", want: http.StatusInternalServerError},
+		// 書き出しを開始した後のエラーはステータスコードを変更しない
+		{path: "/api/export?source=capture&file=partial.dmxcap&channels=1", want: http.StatusOK},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+		rec := httptest.NewRecorder()
+		handler.GetExport(rec, req)
+		assert.Equal(t, tt.want, rec.Code, tt.path)
+	}
+	mockUseCase.AssertExpectations(t)
+}
@@ -0,0 +1,82 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi"
+	"github.com/nasshu2916/dmx_viewer/internal/domain/model"
+	"github.com/nasshu2916/dmx_viewer/internal/interface/httpctx"
+	"github.com/nasshu2916/dmx_viewer/internal/usecase"
+	"github.com/nasshu2916/dmx_viewer/pkg/logger"
+)
+
+type HistoryHandler struct {
+	historyUseCase usecase.UniverseHistoryUseCase
+	logger         *logger.Logger
+}
+
+func NewHistoryHandler(historyUseCase usecase.UniverseHistoryUseCase, logger *logger.Logger) *HistoryHandler {
+	return &HistoryHandler{
+		historyUseCase: historyUseCase,
+		logger:         logger,
+	}
+}
+
+// /api/universes/{universe}/channels/{channel}/history — チャンネルの出力の履歴
+//
+// クエリパラメータ:
+//   - from, to: UNIXミリ秒または RFC 3339（省略時は直近の保持期間）
+//   - step: 集計する間隔（"1s" などの時間、または ミリ秒。省略時は保持している解像度のまま）
+//   - protocol: artnet（既定）または sacn
+func (h *HistoryHandler) GetChannelHistory(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("history handler: GetChannelHistory",
+		"request_id", r.Header.Get("X-Request-Id"),
+		"real_ip", httpctx.RealIP(r.Context()),
+		"method", r.Method,
+		"path", r.URL.Path,
+	)
+
+	w.Header().Set("Content-Type", "application/json")
+
+	universe, err := strconv.ParseUint(chi.URLParam(r, "universe"), 10, 16)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid universe")
+		return
+	}
+	channel, err := strconv.Atoi(chi.URLParam(r, "channel"))
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid channel")
+		return
+	}
+	query := model.ChannelHistoryQuery{
+		Protocol: r.URL.Query().Get("protocol"),
+		Universe: uint16(universe),
+		Channel:  channel,
+	}
+	if query.From, err = model.ParseTimeParam(r.URL.Query().Get("from")); err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid from: "+err.Error())
+		return
+	}
+	if query.To, err = model.ParseTimeParam(r.URL.Query().Get("to")); err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid to: "+err.Error())
+		return
+	}
+	if query.Step, err = model.ParseStepParam(r.URL.Query().Get("step")); err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid step: "+err.Error())
+		return
+	}
+
+	history, err := h.historyUseCase.ChannelHistory(query)
+	switch {
+	case err == nil:
+		_ = json.NewEncoder(w).Encode(history)
+	case errors.Is(err, usecase.ErrInvalidHistoryQuery):
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+	default:
+		h.logger.Error("Failed to get channel history", "error", err)
+		writeJSONError(w, http.StatusInternalServerError, err.Error())
+	}
+}
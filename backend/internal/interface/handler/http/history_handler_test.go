@@ -0,0 +1,73 @@
+package http_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/nasshu2916/dmx_viewer/internal/domain/model"
+	internalHttp "github.com/nasshu2916/dmx_viewer/internal/interface/handler/http"
+	"github.com/nasshu2916/dmx_viewer/internal/usecase"
+	"github.com/nasshu2916/dmx_viewer/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockUniverseHistoryUseCase struct {
+	mock.Mock
+}
+
+func (m *MockUniverseHistoryUseCase) ChannelHistory(query model.ChannelHistoryQuery) (model.ChannelHistory, error) {
+	args := m.Called(query)
+	return args.Get(0).(model.ChannelHistory), args.Error(1)
+}
+
+func TestHistoryHandler_GetChannelHistory(t *testing.T) {
+	from := time.UnixMilli(1700000000000)
+	to := time.Date(2023, 11, 14, 23, 0, 0, 0, time.UTC)
+
+	mockUseCase := new(MockUniverseHistoryUseCase)
+	mockUseCase.On("ChannelHistory", model.ChannelHistoryQuery{Universe: 1, Channel: 10, From: from, To: to, Step: 10 * time.Second}).
+		Return(model.ChannelHistory{Universe: 1, Channel: 10, Points: []model.ChannelHistoryPoint{{Time: from, Min: 1, Max: 3, Avg: 2}}}, nil)
+	mockUseCase.On("ChannelHistory", model.ChannelHistoryQuery{Protocol: model.ProtocolSACN, Universe: 2, Channel: 1, Step: 500 * time.Millisecond}).
+		Return(model.ChannelHistory{Protocol: model.ProtocolSACN, Universe: 2, Channel: 1}, nil)
+	mockUseCase.On("ChannelHistory", model.ChannelHistoryQuery{Universe: 1, Channel: 513}).
+		Return(model.ChannelHistory{}, fmt.Errorf("%w: channel", usecase.ErrInvalidHistoryQuery))
+
+	handler := internalHttp.NewHistoryHandler(mockUseCase, logger.NewLogger("error"))
+	r := chi.NewRouter()
+	r.Get("/api/universes/{universe}/channels/{channel}/history", handler.GetChannelHistory)
+
+	tests := []struct {
+		path string
+		want int
+	}{
+		{path: "/api/universes/1/channels/10/history?from=1700000000000&to=2023-11-14T23:00:00Z&step=10s", want: http.StatusOK},
+		{path: "/api/universes/2/channels/1/history?protocol=sacn&step=500", want: http.StatusOK},
+		{path: "/api/universes/1/channels/513/history", want: http.StatusBadRequest},
+		{path: "/api/universes/x/channels/1/history", want: http.StatusBadRequest},
+		{path: "/api/universes/1/channels/x/history", want: http.StatusBadRequest},
+		{path: "/api/universes/1/channels/1/history?from=yesterday", want: http.StatusBadRequest},
+		{path: "/api/universes/1/channels/1/history?step=fast", want: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		assert.Equal(t, tt.want, rec.Code, tt.path)
+	}
+	mockUseCase.AssertExpectations(t)
+
+	req := httptest.NewRequest(http.MethodGet, tests[0].path, nil)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	var history model.ChannelHistory
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &history))
+	require.Len(t, history.Points, 1)
+	assert.Equal(t, 2.0, history.Points[0].Avg)
+}
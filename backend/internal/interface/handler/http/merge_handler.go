@@ -0,0 +1,80 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi"
+	"github.com/nasshu2916/dmx_viewer/internal/domain/model"
+	"github.com/nasshu2916/dmx_viewer/internal/interface/httpctx"
+	"github.com/nasshu2916/dmx_viewer/internal/usecase"
+	"github.com/nasshu2916/dmx_viewer/pkg/logger"
+)
+
+type MergeHandler struct {
+	mergeUseCase usecase.MergeUseCase
+	logger       *logger.Logger
+}
+
+func NewMergeHandler(mergeUseCase usecase.MergeUseCase, logger *logger.Logger) *MergeHandler {
+	return &MergeHandler{
+		mergeUseCase: mergeUseCase,
+		logger:       logger,
+	}
+}
+
+type mergeModeRequest struct {
+	Mode   string `json:"mode"`   // HTP, LTP, SOURCE
+	Source string `json:"source"` // SOURCE の場合に表示する送信元IP
+}
+
+// /api/merge — ユニバースごとのマージモードと送信元
+func (h *MergeHandler) GetMergeStates(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("merge handler: GetMergeStates",
+		"request_id", r.Header.Get("X-Request-Id"),
+		"real_ip", httpctx.RealIP(r.Context()),
+		"method", r.Method,
+		"path", r.URL.Path,
+	)
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(h.mergeUseCase.GetMergeStates())
+}
+
+// /api/universes/{universe}/merge — ユニバースのマージモードを変更
+func (h *MergeHandler) PutMergeMode(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("merge handler: PutMergeMode",
+		"request_id", r.Header.Get("X-Request-Id"),
+		"real_ip", httpctx.RealIP(r.Context()),
+		"method", r.Method,
+		"path", r.URL.Path,
+	)
+
+	w.Header().Set("Content-Type", "application/json")
+
+	universe, err := strconv.ParseUint(chi.URLParam(r, "universe"), 10, 16)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid universe")
+		return
+	}
+
+	var req mergeModeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return
+	}
+	mode, err := model.ParseMergeMode(req.Mode)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	state, err := h.mergeUseCase.SetMergeMode(uint16(universe), mode, req.Source)
+	if err != nil {
+		h.logger.Warn("Failed to update merge mode", "error", err)
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	_ = json.NewEncoder(w).Encode(state)
+}
@@ -0,0 +1,60 @@
+package http_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/nasshu2916/dmx_viewer/internal/domain/model"
+	internalHttp "github.com/nasshu2916/dmx_viewer/internal/interface/handler/http"
+	"github.com/nasshu2916/dmx_viewer/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockMergeUseCase struct {
+	mock.Mock
+}
+
+func (m *MockMergeUseCase) GetMergeStates() []model.UniverseMergeState {
+	states, _ := m.Called().Get(0).([]model.UniverseMergeState)
+	return states
+}
+
+func (m *MockMergeUseCase) SetMergeMode(universe uint16, mode model.MergeMode, source string) (model.UniverseMergeState, error) {
+	args := m.Called(universe, mode, source)
+	return args.Get(0).(model.UniverseMergeState), args.Error(1)
+}
+
+func TestMergeHandler_PutMergeMode(t *testing.T) {
+	mockUseCase := new(MockMergeUseCase)
+	mockUseCase.On("SetMergeMode", uint16(3), model.MergeModeSource, "2.0.0.10").
+		Return(model.UniverseMergeState{Universe: 3, Mode: model.MergeModeSource, Selected: "2.0.0.10"}, nil)
+	mockUseCase.On("SetMergeMode", uint16(4), model.MergeModeSource, "").
+		Return(model.UniverseMergeState{}, errors.New("source is required"))
+
+	handler := internalHttp.NewMergeHandler(mockUseCase, logger.NewLogger("error"))
+	r := chi.NewRouter()
+	r.Put("/api/universes/{universe}/merge", handler.PutMergeMode)
+
+	tests := []struct {
+		path string
+		body string
+		want int
+	}{
+		{path: "/api/universes/3/merge", body: `{"mode":"source","source":"2.0.0.10"}`, want: http.StatusOK},
+		{path: "/api/universes/4/merge", body: `{"mode":"SOURCE"}`, want: http.StatusBadRequest},
+		{path: "/api/universes/3/merge", body: `{"mode":"max"}`, want: http.StatusBadRequest},
+		{path: "/api/universes/x/merge", body: `{"mode":"HTP"}`, want: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodPut, tt.path, strings.NewReader(tt.body))
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		assert.Equal(t, tt.want, rec.Code, tt.path+" "+tt.body)
+	}
+	mockUseCase.AssertExpectations(t)
+}
@@ -2,13 +2,16 @@ package http
 
 import (
 	"io"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
 	"github.com/nasshu2916/dmx_viewer/internal/config"
+	"github.com/nasshu2916/dmx_viewer/internal/domain/model"
 	"github.com/nasshu2916/dmx_viewer/internal/infrastructure/artnet"
 	metrics "github.com/nasshu2916/dmx_viewer/internal/infrastructure/metrics"
+	"github.com/nasshu2916/dmx_viewer/internal/usecase"
 	"github.com/nasshu2916/dmx_viewer/pkg/logger"
 	"github.com/stretchr/testify/assert"
 )
@@ -18,8 +21,15 @@ func TestMetricsHandler_ServeHTTP(t *testing.T) {
 	cfg := &config.ArtNet{PollIntervalSeconds: 300}
 	server := artnet.NewServer(l, cfg)
 
+	tracker := usecase.NewSequenceTracker(nil, l)
+	tracker.Observe(&model.DMXFrame{Sequence: 1, Universe: 3, SourceIP: net.IPv4(2, 0, 0, 10)})
+
+	routes := []model.BridgeRoute{{Direction: model.BridgeSACNToArtNet, Input: 1, Output: 0}}
+	bridge := usecase.NewProtocolBridge(routes, server, server, nil, usecase.SACNOutputOptions{}, 0, l)
+	bridge.ObserveFrame(&model.DMXFrame{Protocol: model.ProtocolSACN, Universe: 1, Length: 2, SourceCID: "cid"})
+
 	// カスタムRegistryを構築
-	reg := metrics.BuildRegistry(server)
+	reg := metrics.BuildRegistry(server, tracker, bridge)
 	mh := NewMetricsHandlerWithRegistry(reg, l)
 
 	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
@@ -31,4 +41,6 @@ func TestMetricsHandler_ServeHTTP(t *testing.T) {
 	s := string(body)
 	assert.Contains(t, s, "dmx_artnet_channel_buffer_size")
 	assert.Contains(t, s, "dmx_artnet_overall_healthy")
+	assert.Contains(t, s, `dmx_artnet_dmx_loss_percent{source="2.0.0.10",universe="3"} 0`)
+	assert.Contains(t, s, `dmx_bridge_input_frames_total{direction="sacn-to-artnet",input="1",output="0"} 1`)
 }
@@ -0,0 +1,78 @@
+package http
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi"
+	"github.com/nasshu2916/dmx_viewer/internal/domain/model"
+	"github.com/nasshu2916/dmx_viewer/internal/interface/httpctx"
+	"github.com/nasshu2916/dmx_viewer/internal/usecase"
+	"github.com/nasshu2916/dmx_viewer/pkg/logger"
+)
+
+type NodeHandler struct {
+	nodeLivenessUseCase usecase.NodeLivenessUseCase
+	logger              *logger.Logger
+}
+
+func NewNodeHandler(nodeLivenessUseCase usecase.NodeLivenessUseCase, logger *logger.Logger) *NodeHandler {
+	return &NodeHandler{
+		nodeLivenessUseCase: nodeLivenessUseCase,
+		logger:              logger,
+	}
+}
+
+type nodeHistoryResponse struct {
+	Node        string                 `json:"node"`
+	Transitions []model.NodeTransition `json:"transitions"`
+}
+
+// /api/artnet/nodes — 検出したArt-Netノードの一覧と生存状態
+func (h *NodeHandler) GetNodes(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("node handler: GetNodes",
+		"request_id", r.Header.Get("X-Request-Id"),
+		"real_ip", httpctx.RealIP(r.Context()),
+		"method", r.Method,
+		"path", r.URL.Path,
+	)
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(h.nodeLivenessUseCase.GetNodes())
+}
+
+// /api/artnet/nodes/{ip}/history?bind_index= — ノードの状態遷移の履歴（bind_index の既定値は1）
+func (h *NodeHandler) GetNodeHistory(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("node handler: GetNodeHistory",
+		"request_id", r.Header.Get("X-Request-Id"),
+		"real_ip", httpctx.RealIP(r.Context()),
+		"method", r.Method,
+		"path", r.URL.Path,
+	)
+
+	w.Header().Set("Content-Type", "application/json")
+
+	ip := net.ParseIP(chi.URLParam(r, "ip"))
+	if ip == nil || ip.To4() == nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid node ip address")
+		return
+	}
+	bindIndex := uint64(1)
+	if v := r.URL.Query().Get("bind_index"); v != "" {
+		var err error
+		if bindIndex, err = strconv.ParseUint(v, 10, 8); err != nil {
+			writeJSONError(w, http.StatusBadRequest, "invalid bind_index: "+v)
+			return
+		}
+	}
+
+	key := model.NodeKey(ip.To4(), uint8(bindIndex))
+	transitions, ok := h.nodeLivenessUseCase.GetNodeHistory(key)
+	if !ok {
+		writeJSONError(w, http.StatusNotFound, "no history for node "+key)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(nodeHistoryResponse{Node: key, Transitions: transitions})
+}
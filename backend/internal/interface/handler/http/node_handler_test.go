@@ -0,0 +1,97 @@
+package http_test
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/nasshu2916/dmx_viewer/internal/domain/model"
+	internalHttp "github.com/nasshu2916/dmx_viewer/internal/interface/handler/http"
+	"github.com/nasshu2916/dmx_viewer/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockNodeLivenessUseCase struct {
+	mock.Mock
+}
+
+func (m *MockNodeLivenessUseCase) ObserveNode(node *model.ArtNetNode) error {
+	return m.Called(node).Error(0)
+}
+
+func (m *MockNodeLivenessUseCase) StartSweeper(ctx context.Context) {
+	m.Called(ctx)
+}
+
+func (m *MockNodeLivenessUseCase) GetNodes() []*model.ArtNetNode {
+	nodes, _ := m.Called().Get(0).([]*model.ArtNetNode)
+	return nodes
+}
+
+func (m *MockNodeLivenessUseCase) GetNodeHistory(key string) ([]model.NodeTransition, bool) {
+	args := m.Called(key)
+	transitions, _ := args.Get(0).([]model.NodeTransition)
+	return transitions, args.Bool(1)
+}
+
+func newNodeHistoryRouter(handler *internalHttp.NodeHandler) http.Handler {
+	r := chi.NewRouter()
+	r.Get("/api/artnet/nodes/{ip}/history", handler.GetNodeHistory)
+	return r
+}
+
+func TestNodeHandler_GetNodeHistory(t *testing.T) {
+	mockUseCase := new(MockNodeLivenessUseCase)
+	transitions := []model.NodeTransition{
+		{NodeKey: "2.0.0.20#2", IPAddress: net.IPv4(2, 0, 0, 20), BindIndex: 2, From: model.NodeLivenessUnknown, To: model.NodeOnline, At: time.Now()},
+		{NodeKey: "2.0.0.20#2", IPAddress: net.IPv4(2, 0, 0, 20), BindIndex: 2, From: model.NodeOnline, To: model.NodeStale, At: time.Now()},
+	}
+	mockUseCase.On("GetNodeHistory", "2.0.0.20#2").Return(transitions, true)
+
+	handler := internalHttp.NewNodeHandler(mockUseCase, logger.NewLogger("error"))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/artnet/nodes/2.0.0.20/history?bind_index=2", nil)
+	rec := httptest.NewRecorder()
+	newNodeHistoryRouter(handler).ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var response struct {
+		Node        string
+		Transitions []struct{ From, To string }
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, "2.0.0.20#2", response.Node)
+	require.Len(t, response.Transitions, 2)
+	assert.Equal(t, "stale", response.Transitions[1].To)
+	mockUseCase.AssertExpectations(t)
+}
+
+func TestNodeHandler_GetNodeHistory_Errors(t *testing.T) {
+	mockUseCase := new(MockNodeLivenessUseCase)
+	mockUseCase.On("GetNodeHistory", "2.0.0.99#1").Return(nil, false)
+
+	handler := internalHttp.NewNodeHandler(mockUseCase, logger.NewLogger("error"))
+
+	tests := []struct {
+		path string
+		want int
+	}{
+		{path: "/api/artnet/nodes/2.0.0.99/history", want: http.StatusNotFound},
+		{path: "/api/artnet/nodes/not-an-ip/history", want: http.StatusBadRequest},
+		{path: "/api/artnet/nodes/2.0.0.99/history?bind_index=300", want: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+		rec := httptest.NewRecorder()
+		newNodeHistoryRouter(handler).ServeHTTP(rec, req)
+		assert.Equal(t, tt.want, rec.Code, tt.path)
+	}
+}
@@ -0,0 +1,88 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/nasshu2916/dmx_viewer/internal/domain/model"
+	"github.com/nasshu2916/dmx_viewer/internal/interface/httpctx"
+	"github.com/nasshu2916/dmx_viewer/internal/usecase"
+	"github.com/nasshu2916/dmx_viewer/pkg/logger"
+)
+
+type NodeSettingsHandler struct {
+	nodeSettingsUseCase usecase.NodeSettingsUseCase
+	logger              *logger.Logger
+}
+
+func NewNodeSettingsHandler(nodeSettingsUseCase usecase.NodeSettingsUseCase, logger *logger.Logger) *NodeSettingsHandler {
+	return &NodeSettingsHandler{
+		nodeSettingsUseCase: nodeSettingsUseCase,
+		logger:              logger,
+	}
+}
+
+type nodeSettingsResponse struct {
+	Settings           model.NodeSettings `json:"settings"`
+	MonitoredUniverses []uint16           `json:"monitored_universes"`
+}
+
+type monitoredUniversesRequest struct {
+	Universes []uint16 `json:"universes"`
+}
+
+// /api/artnet/node — このビューアのArt-Netノード設定
+func (h *NodeSettingsHandler) GetNodeSettings(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("node settings handler: GetNodeSettings",
+		"request_id", r.Header.Get("X-Request-Id"),
+		"real_ip", httpctx.RealIP(r.Context()),
+		"method", r.Method,
+		"path", r.URL.Path,
+	)
+
+	settings := h.nodeSettingsUseCase.GetNodeSettings()
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(nodeSettingsResponse{
+		Settings:           settings,
+		MonitoredUniverses: settings.MonitoredUniverses(),
+	})
+}
+
+// /api/artnet/monitored-universes — 仮想出力ポートとして公開するユニバースを変更
+func (h *NodeSettingsHandler) PutMonitoredUniverses(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("node settings handler: PutMonitoredUniverses",
+		"request_id", r.Header.Get("X-Request-Id"),
+		"real_ip", httpctx.RealIP(r.Context()),
+		"method", r.Method,
+		"path", r.URL.Path,
+	)
+
+	w.Header().Set("Content-Type", "application/json")
+
+	var req monitoredUniversesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return
+	}
+
+	settings, err := h.nodeSettingsUseCase.SetMonitoredUniverses(req.Universes)
+	if err != nil {
+		h.logger.Warn("Failed to update monitored universes", "error", err)
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(nodeSettingsResponse{
+		Settings:           settings,
+		MonitoredUniverses: settings.MonitoredUniverses(),
+	})
+}
+
+// writeJSONError エラーレスポンスをJSONで返す
+func writeJSONError(w http.ResponseWriter, status int, message string) {
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"status":  "error",
+		"message": message,
+	})
+}
@@ -0,0 +1,194 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/nasshu2916/dmx_viewer/internal/domain/model"
+	"github.com/nasshu2916/dmx_viewer/internal/interface/httpctx"
+	"github.com/nasshu2916/dmx_viewer/internal/usecase"
+	"github.com/nasshu2916/dmx_viewer/pkg/logger"
+)
+
+type PlaybackHandler struct {
+	playbackUseCase usecase.PlaybackUseCase
+	logger          *logger.Logger
+}
+
+func NewPlaybackHandler(playbackUseCase usecase.PlaybackUseCase, logger *logger.Logger) *PlaybackHandler {
+	return &PlaybackHandler{
+		playbackUseCase: playbackUseCase,
+		logger:          logger,
+	}
+}
+
+type playbackStartRequest struct {
+	File       string              `json:"file"`
+	Speed      *float64            `json:"speed"` // 省略時は 1
+	Loop       bool                `json:"loop"`
+	DryRun     bool                `json:"dryRun"`     // WebSocket の表示のみを更新する
+	Remap      model.UniverseRemap `json:"remap"`      // {"記録したユニバース": 出力するユニバース}
+	PositionMs int64               `json:"positionMs"` // 再生を開始する位置
+}
+
+type playbackSeekRequest struct {
+	PositionMs int64 `json:"positionMs"`
+}
+
+type playbackStepRequest struct {
+	Frames int `json:"frames"` // 負の値で前のフレームに戻る
+}
+
+type playbackSpeedRequest struct {
+	Speed float64 `json:"speed"`
+}
+
+// /api/playback — 再生の状態
+func (h *PlaybackHandler) GetStatus(w http.ResponseWriter, r *http.Request) {
+	h.logRequest(r, "GetStatus")
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(h.playbackUseCase.Status())
+}
+
+// /api/playback/files — 再生できるキャプチャファイルの一覧
+func (h *PlaybackHandler) GetFiles(w http.ResponseWriter, r *http.Request) {
+	h.logRequest(r, "GetFiles")
+
+	w.Header().Set("Content-Type", "application/json")
+
+	files, err := h.playbackUseCase.Files()
+	if err != nil {
+		h.logger.Error("Failed to list capture files", "error", err)
+		writeJSONError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	_ = json.NewEncoder(w).Encode(files)
+}
+
+// /api/playback/start — 再生を開始
+func (h *PlaybackHandler) PostStart(w http.ResponseWriter, r *http.Request) {
+	h.logRequest(r, "PostStart")
+
+	w.Header().Set("Content-Type", "application/json")
+
+	var req playbackStartRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return
+	}
+	opts := model.PlaybackOptions{
+		File:   req.File,
+		Speed:  1,
+		Loop:   req.Loop,
+		DryRun: req.DryRun,
+		Remap:  req.Remap,
+		Start:  time.Duration(req.PositionMs) * time.Millisecond,
+	}
+	if req.Speed != nil {
+		opts.Speed = *req.Speed
+	}
+
+	status, err := h.playbackUseCase.Start(opts)
+	h.writeResult(w, status, err)
+}
+
+// /api/playback/stop — 再生を停止
+func (h *PlaybackHandler) PostStop(w http.ResponseWriter, r *http.Request) {
+	h.logRequest(r, "PostStop")
+
+	w.Header().Set("Content-Type", "application/json")
+	status, err := h.playbackUseCase.Stop()
+	h.writeResult(w, status, err)
+}
+
+// /api/playback/pause — 再生を一時停止
+func (h *PlaybackHandler) PostPause(w http.ResponseWriter, r *http.Request) {
+	h.logRequest(r, "PostPause")
+
+	w.Header().Set("Content-Type", "application/json")
+	status, err := h.playbackUseCase.Pause()
+	h.writeResult(w, status, err)
+}
+
+// /api/playback/resume — 一時停止した再生を再開
+func (h *PlaybackHandler) PostResume(w http.ResponseWriter, r *http.Request) {
+	h.logRequest(r, "PostResume")
+
+	w.Header().Set("Content-Type", "application/json")
+	status, err := h.playbackUseCase.Resume()
+	h.writeResult(w, status, err)
+}
+
+// /api/playback/seek — 再生位置を変更
+func (h *PlaybackHandler) PostSeek(w http.ResponseWriter, r *http.Request) {
+	h.logRequest(r, "PostSeek")
+
+	w.Header().Set("Content-Type", "application/json")
+
+	var req playbackSeekRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return
+	}
+	status, err := h.playbackUseCase.Seek(time.Duration(req.PositionMs) * time.Millisecond)
+	h.writeResult(w, status, err)
+}
+
+// /api/playback/step — 一時停止して前後のフレームに移動
+func (h *PlaybackHandler) PostStep(w http.ResponseWriter, r *http.Request) {
+	h.logRequest(r, "PostStep")
+
+	w.Header().Set("Content-Type", "application/json")
+
+	var req playbackStepRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return
+	}
+	status, err := h.playbackUseCase.Step(req.Frames)
+	h.writeResult(w, status, err)
+}
+
+// /api/playback/speed — 再生速度を変更
+func (h *PlaybackHandler) PostSpeed(w http.ResponseWriter, r *http.Request) {
+	h.logRequest(r, "PostSpeed")
+
+	w.Header().Set("Content-Type", "application/json")
+
+	var req playbackSpeedRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return
+	}
+	status, err := h.playbackUseCase.SetSpeed(req.Speed)
+	h.writeResult(w, status, err)
+}
+
+func (h *PlaybackHandler) logRequest(r *http.Request, action string) {
+	h.logger.Info("playback handler: "+action,
+		"request_id", r.Header.Get("X-Request-Id"),
+		"real_ip", httpctx.RealIP(r.Context()),
+		"method", r.Method,
+		"path", r.URL.Path,
+	)
+}
+
+// writeResult 操作の結果をレスポンスに書き込む
+func (h *PlaybackHandler) writeResult(w http.ResponseWriter, status model.PlaybackStatus, err error) {
+	switch {
+	case err == nil:
+		_ = json.NewEncoder(w).Encode(status)
+	case errors.Is(err, usecase.ErrInvalidPlaybackOptions), errors.Is(err, usecase.ErrEmptyCapture), errors.Is(err, usecase.ErrCaptureTooLarge):
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, usecase.ErrCaptureNotFound):
+		writeJSONError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, usecase.ErrAlreadyPlaying), errors.Is(err, usecase.ErrNotPlaying):
+		writeJSONError(w, http.StatusConflict, err.Error())
+	default:
+		h.logger.Error("Playback operation failed", "error", err)
+		writeJSONError(w, http.StatusInternalServerError, err.Error())
+	}
+}
@@ -0,0 +1,122 @@
+package http_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/nasshu2916/dmx_viewer/internal/domain/model"
+	internalHttp "github.com/nasshu2916/dmx_viewer/internal/interface/handler/http"
+	"github.com/nasshu2916/dmx_viewer/internal/usecase"
+	"github.com/nasshu2916/dmx_viewer/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockPlaybackUseCase struct {
+	mock.Mock
+}
+
+func (m *MockPlaybackUseCase) Files() ([]model.CaptureFile, error) {
+	args := m.Called()
+	return args.Get(0).([]model.CaptureFile), args.Error(1)
+}
+
+func (m *MockPlaybackUseCase) Start(opts model.PlaybackOptions) (model.PlaybackStatus, error) {
+	args := m.Called(opts)
+	return args.Get(0).(model.PlaybackStatus), args.Error(1)
+}
+
+func (m *MockPlaybackUseCase) Stop() (model.PlaybackStatus, error) {
+	args := m.Called()
+	return args.Get(0).(model.PlaybackStatus), args.Error(1)
+}
+
+func (m *MockPlaybackUseCase) Pause() (model.PlaybackStatus, error) {
+	args := m.Called()
+	return args.Get(0).(model.PlaybackStatus), args.Error(1)
+}
+
+func (m *MockPlaybackUseCase) Resume() (model.PlaybackStatus, error) {
+	args := m.Called()
+	return args.Get(0).(model.PlaybackStatus), args.Error(1)
+}
+
+func (m *MockPlaybackUseCase) Seek(position time.Duration) (model.PlaybackStatus, error) {
+	args := m.Called(position)
+	return args.Get(0).(model.PlaybackStatus), args.Error(1)
+}
+
+func (m *MockPlaybackUseCase) Step(frames int) (model.PlaybackStatus, error) {
+	args := m.Called(frames)
+	return args.Get(0).(model.PlaybackStatus), args.Error(1)
+}
+
+func (m *MockPlaybackUseCase) SetSpeed(speed float64) (model.PlaybackStatus, error) {
+	args := m.Called(speed)
+	return args.Get(0).(model.PlaybackStatus), args.Error(1)
+}
+
+func (m *MockPlaybackUseCase) Status() model.PlaybackStatus {
+	return m.Called().Get(0).(model.PlaybackStatus)
+}
+
+func TestPlaybackHandler(t *testing.T) {
+	mockUseCase := new(MockPlaybackUseCase)
+	playing := model.PlaybackStatus{State: model.PlaybackStatePlaying}
+	mockUseCase.On("Start", model.PlaybackOptions{File: "a.dmxcap", Speed: 1}).Return(playing, nil).Once()
+	mockUseCase.On("Start", model.PlaybackOptions{File: "b.dmxcap", Speed: 2, Loop: true, DryRun: true, Remap: model.UniverseRemap{1: 5}, Start: 1500 * time.Millisecond}).Return(playing, usecase.ErrAlreadyPlaying).Once()
+	mockUseCase.On("Start", model.PlaybackOptions{File: "missing.dmxcap", Speed: 1}).Return(model.PlaybackStatus{}, usecase.ErrCaptureNotFound).Once()
+	mockUseCase.On("Start", model.PlaybackOptions{File: "a.dmxcap", Speed: 0}).Return(model.PlaybackStatus{}, usecase.ErrInvalidPlaybackOptions).Once()
+	mockUseCase.On("Pause").Return(playing, nil).Once()
+	mockUseCase.On("Resume").Return(model.PlaybackStatus{}, usecase.ErrNotPlaying).Once()
+	mockUseCase.On("Seek", 2*time.Second).Return(playing, nil).Once()
+	mockUseCase.On("Step", -1).Return(playing, nil).Once()
+	mockUseCase.On("SetSpeed", 0.5).Return(playing, nil).Once()
+	mockUseCase.On("Stop").Return(model.PlaybackStatus{}, nil).Once()
+	mockUseCase.On("Status").Return(playing).Once()
+	mockUseCase.On("Files").Return([]model.CaptureFile{{Name: "a.dmxcap"}}, nil).Once()
+
+	handler := internalHttp.NewPlaybackHandler(mockUseCase, logger.NewLogger("error"))
+	r := chi.NewRouter()
+	r.Get("/api/playback", handler.GetStatus)
+	r.Get("/api/playback/files", handler.GetFiles)
+	r.Post("/api/playback/start", handler.PostStart)
+	r.Post("/api/playback/stop", handler.PostStop)
+	r.Post("/api/playback/pause", handler.PostPause)
+	r.Post("/api/playback/resume", handler.PostResume)
+	r.Post("/api/playback/seek", handler.PostSeek)
+	r.Post("/api/playback/step", handler.PostStep)
+	r.Post("/api/playback/speed", handler.PostSpeed)
+
+	tests := []struct {
+		method string
+		path   string
+		body   string
+		want   int
+	}{
+		{method: http.MethodPost, path: "/api/playback/start", body: `{"file":"a.dmxcap"}`, want: http.StatusOK},
+		{method: http.MethodPost, path: "/api/playback/start", body: `{"file":"b.dmxcap","speed":2,"loop":true,"dryRun":true,"remap":{"1":5},"positionMs":1500}`, want: http.StatusConflict},
+		{method: http.MethodPost, path: "/api/playback/start", body: `{"file":"missing.dmxcap"}`, want: http.StatusNotFound},
+		{method: http.MethodPost, path: "/api/playback/start", body: `{"file":"a.dmxcap","speed":0}`, want: http.StatusBadRequest},
+		{method: http.MethodPost, path: "/api/playback/start", body: `{"remap":{"x":1}}`, want: http.StatusBadRequest},
+		{method: http.MethodPost, path: "/api/playback/pause", want: http.StatusOK},
+		{method: http.MethodPost, path: "/api/playback/resume", want: http.StatusConflict},
+		{method: http.MethodPost, path: "/api/playback/seek", body: `{"positionMs":2000}`, want: http.StatusOK},
+		{method: http.MethodPost, path: "/api/playback/step", body: `{"frames":-1}`, want: http.StatusOK},
+		{method: http.MethodPost, path: "/api/playback/speed", body: `{"speed":0.5}`, want: http.StatusOK},
+		{method: http.MethodPost, path: "/api/playback/stop", want: http.StatusOK},
+		{method: http.MethodGet, path: "/api/playback", want: http.StatusOK},
+		{method: http.MethodGet, path: "/api/playback/files", want: http.StatusOK},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		assert.Equal(t, tt.want, rec.Code, tt.path+" "+tt.body)
+	}
+	mockUseCase.AssertExpectations(t)
+}
@@ -0,0 +1,101 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/nasshu2916/dmx_viewer/internal/domain/model"
+	"github.com/nasshu2916/dmx_viewer/internal/interface/httpctx"
+	"github.com/nasshu2916/dmx_viewer/internal/usecase"
+	"github.com/nasshu2916/dmx_viewer/pkg/logger"
+)
+
+type RecorderHandler struct {
+	recorderUseCase usecase.RecorderUseCase
+	logger          *logger.Logger
+}
+
+func NewRecorderHandler(recorderUseCase usecase.RecorderUseCase, logger *logger.Logger) *RecorderHandler {
+	return &RecorderHandler{
+		recorderUseCase: recorderUseCase,
+		logger:          logger,
+	}
+}
+
+type recorderStartRequest struct {
+	Mode string `json:"mode"` // all, changed（省略時は all）
+}
+
+// /api/recorder — 記録の状態
+func (h *RecorderHandler) GetStatus(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("recorder handler: GetStatus",
+		"request_id", r.Header.Get("X-Request-Id"),
+		"real_ip", httpctx.RealIP(r.Context()),
+		"method", r.Method,
+		"path", r.URL.Path,
+	)
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(h.recorderUseCase.Status())
+}
+
+// /api/recorder/start — 記録を開始
+func (h *RecorderHandler) PostStart(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("recorder handler: PostStart",
+		"request_id", r.Header.Get("X-Request-Id"),
+		"real_ip", httpctx.RealIP(r.Context()),
+		"method", r.Method,
+		"path", r.URL.Path,
+	)
+
+	w.Header().Set("Content-Type", "application/json")
+
+	var req recorderStartRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		writeJSONError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return
+	}
+	mode, err := model.ParseRecordMode(req.Mode)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	status, err := h.recorderUseCase.Start(mode)
+	if errors.Is(err, usecase.ErrAlreadyRecording) {
+		writeJSONError(w, http.StatusConflict, err.Error())
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to start recording", "error", err)
+		writeJSONError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	_ = json.NewEncoder(w).Encode(status)
+}
+
+// /api/recorder/stop — 記録を停止
+func (h *RecorderHandler) PostStop(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("recorder handler: PostStop",
+		"request_id", r.Header.Get("X-Request-Id"),
+		"real_ip", httpctx.RealIP(r.Context()),
+		"method", r.Method,
+		"path", r.URL.Path,
+	)
+
+	w.Header().Set("Content-Type", "application/json")
+
+	status, err := h.recorderUseCase.Stop()
+	if errors.Is(err, usecase.ErrNotRecording) {
+		writeJSONError(w, http.StatusConflict, err.Error())
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to stop recording", "error", err)
+		writeJSONError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	_ = json.NewEncoder(w).Encode(status)
+}
@@ -0,0 +1,68 @@
+package http_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/nasshu2916/dmx_viewer/internal/domain/model"
+	internalHttp "github.com/nasshu2916/dmx_viewer/internal/interface/handler/http"
+	"github.com/nasshu2916/dmx_viewer/internal/usecase"
+	"github.com/nasshu2916/dmx_viewer/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockRecorderUseCase struct {
+	mock.Mock
+}
+
+func (m *MockRecorderUseCase) Start(mode model.RecordMode) (model.RecorderStatus, error) {
+	args := m.Called(mode)
+	return args.Get(0).(model.RecorderStatus), args.Error(1)
+}
+
+func (m *MockRecorderUseCase) Stop() (model.RecorderStatus, error) {
+	args := m.Called()
+	return args.Get(0).(model.RecorderStatus), args.Error(1)
+}
+
+func (m *MockRecorderUseCase) Status() model.RecorderStatus {
+	return m.Called().Get(0).(model.RecorderStatus)
+}
+
+func TestRecorderHandler(t *testing.T) {
+	mockUseCase := new(MockRecorderUseCase)
+	mockUseCase.On("Start", model.RecordModeAll).Return(model.RecorderStatus{Recording: true, Mode: model.RecordModeAll}, nil).Once()
+	mockUseCase.On("Start", model.RecordModeChanged).Return(model.RecorderStatus{Recording: true}, usecase.ErrAlreadyRecording).Once()
+	mockUseCase.On("Stop").Return(model.RecorderStatus{}, usecase.ErrNotRecording).Once()
+	mockUseCase.On("Status").Return(model.RecorderStatus{Recording: true, Mode: model.RecordModeAll}).Once()
+
+	handler := internalHttp.NewRecorderHandler(mockUseCase, logger.NewLogger("error"))
+	r := chi.NewRouter()
+	r.Get("/api/recorder", handler.GetStatus)
+	r.Post("/api/recorder/start", handler.PostStart)
+	r.Post("/api/recorder/stop", handler.PostStop)
+
+	tests := []struct {
+		method string
+		path   string
+		body   string
+		want   int
+	}{
+		{method: http.MethodPost, path: "/api/recorder/start", body: "", want: http.StatusOK},
+		{method: http.MethodPost, path: "/api/recorder/start", body: `{"mode":"changed"}`, want: http.StatusConflict},
+		{method: http.MethodPost, path: "/api/recorder/start", body: `{"mode":"delta"}`, want: http.StatusBadRequest},
+		{method: http.MethodPost, path: "/api/recorder/stop", want: http.StatusConflict},
+		{method: http.MethodGet, path: "/api/recorder", want: http.StatusOK},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		assert.Equal(t, tt.want, rec.Code, tt.path+" "+tt.body)
+	}
+	mockUseCase.AssertExpectations(t)
+}
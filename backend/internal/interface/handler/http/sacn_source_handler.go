@@ -0,0 +1,35 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/nasshu2916/dmx_viewer/internal/interface/httpctx"
+	"github.com/nasshu2916/dmx_viewer/internal/usecase"
+	"github.com/nasshu2916/dmx_viewer/pkg/logger"
+)
+
+type SACNSourceHandler struct {
+	sourceDirectory usecase.SACNSourceDirectory
+	logger          *logger.Logger
+}
+
+func NewSACNSourceHandler(sourceDirectory usecase.SACNSourceDirectory, logger *logger.Logger) *SACNSourceHandler {
+	return &SACNSourceHandler{
+		sourceDirectory: sourceDirectory,
+		logger:          logger,
+	}
+}
+
+// /api/sacn/sources — ユニバースディスカバリーで検出したsACN送信元の一覧
+func (h *SACNSourceHandler) GetSources(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("sacn source handler: GetSources",
+		"request_id", r.Header.Get("X-Request-Id"),
+		"real_ip", httpctx.RealIP(r.Context()),
+		"method", r.Method,
+		"path", r.URL.Path,
+	)
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(h.sourceDirectory.GetSources())
+}
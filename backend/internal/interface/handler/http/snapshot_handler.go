@@ -0,0 +1,167 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"github.com/nasshu2916/dmx_viewer/internal/interface/httpctx"
+	"github.com/nasshu2916/dmx_viewer/internal/usecase"
+	"github.com/nasshu2916/dmx_viewer/pkg/logger"
+)
+
+type SnapshotHandler struct {
+	snapshotUseCase usecase.SnapshotUseCase
+	logger          *logger.Logger
+}
+
+func NewSnapshotHandler(snapshotUseCase usecase.SnapshotUseCase, logger *logger.Logger) *SnapshotHandler {
+	return &SnapshotHandler{
+		snapshotUseCase: snapshotUseCase,
+		logger:          logger,
+	}
+}
+
+type snapshotRequest struct {
+	Name string `json:"name"`
+}
+
+// /api/snapshots — 保存済みのスナップショットの一覧
+func (h *SnapshotHandler) GetSnapshots(w http.ResponseWriter, r *http.Request) {
+	h.logRequest(r, "GetSnapshots")
+
+	w.Header().Set("Content-Type", "application/json")
+	snapshots, err := h.snapshotUseCase.List()
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(snapshots)
+}
+
+// /api/snapshots — 現在の出力を名前を付けて保存
+func (h *SnapshotHandler) PostSnapshot(w http.ResponseWriter, r *http.Request) {
+	h.logRequest(r, "PostSnapshot")
+
+	w.Header().Set("Content-Type", "application/json")
+
+	var req snapshotRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return
+	}
+	info, err := h.snapshotUseCase.Create(req.Name)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(info)
+}
+
+// /api/snapshots/{name} — スナップショットの内容
+func (h *SnapshotHandler) GetSnapshot(w http.ResponseWriter, r *http.Request) {
+	h.logRequest(r, "GetSnapshot")
+
+	w.Header().Set("Content-Type", "application/json")
+	snapshot, err := h.snapshotUseCase.Get(chi.URLParam(r, "name"))
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(snapshot)
+}
+
+// /api/snapshots/{name} — スナップショットを削除
+func (h *SnapshotHandler) DeleteSnapshot(w http.ResponseWriter, r *http.Request) {
+	h.logRequest(r, "DeleteSnapshot")
+
+	if err := h.snapshotUseCase.Delete(chi.URLParam(r, "name")); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		h.writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// /api/snapshots/{name}/diff?to= — 値が異なるチャンネルの一覧（to を省略した場合は現在の出力と比較）
+func (h *SnapshotHandler) GetSnapshotDiff(w http.ResponseWriter, r *http.Request) {
+	h.logRequest(r, "GetSnapshotDiff")
+
+	w.Header().Set("Content-Type", "application/json")
+	diff, err := h.snapshotUseCase.Diff(chi.URLParam(r, "name"), r.URL.Query().Get("to"))
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(diff)
+}
+
+// /api/pinned-snapshot — 固定したスナップショットと現在の出力の差分
+func (h *SnapshotHandler) GetPinned(w http.ResponseWriter, r *http.Request) {
+	h.logRequest(r, "GetPinned")
+
+	w.Header().Set("Content-Type", "application/json")
+	diff, err := h.snapshotUseCase.Drift()
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(diff)
+}
+
+// /api/pinned-snapshot — スナップショットを固定し、差分を snapshot/drift トピックに配信
+func (h *SnapshotHandler) PutPinned(w http.ResponseWriter, r *http.Request) {
+	h.logRequest(r, "PutPinned")
+
+	w.Header().Set("Content-Type", "application/json")
+
+	var req snapshotRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return
+	}
+	diff, err := h.snapshotUseCase.Pin(req.Name)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(diff)
+}
+
+// /api/pinned-snapshot — スナップショットの固定を解除
+func (h *SnapshotHandler) DeletePinned(w http.ResponseWriter, r *http.Request) {
+	h.logRequest(r, "DeletePinned")
+
+	if err := h.snapshotUseCase.Unpin(); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		h.writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *SnapshotHandler) logRequest(r *http.Request, action string) {
+	h.logger.Info("snapshot handler: "+action,
+		"request_id", r.Header.Get("X-Request-Id"),
+		"real_ip", httpctx.RealIP(r.Context()),
+		"method", r.Method,
+		"path", r.URL.Path,
+	)
+}
+
+// writeError 操作のエラーをレスポンスに書き込む
+func (h *SnapshotHandler) writeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, usecase.ErrInvalidSnapshotName):
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, usecase.ErrSnapshotNotFound), errors.Is(err, usecase.ErrNoPinnedSnapshot):
+		writeJSONError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, usecase.ErrSnapshotExists):
+		writeJSONError(w, http.StatusConflict, err.Error())
+	default:
+		h.logger.Error("Snapshot operation failed", "error", err)
+		writeJSONError(w, http.StatusInternalServerError, err.Error())
+	}
+}
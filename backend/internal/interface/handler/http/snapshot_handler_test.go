@@ -0,0 +1,115 @@
+package http_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/nasshu2916/dmx_viewer/internal/domain/model"
+	internalHttp "github.com/nasshu2916/dmx_viewer/internal/interface/handler/http"
+	"github.com/nasshu2916/dmx_viewer/internal/usecase"
+	"github.com/nasshu2916/dmx_viewer/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockSnapshotUseCase struct {
+	mock.Mock
+}
+
+func (m *MockSnapshotUseCase) Create(name string) (model.SnapshotInfo, error) {
+	args := m.Called(name)
+	return args.Get(0).(model.SnapshotInfo), args.Error(1)
+}
+
+func (m *MockSnapshotUseCase) List() ([]model.SnapshotInfo, error) {
+	args := m.Called()
+	return args.Get(0).([]model.SnapshotInfo), args.Error(1)
+}
+
+func (m *MockSnapshotUseCase) Get(name string) (*model.Snapshot, error) {
+	args := m.Called(name)
+	snapshot, _ := args.Get(0).(*model.Snapshot)
+	return snapshot, args.Error(1)
+}
+
+func (m *MockSnapshotUseCase) Delete(name string) error {
+	return m.Called(name).Error(0)
+}
+
+func (m *MockSnapshotUseCase) Diff(from, to string) (model.SnapshotDiff, error) {
+	args := m.Called(from, to)
+	return args.Get(0).(model.SnapshotDiff), args.Error(1)
+}
+
+func (m *MockSnapshotUseCase) Pin(name string) (model.SnapshotDiff, error) {
+	args := m.Called(name)
+	return args.Get(0).(model.SnapshotDiff), args.Error(1)
+}
+
+func (m *MockSnapshotUseCase) Unpin() error {
+	return m.Called().Error(0)
+}
+
+func (m *MockSnapshotUseCase) Drift() (model.SnapshotDiff, error) {
+	args := m.Called()
+	return args.Get(0).(model.SnapshotDiff), args.Error(1)
+}
+
+func TestSnapshotHandler(t *testing.T) {
+	mockUseCase := new(MockSnapshotUseCase)
+	mockUseCase.On("List").Return([]model.SnapshotInfo{{Name: "cue 12"}}, nil)
+	mockUseCase.On("Create", "cue 12").Return(model.SnapshotInfo{Name: "cue 12"}, nil)
+	mockUseCase.On("Create", "cue 13").Return(model.SnapshotInfo{}, fmt.Errorf("%w: cue 13", usecase.ErrSnapshotExists))
+	mockUseCase.On("Create", "").Return(model.SnapshotInfo{}, fmt.Errorf("%w: empty", usecase.ErrInvalidSnapshotName))
+	mockUseCase.On("Get", "cue 12").Return(&model.Snapshot{Name: "cue 12"}, nil)
+	mockUseCase.On("Get", "cue 99").Return(nil, fmt.Errorf("%w: cue 99", usecase.ErrSnapshotNotFound))
+	mockUseCase.On("Delete", "cue 12").Return(nil)
+	mockUseCase.On("Diff", "cue 12", "").Return(model.SnapshotDiff{From: "cue 12", To: model.SnapshotLiveName}, nil)
+	mockUseCase.On("Diff", "cue 12", "cue 13").Return(model.SnapshotDiff{From: "cue 12", To: "cue 13"}, nil)
+	mockUseCase.On("Pin", "cue 12").Return(model.SnapshotDiff{From: "cue 12", To: model.SnapshotLiveName}, nil)
+	mockUseCase.On("Drift").Return(model.SnapshotDiff{}, usecase.ErrNoPinnedSnapshot)
+	mockUseCase.On("Unpin").Return(nil)
+
+	handler := internalHttp.NewSnapshotHandler(mockUseCase, logger.NewLogger("error"))
+	r := chi.NewRouter()
+	r.Get("/api/snapshots", handler.GetSnapshots)
+	r.Post("/api/snapshots", handler.PostSnapshot)
+	r.Get("/api/snapshots/{name}", handler.GetSnapshot)
+	r.Delete("/api/snapshots/{name}", handler.DeleteSnapshot)
+	r.Get("/api/snapshots/{name}/diff", handler.GetSnapshotDiff)
+	r.Get("/api/pinned-snapshot", handler.GetPinned)
+	r.Put("/api/pinned-snapshot", handler.PutPinned)
+	r.Delete("/api/pinned-snapshot", handler.DeletePinned)
+
+	tests := []struct {
+		method string
+		path   string
+		body   string
+		want   int
+	}{
+		{method: http.MethodGet, path: "/api/snapshots", want: http.StatusOK},
+		{method: http.MethodPost, path: "/api/snapshots", body: `{"name":"cue 12"}`, want: http.StatusCreated},
+		{method: http.MethodPost, path: "/api/snapshots", body: `{"name":"cue 13"}`, want: http.StatusConflict},
+		{method: http.MethodPost, path: "/api/snapshots", body: `{}`, want: http.StatusBadRequest},
+		{method: http.MethodPost, path: "/api/snapshots", body: `{`, want: http.StatusBadRequest},
+		{method: http.MethodGet, path: "/api/snapshots/cue%2012", want: http.StatusOK},
+		{method: http.MethodGet, path: "/api/snapshots/cue%2099", want: http.StatusNotFound},
+		{method: http.MethodDelete, path: "/api/snapshots/cue%2012", want: http.StatusNoContent},
+		{method: http.MethodGet, path: "/api/snapshots/cue%2012/diff", want: http.StatusOK},
+		{method: http.MethodGet, path: "/api/snapshots/cue%2012/diff?to=cue+13", want: http.StatusOK},
+		{method: http.MethodPut, path: "/api/pinned-snapshot", body: `{"name":"cue 12"}`, want: http.StatusOK},
+		{method: http.MethodGet, path: "/api/pinned-snapshot", want: http.StatusNotFound},
+		{method: http.MethodDelete, path: "/api/pinned-snapshot", want: http.StatusNoContent},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		assert.Equal(t, tt.want, rec.Code, tt.method+" "+tt.path+" "+tt.body)
+	}
+	mockUseCase.AssertExpectations(t)
+}
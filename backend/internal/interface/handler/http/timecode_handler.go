@@ -0,0 +1,61 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/nasshu2916/dmx_viewer/internal/interface/httpctx"
+	"github.com/nasshu2916/dmx_viewer/internal/usecase"
+	"github.com/nasshu2916/dmx_viewer/pkg/logger"
+)
+
+type TimeCodeHandler struct {
+	timeCodeUseCase usecase.TimeCodeUseCase
+	logger          *logger.Logger
+}
+
+func NewTimeCodeHandler(timeCodeUseCase usecase.TimeCodeUseCase, logger *logger.Logger) *TimeCodeHandler {
+	return &TimeCodeHandler{
+		timeCodeUseCase: timeCodeUseCase,
+		logger:          logger,
+	}
+}
+
+// /api/timecode — 最後に受信したArtTimeCodeとその送信元
+func (h *TimeCodeHandler) GetTimeCode(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("timecode handler: GetTimeCode",
+		"request_id", r.Header.Get("X-Request-Id"),
+		"real_ip", httpctx.RealIP(r.Context()),
+		"method", r.Method,
+		"path", r.URL.Path,
+	)
+
+	w.Header().Set("Content-Type", "application/json")
+
+	tc, ok := h.timeCodeUseCase.GetCurrentTimeCode()
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"status":  "not_found",
+			"message": "no timecode received",
+		})
+		return
+	}
+
+	source := ""
+	if tc.SourceIP != nil {
+		source = tc.SourceIP.String()
+	}
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"timecode":    tc.String(),
+		"hours":       tc.Hours,
+		"minutes":     tc.Minutes,
+		"seconds":     tc.Seconds,
+		"frames":      tc.Frames,
+		"type":        tc.Type.String(),
+		"frame_rate":  tc.Type.FrameRate(),
+		"source":      source,
+		"received_at": tc.ReceivedAt.Format(time.RFC3339Nano),
+	})
+}
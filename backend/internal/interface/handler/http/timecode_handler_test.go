@@ -0,0 +1,66 @@
+package http_test
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/nasshu2916/dmx_viewer/internal/domain/model"
+	internalHttp "github.com/nasshu2916/dmx_viewer/internal/interface/handler/http"
+	"github.com/nasshu2916/dmx_viewer/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockTimeCodeUseCase struct {
+	mock.Mock
+}
+
+func (m *MockTimeCodeUseCase) GetCurrentTimeCode() (*model.TimeCode, bool) {
+	args := m.Called()
+	tc, _ := args.Get(0).(*model.TimeCode)
+	return tc, args.Bool(1)
+}
+
+func TestTimeCodeHandler_GetTimeCode(t *testing.T) {
+	mockUseCase := new(MockTimeCodeUseCase)
+	tc := &model.TimeCode{
+		Hours: 1, Minutes: 2, Seconds: 3, Frames: 4,
+		Type:       model.TimeCodeEBU,
+		SourceIP:   net.ParseIP("10.0.0.5"),
+		ReceivedAt: time.Now(),
+	}
+	mockUseCase.On("GetCurrentTimeCode").Return(tc, true)
+
+	handler := internalHttp.NewTimeCodeHandler(mockUseCase, logger.NewLogger("error"))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/timecode", nil)
+	rec := httptest.NewRecorder()
+	handler.GetTimeCode(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var response map[string]interface{}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, "01:02:03:04", response["timecode"])
+	assert.Equal(t, "EBU", response["type"])
+	assert.Equal(t, "10.0.0.5", response["source"])
+	mockUseCase.AssertExpectations(t)
+}
+
+func TestTimeCodeHandler_GetTimeCode_NotReceived(t *testing.T) {
+	mockUseCase := new(MockTimeCodeUseCase)
+	mockUseCase.On("GetCurrentTimeCode").Return(nil, false)
+
+	handler := internalHttp.NewTimeCodeHandler(mockUseCase, logger.NewLogger("error"))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/timecode", nil)
+	rec := httptest.NewRecorder()
+	handler.GetTimeCode(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	mockUseCase.AssertExpectations(t)
+}
@@ -0,0 +1,146 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/nasshu2916/dmx_viewer/internal/domain/model"
+	"github.com/nasshu2916/dmx_viewer/internal/interface/httpctx"
+	"github.com/nasshu2916/dmx_viewer/internal/usecase"
+	"github.com/nasshu2916/dmx_viewer/pkg/logger"
+)
+
+type TrafficCaptureHandler struct {
+	trafficCaptureUseCase usecase.TrafficCaptureUseCase
+	logger                *logger.Logger
+}
+
+func NewTrafficCaptureHandler(trafficCaptureUseCase usecase.TrafficCaptureUseCase, logger *logger.Logger) *TrafficCaptureHandler {
+	return &TrafficCaptureHandler{
+		trafficCaptureUseCase: trafficCaptureUseCase,
+		logger:                logger,
+	}
+}
+
+type trafficCaptureStartRequest struct {
+	SourceIPs          []string `json:"sourceIps"`          // 送信元IPアドレス
+	OpCodes            []string `json:"opcodes"`            // Art-Net のオペコード（"ArtDmx"、"0x5000" など）
+	Universes          string   `json:"universes"`          // ユニバース（例: "0-3,16"）
+	MaxDurationSeconds int      `json:"maxDurationSeconds"` // 省略時は 60 秒
+}
+
+// /api/traffic-capture — 受信したパケットの書き出しの状態
+func (h *TrafficCaptureHandler) GetStatus(w http.ResponseWriter, r *http.Request) {
+	h.logRequest(r, "GetStatus")
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(h.trafficCaptureUseCase.Status())
+}
+
+// /api/traffic-capture/start — 受信したパケットの pcap ファイルへの書き出しを開始
+func (h *TrafficCaptureHandler) PostStart(w http.ResponseWriter, r *http.Request) {
+	h.logRequest(r, "PostStart")
+
+	w.Header().Set("Content-Type", "application/json")
+
+	var req trafficCaptureStartRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		writeJSONError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return
+	}
+	if req.MaxDurationSeconds < 0 {
+		writeJSONError(w, http.StatusBadRequest, "maxDurationSeconds must not be negative")
+		return
+	}
+	status, err := h.trafficCaptureUseCase.Start(model.TrafficCaptureOptions{
+		Filter: model.TrafficCaptureFilter{
+			SourceIPs: req.SourceIPs,
+			OpCodes:   req.OpCodes,
+			Universes: req.Universes,
+		},
+		MaxDuration: time.Duration(req.MaxDurationSeconds) * time.Second,
+	})
+	h.writeResult(w, status, err)
+}
+
+// /api/traffic-capture/stop — 書き出しを停止
+func (h *TrafficCaptureHandler) PostStop(w http.ResponseWriter, r *http.Request) {
+	h.logRequest(r, "PostStop")
+
+	w.Header().Set("Content-Type", "application/json")
+	status, err := h.trafficCaptureUseCase.Stop()
+	h.writeResult(w, status, err)
+}
+
+// /api/traffic-capture/files — 書き出した pcap ファイルの一覧
+func (h *TrafficCaptureHandler) GetFiles(w http.ResponseWriter, r *http.Request) {
+	h.logRequest(r, "GetFiles")
+
+	w.Header().Set("Content-Type", "application/json")
+
+	files, err := h.trafficCaptureUseCase.Files()
+	if err != nil {
+		h.logger.Error("Failed to list pcap files", "error", err)
+		writeJSONError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	_ = json.NewEncoder(w).Encode(files)
+}
+
+// /api/traffic-capture/files/{name} — pcap ファイルをダウンロード
+func (h *TrafficCaptureHandler) GetFile(w http.ResponseWriter, r *http.Request) {
+	h.logRequest(r, "GetFile")
+
+	file, err := h.trafficCaptureUseCase.File(chi.URLParam(r, "name"))
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		if errors.Is(err, usecase.ErrTrafficCaptureNotFound) {
+			writeJSONError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		h.logger.Error("Failed to read pcap file", "error", err)
+		writeJSONError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	f, err := os.Open(file.Path)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		writeJSONError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	defer f.Close()
+
+	w.Header().Set("Content-Type", "application/vnd.tcpdump.pcap")
+	w.Header().Set("Content-Disposition", `attachment; filename="`+file.Name+`"`)
+	http.ServeContent(w, r, file.Name, file.StartedAt, f)
+}
+
+func (h *TrafficCaptureHandler) logRequest(r *http.Request, action string) {
+	h.logger.Info("traffic capture handler: "+action,
+		"request_id", r.Header.Get("X-Request-Id"),
+		"real_ip", httpctx.RealIP(r.Context()),
+		"method", r.Method,
+		"path", r.URL.Path,
+	)
+}
+
+// writeResult 操作の結果をレスポンスに書き込む
+func (h *TrafficCaptureHandler) writeResult(w http.ResponseWriter, status model.TrafficCaptureStatus, err error) {
+	switch {
+	case err == nil:
+		_ = json.NewEncoder(w).Encode(status)
+	case errors.Is(err, usecase.ErrInvalidTrafficCaptureOptions):
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, usecase.ErrAlreadyCapturingTraffic), errors.Is(err, usecase.ErrNotCapturingTraffic):
+		writeJSONError(w, http.StatusConflict, err.Error())
+	default:
+		h.logger.Error("Traffic capture operation failed", "error", err)
+		writeJSONError(w, http.StatusInternalServerError, err.Error())
+	}
+}
@@ -0,0 +1,103 @@
+package http_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/nasshu2916/dmx_viewer/internal/domain/model"
+	internalHttp "github.com/nasshu2916/dmx_viewer/internal/interface/handler/http"
+	"github.com/nasshu2916/dmx_viewer/internal/usecase"
+	"github.com/nasshu2916/dmx_viewer/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockTrafficCaptureUseCase struct {
+	mock.Mock
+}
+
+func (m *MockTrafficCaptureUseCase) Start(opts model.TrafficCaptureOptions) (model.TrafficCaptureStatus, error) {
+	args := m.Called(opts)
+	return args.Get(0).(model.TrafficCaptureStatus), args.Error(1)
+}
+
+func (m *MockTrafficCaptureUseCase) Stop() (model.TrafficCaptureStatus, error) {
+	args := m.Called()
+	return args.Get(0).(model.TrafficCaptureStatus), args.Error(1)
+}
+
+func (m *MockTrafficCaptureUseCase) Status() model.TrafficCaptureStatus {
+	return m.Called().Get(0).(model.TrafficCaptureStatus)
+}
+
+func (m *MockTrafficCaptureUseCase) Files() ([]model.CaptureFile, error) {
+	args := m.Called()
+	return args.Get(0).([]model.CaptureFile), args.Error(1)
+}
+
+func (m *MockTrafficCaptureUseCase) File(name string) (model.CaptureFile, error) {
+	args := m.Called(name)
+	return args.Get(0).(model.CaptureFile), args.Error(1)
+}
+
+func TestTrafficCaptureHandler(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "traffic.pcap")
+	require.NoError(t, os.WriteFile(path, []byte("pcap"), 0o644))
+	file := model.CaptureFile{Name: "traffic.pcap", Path: path, Size: 4, StartedAt: time.Now()}
+
+	mockUseCase := new(MockTrafficCaptureUseCase)
+	mockUseCase.On("Start", model.TrafficCaptureOptions{
+		Filter:      model.TrafficCaptureFilter{SourceIPs: []string{"2.0.0.10"}, OpCodes: []string{"ArtDmx"}, Universes: "0-3"},
+		MaxDuration: 30 * time.Second,
+	}).Return(model.TrafficCaptureStatus{Capturing: true}, nil).Once()
+	mockUseCase.On("Start", model.TrafficCaptureOptions{}).Return(model.TrafficCaptureStatus{Capturing: true}, usecase.ErrAlreadyCapturingTraffic).Once()
+	mockUseCase.On("Start", model.TrafficCaptureOptions{Filter: model.TrafficCaptureFilter{OpCodes: []string{"unknown"}}}).Return(model.TrafficCaptureStatus{}, usecase.ErrInvalidTrafficCaptureOptions).Once()
+	mockUseCase.On("Stop").Return(model.TrafficCaptureStatus{}, usecase.ErrNotCapturingTraffic).Once()
+	mockUseCase.On("Status").Return(model.TrafficCaptureStatus{}).Once()
+	mockUseCase.On("Files").Return([]model.CaptureFile{file}, nil).Once()
+	mockUseCase.On("File", "traffic.pcap").Return(file, nil).Once()
+	mockUseCase.On("File", "missing.pcap").Return(model.CaptureFile{}, usecase.ErrTrafficCaptureNotFound).Once()
+
+	handler := internalHttp.NewTrafficCaptureHandler(mockUseCase, logger.NewLogger("error"))
+	r := chi.NewRouter()
+	r.Get("/api/traffic-capture", handler.GetStatus)
+	r.Post("/api/traffic-capture/start", handler.PostStart)
+	r.Post("/api/traffic-capture/stop", handler.PostStop)
+	r.Get("/api/traffic-capture/files", handler.GetFiles)
+	r.Get("/api/traffic-capture/files/{name}", handler.GetFile)
+
+	tests := []struct {
+		method string
+		path   string
+		body   string
+		want   int
+	}{
+		{method: http.MethodPost, path: "/api/traffic-capture/start", body: `{"sourceIps":["2.0.0.10"],"opcodes":["ArtDmx"],"universes":"0-3","maxDurationSeconds":30}`, want: http.StatusOK},
+		{method: http.MethodPost, path: "/api/traffic-capture/start", body: "", want: http.StatusConflict},
+		{method: http.MethodPost, path: "/api/traffic-capture/start", body: `{"opcodes":["unknown"]}`, want: http.StatusBadRequest},
+		{method: http.MethodPost, path: "/api/traffic-capture/start", body: `{"maxDurationSeconds":-1}`, want: http.StatusBadRequest},
+		{method: http.MethodPost, path: "/api/traffic-capture/stop", want: http.StatusConflict},
+		{method: http.MethodGet, path: "/api/traffic-capture", want: http.StatusOK},
+		{method: http.MethodGet, path: "/api/traffic-capture/files", want: http.StatusOK},
+		{method: http.MethodGet, path: "/api/traffic-capture/files/traffic.pcap", want: http.StatusOK},
+		{method: http.MethodGet, path: "/api/traffic-capture/files/missing.pcap", want: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		assert.Equal(t, tt.want, rec.Code, tt.path+" "+tt.body)
+		if tt.path == "/api/traffic-capture/files/traffic.pcap" {
+			assert.Equal(t, "pcap", rec.Body.String())
+			assert.Equal(t, `attachment; filename="traffic.pcap"`, rec.Header().Get("Content-Disposition"))
+		}
+	}
+	mockUseCase.AssertExpectations(t)
+}
@@ -2,9 +2,11 @@ package websocket
 
 import (
 	"encoding/json"
+	"sync/atomic"
 	"time"
 
 	"github.com/gorilla/websocket"
+	"github.com/nasshu2916/dmx_viewer/internal/domain/model"
 	"github.com/nasshu2916/dmx_viewer/pkg/logger"
 )
 
@@ -13,15 +15,35 @@ type Client struct {
 	hub       *Hub
 	conn      *websocket.Conn
 	logger    *logger.Logger
-	send      chan []byte
+	send      chan outgoingMessage
 	topics    map[SubscribeTopic]struct{}
+	binary    atomic.Bool // DMXフレームをバイナリ表現で受信する
 	createdAt time.Time
 }
 
+// outgoingMessage クライアントに送信するメッセージ
+type outgoingMessage struct {
+	data   []byte
+	binary bool // true の場合はバイナリメッセージ、false の場合はテキストメッセージとして送信する
+}
+
+// BinarySubprotocol DMXフレームをバイナリ表現（infrastructure/wsframe）で受信するサブプロトコル
+const BinarySubprotocol = "dmx-viewer.binary.v1"
+
+// DMXフレームのエンコード（subscribe メッセージの encoding で指定する）
+const (
+	EncodingJSON   = "json"
+	EncodingBinary = "binary"
+)
+
 type WebSocketMessage struct {
-	Type    string          `json:"type"`    // Type of message
-	Topic   SubscribeTopic  `json:"topic"`   // Topic name
-	Payload json.RawMessage `json:"payload"` // Actual message payload for "publish" type
+	Type    string          `json:"type"`              // Type of message
+	Topic   SubscribeTopic  `json:"topic"`             // Topic name
+	Payload json.RawMessage `json:"payload"`           // Actual message payload for "publish" type
+	Command string          `json:"command,omitempty"` // Command name for command types (e.g. "review_command")
+	Data    json.RawMessage `json:"data,omitempty"`    // Command arguments
+
+	Encoding string `json:"encoding,omitempty"` // DMX frame encoding for "subscribe" type ("json" or "binary")
 }
 
 const (
@@ -36,14 +58,16 @@ func NewClient(hub *Hub, conn *websocket.Conn, logger *logger.Logger) *Client {
 	topics := make(map[SubscribeTopic]struct{})
 	topics[AllSubscribedTopic] = struct{}{}
 
-	return &Client{
+	client := &Client{
 		hub:       hub,
 		conn:      conn,
 		logger:    logger,
-		send:      make(chan []byte, 256),
+		send:      make(chan outgoingMessage, 256),
 		topics:    topics,
 		createdAt: time.Now(),
 	}
+	client.binary.Store(conn.Subprotocol() == BinarySubprotocol)
+	return client
 }
 
 func (c *Client) readPump() {
@@ -77,11 +101,33 @@ func (c *Client) readPump() {
 		}
 
 		switch wsMsg.Type {
-		case "subscribe":
-			c.SubscribeToTopic(wsMsg.Topic)
-		case "unsubscribe":
-			c.UnsubscribeFromTopic(wsMsg.Topic)
+		case "subscribe", "unsubscribe":
+			// {"type": "subscribe", "topic": "artnet/dmx/1", "encoding": "binary"} のように、
+			// 購読と同時にDMXフレームのエンコードを選択できる（接続全体に適用する）
+			switch wsMsg.Encoding {
+			case EncodingBinary:
+				c.binary.Store(true)
+			case EncodingJSON:
+				c.binary.Store(false)
+			}
+			// "artnet/dmx/0-3,8" のようにユニバースの範囲・集合を指定した場合はユニバースごとのトピックとして扱う
+			topics, err := model.ExpandTopic(string(wsMsg.Topic))
+			if err != nil {
+				c.logger.Debug("Invalid WebSocket topic", "addr", c.conn.RemoteAddr(), "error", err)
+				continue
+			}
+			for _, topic := range topics {
+				if wsMsg.Type == "subscribe" {
+					c.SubscribeToTopic(SubscribeTopic(topic))
+				} else {
+					c.UnsubscribeFromTopic(SubscribeTopic(topic))
+				}
+			}
 		default:
+			if handler, ok := c.hub.commandHandler(wsMsg.Type); ok {
+				c.handleCommand(handler, wsMsg)
+				continue
+			}
 			c.logger.Debug("Unknown WebSocket message type", "addr", c.conn.RemoteAddr(), "message", wsMsg)
 		}
 	}
@@ -102,7 +148,11 @@ func (c *Client) writePump() {
 				c.conn.WriteMessage(websocket.CloseMessage, []byte{})
 				return
 			}
-			if err := c.conn.WriteMessage(websocket.TextMessage, message); err != nil {
+			messageType := websocket.TextMessage
+			if message.binary {
+				messageType = websocket.BinaryMessage
+			}
+			if err := c.conn.WriteMessage(messageType, message.data); err != nil {
 				return
 			}
 		case <-ticker.C:
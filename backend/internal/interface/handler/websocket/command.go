@@ -0,0 +1,43 @@
+package websocket
+
+import (
+	"encoding/json"
+
+	"github.com/nasshu2916/dmx_viewer/internal/domain/model"
+)
+
+// CommandHandler クライアントから受信したコマンドを処理するインターフェース
+// 戻り値はコマンドを送信したクライアントにのみ返す
+type CommandHandler interface {
+	HandleCommand(command string, data json.RawMessage) (interface{}, error)
+}
+
+// commandError コマンドの処理に失敗したときにクライアントに返す内容
+type commandError struct {
+	Command string `json:"Command"`
+	Error   string `json:"Error"`
+}
+
+// handleCommand コマンドを処理し、結果を "<type>_result"、失敗した場合は "<type>_error" として返す
+func (c *Client) handleCommand(handler CommandHandler, wsMsg WebSocketMessage) {
+	result, err := handler.HandleCommand(wsMsg.Command, wsMsg.Data)
+
+	var reply *model.WebSocketMessage
+	if err != nil {
+		c.logger.Debug("WebSocket command failed", "addr", c.conn.RemoteAddr(), "type", wsMsg.Type, "command", wsMsg.Command, "error", err)
+		reply = model.NewWebSocketMessage(wsMsg.Type+"_error", commandError{Command: wsMsg.Command, Error: err.Error()})
+	} else {
+		reply = model.NewWebSocketMessage(wsMsg.Type+"_result", result)
+	}
+	message, err := json.Marshal(reply)
+	if err != nil {
+		c.logger.Error("Failed to marshal WebSocket command reply", "error", err)
+		return
+	}
+
+	select {
+	case c.send <- outgoingMessage{data: message}:
+	default:
+		c.logger.Warn("Failed to send WebSocket command reply, send buffer full", "addr", c.conn.RemoteAddr())
+	}
+}
@@ -1,6 +1,9 @@
 package websocket
 
 import (
+	"encoding/json"
+
+	"github.com/nasshu2916/dmx_viewer/internal/domain/model"
 	"github.com/nasshu2916/dmx_viewer/pkg/logger"
 )
 
@@ -9,8 +12,30 @@ const AllSubscribedTopic = "BROADCAST_ALL"
 type SubscribeTopic string
 
 type TopicMessage struct {
-	topic   SubscribeTopic
-	message []byte
+	topics []SubscribeTopic
+	text   *lazyMessage
+	binary *lazyMessage  // バイナリ表現（DMXフレームのみ。nil の場合はすべてのクライアントに text を送信する）
+	done   chan struct{} // 配信が終わったら閉じる（nil の場合は通知しない）
+}
+
+// lazyMessage 購読しているクライアントが必要とするまでエンコードしないメッセージ
+// ハブのゴルーチンからのみ使用し、エンコードは1回のみ行う
+type lazyMessage struct {
+	encode  func() []byte
+	data    []byte
+	encoded bool
+}
+
+// get エンコードしたメッセージを返す（エンコードに失敗した場合は nil）
+func (m *lazyMessage) get() []byte {
+	if m == nil {
+		return nil
+	}
+	if !m.encoded {
+		m.data = m.encode()
+		m.encoded = true
+	}
+	return m.data
 }
 
 type SubscribeRequest struct {
@@ -18,6 +43,13 @@ type SubscribeRequest struct {
 	client *Client
 }
 
+// ClientMessage トピックを購読している1つのクライアントにのみ送信するメッセージ
+type ClientMessage struct {
+	topic   SubscribeTopic
+	client  *Client
+	message []byte
+}
+
 type Hub struct {
 	logger *logger.Logger
 
@@ -29,7 +61,20 @@ type Hub struct {
 	subscribe   chan SubscribeRequest // Channel for subscribing to topics
 	unsubscribe chan SubscribeRequest // Channel for unsubscribing from topics
 
-	broadcast chan TopicMessage // Channel for broadcasting messages to subscribed clients
+	broadcast chan TopicMessage  // Channel for broadcasting messages to subscribed clients
+	direct    chan ClientMessage // Channel for sending messages to a single subscribed client
+
+	commands map[string]CommandHandler // Command handlers keyed by message type (registered before clients connect)
+
+	subscribeHandlers []SubscribeHandler // Handlers notified when a client subscribes to a topic (registered before clients connect)
+}
+
+// SubscribeHandler クライアントがトピックを購読したときに通知を受け取るインターフェース
+// ハブの処理中に呼ばれるため、実装はブロックせずに戻る必要がある。
+// reply は購読したクライアントにのみメッセージを送信する。ハブを経由してトピックの配信と同じ順に送信するため、
+// TopicSubscribed の中では呼ばず、別のゴルーチンから呼ぶ。クライアントが購読をやめた後は送信しない
+type SubscribeHandler interface {
+	TopicSubscribed(topic string, reply func(message *model.WebSocketMessage))
 }
 
 func NewHub(logger *logger.Logger) *Hub {
@@ -45,6 +90,9 @@ func NewHub(logger *logger.Logger) *Hub {
 		unsubscribe: make(chan SubscribeRequest),
 
 		broadcast: make(chan TopicMessage),
+		direct:    make(chan ClientMessage),
+
+		commands: make(map[string]CommandHandler),
 	}
 }
 
@@ -74,25 +122,57 @@ func (h *Hub) Run() {
 
 		case request := <-h.subscribe:
 			h.subscribeTopic(request.client, request.topic)
+			for _, handler := range h.subscribeHandlers {
+				handler.TopicSubscribed(string(request.topic), h.replyTo(request.client, request.topic))
+			}
+
+		case clientMessage := <-h.direct:
+			if _, ok := h.SubscribedClients[clientMessage.topic][clientMessage.client]; !ok {
+				continue
+			}
+			select {
+			case clientMessage.client.send <- outgoingMessage{data: clientMessage.message}:
+			default:
+				h.logger.Info("Failed to send message to client, send buffer full", "addr", clientMessage.client.conn.RemoteAddr(), "topic", clientMessage.topic)
+			}
 
 		case request := <-h.unsubscribe:
 			h.unsubscribeTopic(request.client, request.topic)
 
 		case topicMessage := <-h.broadcast:
-			if clientsInTopic, ok := h.SubscribedClients[topicMessage.topic]; ok {
-				for client := range clientsInTopic {
-					select {
-					case client.send <- topicMessage.message:
-					default:
-						if _, ok := h.clients[client]; ok {
-							h.logger.Info("Failed to send message to client, closing connection", "addr", client.conn.RemoteAddr(), "topic", topicMessage.topic)
-						} else {
-							h.logger.Warn("Failed to send message to client, client not found", "addr", client.conn.RemoteAddr(), "topic", topicMessage.topic)
-							// If the client is not connected, remove it from the hub
-							h.unsubscribeTopic(client, topicMessage.topic)
-						}
-					}
-				}
+			for _, topic := range topicMessage.topics {
+				h.broadcastTopic(topic, topicMessage)
+			}
+			if topicMessage.done != nil {
+				close(topicMessage.done)
+			}
+		}
+	}
+}
+
+// broadcastTopic トピックを購読しているクライアントに、クライアントが選択した表現でメッセージを送信する
+func (h *Hub) broadcastTopic(topic SubscribeTopic, topicMessage TopicMessage) {
+	for client := range h.SubscribedClients[topic] {
+		var message outgoingMessage
+		if client.binary.Load() {
+			if data := topicMessage.binary.get(); data != nil {
+				message = outgoingMessage{data: data, binary: true}
+			}
+		}
+		if message.data == nil {
+			if message.data = topicMessage.text.get(); message.data == nil {
+				continue
+			}
+		}
+		select {
+		case client.send <- message:
+		default:
+			if _, ok := h.clients[client]; ok {
+				h.logger.Info("Failed to send message to client, closing connection", "addr", client.conn.RemoteAddr(), "topic", topic)
+			} else {
+				h.logger.Warn("Failed to send message to client, client not found", "addr", client.conn.RemoteAddr(), "topic", topic)
+				// If the client is not connected, remove it from the hub
+				h.unsubscribeTopic(client, topic)
 			}
 		}
 	}
@@ -114,6 +194,35 @@ func (h *Hub) unsubscribeTopic(client *Client, topic SubscribeTopic) {
 	}
 }
 
+// RegisterCommandHandler registers a handler for client messages of the given type.
+// It must be called before any client connects.
+func (h *Hub) RegisterCommandHandler(messageType string, handler CommandHandler) {
+	h.commands[messageType] = handler
+}
+
+// RegisterSubscribeHandler registers a handler notified when a client subscribes to a topic.
+// It must be called before any client connects.
+func (h *Hub) RegisterSubscribeHandler(handler SubscribeHandler) {
+	h.subscribeHandlers = append(h.subscribeHandlers, handler)
+}
+
+// replyTo トピックを購読したクライアントにのみメッセージを送信する関数を返す
+func (h *Hub) replyTo(client *Client, topic SubscribeTopic) func(message *model.WebSocketMessage) {
+	return func(message *model.WebSocketMessage) {
+		data, err := json.Marshal(message)
+		if err != nil {
+			h.logger.Error("Failed to marshal WebSocket message", "error", err, "topic", topic)
+			return
+		}
+		h.direct <- ClientMessage{topic: topic, client: client, message: data}
+	}
+}
+
+func (h *Hub) commandHandler(messageType string) (CommandHandler, bool) {
+	handler, ok := h.commands[messageType]
+	return handler, ok
+}
+
 func (h *Hub) JoinClient(client *Client) {
 	h.join <- client
 }
@@ -123,5 +232,18 @@ func (h *Hub) LeaveClient(client *Client) {
 }
 
 func (h *Hub) BroadcastMessage(topic SubscribeTopic, message []byte) {
-	h.broadcast <- TopicMessage{topic: topic, message: message}
+	h.broadcast <- TopicMessage{topics: []SubscribeTopic{topic}, text: &lazyMessage{data: message, encoded: true}}
+}
+
+// BroadcastEncoded broadcasts a message to the topics, encoding it only for the clients that need it.
+// text and binary are called at most once each, in the hub goroutine, and only if a subscribed client uses that encoding.
+// Clients that negotiated the binary encoding receive binary as a binary message, others (or all, if binary is nil
+// or returns nil) receive text. It returns after the message has been queued to every client.
+func (h *Hub) BroadcastEncoded(topics []SubscribeTopic, text, binary func() []byte) {
+	message := TopicMessage{topics: topics, text: &lazyMessage{encode: text}, done: make(chan struct{})}
+	if binary != nil {
+		message.binary = &lazyMessage{encode: binary}
+	}
+	h.broadcast <- message
+	<-message.done
 }
@@ -0,0 +1,88 @@
+package websocket
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/nasshu2916/dmx_viewer/internal/domain/model"
+	"github.com/nasshu2916/dmx_viewer/pkg/logger"
+)
+
+// ReviewCommandType オフラインレビューモードの操作に使用するメッセージの種類
+//
+//	{"type": "review_command", "command": "play"}
+//	{"type": "review_command", "command": "pause"}
+//	{"type": "review_command", "command": "seek", "data": {"positionMs": 12000}}
+//	{"type": "review_command", "command": "step", "data": {"frames": -1}}
+//	{"type": "review_command", "command": "speed", "data": {"speed": 0.5}}
+//	{"type": "review_command", "command": "status"}
+//
+// 結果の再生状態は送信したクライアントに "review_command_result" として返し、
+// 他のクライアントには playback/status トピックで配信する。
+const ReviewCommandType = "review_command"
+
+type reviewCommandData struct {
+	PositionMs int64   `json:"positionMs"`
+	Frames     int     `json:"frames"`
+	Speed      float64 `json:"speed"`
+}
+
+// ReviewPlayback オフラインレビューモードで操作するキャプチャファイルの再生（usecase.PlaybackUseCase）
+type ReviewPlayback interface {
+	Start(opts model.PlaybackOptions) (model.PlaybackStatus, error)
+	Pause() (model.PlaybackStatus, error)
+	Resume() (model.PlaybackStatus, error)
+	Seek(position time.Duration) (model.PlaybackStatus, error)
+	Step(frames int) (model.PlaybackStatus, error)
+	SetSpeed(speed float64) (model.PlaybackStatus, error)
+	Status() model.PlaybackStatus
+}
+
+// ReviewCommandHandler オフラインレビューモードでキャプチャファイルの再生を操作する
+type ReviewCommandHandler struct {
+	playbackUseCase ReviewPlayback
+	opts            model.PlaybackOptions // 停止した後に再生する場合の再生方法
+	logger          *logger.Logger
+}
+
+func NewReviewCommandHandler(playbackUseCase ReviewPlayback, opts model.PlaybackOptions, logger *logger.Logger) *ReviewCommandHandler {
+	return &ReviewCommandHandler{
+		playbackUseCase: playbackUseCase,
+		opts:            opts,
+		logger:          logger,
+	}
+}
+
+func (h *ReviewCommandHandler) HandleCommand(command string, data json.RawMessage) (interface{}, error) {
+	var args reviewCommandData
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &args); err != nil {
+			return nil, fmt.Errorf("invalid command data: %w", err)
+		}
+	}
+
+	h.logger.Debug("Review command received", "command", command)
+	switch command {
+	case "play":
+		if h.playbackUseCase.Status().State == model.PlaybackStateStopped {
+			return h.playbackUseCase.Start(h.opts)
+		}
+		return h.playbackUseCase.Resume()
+	case "pause":
+		return h.playbackUseCase.Pause()
+	case "seek":
+		return h.playbackUseCase.Seek(time.Duration(args.PositionMs) * time.Millisecond)
+	case "step":
+		if args.Frames == 0 {
+			args.Frames = 1
+		}
+		return h.playbackUseCase.Step(args.Frames)
+	case "speed":
+		return h.playbackUseCase.SetSpeed(args.Speed)
+	case "status":
+		return h.playbackUseCase.Status(), nil
+	default:
+		return nil, fmt.Errorf("unknown review command %q", command)
+	}
+}
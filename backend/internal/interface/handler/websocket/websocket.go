@@ -19,6 +19,8 @@ func NewWebSocketHandler(hub *Hub, logger *logger.Logger) *WebSocketHandler {
 		upgrader: websocket.Upgrader{
 			ReadBufferSize:  1024,
 			WriteBufferSize: 1024,
+			// クライアントが BinarySubprotocol を要求した場合はDMXフレームをバイナリ表現で送信する
+			Subprotocols: []string{BinarySubprotocol},
 			CheckOrigin: func(r *http.Request) bool {
 				// TODO: オリジンを適切にチェックする
 				return true
@@ -10,7 +10,7 @@ import (
 	"github.com/nasshu2916/dmx_viewer/pkg/logger"
 )
 
-func NewRouter(static *httpHandler.StaticHandler, timeHandler *httpHandler.TimeHandler, health *httpHandler.HealthHandler, metrics *httpHandler.MetricsHandler, ws *websocket.WebSocketHandler, l *logger.Logger, httpTimeout time.Duration) http.Handler {
+func NewRouter(static *httpHandler.StaticHandler, timeHandler *httpHandler.TimeHandler, timeCodeHandler *httpHandler.TimeCodeHandler, nodeSettings *httpHandler.NodeSettingsHandler, nodes *httpHandler.NodeHandler, sacnSources *httpHandler.SACNSourceHandler, merge *httpHandler.MergeHandler, history *httpHandler.HistoryHandler, snapshot *httpHandler.SnapshotHandler, export *httpHandler.ExportHandler, recorder *httpHandler.RecorderHandler, playback *httpHandler.PlaybackHandler, trafficCapture *httpHandler.TrafficCaptureHandler, health *httpHandler.HealthHandler, metrics *httpHandler.MetricsHandler, ws *websocket.WebSocketHandler, l *logger.Logger, httpTimeout time.Duration) http.Handler {
 	r := chi.NewRouter()
 
 	// ベース（全体）ミドルウェア
@@ -27,11 +27,51 @@ func NewRouter(static *httpHandler.StaticHandler, timeHandler *httpHandler.TimeH
 		gr.Get("/", static.GetIndex)
 		gr.Handle("/assets/*", static.AssetsHandler())
 		gr.Get("/api/time", timeHandler.GetTime)
+		gr.Get("/api/timecode", timeCodeHandler.GetTimeCode)
+		gr.Get("/api/artnet/node", nodeSettings.GetNodeSettings)
+		gr.Put("/api/artnet/monitored-universes", nodeSettings.PutMonitoredUniverses)
+		gr.Get("/api/artnet/nodes", nodes.GetNodes)
+		gr.Get("/api/artnet/nodes/{ip}/history", nodes.GetNodeHistory)
+		gr.Get("/api/sacn/sources", sacnSources.GetSources)
+		gr.Get("/api/merge", merge.GetMergeStates)
+		gr.Put("/api/universes/{universe}/merge", merge.PutMergeMode)
+		gr.Get("/api/universes/{universe}/channels/{channel}/history", history.GetChannelHistory)
+		gr.Get("/api/snapshots", snapshot.GetSnapshots)
+		gr.Post("/api/snapshots", snapshot.PostSnapshot)
+		gr.Get("/api/snapshots/{name}", snapshot.GetSnapshot)
+		gr.Delete("/api/snapshots/{name}", snapshot.DeleteSnapshot)
+		gr.Get("/api/snapshots/{name}/diff", snapshot.GetSnapshotDiff)
+		gr.Get("/api/pinned-snapshot", snapshot.GetPinned)
+		gr.Put("/api/pinned-snapshot", snapshot.PutPinned)
+		gr.Delete("/api/pinned-snapshot", snapshot.DeletePinned)
+		gr.Get("/api/recorder", recorder.GetStatus)
+		gr.Post("/api/recorder/start", recorder.PostStart)
+		gr.Post("/api/recorder/stop", recorder.PostStop)
+		gr.Get("/api/playback", playback.GetStatus)
+		gr.Get("/api/playback/files", playback.GetFiles)
+		gr.Post("/api/playback/start", playback.PostStart)
+		gr.Post("/api/playback/stop", playback.PostStop)
+		gr.Post("/api/playback/pause", playback.PostPause)
+		gr.Post("/api/playback/resume", playback.PostResume)
+		gr.Post("/api/playback/seek", playback.PostSeek)
+		gr.Post("/api/playback/step", playback.PostStep)
+		gr.Post("/api/playback/speed", playback.PostSpeed)
+		gr.Get("/api/traffic-capture", trafficCapture.GetStatus)
+		gr.Post("/api/traffic-capture/start", trafficCapture.PostStart)
+		gr.Post("/api/traffic-capture/stop", trafficCapture.PostStop)
+		gr.Get("/api/traffic-capture/files", trafficCapture.GetFiles)
 		gr.Get("/healthz", health.Healthz)
 		gr.Get("/readyz", health.Readyz)
 		gr.Handle("/metrics", metrics)
 	})
 
+	// ストリーミングで書き出すグループ（http.TimeoutHandler はレスポンス全体をバッファするため、タイムアウトは適用しない）
+	r.Group(func(gr chi.Router) {
+		gr.Use(RecovererMiddleware(l))
+		gr.Get("/api/export", export.GetExport)
+		gr.Get("/api/traffic-capture/files/{name}", trafficCapture.GetFile)
+	})
+
 	// WebSocket グループ（タイムアウトは適用しない）
 	r.Group(func(gr chi.Router) {
 		gr.Use(RecovererMiddleware(l))
@@ -2,6 +2,7 @@ package usecase
 
 import (
 	"context"
+	"fmt"
 	"net"
 	"sync/atomic"
 	"time"
@@ -11,6 +12,7 @@ import (
 	"github.com/nasshu2916/dmx_viewer/internal/config"
 	"github.com/nasshu2916/dmx_viewer/internal/domain/model"
 	"github.com/nasshu2916/dmx_viewer/internal/domain/repository"
+	"github.com/nasshu2916/dmx_viewer/internal/infrastructure/artnet"
 	"github.com/nasshu2916/dmx_viewer/pkg/logger"
 )
 
@@ -19,6 +21,11 @@ type ArtNetWriter interface {
 	SendToWriteChan(data []byte, addr net.Addr) error
 }
 
+// NetworkInterfaceProvider ArtNetの送受信に使用しているネットワークインターフェースを提供するインターフェース
+type NetworkInterfaceProvider interface {
+	LocalInterface() *model.NetworkInterface
+}
+
 // ArtNetPacketHandler ArtNetパケットを処理するハンドラーのインターフェース
 type ArtNetPacketHandler interface {
 	// ArtNetパケットを処理する
@@ -35,49 +42,121 @@ type ArtNetPacketHandler interface {
 type ArtNetPacketHandlerImpl struct {
 	wsUseCase         WebSocketUseCase
 	artNetWriter      ArtNetWriter
+	netProvider       NetworkInterfaceProvider
 	logger            *logger.Logger
 	config            *config.ArtNet
 	activeGoroutines  int32         // アクティブなゴルーチン数（atomic操作用）
 	maxGoroutines     int32         // 最大ゴルーチン数
 	processingTimeout time.Duration // 処理タイムアウト
-	nodeRepo          repository.ArtNetNodeRepository
+	nodeObserver      NodeObserver
+	timeCodeRepo      repository.TimeCodeRepository
+	settingsRepo      repository.NodeSettingsRepository
+	syncBuffer        *ArtSyncBuffer // ArtSync同期モード用のバッファ（無効時はnil）
+	seqTracker        *SequenceTracker
+	merger            *UniverseMerger
+	frameObserver     FrameObserver // 送信元ごとのフレームを受け取る（nilの場合は使用しない）
 }
 
 // NewArtNetPacketHandler ArtNetPacketHandlerの新しいインスタンスを作成
-func NewArtNetPacketHandler(wsUseCase WebSocketUseCase, artNetWriter ArtNetWriter, cfg *config.ArtNet, logger *logger.Logger, nodeRepo repository.ArtNetNodeRepository) *ArtNetPacketHandlerImpl {
-	return &ArtNetPacketHandlerImpl{
+func NewArtNetPacketHandler(wsUseCase WebSocketUseCase, artNetWriter ArtNetWriter, netProvider NetworkInterfaceProvider, cfg *config.ArtNet, logger *logger.Logger, nodeObserver NodeObserver, timeCodeRepo repository.TimeCodeRepository, settingsRepo repository.NodeSettingsRepository, seqTracker *SequenceTracker, merger *UniverseMerger, frameObserver FrameObserver) *ArtNetPacketHandlerImpl {
+	h := &ArtNetPacketHandlerImpl{
 		wsUseCase:         wsUseCase,
 		artNetWriter:      artNetWriter,
+		netProvider:       netProvider,
 		logger:            logger,
 		config:            cfg,
 		maxGoroutines:     100,             // デフォルト最大ゴルーチン数
 		processingTimeout: 5 * time.Second, // デフォルト処理タイムアウト
-		nodeRepo:          nodeRepo,
+		nodeObserver:      nodeObserver,
+		timeCodeRepo:      timeCodeRepo,
+		settingsRepo:      settingsRepo,
+		seqTracker:        seqTracker,
+		merger:            merger,
+		frameObserver:     frameObserver,
+	}
+	if cfg.SyncEnabled {
+		h.syncBuffer = NewArtSyncBuffer(ArtSyncTimeout, h.handleSyncTimeout)
 	}
+	return h
 }
 
 func (h *ArtNetPacketHandlerImpl) HandlePacket(artNetPacket model.ReceivedArtPacket) error {
 	switch packet := artNetPacket.Packet.(type) {
 	case *packet.ArtDMXPacket:
-		return h.broadcastDMXPacket(artNetPacket.Addr, packet)
+		return h.handleArtDMXPacket(artNetPacket.Addr, packet, artNetPacket.ReceivedAt)
+	case *packet.ArtSyncPacket:
+		return h.handleArtSyncPacket(artNetPacket.Addr)
 	case *packet.ArtPollPacket:
 		return h.handleArtPollPacket(artNetPacket.Addr, packet)
 	case *packet.ArtPollReplyPacket:
 		return h.handleArtPollReplyPacket(packet)
+	case *packet.ArtTimeCodePacket:
+		return h.handleArtTimeCodePacket(artNetPacket.Addr, packet)
+	case *packet.ArtAddressPacket:
+		return h.handleArtAddressPacket(artNetPacket.Addr, packet)
 	default:
 		h.logger.Debug("Unsupported ArtNet packet type for WebSocket broadcast", "type", artNetPacket.Packet.GetOpCode().String())
 		return nil
 	}
 }
 
-func (h *ArtNetPacketHandlerImpl) broadcastDMXPacket(srcAddr net.Addr, dmxPacket *packet.ArtDMXPacket) error {
-	dmxData, err := model.NewDMXData(srcAddr, dmxPacket)
+// handleArtDMXPacket ArtDMXパケットを処理する
+// 送信元が同期モードの場合はArtSyncを受信するまでバッファに保持する
+func (h *ArtNetPacketHandlerImpl) handleArtDMXPacket(srcAddr net.Addr, dmxPacket *packet.ArtDMXPacket, receivedAt time.Time) error {
+	dmxData, err := artnet.NewDMXFrame(srcAddr, dmxPacket, receivedAt)
 	if err != nil {
 		h.logger.Error("Failed to create DMX data", "error", err)
 		return err
 	}
-	msg := model.NewWebSocketMessage("artnet_dmx_packet", dmxData)
-	return h.wsUseCase.BroadcastToTopic("artnet/dmx_packet", msg)
+	h.seqTracker.Observe(dmxData)
+
+	if h.syncBuffer != nil && h.syncBuffer.Push(dmxData) {
+		return nil
+	}
+	return h.broadcastDMXFrame(dmxData)
+}
+
+// handleArtSyncPacket ArtSyncパケットを処理し、送信元が保持していたフレームをまとめて出力する
+func (h *ArtNetPacketHandlerImpl) handleArtSyncPacket(srcAddr net.Addr) error {
+	if h.syncBuffer == nil {
+		return nil
+	}
+	udpAddr, ok := srcAddr.(*net.UDPAddr)
+	if !ok {
+		return nil
+	}
+
+	frames := h.syncBuffer.Sync(udpAddr.IP.String())
+	return h.broadcastDMXFrames(frames)
+}
+
+// handleSyncTimeout 同期モードのタイムアウト時に保持されていたフレームを非同期として出力する
+func (h *ArtNetPacketHandlerImpl) handleSyncTimeout(frames []*model.DMXFrame) {
+	h.logger.Debug("ArtSync timed out, reverting to immediate mode", "frames", len(frames))
+	if err := h.broadcastDMXFrames(frames); err != nil {
+		h.logger.Error("Failed to broadcast frames after ArtSync timeout", "error", err)
+	}
+}
+
+// broadcastDMXFrames 複数のDMXフレームを順にブロードキャストする
+func (h *ArtNetPacketHandlerImpl) broadcastDMXFrames(frames []*model.DMXFrame) error {
+	for _, frame := range frames {
+		if err := h.broadcastDMXFrame(frame); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// broadcastDMXFrame 送信元ごとのフレームと、マージ後のフレームをブロードキャストする
+func (h *ArtNetPacketHandlerImpl) broadcastDMXFrame(dmxData *model.DMXFrame) error {
+	if h.frameObserver != nil {
+		h.frameObserver.ObserveFrame(dmxData)
+	}
+	if err := publishDMXFrame(h.wsUseCase, dmxData); err != nil {
+		return err
+	}
+	return h.merger.Push(dmxData)
 }
 
 // handleArtPollPacket ArtPollパケットを処理し、ArtPollReplyパケットを送信する
@@ -85,51 +164,140 @@ func (h *ArtNetPacketHandlerImpl) handleArtPollPacket(srcAddr net.Addr, _ *packe
 	// 送信元が自分自身の場合は返信しない
 	udpAddr, ok := srcAddr.(*net.UDPAddr)
 	if ok {
-		localIP, err := h.getLocalIPAddress()
-		if err == nil && udpAddr.IP.Equal(localIP) {
+		if iface := h.netProvider.LocalInterface(); iface != nil && udpAddr.IP.Equal(iface.IP) {
 			return nil
 		}
 	}
 
-	// ArtPollReplyパケットを作成
-	replyPacket, err := h.createArtPollReplyPacket()
+	// ArtPollReplyパケットを作成（ポートページごとに1パケット）
+	replyPackets, err := h.createArtPollReplyPackets()
 	if err != nil {
 		h.logger.Error("Failed to create ArtPollReply packet", "error", err)
 		return err
 	}
 
 	// 送信元アドレスにArtPollReplyパケットを送信
-	if err := h.SendPacket(replyPacket, srcAddr); err != nil {
-		h.logger.Error("Failed to send ArtPollReply packet", "error", err)
-		return err
+	for _, replyPacket := range replyPackets {
+		if err := h.SendPacket(replyPacket, srcAddr); err != nil {
+			h.logger.Error("Failed to send ArtPollReply packet", "error", err)
+			return err
+		}
 	}
 	return nil
 }
 
 func (h *ArtNetPacketHandlerImpl) handleArtPollReplyPacket(replyPacket *packet.ArtPollReplyPacket) error {
-	// 新しいノード情報を保存
-	node := model.NewArtNetNode(replyPacket)
-	h.nodeRepo.Save(node)
-
-	// すべてのノード情報を返す
-	nodes := h.nodeRepo.All()
-	msg := model.NewWebSocketMessage("artnet_nodes", nodes)
-	return h.wsUseCase.BroadcastToTopic("artnet/nodes", msg)
+	// 新しいノード情報を保存し、すべてのノード情報を配信する
+	return h.nodeObserver.ObserveNode(model.NewArtNetNode(replyPacket))
 }
 
-// createArtPollReplyPacket ArtPollReplyパケットを作成する
-func (h *ArtNetPacketHandlerImpl) createArtPollReplyPacket() (*packet.ArtPollReplyPacket, error) {
-	replyPacket := packet.NewArtPollReplyPacket()
+// handleArtTimeCodePacket ArtTimeCodeパケットをデコードして保存し、WebSocketに配信する
+func (h *ArtNetPacketHandlerImpl) handleArtTimeCodePacket(srcAddr net.Addr, timeCodePacket *packet.ArtTimeCodePacket) error {
+	timeCode, err := model.NewTimeCode(srcAddr, timeCodePacket)
+	if err != nil {
+		h.logger.Debug("Failed to decode ArtTimeCode packet", "error", err)
+		return err
+	}
+	h.timeCodeRepo.Save(timeCode)
+
+	msg := model.NewWebSocketMessage("artnet_timecode", timeCode)
+	return h.wsUseCase.BroadcastToTopic("artnet/timecode", msg)
+}
 
-	// IPアドレスを取得
-	localIP, err := h.getLocalIPAddress()
+// handleArtAddressPacket ArtAddressパケットを処理し、ノード設定を更新してArtPollReplyをブロードキャストする
+func (h *ArtNetPacketHandlerImpl) handleArtAddressPacket(srcAddr net.Addr, addressPacket *packet.ArtAddressPacket) error {
+	var result model.ArtAddressResult
+	var applyErr error
+	defaults := h.settingsRepo.Defaults()
+	settings, err := h.settingsRepo.Update(func(settings *model.NodeSettings) error {
+		result, applyErr = settings.ApplyArtAddress(addressPacket, defaults)
+		return applyErr
+	})
+	if applyErr != nil {
+		h.logger.Debug("Ignoring ArtAddress packet", "error", applyErr, "bindIndex", addressPacket.BindIndex)
+		return nil
+	}
+	if result.Unsupported {
+		h.logger.Debug("Unsupported ArtAddress command", "command", fmt.Sprintf("0x%02x", result.Command))
+	}
+
+	// 保存に失敗してもメモリ上の設定は更新されているので処理を続ける
 	if err != nil {
-		h.logger.Warn("Failed to get local IP address, using 127.0.0.1", "error", err)
-		localIP = net.ParseIP("127.0.0.1")
+		h.logger.Error("Failed to persist node settings", "error", err)
+	}
+	page := &settings.Pages[result.Page]
+	h.logger.Info("Node settings updated by ArtAddress",
+		"source", srcAddr.String(),
+		"bindIndex", result.Page+1,
+		"shortName", settings.ShortName,
+		"longName", settings.LongName,
+		"netSwitch", page.NetSwitch,
+		"subSwitch", page.SubSwitch,
+		"indicator", settings.Indicator)
+
+	for _, port := range result.ClearPorts {
+		msg := model.NewWebSocketMessage("artnet_clear_output", map[string]interface{}{
+			"BindIndex": result.Page + 1,
+			"Port":      port,
+			"Universe":  page.OutputUniverse(port),
+		})
+		if err := h.wsUseCase.BroadcastToTopic("artnet/node_settings", msg); err != nil {
+			return err
+		}
 	}
 
-	// IPアドレスを設定
-	copy(replyPacket.IPAddress[:], localIP.To4())
+	msg := model.NewWebSocketMessage("artnet_node_settings", settings)
+	if err := h.wsUseCase.BroadcastToTopic("artnet/node_settings", msg); err != nil {
+		return err
+	}
+
+	return h.AnnouncePollReply()
+}
+
+// AnnouncePollReply 現在のノード設定でArtPollReplyを全ページ分ブロードキャストする
+// ノード設定が変更された際にコントローラーへ通知するために使用する
+func (h *ArtNetPacketHandlerImpl) AnnouncePollReply() error {
+	replyPackets, err := h.createArtPollReplyPackets()
+	if err != nil {
+		h.logger.Error("Failed to create ArtPollReply packet", "error", err)
+		return err
+	}
+	for _, replyPacket := range replyPackets {
+		if err := h.BroadcastPacket(replyPacket); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// createArtPollReplyPackets ポートページごとのArtPollReplyパケットを作成する
+// モニター対象のユニバースは仮想出力ポート（SwOut）として公開し、
+// Art-Net 4 のコントローラーがこのビューアへユニキャストするようにする
+func (h *ArtNetPacketHandlerImpl) createArtPollReplyPackets() ([]*packet.ArtPollReplyPacket, error) {
+	// 使用中のインターフェースを取得
+	iface := h.netProvider.LocalInterface()
+	if iface == nil {
+		h.logger.Warn("Network interface is not selected yet, using 127.0.0.1")
+		iface = model.NewNetworkInterface("", net.IPv4(127, 0, 0, 1), net.CIDRMask(8, 32), nil)
+	}
+
+	settings := h.settingsRepo.Get()
+	replyPackets := make([]*packet.ArtPollReplyPacket, 0, len(settings.Pages))
+	for i := range settings.Pages {
+		replyPackets = append(replyPackets, h.createArtPollReplyPacket(iface, &settings, i))
+	}
+	return replyPackets, nil
+}
+
+// createArtPollReplyPacket 指定したページのArtPollReplyパケットを作成する
+func (h *ArtNetPacketHandlerImpl) createArtPollReplyPacket(iface *model.NetworkInterface, settings *model.NodeSettings, pageIndex int) *packet.ArtPollReplyPacket {
+	replyPacket := packet.NewArtPollReplyPacket()
+	page := &settings.Pages[pageIndex]
+
+	// インターフェースのIPアドレスとMACアドレスを設定
+	copy(replyPacket.IPAddress[:], iface.IP.To4())
+	copy(replyPacket.BindIP[:], iface.IP.To4())
+	copy(replyPacket.Macaddress[:], iface.MAC)
 
 	// ポート番号を設定
 	replyPacket.Port = 6454 // ArtNetの標準ポート
@@ -137,16 +305,34 @@ func (h *ArtNetPacketHandlerImpl) createArtPollReplyPacket() (*packet.ArtPollRep
 	// VersionInfo (ファームウェアバージョン)
 	replyPacket.VersionInfo = 1
 
-	// ショートネームとロングネームを設定
-	copy(replyPacket.ShortName[:], []byte(h.config.ShortName))
-	copy(replyPacket.LongName[:], []byte(h.config.LongName))
+	// ショートネームとロングネームを設定（末尾のヌル文字分を残す）
+	copy(replyPacket.ShortName[:len(replyPacket.ShortName)-1], []byte(settings.ShortName))
+	copy(replyPacket.LongName[:len(replyPacket.LongName)-1], []byte(settings.LongName))
+
+	// ページ番号（1始まり）とPort-Addressを設定
+	replyPacket.BindIndex = uint8(pageIndex + 1)
+	replyPacket.NetSwitch = page.NetSwitch
+	replyPacket.SubSwitch = page.SubSwitch
+	replyPacket.SwIn = page.SwIn
+	replyPacket.SwOut = page.SwOut
+
+	// 仮想出力ポート（DMX512出力）を設定
+	replyPacket.NumPorts = uint16(page.NumPorts)
+	for port := 0; port < int(page.NumPorts); port++ {
+		replyPacket.PortTypes[port] = code.PortType(0).WithType("DMX512").WithOutput(true)
+		replyPacket.GoodOutput[port] = code.GoodOutput(0).WithLTP(page.MergeMode[port] == model.MergeModeLTP)
+	}
 
 	// ノードのタイプを設定 (Node)
 	replyPacket.Style = code.StNode
 
-	// ステータスを設定 (デフォルト値)
-	replyPacket.Status1 = code.Status1(0x00)
-	replyPacket.Status2 = code.Status2(0x00)
+	// ステータスを設定（インジケーター状態とPort-Addressの設定元）
+	replyPacket.Status1 = code.Status1(0x00).WithIndicator(string(settings.Indicator))
+	if settings.ProgrammedByRemote {
+		replyPacket.Status1 = replyPacket.Status1.WithPortAddr("net")
+	}
+	// 15bit Port-Address (Art-Net 3/4) に対応
+	replyPacket.Status2 = code.Status2(0x08)
 
 	// ESTAマニュファクチャーコード（適当な値を設定）
 	replyPacket.ESTAmanufacturer = [2]byte{'D', 'V'} // DMX Viewer
@@ -154,9 +340,6 @@ func (h *ArtNetPacketHandlerImpl) createArtPollReplyPacket() (*packet.ArtPollRep
 	// OEMコード
 	replyPacket.Oem = 0x0000
 
-	// ポート数（この実装では出力ポートなしで設定）
-	replyPacket.NumPorts = 0
-
 	// ノードレポート（NodeReportCodeのスライスとして設定）
 	nodeReportStr := "DMX Viewer Ready"
 	for i, char := range []byte(nodeReportStr) {
@@ -166,19 +349,7 @@ func (h *ArtNetPacketHandlerImpl) createArtPollReplyPacket() (*packet.ArtPollRep
 		replyPacket.NodeReport[i] = code.NodeReportCode(char)
 	}
 
-	return replyPacket, nil
-}
-
-// getLocalIPAddress ローカルIPアドレスを取得する
-func (h *ArtNetPacketHandlerImpl) getLocalIPAddress() (net.IP, error) {
-	conn, err := net.Dial("udp", "8.8.8.8:80")
-	if err != nil {
-		return nil, err
-	}
-	defer conn.Close()
-
-	localAddr := conn.LocalAddr().(*net.UDPAddr)
-	return localAddr.IP, nil
+	return replyPacket
 }
 
 // HandlePacketAsync ArtNetパケットを非同期で処理する
@@ -262,8 +433,13 @@ func (h *ArtNetPacketHandlerImpl) SendPacket(artNetPacket packet.ArtNetPacket, a
 //	pollPacket := packet.NewArtPollPacket()
 //	err := handler.BroadcastPacket(pollPacket)
 func (h *ArtNetPacketHandlerImpl) BroadcastPacket(artNetPacket packet.ArtNetPacket) error {
+	// 選択したインターフェースのディレクテッドブロードキャストアドレスに送信する
+	broadcastIP := net.IPv4bcast
+	if iface := h.netProvider.LocalInterface(); iface != nil {
+		broadcastIP = iface.Broadcast
+	}
 	broadcastAddr := &net.UDPAddr{
-		IP:   net.IPv4bcast,
+		IP:   broadcastIP,
 		Port: 6454, // ArtNetのデフォルトポート
 	}
 
@@ -0,0 +1,211 @@
+package usecase
+
+import (
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jsimonetti/go-artnet/packet"
+	"github.com/nasshu2916/dmx_viewer/internal/config"
+	"github.com/nasshu2916/dmx_viewer/internal/domain/model"
+	"github.com/nasshu2916/dmx_viewer/internal/infrastructure"
+	"github.com/nasshu2916/dmx_viewer/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeWebSocketUseCase 配信されたメッセージを記録するWebSocketUseCase
+type fakeWebSocketUseCase struct {
+	mu       sync.Mutex
+	messages map[string][]*model.WebSocketMessage
+}
+
+func newFakeWebSocketUseCase() *fakeWebSocketUseCase {
+	return &fakeWebSocketUseCase{messages: make(map[string][]*model.WebSocketMessage)}
+}
+
+func (f *fakeWebSocketUseCase) BroadcastToTopic(topic string, message *model.WebSocketMessage) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.messages[topic] = append(f.messages[topic], message)
+	return nil
+}
+
+func (f *fakeWebSocketUseCase) BroadcastToTopics(topics []string, message *model.WebSocketMessage) error {
+	for _, topic := range topics {
+		if err := f.BroadcastToTopic(topic, message); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *fakeWebSocketUseCase) Messages(topic string) []*model.WebSocketMessage {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]*model.WebSocketMessage(nil), f.messages[topic]...)
+}
+
+// fakeArtNetWriter 送信されたパケットを記録するArtNetWriter
+type fakeArtNetWriter struct {
+	mu      sync.Mutex
+	packets []packet.ArtNetPacket
+	addrs   []net.Addr
+}
+
+func (f *fakeArtNetWriter) SendToWriteChan(data []byte, addr net.Addr) error {
+	p, err := packet.Unmarshal(data)
+	if err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.packets = append(f.packets, p)
+	f.addrs = append(f.addrs, addr)
+	return nil
+}
+
+func (f *fakeArtNetWriter) PollReplies() []*packet.ArtPollReplyPacket {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var replies []*packet.ArtPollReplyPacket
+	for _, p := range f.packets {
+		if reply, ok := p.(*packet.ArtPollReplyPacket); ok {
+			replies = append(replies, reply)
+		}
+	}
+	return replies
+}
+
+// fakeNetworkInterfaceProvider 固定のインターフェースを返すNetworkInterfaceProvider
+type fakeNetworkInterfaceProvider struct {
+	iface *model.NetworkInterface
+}
+
+func (f *fakeNetworkInterfaceProvider) LocalInterface() *model.NetworkInterface {
+	return f.iface
+}
+
+func newTestPacketHandler(t *testing.T, cfg *config.ArtNet, universes []uint16) (*ArtNetPacketHandlerImpl, *fakeWebSocketUseCase, *fakeArtNetWriter) {
+	t.Helper()
+	ws := newFakeWebSocketUseCase()
+	writer := &fakeArtNetWriter{}
+	settingsRepo, err := infrastructure.NewNodeSettingsRepository(model.NewNodeSettings("Viewer", "DMX Viewer", universes), "")
+	require.NoError(t, err)
+	mac, _ := net.ParseMAC("02:00:00:00:00:01")
+	netProvider := &fakeNetworkInterfaceProvider{
+		iface: model.NewNetworkInterface("eth0", net.IPv4(2, 0, 0, 1), net.CIDRMask(8, 32), mac),
+	}
+	l := logger.NewLogger("fatal")
+	nodeLiveness := NewNodeLivenessUseCaseImpl(infrastructure.NewArtNetNodeRepository(), ws, model.NewNodeLivenessPolicy(5*time.Second, 0), l)
+	h := NewArtNetPacketHandler(ws, writer, netProvider, cfg, l, nodeLiveness, infrastructure.NewTimeCodeRepository(), settingsRepo, NewSequenceTracker(ws, l), NewUniverseMerger(model.MergeModeHTP, ws, nil, l), nil)
+	return h, ws, writer
+}
+
+func TestArtNetPacketHandler_ArtPollRepliesPerPage(t *testing.T) {
+	h, _, writer := newTestPacketHandler(t, &config.ArtNet{}, []uint16{0, 1, 2, 3, 4, 0x210})
+
+	src := &net.UDPAddr{IP: net.ParseIP("192.0.2.10"), Port: 6454}
+	require.NoError(t, h.HandlePacket(model.ReceivedArtPacket{Packet: packet.NewArtPollPacket(), Addr: src}))
+
+	replies := writer.PollReplies()
+	require.Len(t, replies, 3)
+	for i, reply := range replies {
+		assert.Equal(t, uint8(i+1), reply.BindIndex)
+		assert.Equal(t, [4]byte{2, 0, 0, 1}, reply.IPAddress)
+		assert.Equal(t, [6]byte{2, 0, 0, 0, 0, 1}, reply.Macaddress)
+	}
+	assert.Equal(t, uint16(4), replies[0].NumPorts)
+	assert.Equal(t, [4]uint8{0, 1, 2, 3}, replies[0].SwOut)
+	assert.True(t, replies[0].PortTypes[0].Output())
+	assert.Equal(t, uint16(1), replies[1].NumPorts)
+	assert.Equal(t, uint8(2), replies[2].NetSwitch)
+	assert.Equal(t, uint8(1), replies[2].SubSwitch)
+	assert.Equal(t, uint8(0), replies[2].SwOut[0])
+}
+
+func TestArtNetPacketHandler_ArtAddressRenamesNode(t *testing.T) {
+	h, ws, writer := newTestPacketHandler(t, &config.ArtNet{}, nil)
+
+	addressPacket := &packet.ArtAddressPacket{
+		NetSwitch: 0x7F,
+		SubSwitch: 0x7F,
+		SwIn:      [4]uint8{0x7F, 0x7F, 0x7F, 0x7F},
+		SwOut:     [4]uint8{0x7F, 0x7F, 0x7F, 0x7F},
+		Command:   model.AcLedLocate,
+	}
+	copy(addressPacket.ShortName[:], "Renamed")
+
+	src := &net.UDPAddr{IP: net.ParseIP("192.0.2.10"), Port: 6454}
+	require.NoError(t, h.HandlePacket(model.ReceivedArtPacket{Packet: addressPacket, Addr: src}))
+
+	replies := writer.PollReplies()
+	require.Len(t, replies, 1)
+	// ArtAddressへの応答はインターフェースのディレクテッドブロードキャストに送信される
+	assert.Equal(t, "2.255.255.255:6454", writer.addrs[0].String())
+	assert.Equal(t, "Renamed", string(replies[0].ShortName[:len("Renamed")]))
+	assert.Len(t, ws.Messages("artnet/node_settings"), 1)
+}
+
+func TestArtNetPacketHandler_ArtAddressDoesNotLoseConcurrentUpdates(t *testing.T) {
+	for i := 0; i < 20; i++ {
+		h, ws, _ := newTestPacketHandler(t, &config.ArtNet{}, nil)
+		settingsUseCase := NewNodeSettingsUseCaseImpl(h.settingsRepo, h, ws, logger.NewLogger("fatal"))
+
+		addressPacket := &packet.ArtAddressPacket{
+			NetSwitch: 0x7F,
+			SubSwitch: 0x7F,
+			SwIn:      [4]uint8{0x7F, 0x7F, 0x7F, 0x7F},
+			SwOut:     [4]uint8{0x7F, 0x7F, 0x7F, 0x7F},
+		}
+		copy(addressPacket.ShortName[:], "Renamed")
+		src := &net.UDPAddr{IP: net.ParseIP("192.0.2.10"), Port: 6454}
+
+		var wg sync.WaitGroup
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, h.HandlePacket(model.ReceivedArtPacket{Packet: addressPacket, Addr: src}))
+		}()
+		go func() {
+			defer wg.Done()
+			_, err := settingsUseCase.SetMonitoredUniverses([]uint16{1, 2})
+			assert.NoError(t, err)
+		}()
+		wg.Wait()
+
+		// 同時に変更しても、どちらの変更も失われない
+		settings := h.settingsRepo.Get()
+		assert.Equal(t, "Renamed", settings.ShortName)
+		assert.Equal(t, []uint16{1, 2}, settings.MonitoredUniverses())
+	}
+}
+
+func TestArtNetPacketHandler_ArtSyncReleasesFrames(t *testing.T) {
+	h, ws, _ := newTestPacketHandler(t, &config.ArtNet{SyncEnabled: true}, nil)
+	defer h.syncBuffer.Stop()
+
+	src := &net.UDPAddr{IP: net.ParseIP("192.0.2.20"), Port: 6454}
+	dmx := func(universe uint8) model.ReceivedArtPacket {
+		return model.ReceivedArtPacket{Packet: &packet.ArtDMXPacket{SubUni: universe, Length: 512}, Addr: src}
+	}
+
+	// 同期モード前は即時に配信される
+	require.NoError(t, h.HandlePacket(dmx(0)))
+	assert.Len(t, ws.Messages("artnet/dmx_packet"), 1)
+
+	require.NoError(t, h.HandlePacket(model.ReceivedArtPacket{Packet: packet.NewArtSyncPacket(), Addr: src}))
+	require.NoError(t, h.HandlePacket(dmx(0)))
+	require.NoError(t, h.HandlePacket(dmx(1)))
+	assert.Len(t, ws.Messages("artnet/dmx_packet"), 1)
+
+	require.NoError(t, h.HandlePacket(model.ReceivedArtPacket{Packet: packet.NewArtSyncPacket(), Addr: src}))
+	messages := ws.Messages("artnet/dmx_packet")
+	require.Len(t, messages, 3)
+	assert.True(t, messages[1].Data.(*model.DMXFrame).Synced)
+	assert.True(t, messages[2].Data.(*model.DMXFrame).Synced)
+	// ユニバースごとのトピックにも配信される
+	assert.Len(t, ws.Messages("artnet/dmx/0"), 2)
+	assert.Len(t, ws.Messages("artnet/dmx/1"), 1)
+}
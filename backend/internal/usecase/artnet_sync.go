@@ -0,0 +1,135 @@
+package usecase
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/nasshu2916/dmx_viewer/internal/domain/model"
+)
+
+// ArtSyncTimeout Art-Net 4 で規定された同期モードのタイムアウト
+// この時間ArtSyncを受信しなかった送信元は非同期モードに戻る
+const ArtSyncTimeout = 4 * time.Second
+
+// ArtSyncBuffer ArtSyncによる同期出力のため、送信元ごとにArtDMXを保持するバッファ
+// Push と Sync は受信した順に呼ぶ必要がある（ArtNetBridgeUseCaseImpl の受信ループで処理する）
+type ArtSyncBuffer struct {
+	mu        sync.Mutex
+	timeout   time.Duration
+	sources   map[string]*artSyncSource
+	onTimeout func(frames []*model.DMXFrame)
+}
+
+// artSyncSource 同期モード中の送信元の状態
+type artSyncSource struct {
+	pending  map[uint16]*model.DMXFrame // ユニバースごとの未出力フレーム
+	lastSync time.Time                  // 最後にArtSyncを受信した時刻
+	timer    *time.Timer
+}
+
+// NewArtSyncBuffer ArtSyncBufferの新しいインスタンスを作成
+// onTimeout は同期モードがタイムアウトした際に、保持していたフレームを渡して呼び出される
+func NewArtSyncBuffer(timeout time.Duration, onTimeout func(frames []*model.DMXFrame)) *ArtSyncBuffer {
+	if timeout <= 0 {
+		timeout = ArtSyncTimeout
+	}
+	return &ArtSyncBuffer{
+		timeout:   timeout,
+		sources:   make(map[string]*artSyncSource),
+		onTimeout: onTimeout,
+	}
+}
+
+// Push 送信元が同期モードであればフレームを保持してtrueを返す
+// 非同期モードの場合は保持せずfalseを返すので、呼び出し側で即時に出力する
+func (b *ArtSyncBuffer) Push(dmx *model.DMXFrame) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	src, ok := b.sources[dmx.SourceIP.String()]
+	if !ok {
+		return false
+	}
+
+	// 同一ユニバースの未出力フレームは最新のもので上書きする
+	src.pending[dmx.Universe] = dmx
+	return true
+}
+
+// Sync ArtSyncの受信を記録し、送信元が保持していたフレームを同期済みとして返す
+// 受信した送信元は同期モードに入り、タイムアウトまでArtDMXが保持されるようになる
+func (b *ArtSyncBuffer) Sync(srcIP string) []*model.DMXFrame {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	src, ok := b.sources[srcIP]
+	if !ok {
+		src = &artSyncSource{pending: make(map[uint16]*model.DMXFrame)}
+		src.timer = time.AfterFunc(b.timeout, func() { b.expire(srcIP, src) })
+		b.sources[srcIP] = src
+	} else {
+		src.timer.Reset(b.timeout)
+	}
+	src.lastSync = time.Now()
+
+	frames := takePendingFrames(src)
+	for _, frame := range frames {
+		frame.Synced = true
+	}
+	return frames
+}
+
+// IsSyncMode 指定した送信元が同期モードかどうかを返す
+func (b *ArtSyncBuffer) IsSyncMode(srcIP string) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	_, ok := b.sources[srcIP]
+	return ok
+}
+
+// Stop すべての送信元の同期モードを解除する（保持中のフレームは破棄される）
+func (b *ArtSyncBuffer) Stop() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for key, src := range b.sources {
+		src.timer.Stop()
+		delete(b.sources, key)
+	}
+}
+
+// expire 同期モードのタイムアウト処理
+// 保持していたフレームは非同期フレームとして onTimeout に渡す
+func (b *ArtSyncBuffer) expire(srcIP string, src *artSyncSource) {
+	b.mu.Lock()
+	// タイマー発火とリセットが競合した場合、既に別の状態に置き換わっていれば何もしない
+	if current, ok := b.sources[srcIP]; !ok || current != src {
+		b.mu.Unlock()
+		return
+	}
+	// 発火待ちの間にArtSyncを受信していた場合はリセット済みのタイマーに任せる
+	if time.Since(src.lastSync) < b.timeout {
+		b.mu.Unlock()
+		return
+	}
+	delete(b.sources, srcIP)
+	frames := takePendingFrames(src)
+	b.mu.Unlock()
+
+	if len(frames) > 0 && b.onTimeout != nil {
+		b.onTimeout(frames)
+	}
+}
+
+// takePendingFrames 保持しているフレームをユニバース順に取り出す
+func takePendingFrames(src *artSyncSource) []*model.DMXFrame {
+	frames := make([]*model.DMXFrame, 0, len(src.pending))
+	for universe, frame := range src.pending {
+		frames = append(frames, frame)
+		delete(src.pending, universe)
+	}
+	sort.Slice(frames, func(i, j int) bool {
+		return frames[i].Universe < frames[j].Universe
+	})
+	return frames
+}
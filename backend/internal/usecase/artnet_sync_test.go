@@ -0,0 +1,73 @@
+package usecase
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/nasshu2916/dmx_viewer/internal/domain/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newSyncTestFrame(ip string, universe uint16, value uint8) *model.DMXFrame {
+	dmx := &model.DMXFrame{Universe: universe, Length: 512, SourceIP: net.ParseIP(ip)}
+	dmx.Data[0] = value
+	return dmx
+}
+
+func TestArtSyncBuffer_ImmediateUntilSync(t *testing.T) {
+	b := NewArtSyncBuffer(time.Second, nil)
+	defer b.Stop()
+
+	// ArtSync受信前は保持されない
+	assert.False(t, b.Push(newSyncTestFrame("10.0.0.1", 0, 1)))
+	assert.False(t, b.IsSyncMode("10.0.0.1"))
+
+	frames := b.Sync("10.0.0.1")
+	assert.Empty(t, frames)
+	assert.True(t, b.IsSyncMode("10.0.0.1"))
+
+	// 同期モードに入ると保持される
+	assert.True(t, b.Push(newSyncTestFrame("10.0.0.1", 2, 10)))
+	assert.True(t, b.Push(newSyncTestFrame("10.0.0.1", 1, 20)))
+	// 同一ユニバースは最新で上書き
+	assert.True(t, b.Push(newSyncTestFrame("10.0.0.1", 2, 30)))
+	// 別の送信元は影響を受けない
+	assert.False(t, b.Push(newSyncTestFrame("10.0.0.2", 1, 40)))
+
+	frames = b.Sync("10.0.0.1")
+	require.Len(t, frames, 2)
+	assert.Equal(t, uint16(1), frames[0].Universe)
+	assert.Equal(t, uint16(2), frames[1].Universe)
+	assert.Equal(t, uint8(30), frames[1].Data[0])
+	for _, f := range frames {
+		assert.True(t, f.Synced)
+	}
+
+	// 取り出した後は空になる
+	assert.Empty(t, b.Sync("10.0.0.1"))
+}
+
+func TestArtSyncBuffer_Timeout(t *testing.T) {
+	released := make(chan []*model.DMXFrame, 1)
+	b := NewArtSyncBuffer(50*time.Millisecond, func(frames []*model.DMXFrame) {
+		released <- frames
+	})
+	defer b.Stop()
+
+	b.Sync("10.0.0.1")
+	assert.True(t, b.Push(newSyncTestFrame("10.0.0.1", 3, 1)))
+
+	select {
+	case frames := <-released:
+		require.Len(t, frames, 1)
+		assert.False(t, frames[0].Synced)
+	case <-time.After(time.Second):
+		t.Fatal("sync timeout did not release pending frames")
+	}
+
+	// タイムアウト後は非同期モードに戻る
+	assert.False(t, b.IsSyncMode("10.0.0.1"))
+	assert.False(t, b.Push(newSyncTestFrame("10.0.0.1", 3, 2)))
+}
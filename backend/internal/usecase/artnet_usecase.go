@@ -60,12 +60,43 @@ func (uc *ArtNetBridgeUseCaseImpl) StartPacketForwarding(ctx context.Context, ar
 			}
 
 			packet := model.ReceivedArtPacket{
-				Packet: artPacket,
-				Addr:   receivedData.Addr,
+				Packet:     artPacket,
+				Addr:       receivedData.Addr,
+				ReceivedAt: receivedData.ReceivedAt,
 			}
 
+			if inOrderPacket(artPacket) {
+				uc.handlePacketInOrder(packet)
+				continue
+			}
 			// パケットを非同期でハンドラーに渡して処理
 			uc.packetHandler.HandlePacketAsync(ctx, packet)
 		}
 	}
 }
+
+// inOrderPacket 受信した順に処理する必要があるパケットか
+// ArtDMX はシーケンス番号の並び替え・欠落を受信した順に記録するため、
+// ArtSync は直前に受信した ArtDMX を保持した後に出力するため、受信ループで処理する
+func inOrderPacket(artPacket packet.ArtNetPacket) bool {
+	switch artPacket.(type) {
+	case *packet.ArtDMXPacket, *packet.ArtSyncPacket:
+		return true
+	default:
+		return false
+	}
+}
+
+// handlePacketInOrder パケットを受信ループで処理する（パニックしても受信を継続する）
+func (uc *ArtNetBridgeUseCaseImpl) handlePacketInOrder(received model.ReceivedArtPacket) {
+	defer func() {
+		if r := recover(); r != nil {
+			uc.logger.Error("Panic occurred in packet processing", "panic", r)
+		}
+	}()
+	if err := uc.packetHandler.HandlePacket(received); err != nil {
+		uc.logger.Error("Failed to process packet",
+			"error", err,
+			"packetType", received.Packet.GetOpCode().String())
+	}
+}
@@ -0,0 +1,212 @@
+package usecase
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/nasshu2916/dmx_viewer/internal/domain/model"
+	"github.com/nasshu2916/dmx_viewer/pkg/logger"
+)
+
+const (
+	// DMXKeyframeInterval 送信元ごとに全チャンネルの値（キーフレーム）を配信する間隔
+	DMXKeyframeInterval = 2 * time.Second
+	// DMXDeltaStreamTimeout フレームを受信しなくなった送信元の状態を破棄するまでの時間
+	DMXDeltaStreamTimeout = 10 * time.Second
+)
+
+type dmxDeltaKey struct {
+	protocol string
+	universe uint16
+	source   string
+}
+
+// dmxKeyframeRequest クライアントがトピックを購読したときのキーフレームの要求
+type dmxKeyframeRequest struct {
+	universe model.UniverseKey
+	reply    func(message *model.WebSocketMessage) // 購読したクライアントにのみ送信する
+}
+
+// dmxDeltaStream 送信元・ユニバースごとに最後に配信した値
+type dmxDeltaStream struct {
+	data       [512]uint8
+	length     uint16
+	sequence   uint32
+	keyframeAt time.Time
+	updatedAt  time.Time
+}
+
+// DMXDeltaUseCaseImpl 受信したDMXフレームを送信元ごとの値の差分として {protocol}/dmx_delta/{universe} トピックに配信する
+//
+// FrameObserver としてフレームを受け取り、前回から変化したチャンネルのみを配信する。
+// キーフレームは一定間隔ごとにトピックに配信するほか、クライアントがトピックを購読したときにそのクライアントにのみ送信する。
+// 差分の Sequence が連続しなくなったクライアントは、購読中のトピックをもう一度購読すると
+// （{"type": "subscribe", "topic": "artnet/dmx_delta/1"}）キーフレームを受け取って同期し直せる。
+type DMXDeltaUseCaseImpl struct {
+	wsUseCase WebSocketUseCase
+	logger    *logger.Logger
+	now       func() time.Time
+
+	// mu は配信中も保持し、同じ送信元の差分とキーフレームを Sequence の順に配信する
+	mu      sync.Mutex
+	streams map[dmxDeltaKey]*dmxDeltaStream
+
+	// requests はキーフレームの要求（WebSocketのハブから呼ばれるため mu とは別に保護する）
+	requestMu sync.Mutex
+	requests  []dmxKeyframeRequest
+	wake      chan struct{}
+}
+
+// NewDMXDeltaUseCaseImpl DMXDeltaUseCaseImplの新しいインスタンスを作成
+func NewDMXDeltaUseCaseImpl(wsUseCase WebSocketUseCase, logger *logger.Logger) *DMXDeltaUseCaseImpl {
+	return &DMXDeltaUseCaseImpl{
+		wsUseCase: wsUseCase,
+		logger:    logger,
+		now:       time.Now,
+		streams:   make(map[dmxDeltaKey]*dmxDeltaStream),
+		wake:      make(chan struct{}, 1),
+	}
+}
+
+// ObserveFrame 前回から変化したチャンネルを配信する（初めて受信した送信元、スロット数が変わった場合はキーフレーム）
+func (uc *DMXDeltaUseCaseImpl) ObserveFrame(frame *model.DMXFrame) {
+	if frame.StartCode != model.StartCodeDMX {
+		return
+	}
+	key := dmxDeltaKey{protocol: frame.Protocol, universe: frame.Universe, source: frameSourceKey(frame)}
+	now := uc.now()
+
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	stream, ok := uc.streams[key]
+	if !ok || stream.length != frame.Length {
+		if !ok {
+			stream = &dmxDeltaStream{}
+			uc.streams[key] = stream
+		}
+		stream.data, stream.length, stream.updatedAt = frame.Data, frame.Length, now
+		uc.publishKeyframe(key, stream, now)
+		return
+	}
+
+	stream.updatedAt = now
+	changes := model.DiffDMX(&stream.data, &frame.Data)
+	if len(changes) == 0 {
+		return
+	}
+	stream.data = frame.Data
+	stream.sequence++
+	uc.publish(key, &model.DMXDelta{
+		Protocol: key.protocol,
+		Universe: key.universe,
+		Source:   key.source,
+		Sequence: stream.sequence,
+		Length:   stream.length,
+		Changes:  changes,
+	})
+}
+
+// TopicSubscribed クライアントが差分のトピックを購読したときに、そのクライアントに送信するキーフレームを要求する
+// WebSocketのハブから呼ばれるため、送信を待たずに戻る
+func (uc *DMXDeltaUseCaseImpl) TopicSubscribed(topic string, reply func(message *model.WebSocketMessage)) {
+	protocol, universe, ok := model.ParseDMXDeltaTopic(topic)
+	if !ok {
+		return
+	}
+	uc.requestMu.Lock()
+	uc.requests = append(uc.requests, dmxKeyframeRequest{
+		universe: model.UniverseKey{Protocol: protocol, Universe: universe},
+		reply:    reply,
+	})
+	uc.requestMu.Unlock()
+
+	select {
+	case uc.wake <- struct{}{}:
+	default:
+	}
+}
+
+// StartKeyframer 要求されたキーフレームと、一定間隔のキーフレームを配信する
+func (uc *DMXDeltaUseCaseImpl) StartKeyframer(ctx context.Context) {
+	ticker := time.NewTicker(DMXKeyframeInterval / 4)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-uc.wake:
+			uc.publishRequestedKeyframes()
+		case <-ticker.C:
+			uc.publishPeriodicKeyframes()
+		}
+	}
+}
+
+// publishRequestedKeyframes キーフレームを要求したクライアントに、そのユニバースの全送信元のキーフレームを送信する
+// 差分と同じく mu を保持して送信し、キーフレームより前の Sequence の差分が後から届かないようにする
+func (uc *DMXDeltaUseCaseImpl) publishRequestedKeyframes() {
+	uc.requestMu.Lock()
+	requests := uc.requests
+	uc.requests = nil
+	uc.requestMu.Unlock()
+	if len(requests) == 0 {
+		return
+	}
+
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	for _, request := range requests {
+		for key, stream := range uc.streams {
+			if key.protocol == request.universe.Protocol && key.universe == request.universe.Universe {
+				request.reply(model.NewWebSocketMessage("dmx_delta", keyframe(key, stream)))
+			}
+		}
+	}
+}
+
+// publishPeriodicKeyframes 前回のキーフレームから DMXKeyframeInterval 以上経過した送信元のキーフレームを配信し、
+// フレームを受信しなくなった送信元の状態を破棄する
+func (uc *DMXDeltaUseCaseImpl) publishPeriodicKeyframes() {
+	now := uc.now()
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	for key, stream := range uc.streams {
+		if now.Sub(stream.updatedAt) >= DMXDeltaStreamTimeout {
+			delete(uc.streams, key)
+			continue
+		}
+		if now.Sub(stream.keyframeAt) >= DMXKeyframeInterval {
+			uc.publishKeyframe(key, stream, now)
+		}
+	}
+}
+
+// publishKeyframe 全チャンネルの値を配信する（mu を保持して呼ぶ）
+func (uc *DMXDeltaUseCaseImpl) publishKeyframe(key dmxDeltaKey, stream *dmxDeltaStream, now time.Time) {
+	stream.keyframeAt = now
+	uc.publish(key, keyframe(key, stream))
+}
+
+// keyframe 送信元の全チャンネルの値（mu を保持して呼ぶ）
+func keyframe(key dmxDeltaKey, stream *dmxDeltaStream) *model.DMXDelta {
+	data := stream.data
+	return &model.DMXDelta{
+		Protocol: key.protocol,
+		Universe: key.universe,
+		Source:   key.source,
+		Sequence: stream.sequence,
+		Keyframe: true,
+		Length:   stream.length,
+		Data:     &data,
+	}
+}
+
+func (uc *DMXDeltaUseCaseImpl) publish(key dmxDeltaKey, delta *model.DMXDelta) {
+	msg := model.NewWebSocketMessage("dmx_delta", delta)
+	if err := uc.wsUseCase.BroadcastToTopic(model.DMXDeltaTopic(key.protocol, key.universe), msg); err != nil {
+		uc.logger.Error("Failed to broadcast DMX delta", "error", err, "universe", key.universe)
+	}
+}
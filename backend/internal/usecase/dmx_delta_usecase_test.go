@@ -0,0 +1,152 @@
+package usecase
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/nasshu2916/dmx_viewer/internal/domain/model"
+	"github.com/nasshu2916/dmx_viewer/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestDMXDeltaUseCase() (*DMXDeltaUseCaseImpl, *fakeWebSocketUseCase, *time.Time) {
+	ws := newFakeWebSocketUseCase()
+	uc := NewDMXDeltaUseCaseImpl(ws, logger.NewLogger("fatal"))
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	uc.now = func() time.Time { return now }
+	return uc, ws, &now
+}
+
+func deltaFrame(universe uint16, ip byte, values ...uint8) *model.DMXFrame {
+	frame := &model.DMXFrame{Protocol: model.ProtocolArtNet, Universe: universe, SourceIP: net.IPv4(2, 0, 0, ip), Length: 512}
+	copy(frame.Data[:], values)
+	return frame
+}
+
+func deltas(ws *fakeWebSocketUseCase, topic string) []*model.DMXDelta {
+	var result []*model.DMXDelta
+	for _, msg := range ws.Messages(topic) {
+		result = append(result, msg.Data.(*model.DMXDelta))
+	}
+	return result
+}
+
+func TestDMXDeltaUseCase_KeyframeThenChanges(t *testing.T) {
+	uc, ws, _ := newTestDMXDeltaUseCase()
+
+	uc.ObserveFrame(deltaFrame(1, 10, 255, 0, 3))
+	uc.ObserveFrame(deltaFrame(1, 10, 255, 0, 3))
+	uc.ObserveFrame(deltaFrame(1, 10, 255, 7, 0))
+	uc.ObserveFrame(deltaFrame(1, 10, 0, 7, 0))
+
+	got := deltas(ws, "artnet/dmx_delta/1")
+	require.Len(t, got, 3)
+	assert.True(t, got[0].Keyframe)
+	assert.Equal(t, uint32(0), got[0].Sequence)
+	assert.Equal(t, "2.0.0.10", got[0].Source)
+	assert.Equal(t, uint8(255), got[0].Data[0])
+
+	// 変化のないフレームは配信せず、Sequence も進めない
+	assert.False(t, got[1].Keyframe)
+	assert.Equal(t, uint32(1), got[1].Sequence)
+	assert.Nil(t, got[1].Data)
+	assert.Equal(t, [][2]uint16{{2, 7}, {3, 0}}, got[1].Changes)
+	assert.Equal(t, uint32(2), got[2].Sequence)
+	assert.Equal(t, [][2]uint16{{1, 0}}, got[2].Changes)
+
+	// 差分を順に反映すると最後のフレームと一致する
+	var data [512]uint8
+	for _, d := range got {
+		d.Apply(&data)
+	}
+	assert.Equal(t, deltaFrame(1, 10, 0, 7, 0).Data, data)
+}
+
+func TestDMXDeltaUseCase_SourcesAreIndependent(t *testing.T) {
+	uc, ws, _ := newTestDMXDeltaUseCase()
+
+	uc.ObserveFrame(deltaFrame(1, 10, 1))
+	uc.ObserveFrame(deltaFrame(1, 11, 2))
+	uc.ObserveFrame(deltaFrame(2, 10, 3))
+	uc.ObserveFrame(deltaFrame(1, 10, 4))
+
+	got := deltas(ws, "artnet/dmx_delta/1")
+	require.Len(t, got, 3)
+	assert.True(t, got[0].Keyframe)
+	assert.True(t, got[1].Keyframe)
+	assert.Equal(t, "2.0.0.11", got[1].Source)
+	assert.Equal(t, uint32(1), got[2].Sequence)
+	assert.Len(t, deltas(ws, "artnet/dmx_delta/2"), 1)
+
+	// スロット数が変わった場合はキーフレームを配信する
+	frame := deltaFrame(1, 10, 4)
+	frame.Length = 24
+	uc.ObserveFrame(frame)
+	got = deltas(ws, "artnet/dmx_delta/1")
+	require.Len(t, got, 4)
+	assert.True(t, got[3].Keyframe)
+	assert.Equal(t, uint16(24), got[3].Length)
+	assert.Equal(t, uint32(1), got[3].Sequence)
+}
+
+func TestDMXDeltaUseCase_KeyframeOnSubscribe(t *testing.T) {
+	uc, ws, _ := newTestDMXDeltaUseCase()
+
+	uc.ObserveFrame(deltaFrame(1, 10, 1))
+	uc.ObserveFrame(deltaFrame(1, 10, 2))
+	uc.ObserveFrame(deltaFrame(2, 10, 3))
+
+	var replies, otherReplies []*model.DMXDelta
+	reply := func(msg *model.WebSocketMessage) {
+		assert.Equal(t, "dmx_delta", msg.Type)
+		replies = append(replies, msg.Data.(*model.DMXDelta))
+	}
+	uc.TopicSubscribed("artnet/dmx/1", func(*model.WebSocketMessage) { t.Fatal("unexpected keyframe") })
+	uc.TopicSubscribed("artnet/dmx_delta/1", reply)
+	uc.TopicSubscribed("artnet/dmx_delta/3", func(msg *model.WebSocketMessage) {
+		otherReplies = append(otherReplies, msg.Data.(*model.DMXDelta))
+	})
+	uc.publishRequestedKeyframes()
+	uc.publishRequestedKeyframes()
+
+	// キーフレームは購読したクライアントにのみ送信し、トピックには配信しない
+	require.Len(t, replies, 1)
+	assert.True(t, replies[0].Keyframe)
+	assert.Equal(t, uint32(1), replies[0].Sequence)
+	assert.Equal(t, uint8(2), replies[0].Data[0])
+	assert.Empty(t, otherReplies)
+	assert.Len(t, deltas(ws, "artnet/dmx_delta/1"), 2)
+	assert.Len(t, deltas(ws, "artnet/dmx_delta/2"), 1)
+
+	// 購読し直すと、もう一度キーフレームを受け取る
+	uc.ObserveFrame(deltaFrame(1, 10, 5))
+	uc.TopicSubscribed("artnet/dmx_delta/1", reply)
+	uc.publishRequestedKeyframes()
+	require.Len(t, replies, 2)
+	assert.Equal(t, uint32(2), replies[1].Sequence)
+	assert.Equal(t, uint8(5), replies[1].Data[0])
+}
+
+func TestDMXDeltaUseCase_PeriodicKeyframes(t *testing.T) {
+	uc, ws, now := newTestDMXDeltaUseCase()
+
+	uc.ObserveFrame(deltaFrame(1, 10, 1))
+	*now = now.Add(DMXKeyframeInterval / 2)
+	uc.ObserveFrame(deltaFrame(1, 10, 2))
+	uc.publishPeriodicKeyframes()
+	assert.Len(t, deltas(ws, "artnet/dmx_delta/1"), 2)
+
+	*now = now.Add(DMXKeyframeInterval / 2)
+	uc.publishPeriodicKeyframes()
+	got := deltas(ws, "artnet/dmx_delta/1")
+	require.Len(t, got, 3)
+	assert.True(t, got[2].Keyframe)
+
+	// フレームを受信しなくなった送信元は破棄する
+	*now = now.Add(DMXDeltaStreamTimeout)
+	uc.publishPeriodicKeyframes()
+	assert.Len(t, deltas(ws, "artnet/dmx_delta/1"), 3)
+	assert.Empty(t, uc.streams)
+}
@@ -0,0 +1,315 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"sort"
+	"time"
+
+	"github.com/nasshu2916/dmx_viewer/internal/domain/model"
+	"github.com/nasshu2916/dmx_viewer/internal/domain/repository"
+)
+
+// ExportHistorySource 履歴を書き出すときの送信元の表示
+const ExportHistorySource = "merged"
+
+var ErrInvalidExportOptions = errors.New("invalid export options")
+
+// exportContextCheckInterval キャンセルを確認する間隔（行数）
+const exportContextCheckInterval = 1000
+
+// ExportRowWriter 書き出す行を受け取るインターフェース
+type ExportRowWriter interface {
+	WriteRow(row *model.ExportRow) error
+}
+
+// CaptureRecordSource キャプチャファイルのレコードを順に返すインターフェース（末尾では io.EOF）
+type CaptureRecordSource interface {
+	Next() (*model.CaptureRecord, error)
+}
+
+// ExportUseCase チャンネルの値の時系列を書き出すインターフェース
+type ExportUseCase interface {
+	// サーバーが保持している履歴を書き出す
+	ExportHistory(ctx context.Context, opts model.ExportOptions, w ExportRowWriter) error
+	// 記録したキャプチャファイルを書き出す
+	ExportCapture(ctx context.Context, name string, opts model.ExportOptions, w ExportRowWriter) error
+}
+
+// ExportUseCaseImpl ExportUseCaseの実装
+// キャプチャファイルはレコードを読み込みながら順に書き出し、ファイル全体をメモリに保持しない。
+// 履歴は選択した期間・チャンネルの履歴をまとめて取得してから書き出す
+type ExportUseCaseImpl struct {
+	history  repository.UniverseHistoryRepository // 履歴（コマンドから使用する場合は nil）
+	captures repository.CaptureRepository         // キャプチャファイル（コマンドから使用する場合は nil）
+	now      func() time.Time
+}
+
+// NewExportUseCaseImpl ExportUseCaseの新しいインスタンスを作成
+func NewExportUseCaseImpl(history repository.UniverseHistoryRepository, captures repository.CaptureRepository) *ExportUseCaseImpl {
+	return &ExportUseCaseImpl{
+		history:  history,
+		captures: captures,
+		now:      time.Now,
+	}
+}
+
+// NormalizeExportOptions 省略した値を補い、書き出す条件を確認する
+func NormalizeExportOptions(opts model.ExportOptions) (model.ExportOptions, error) {
+	if opts.Mode == "" {
+		opts.Mode = model.ExportModeChanges
+	}
+	if opts.Protocol == "" {
+		opts.Protocol = model.ProtocolArtNet
+	}
+	if opts.FirstChannel == 0 {
+		opts.FirstChannel = 1
+	}
+	if opts.LastChannel == 0 {
+		opts.LastChannel = 512
+	}
+	switch opts.Protocol {
+	case model.ProtocolArtNet:
+		if opts.Universe > model.MaxUniverse {
+			return opts, fmt.Errorf("%w: universe must be between 0 and %d", ErrInvalidExportOptions, model.MaxUniverse)
+		}
+	case model.ProtocolSACN:
+		if opts.Universe < 1 || opts.Universe > model.MaxSACNUniverse {
+			return opts, fmt.Errorf("%w: universe must be between 1 and %d", ErrInvalidExportOptions, model.MaxSACNUniverse)
+		}
+	default:
+		return opts, fmt.Errorf("%w: unknown protocol %q", ErrInvalidExportOptions, opts.Protocol)
+	}
+	if opts.FirstChannel < 1 || opts.LastChannel > 512 || opts.FirstChannel > opts.LastChannel {
+		return opts, fmt.Errorf("%w: invalid channel range %d-%d", ErrInvalidExportOptions, opts.FirstChannel, opts.LastChannel)
+	}
+	if !opts.From.IsZero() && !opts.To.IsZero() && !opts.From.Before(opts.To) {
+		return opts, fmt.Errorf("%w: from must be before to", ErrInvalidExportOptions)
+	}
+	switch opts.Mode {
+	case model.ExportModeChanges:
+	case model.ExportModeResample:
+		if opts.Step < time.Millisecond {
+			return opts, fmt.Errorf("%w: resample step must be at least 1ms", ErrInvalidExportOptions)
+		}
+	default:
+		return opts, fmt.Errorf("%w: unknown mode %q", ErrInvalidExportOptions, opts.Mode)
+	}
+	return opts, nil
+}
+
+func (uc *ExportUseCaseImpl) ExportHistory(ctx context.Context, opts model.ExportOptions, w ExportRowWriter) error {
+	opts, err := NormalizeExportOptions(opts)
+	if err != nil {
+		return err
+	}
+	if opts.To.IsZero() {
+		opts.To = uc.now()
+	}
+
+	points := uc.history.QueryRange(opts.Protocol, opts.Universe, opts.FirstChannel-1, opts.LastChannel-1, opts.From, opts.To)
+	out := newExportRowEmitter(ctx, w)
+	if opts.Mode == model.ExportModeResample {
+		return resampleHistory(points, opts.From, opts.To, opts.Step, out)
+	}
+
+	var last []float64
+	for _, p := range points {
+		if last != nil && equalValues(last, p.Avg) {
+			continue
+		}
+		last = p.Avg
+		if err := out.emit(p.Time, ExportHistorySource, p.Avg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resampleHistory 履歴を step ごとの区間の平均値（受信したフレーム数による加重平均）として書き出す
+//
+// ExportRecords と同じく from（省略した場合は最初の履歴の区間）から to までのすべての時刻を書き出し、
+// 履歴のない区間は直前の区間の値を書き出す。最初の履歴より前の区間は書き出さない。
+func resampleHistory(points []model.ChannelRangeHistoryPoint, from, to time.Time, step time.Duration, out *exportRowEmitter) error {
+	if len(points) == 0 {
+		return nil
+	}
+	next := from
+	if next.IsZero() {
+		next = points[0].Time.Truncate(step)
+	}
+
+	var values []float64 // 直前の区間の値
+	for i := 0; next.Before(to); next = next.Add(step) {
+		end := next.Add(step)
+		var sums []float64
+		var count float64
+		for ; i < len(points) && points[i].Time.Before(end); i++ {
+			p := points[i]
+			if sums == nil {
+				sums = make([]float64, len(p.Avg))
+			}
+			for ch, v := range p.Avg {
+				sums[ch] += v * float64(p.Count)
+			}
+			count += float64(p.Count)
+		}
+		if count > 0 {
+			values = make([]float64, len(sums))
+			for ch, sum := range sums {
+				values[ch] = sum / count
+			}
+		}
+		if values == nil {
+			continue
+		}
+		if err := out.emit(next, ExportHistorySource, values); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (uc *ExportUseCaseImpl) ExportCapture(ctx context.Context, name string, opts model.ExportOptions, w ExportRowWriter) error {
+	opts, err := NormalizeExportOptions(opts)
+	if err != nil {
+		return err
+	}
+	_, reader, err := uc.captures.Stream(name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("%w: %s", ErrCaptureNotFound, name)
+	}
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+	return ExportRecords(ctx, reader, opts, w)
+}
+
+// ExportRecords キャプチャファイルのレコードから選択したユニバース・期間のフレームを送信元ごとに書き出す
+//
+// 変化したときに書き出す場合は、送信元ごとに選択したチャンネルのいずれかが変化したフレームを1行とする。
+// 一定間隔で書き出す場合は、各時刻にそれまでに受信した送信元ごとの直前の値を1行ずつ書き出す。
+func ExportRecords(ctx context.Context, source CaptureRecordSource, opts model.ExportOptions, w ExportRowWriter) error {
+	opts, err := NormalizeExportOptions(opts)
+	if err != nil {
+		return err
+	}
+	out := newExportRowEmitter(ctx, w)
+	latest := make(map[string][]float64) // 送信元ごとの直前の値
+	var sources []string                 // 最初に受信した順の送信元（一定間隔の場合に並べ替えて使用する）
+	var next time.Time                   // 次に書き出す時刻（一定間隔の場合）
+	var last time.Time
+
+	// emitTicks until より前の時刻の値を書き出す
+	emitTicks := func(until time.Time, inclusive bool) error {
+		for !next.IsZero() && (next.Before(until) || (inclusive && next.Equal(until))) {
+			for _, src := range sources {
+				if err := out.emit(next, src, latest[src]); err != nil {
+					return err
+				}
+			}
+			next = next.Add(opts.Step)
+		}
+		return nil
+	}
+
+	for {
+		record, err := source.Next()
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		frame := record.Frame
+		if frame.Protocol != opts.Protocol || frame.Universe != opts.Universe || frame.StartCode != model.StartCodeDMX {
+			continue
+		}
+		at := frame.ReceivedAt
+		if !opts.From.IsZero() && at.Before(opts.From) {
+			continue
+		}
+		if !opts.To.IsZero() && !at.Before(opts.To) {
+			break
+		}
+		last = at
+
+		values := make([]float64, opts.LastChannel-opts.FirstChannel+1)
+		for i := range values {
+			values[i] = float64(frame.Data[opts.FirstChannel-1+i])
+		}
+		src := frameSourceKey(frame)
+
+		if opts.Mode == model.ExportModeResample {
+			if next.IsZero() {
+				next = at.Truncate(opts.Step)
+				if !opts.From.IsZero() {
+					next = opts.From
+				}
+			}
+			if err := emitTicks(at, false); err != nil {
+				return err
+			}
+			if _, ok := latest[src]; !ok {
+				sources = append(sources, src)
+				sort.Strings(sources)
+			}
+			latest[src] = values
+			continue
+		}
+
+		if prev, ok := latest[src]; ok && equalValues(prev, values) {
+			continue
+		}
+		latest[src] = values
+		if err := out.emit(at, src, values); err != nil {
+			return err
+		}
+	}
+
+	if opts.Mode == model.ExportModeResample {
+		if !opts.To.IsZero() {
+			return emitTicks(opts.To, false)
+		}
+		return emitTicks(last, true)
+	}
+	return nil
+}
+
+// exportRowEmitter 最初の行からの経過時間を付けて行を書き出し、一定行数ごとにキャンセルを確認する
+type exportRowEmitter struct {
+	ctx   context.Context
+	w     ExportRowWriter
+	first time.Time
+	rows  int
+}
+
+func newExportRowEmitter(ctx context.Context, w ExportRowWriter) *exportRowEmitter {
+	return &exportRowEmitter{ctx: ctx, w: w}
+}
+
+func (e *exportRowEmitter) emit(at time.Time, source string, values []float64) error {
+	if e.rows%exportContextCheckInterval == 0 {
+		if err := e.ctx.Err(); err != nil {
+			return err
+		}
+	}
+	if e.rows == 0 {
+		e.first = at
+	}
+	e.rows++
+	return e.w.WriteRow(&model.ExportRow{Time: at, Elapsed: at.Sub(e.first), Source: source, Values: values})
+}
+
+func equalValues(a, b []float64) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,167 @@
+package usecase
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/nasshu2916/dmx_viewer/internal/domain/model"
+	"github.com/nasshu2916/dmx_viewer/internal/infrastructure"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// exportRows 書き出した行を記録するExportRowWriter
+type exportRows []model.ExportRow
+
+func (r *exportRows) WriteRow(row *model.ExportRow) error {
+	*r = append(*r, *row)
+	return nil
+}
+
+func newExportTestFrame(start time.Time, offset time.Duration, source string, values ...uint8) *model.DMXFrame {
+	frame := &model.DMXFrame{Protocol: model.ProtocolArtNet, Universe: 2, SourceIP: net.ParseIP(source), Length: 512, ReceivedAt: start.Add(offset)}
+	copy(frame.Data[:], values)
+	return frame
+}
+
+func newExportTestCapture(t *testing.T, start time.Time) (*ExportUseCaseImpl, string) {
+	t.Helper()
+	repo := infrastructure.NewCaptureRepository(t.TempDir(), 0, 0)
+	require.NoError(t, repo.Open(start))
+	for _, frame := range []*model.DMXFrame{
+		newExportTestFrame(start, 0, "2.0.0.10", 0, 10),
+		newExportTestFrame(start, 100*time.Millisecond, "2.0.0.10", 0, 10, 99), // 選択していないチャンネルのみの変化
+		newExportTestFrame(start, 200*time.Millisecond, "2.0.0.11", 50, 50),
+		newExportTestFrame(start, 300*time.Millisecond, "2.0.0.10", 5, 10),
+		{Protocol: model.ProtocolArtNet, Universe: 3, SourceIP: net.IPv4(2, 0, 0, 10), ReceivedAt: start.Add(350 * time.Millisecond)},
+		newExportTestFrame(start, 500*time.Millisecond, "2.0.0.10", 9, 9),
+	} {
+		require.NoError(t, repo.Append(frame))
+	}
+	require.NoError(t, repo.Close())
+	return NewExportUseCaseImpl(nil, repo), repo.Files()[0].Name
+}
+
+func TestExportUseCase_CaptureChanges(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.Local)
+	uc, name := newExportTestCapture(t, start)
+
+	var rows exportRows
+	opts := model.ExportOptions{Universe: 2, FirstChannel: 1, LastChannel: 2}
+	require.NoError(t, uc.ExportCapture(context.Background(), name, opts, &rows))
+	require.Len(t, rows, 4)
+	assert.Equal(t, model.ExportRow{Time: start, Source: "2.0.0.10", Values: []float64{0, 10}}, rows[0])
+	assert.Equal(t, model.ExportRow{Time: start.Add(200 * time.Millisecond), Elapsed: 200 * time.Millisecond, Source: "2.0.0.11", Values: []float64{50, 50}}, rows[1])
+	assert.Equal(t, []float64{5, 10}, rows[2].Values)
+	assert.Equal(t, []float64{9, 9}, rows[3].Values)
+
+	// 期間の指定
+	rows = nil
+	opts.From, opts.To = start.Add(250*time.Millisecond), start.Add(400*time.Millisecond)
+	require.NoError(t, uc.ExportCapture(context.Background(), name, opts, &rows))
+	require.Len(t, rows, 1)
+	assert.Equal(t, start.Add(300*time.Millisecond), rows[0].Time)
+
+	err := uc.ExportCapture(context.Background(), "missing.dmxcap", opts, &rows)
+	assert.ErrorIs(t, err, ErrCaptureNotFound)
+}
+
+func TestExportUseCase_CaptureResample(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.Local)
+	uc, name := newExportTestCapture(t, start)
+
+	var rows exportRows
+	opts := model.ExportOptions{Mode: model.ExportModeResample, Universe: 2, FirstChannel: 1, LastChannel: 1, Step: 250 * time.Millisecond}
+	require.NoError(t, uc.ExportCapture(context.Background(), name, opts, &rows))
+
+	type sample struct {
+		offset time.Duration
+		source string
+		value  float64
+	}
+	var got []sample
+	for _, row := range rows {
+		got = append(got, sample{row.Time.Sub(start), row.Source, row.Values[0]})
+	}
+	assert.Equal(t, []sample{
+		{0, "2.0.0.10", 0},
+		{250 * time.Millisecond, "2.0.0.10", 0},
+		{250 * time.Millisecond, "2.0.0.11", 50},
+		{500 * time.Millisecond, "2.0.0.10", 9},
+		{500 * time.Millisecond, "2.0.0.11", 50},
+	}, got)
+}
+
+func TestExportUseCase_History(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	policy := model.NewHistoryPolicy(time.Minute, 10*time.Minute, time.Hour)
+	history := infrastructure.NewUniverseHistoryRepository(policy)
+	for i, v := range []uint8{0, 0, 100, 100, 200, 200} {
+		history.Append(model.ProtocolSACN, 1, start.Add(time.Duration(i)*500*time.Millisecond), &[512]uint8{v, 1})
+	}
+	uc := NewExportUseCaseImpl(history, nil)
+	uc.now = func() time.Time { return start.Add(time.Minute) }
+
+	var rows exportRows
+	opts := model.ExportOptions{Protocol: model.ProtocolSACN, Universe: 1, FirstChannel: 1, LastChannel: 2}
+	require.NoError(t, uc.ExportHistory(context.Background(), opts, &rows))
+	require.Len(t, rows, 3)
+	assert.Equal(t, model.ExportRow{Time: start.Add(time.Second), Elapsed: time.Second, Source: ExportHistorySource, Values: []float64{100, 1}}, rows[1])
+
+	rows = nil
+	opts.Mode, opts.Step = model.ExportModeResample, 2*time.Second
+	opts.To = start.Add(7 * time.Second)
+	require.NoError(t, uc.ExportHistory(context.Background(), opts, &rows))
+	require.Len(t, rows, 4)
+	assert.Equal(t, []float64{50, 1}, rows[0].Values)
+	assert.Equal(t, []float64{200, 1}, rows[1].Values)
+	// 履歴のない区間も直前の値で書き出す
+	assert.Equal(t, start.Add(4*time.Second), rows[2].Time)
+	assert.Equal(t, []float64{200, 1}, rows[2].Values)
+	assert.Equal(t, start.Add(6*time.Second), rows[3].Time)
+	assert.Equal(t, []float64{200, 1}, rows[3].Values)
+
+	// 開始時刻を指定した場合は、その時刻から区切る（最初の履歴より前の区間は書き出さない）
+	rows = nil
+	opts.From = start.Add(-3 * time.Second)
+	require.NoError(t, uc.ExportHistory(context.Background(), opts, &rows))
+	require.Len(t, rows, 4)
+	assert.Equal(t, start.Add(-time.Second), rows[0].Time)
+	assert.Equal(t, []float64{0, 1}, rows[0].Values)
+	assert.Equal(t, []float64{150, 1}, rows[1].Values)
+	assert.Equal(t, start.Add(5*time.Second), rows[3].Time)
+	assert.Equal(t, []float64{150, 1}, rows[3].Values)
+}
+
+func TestNormalizeExportOptions(t *testing.T) {
+	opts, err := NormalizeExportOptions(model.ExportOptions{Universe: 1})
+	require.NoError(t, err)
+	assert.Equal(t, model.ExportOptions{Mode: model.ExportModeChanges, Protocol: model.ProtocolArtNet, Universe: 1, FirstChannel: 1, LastChannel: 512}, opts)
+
+	start := time.Now()
+	for _, opts := range []model.ExportOptions{
+		{Protocol: "dmx"},
+		{Protocol: model.ProtocolSACN},
+		{FirstChannel: 10, LastChannel: 5},
+		{From: start, To: start},
+		{Mode: model.ExportModeResample},
+		{Mode: "sparse"},
+	} {
+		_, err := NormalizeExportOptions(opts)
+		assert.ErrorIs(t, err, ErrInvalidExportOptions, opts)
+	}
+}
+
+func TestExportRecords_Canceled(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.Local)
+	uc, name := newExportTestCapture(t, start)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var rows exportRows
+	err := uc.ExportCapture(ctx, name, model.ExportOptions{Universe: 2}, &rows)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Empty(t, rows)
+}
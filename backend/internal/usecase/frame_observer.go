@@ -0,0 +1,115 @@
+package usecase
+
+import (
+	"sync"
+	"time"
+
+	"github.com/nasshu2916/dmx_viewer/internal/domain/model"
+)
+
+// FrameObserver 受信した送信元ごとのDMXフレームを受け取るインターフェース
+// Art-Net と sACN の両方から呼ばれるため、実装は並行に呼ばれても安全である必要がある
+type FrameObserver interface {
+	ObserveFrame(frame *model.DMXFrame)
+}
+
+// FrameObservers 複数の FrameObserver に順にフレームを渡す
+type FrameObservers []FrameObserver
+
+func (o FrameObservers) ObserveFrame(frame *model.DMXFrame) {
+	for _, observer := range o {
+		observer.ObserveFrame(frame)
+	}
+}
+
+// FrameMerger 記録したDMXフレームを、受信したフレームと同じくユニバースごとにマージするインターフェース
+// 無通信の送信元の判定は受信した時刻ではなく記録時の時刻（フレームの ReceivedAt と再生位置）で行う
+type FrameMerger interface {
+	// 記録時の受信時刻のフレームとしてマージする
+	MergeFrame(frame *model.DMXFrame) error
+	// 記録時の時刻 at の時点で無通信の送信元をマージ対象から外す
+	Sweep(at time.Time)
+	// すべての送信元をマージ対象から外す（再生位置を戻すときに呼ぶ）
+	Reset()
+}
+
+// ProtocolFrameMerger フレームのプロトコルに応じて Art-Net のマージ（artnet/dmx_merged）と
+// sACN の優先度によるマージ（sacn/dmx_merged）に振り分ける FrameMerger
+// マージしたデータは受信したフレームと同じく UniverseObserver にも渡る
+type ProtocolFrameMerger struct {
+	artNet *UniverseMerger
+	sacn   *SACNBridgeUseCaseImpl
+	clock  *recordedClock
+}
+
+// NewProtocolFrameMerger ProtocolFrameMergerの新しいインスタンスを作成
+// artNet と sacn の時計を記録時の時刻に置き換えるため、それぞれの StartSweeper は起動しない
+func NewProtocolFrameMerger(artNet *UniverseMerger, sacn *SACNBridgeUseCaseImpl) *ProtocolFrameMerger {
+	clock := &recordedClock{}
+	artNet.now = clock.Now
+	sacn.tracker.now = clock.Now
+	return &ProtocolFrameMerger{artNet: artNet, sacn: sacn, clock: clock}
+}
+
+func (m *ProtocolFrameMerger) MergeFrame(frame *model.DMXFrame) error {
+	m.clock.Set(frame.ReceivedAt)
+	if frame.Protocol == model.ProtocolSACN {
+		return m.sacn.MergeFrame(frame)
+	}
+	if frame.StartCode != model.StartCodeDMX {
+		return nil
+	}
+	return m.artNet.Push(frame)
+}
+
+func (m *ProtocolFrameMerger) Sweep(at time.Time) {
+	m.clock.Set(at)
+	m.artNet.Sweep()
+	m.sacn.Sweep()
+}
+
+func (m *ProtocolFrameMerger) Reset() {
+	m.artNet.Reset()
+	m.sacn.Reset()
+}
+
+// recordedClock 記録時の時刻を返す時計（ProtocolFrameMerger がマージするフレームと再生位置に合わせて進める）
+type recordedClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *recordedClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *recordedClock) Set(now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = now
+}
+
+// frameSourceKey 送信元を識別するキー（sACN はCID、Art-Net は送信元IP）
+func frameSourceKey(frame *model.DMXFrame) string {
+	if frame.Protocol == model.ProtocolSACN && frame.SourceCID != "" {
+		return frame.SourceCID
+	}
+	return frame.SourceIP.String()
+}
+
+// UniverseObserver マージしたユニバースの出力を受け取るインターフェース
+// Art-Net と sACN の両方から呼ばれるため、実装は並行に呼ばれても安全である必要がある
+type UniverseObserver interface {
+	ObserveUniverse(protocol string, universe uint16, data *[512]uint8)
+}
+
+// UniverseObservers 複数の UniverseObserver に順に出力を渡す
+type UniverseObservers []UniverseObserver
+
+func (o UniverseObservers) ObserveUniverse(protocol string, universe uint16, data *[512]uint8) {
+	for _, observer := range o {
+		observer.ObserveUniverse(protocol, universe, data)
+	}
+}
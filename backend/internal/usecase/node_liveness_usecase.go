@@ -0,0 +1,163 @@
+package usecase
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/nasshu2916/dmx_viewer/internal/domain/model"
+	"github.com/nasshu2916/dmx_viewer/internal/domain/repository"
+	"github.com/nasshu2916/dmx_viewer/pkg/logger"
+)
+
+// NodeSweepInterval ノードの状態を再評価する間隔
+const NodeSweepInterval = 1 * time.Second
+
+// NodeObserver ArtPollReplyで通知されたノードを受け取るインターフェース
+type NodeObserver interface {
+	ObserveNode(node *model.ArtNetNode) error
+}
+
+// NodeLivenessUseCase ノードの生存状態を管理するインターフェース
+type NodeLivenessUseCase interface {
+	NodeObserver
+	// 一定間隔でノードの状態を再評価し、期限切れのノードを削除する
+	StartSweeper(ctx context.Context)
+	// 現在のノード一覧を取得する
+	GetNodes() []*model.ArtNetNode
+	// ノードの状態遷移の履歴を取得する（履歴がない場合はfalse）
+	GetNodeHistory(key string) ([]model.NodeTransition, bool)
+}
+
+// NodeLivenessUseCaseImpl NodeLivenessUseCaseの実装
+type NodeLivenessUseCaseImpl struct {
+	mu        sync.Mutex // 受信と再評価による状態遷移を直列化する
+	nodeRepo  repository.ArtNetNodeRepository
+	wsUseCase WebSocketUseCase
+	policy    model.NodeLivenessPolicy
+	logger    *logger.Logger
+	now       func() time.Time
+}
+
+// NewNodeLivenessUseCaseImpl NodeLivenessUseCaseの新しいインスタンスを作成
+func NewNodeLivenessUseCaseImpl(nodeRepo repository.ArtNetNodeRepository, wsUseCase WebSocketUseCase, policy model.NodeLivenessPolicy, logger *logger.Logger) *NodeLivenessUseCaseImpl {
+	return &NodeLivenessUseCaseImpl{
+		nodeRepo:  nodeRepo,
+		wsUseCase: wsUseCase,
+		policy:    policy,
+		logger:    logger,
+		now:       time.Now,
+	}
+}
+
+// ObserveNode ArtPollReplyを受信したノードを online として保存し、ノード一覧を配信する
+func (u *NodeLivenessUseCaseImpl) ObserveNode(node *model.ArtNetNode) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	from := model.NodeLivenessUnknown
+	if prev, ok := u.nodeRepo.Get(node.Key()); ok {
+		from = prev.Liveness
+	}
+	node = node.WithLiveness(model.NodeOnline)
+	u.nodeRepo.Save(node)
+
+	if from != model.NodeOnline {
+		u.recordTransition(node, from, node.LastSeen)
+	}
+	return u.broadcastNodes()
+}
+
+func (u *NodeLivenessUseCaseImpl) StartSweeper(ctx context.Context) {
+	ticker := time.NewTicker(NodeSweepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := u.Sweep(); err != nil {
+				u.logger.Debug("Failed to broadcast node list", "error", err)
+			}
+		case <-ctx.Done():
+			u.logger.Info("Node liveness sweeper stopped.")
+			return
+		}
+	}
+}
+
+// Sweep すべてのノードの状態を再評価し、変化があればノード一覧を配信する
+func (u *NodeLivenessUseCaseImpl) Sweep() error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	now := u.now()
+	changed := false
+	for _, node := range u.nodeRepo.All() {
+		if u.policy.Expired(node.LastSeen, now) {
+			// 再評価の前に期限切れになった場合も offline への遷移を記録してから削除する
+			if node.Liveness != model.NodeOffline {
+				u.recordTransition(node.WithLiveness(model.NodeOffline), node.Liveness, now)
+			}
+			u.nodeRepo.Delete(node.Key())
+			u.logger.Info("Art-Net node expired", "node", node.Key(), "last_seen", node.LastSeen)
+			changed = true
+			continue
+		}
+
+		liveness := u.policy.Evaluate(node.LastSeen, now)
+		if liveness == node.Liveness {
+			continue
+		}
+		updated := node.WithLiveness(liveness)
+		u.nodeRepo.Save(updated)
+		u.recordTransition(updated, node.Liveness, now)
+		changed = true
+	}
+
+	if !changed {
+		return nil
+	}
+	return u.broadcastNodes()
+}
+
+func (u *NodeLivenessUseCaseImpl) GetNodes() []*model.ArtNetNode {
+	return u.nodeRepo.All()
+}
+
+func (u *NodeLivenessUseCaseImpl) GetNodeHistory(key string) ([]model.NodeTransition, bool) {
+	return u.nodeRepo.Transitions(key)
+}
+
+// recordTransition 状態遷移を履歴に追加し、online / offline への遷移をイベントとして配信する
+func (u *NodeLivenessUseCaseImpl) recordTransition(node *model.ArtNetNode, from model.NodeLiveness, at time.Time) {
+	transition := model.NodeTransition{
+		NodeKey:   node.Key(),
+		IPAddress: node.IPAddress,
+		BindIndex: node.BindIndex,
+		From:      from,
+		To:        node.Liveness,
+		At:        at,
+	}
+	u.nodeRepo.AddTransition(transition)
+	u.logger.Info("Art-Net node state changed", "node", transition.NodeKey, "from", from, "to", node.Liveness)
+
+	var eventType string
+	switch node.Liveness {
+	case model.NodeOnline:
+		eventType = "node_online"
+	case model.NodeOffline:
+		eventType = "node_offline"
+	default:
+		return
+	}
+	msg := model.NewWebSocketMessage(eventType, model.NodeEvent{Node: node, Transition: transition})
+	if err := u.wsUseCase.BroadcastToTopic("artnet/nodes", msg); err != nil {
+		u.logger.Debug("Failed to broadcast node event", "type", eventType, "error", err)
+	}
+}
+
+// broadcastNodes すべてのノード情報を配信する
+func (u *NodeLivenessUseCaseImpl) broadcastNodes() error {
+	msg := model.NewWebSocketMessage("artnet_nodes", u.nodeRepo.All())
+	return u.wsUseCase.BroadcastToTopic("artnet/nodes", msg)
+}
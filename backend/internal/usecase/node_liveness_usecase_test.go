@@ -0,0 +1,124 @@
+package usecase
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/nasshu2916/dmx_viewer/internal/domain/model"
+	"github.com/nasshu2916/dmx_viewer/internal/infrastructure"
+	"github.com/nasshu2916/dmx_viewer/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestNode(ip net.IP, bindIndex uint8, lastSeen time.Time) *model.ArtNetNode {
+	return &model.ArtNetNode{IPAddress: ip, BindIndex: bindIndex, LastSeen: lastSeen, Liveness: model.NodeOnline}
+}
+
+func messageTypes(messages []*model.WebSocketMessage) []string {
+	types := make([]string, 0, len(messages))
+	for _, msg := range messages {
+		types = append(types, msg.Type)
+	}
+	return types
+}
+
+func TestNodeLivenessUseCase_Transitions(t *testing.T) {
+	ws := newFakeWebSocketUseCase()
+	policy := model.NewNodeLivenessPolicy(time.Second, 10*time.Second)
+	u := NewNodeLivenessUseCaseImpl(infrastructure.NewArtNetNodeRepository(), ws, policy, logger.NewLogger("fatal"))
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := start
+	u.now = func() time.Time { return clock }
+
+	ip := net.IPv4(2, 0, 0, 20)
+	key := model.NodeKey(ip, 1)
+	require.NoError(t, u.ObserveNode(newTestNode(ip, 1, start)))
+	assert.Equal(t, []string{"node_online", "artnet_nodes"}, messageTypes(ws.Messages("artnet/nodes")))
+
+	// 応答が続いている間は状態遷移しない
+	require.NoError(t, u.ObserveNode(newTestNode(ip, 1, start)))
+	clock = start.Add(500 * time.Millisecond)
+	require.NoError(t, u.Sweep())
+
+	clock = start.Add(2 * time.Second)
+	require.NoError(t, u.Sweep())
+	nodes := u.GetNodes()
+	require.Len(t, nodes, 1)
+	assert.Equal(t, model.NodeStale, nodes[0].Liveness)
+
+	clock = start.Add(3 * time.Second)
+	require.NoError(t, u.Sweep())
+	assert.Equal(t, model.NodeOffline, u.GetNodes()[0].Liveness)
+
+	// 再び応答すれば online に戻る
+	clock = start.Add(4 * time.Second)
+	require.NoError(t, u.ObserveNode(newTestNode(ip, 1, clock)))
+	assert.Equal(t, model.NodeOnline, u.GetNodes()[0].Liveness)
+
+	// 期限切れのノードは一覧から削除されるが履歴は残る
+	clock = start.Add(20 * time.Second)
+	require.NoError(t, u.Sweep())
+	assert.Empty(t, u.GetNodes())
+
+	history, ok := u.GetNodeHistory(key)
+	require.True(t, ok)
+	var states []model.NodeLiveness
+	for _, transition := range history {
+		states = append(states, transition.To)
+	}
+	assert.Equal(t, []model.NodeLiveness{model.NodeOnline, model.NodeStale, model.NodeOffline, model.NodeOnline, model.NodeOffline}, states)
+	assert.Equal(t, model.NodeLivenessUnknown, history[0].From)
+
+	events := map[string]int{}
+	for _, msg := range ws.Messages("artnet/nodes") {
+		events[msg.Type]++
+	}
+	assert.Equal(t, 2, events["node_online"])
+	assert.Equal(t, 2, events["node_offline"])
+
+	_, ok = u.GetNodeHistory(model.NodeKey(net.IPv4(2, 0, 0, 99), 1))
+	assert.False(t, ok)
+}
+
+func TestNodeLivenessUseCase_EvictsHistoryOfExpiredNodes(t *testing.T) {
+	u := NewNodeLivenessUseCaseImpl(infrastructure.NewArtNetNodeRepository(), newFakeWebSocketUseCase(), model.NewNodeLivenessPolicy(time.Second, 10*time.Second), logger.NewLogger("fatal"))
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := start
+	u.now = func() time.Time { return clock }
+
+	expired := net.IPv4(2, 0, 0, 20)
+	require.NoError(t, u.ObserveNode(newTestNode(expired, 1, start)))
+	clock = start.Add(20 * time.Second)
+	require.NoError(t, u.Sweep())
+	_, ok := u.GetNodeHistory(model.NodeKey(expired, 1))
+	require.True(t, ok)
+
+	// 履歴を保持するノード数（1024）を超えると、一覧から削除したノードの履歴から削除される
+	for i := 0; i < 1024; i++ {
+		require.NoError(t, u.ObserveNode(newTestNode(net.IPv4(10, 0, byte(i>>8), byte(i)), 1, clock)))
+	}
+	_, ok = u.GetNodeHistory(model.NodeKey(expired, 1))
+	assert.False(t, ok)
+	_, ok = u.GetNodeHistory(model.NodeKey(net.IPv4(10, 0, 0, 0), 1))
+	assert.True(t, ok)
+}
+
+func TestNodeLivenessUseCase_BindIndexesAreSeparateNodes(t *testing.T) {
+	ws := newFakeWebSocketUseCase()
+	u := NewNodeLivenessUseCaseImpl(infrastructure.NewArtNetNodeRepository(), ws, model.NewNodeLivenessPolicy(time.Second, 0), logger.NewLogger("fatal"))
+
+	ip := net.IPv4(2, 0, 0, 20)
+	now := time.Now()
+	require.NoError(t, u.ObserveNode(newTestNode(ip, 2, now)))
+	require.NoError(t, u.ObserveNode(newTestNode(ip, 1, now)))
+	require.NoError(t, u.ObserveNode(newTestNode(net.IPv4(2, 0, 0, 3), 0, now)))
+
+	nodes := u.GetNodes()
+	require.Len(t, nodes, 3)
+	assert.Equal(t, "2.0.0.3#1", nodes[0].Key())
+	assert.Equal(t, "2.0.0.20#1", nodes[1].Key())
+	assert.Equal(t, "2.0.0.20#2", nodes[2].Key())
+}
@@ -0,0 +1,64 @@
+package usecase
+
+import (
+	"github.com/nasshu2916/dmx_viewer/internal/domain/model"
+	"github.com/nasshu2916/dmx_viewer/internal/domain/repository"
+	"github.com/nasshu2916/dmx_viewer/pkg/logger"
+)
+
+// PollReplyAnnouncer ノード設定の変更をArtPollReplyで通知するためのインターフェース
+type PollReplyAnnouncer interface {
+	AnnouncePollReply() error
+}
+
+// NodeSettingsUseCase このビューアのArt-Netノード設定を参照・変更するインターフェース
+type NodeSettingsUseCase interface {
+	// 現在のノード設定を取得する
+	GetNodeSettings() model.NodeSettings
+	// モニターするユニバースを変更し、新しいArtPollReplyを送信する
+	SetMonitoredUniverses(universes []uint16) (model.NodeSettings, error)
+}
+
+// NodeSettingsUseCaseImpl NodeSettingsUseCaseの実装
+type NodeSettingsUseCaseImpl struct {
+	settingsRepo repository.NodeSettingsRepository
+	announcer    PollReplyAnnouncer
+	wsUseCase    WebSocketUseCase
+	logger       *logger.Logger
+}
+
+// NewNodeSettingsUseCaseImpl NodeSettingsUseCaseの新しいインスタンスを作成
+func NewNodeSettingsUseCaseImpl(settingsRepo repository.NodeSettingsRepository, announcer PollReplyAnnouncer, wsUseCase WebSocketUseCase, logger *logger.Logger) *NodeSettingsUseCaseImpl {
+	return &NodeSettingsUseCaseImpl{
+		settingsRepo: settingsRepo,
+		announcer:    announcer,
+		wsUseCase:    wsUseCase,
+		logger:       logger,
+	}
+}
+
+func (u *NodeSettingsUseCaseImpl) GetNodeSettings() model.NodeSettings {
+	return u.settingsRepo.Get()
+}
+
+func (u *NodeSettingsUseCaseImpl) SetMonitoredUniverses(universes []uint16) (model.NodeSettings, error) {
+	var updateErr error
+	settings, err := u.settingsRepo.Update(func(settings *model.NodeSettings) error {
+		updateErr = settings.SetMonitoredUniverses(universes)
+		return updateErr
+	})
+	if updateErr != nil {
+		return settings, updateErr
+	}
+	// 保存に失敗してもメモリ上の設定は更新されているので処理を続ける
+	if err != nil {
+		u.logger.Error("Failed to persist node settings", "error", err)
+	}
+	u.logger.Info("Monitored universes updated", "universes", settings.MonitoredUniverses(), "pages", len(settings.Pages))
+
+	msg := model.NewWebSocketMessage("artnet_node_settings", settings)
+	if err := u.wsUseCase.BroadcastToTopic("artnet/node_settings", msg); err != nil {
+		return settings, err
+	}
+	return settings, u.announcer.AnnouncePollReply()
+}
@@ -0,0 +1,242 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/jsimonetti/go-artnet/packet"
+	"github.com/nasshu2916/dmx_viewer/internal/domain/model"
+	"github.com/nasshu2916/dmx_viewer/internal/domain/repository"
+	"github.com/nasshu2916/dmx_viewer/internal/infrastructure/artnet"
+	"github.com/nasshu2916/dmx_viewer/internal/infrastructure/sacn"
+	"github.com/nasshu2916/dmx_viewer/pkg/logger"
+)
+
+var ErrNoDMXFrames = errors.New("packet capture contains no DMX frames")
+
+// DatagramSource パケットキャプチャからUDPデータグラムを順に読み込む（pcap.Reader）
+type DatagramSource interface {
+	// 次のデータグラムを読み込む（終端では io.EOF を返す）
+	Next() (*model.CapturedDatagram, error)
+	// UDP以外などのため読み飛ばしたパケット数
+	Skipped() int
+}
+
+// ReceivedDataHandler 受信したUDPデータを処理するインターフェース（SACNBridgeUseCase）
+type ReceivedDataHandler interface {
+	HandlePacket(received model.ReceivedData) error
+}
+
+// PcapImportUseCaseImpl Wireshark / tcpdump のパケットキャプチャを読み込み、
+// キャプチャファイルに変換する、または受信時と同じ間隔でパケットの処理に渡す
+type PcapImportUseCaseImpl struct {
+	repo          repository.CaptureRepository // 変換先（再生のみの場合は nil）
+	artNetHandler ArtNetPacketHandler          // 再生する Art-Net パケットの処理（nilの場合は再生しない）
+	sacnHandler   ReceivedDataHandler          // 再生する sACN パケットの処理（nilの場合は再生しない）
+	logger        *logger.Logger
+	now           func() time.Time
+}
+
+// NewPcapImportUseCaseImpl PcapImportUseCaseImplの新しいインスタンスを作成
+func NewPcapImportUseCaseImpl(repo repository.CaptureRepository, artNetHandler ArtNetPacketHandler, sacnHandler ReceivedDataHandler, logger *logger.Logger) *PcapImportUseCaseImpl {
+	return &PcapImportUseCaseImpl{
+		repo:          repo,
+		artNetHandler: artNetHandler,
+		sacnHandler:   sacnHandler,
+		logger:        logger,
+		now:           time.Now,
+	}
+}
+
+// Convert Art-Net の ArtDMX と sACN のDMXデータをキャプチャファイルに変換する
+// フレームの時刻はキャプチャした時刻とする
+func (uc *PcapImportUseCaseImpl) Convert(source DatagramSource) (model.PcapImportResult, error) {
+	var result model.PcapImportResult
+	if uc.repo == nil {
+		return result, fmt.Errorf("capture repository is not configured")
+	}
+
+	opened := false
+	defer func() {
+		if opened {
+			if err := uc.repo.Close(); err != nil {
+				uc.logger.Error("Failed to close converted capture file", "error", err)
+			}
+		}
+	}()
+
+	err := uc.each(source, &result, func(datagram *model.CapturedDatagram) error {
+		frame, err := uc.decodeFrame(datagram, &result)
+		if err != nil || frame == nil {
+			return err
+		}
+		if !opened {
+			if err := uc.repo.Open(datagram.Timestamp); err != nil {
+				return err
+			}
+			opened = true
+		}
+		if err := uc.repo.Append(frame); err != nil {
+			return err
+		}
+		result.Frames++
+		return nil
+	})
+	if opened {
+		result.Files = uc.repo.Files()
+	}
+	if err != nil {
+		return result, err
+	}
+	if result.Frames == 0 {
+		return result, ErrNoDMXFrames
+	}
+	return result, nil
+}
+
+// Replay キャプチャした時刻の間隔を再現してパケットの処理に渡す
+// 受信時刻は処理に渡した時刻とする。ctx がキャンセルされた場合は途中で終了する
+func (uc *PcapImportUseCaseImpl) Replay(ctx context.Context, source DatagramSource) (model.PcapImportResult, error) {
+	var result model.PcapImportResult
+	var first time.Time
+	var startedAt time.Time
+
+	err := uc.each(source, &result, func(datagram *model.CapturedDatagram) error {
+		if first.IsZero() {
+			first, startedAt = datagram.Timestamp, uc.now()
+		}
+		if err := sleepUntil(ctx, startedAt.Add(datagram.Timestamp.Sub(first)), uc.now); err != nil {
+			return err
+		}
+
+		received := model.ReceivedData{Data: datagram.Payload, Addr: datagram.Src, ReceivedAt: uc.now()}
+		var err error
+		if isArtNetDatagram(datagram) {
+			result.ArtNet++
+			if uc.artNetHandler == nil {
+				return nil
+			}
+			err = uc.replayArtNet(received)
+		} else {
+			result.SACN++
+			if uc.sacnHandler == nil {
+				return nil
+			}
+			err = uc.sacnHandler.HandlePacket(received)
+		}
+		if err != nil {
+			result.Skipped++
+			uc.logger.Debug("Failed to handle replayed packet", "error", err, "addr", datagram.Src)
+			return nil
+		}
+		result.Frames++
+		return nil
+	})
+	return result, err
+}
+
+func (uc *PcapImportUseCaseImpl) replayArtNet(received model.ReceivedData) error {
+	artPacket, err := packet.Unmarshal(received.Data)
+	if err != nil {
+		return err
+	}
+	return uc.artNetHandler.HandlePacket(model.ReceivedArtPacket{
+		Packet:     artPacket,
+		Addr:       received.Addr,
+		ReceivedAt: received.ReceivedAt,
+	})
+}
+
+// each Art-Net / sACN のポートのデータグラムを順に処理する
+func (uc *PcapImportUseCaseImpl) each(source DatagramSource, result *model.PcapImportResult, handle func(*model.CapturedDatagram) error) error {
+	defer func() {
+		result.Skipped += source.Skipped()
+	}()
+	for {
+		datagram, err := source.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if !isArtNetDatagram(datagram) && !isSACNDatagram(datagram) {
+			result.Skipped++
+			continue
+		}
+		result.Datagrams++
+		if err := handle(datagram); err != nil {
+			return err
+		}
+	}
+}
+
+// decodeFrame データグラムからDMXフレームを取り出す（DMXデータ以外の場合は nil）
+func (uc *PcapImportUseCaseImpl) decodeFrame(datagram *model.CapturedDatagram, result *model.PcapImportResult) (*model.DMXFrame, error) {
+	if isArtNetDatagram(datagram) {
+		result.ArtNet++
+		p, err := packet.Unmarshal(datagram.Payload)
+		if err != nil {
+			result.Skipped++
+			uc.logger.Debug("Failed to unmarshal captured ArtNet packet", "error", err)
+			return nil, nil
+		}
+		dmx, ok := p.(*packet.ArtDMXPacket)
+		if !ok {
+			return nil, nil
+		}
+		frame, err := artnet.NewDMXFrame(datagram.Src, dmx, datagram.Timestamp)
+		if err != nil {
+			result.Skipped++
+			return nil, nil
+		}
+		return frame, nil
+	}
+
+	result.SACN++
+	p, err := sacn.Unmarshal(datagram.Payload)
+	if err != nil {
+		if !errors.Is(err, sacn.ErrUnsupportedVector) {
+			result.Skipped++
+			uc.logger.Debug("Failed to unmarshal captured sACN packet", "error", err)
+		}
+		return nil, nil
+	}
+	data, ok := p.(*sacn.DataPacket)
+	if !ok || data.StartCode != sacn.StartCodeDMX {
+		return nil, nil
+	}
+	frame, err := sacn.NewDMXFrame(datagram.Src, data, datagram.Timestamp)
+	if err != nil {
+		result.Skipped++
+		return nil, nil
+	}
+	return frame, nil
+}
+
+func isArtNetDatagram(datagram *model.CapturedDatagram) bool {
+	return datagram.Dst.Port == artnet.DefaultPort || datagram.Src.Port == artnet.DefaultPort
+}
+
+func isSACNDatagram(datagram *model.CapturedDatagram) bool {
+	return datagram.Dst.Port == sacn.DefaultPort || datagram.Src.Port == sacn.DefaultPort
+}
+
+// sleepUntil 指定した時刻まで待つ
+func sleepUntil(ctx context.Context, at time.Time, now func() time.Time) error {
+	wait := at.Sub(now())
+	if wait <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
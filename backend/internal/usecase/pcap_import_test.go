@@ -0,0 +1,164 @@
+package usecase
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/jsimonetti/go-artnet/packet"
+	"github.com/nasshu2916/dmx_viewer/internal/config"
+	"github.com/nasshu2916/dmx_viewer/internal/domain/model"
+	"github.com/nasshu2916/dmx_viewer/internal/infrastructure"
+	"github.com/nasshu2916/dmx_viewer/internal/infrastructure/sacn"
+	"github.com/nasshu2916/dmx_viewer/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeDatagramSource 用意したデータグラムを順に返すDatagramSource
+type fakeDatagramSource struct {
+	datagrams []*model.CapturedDatagram
+	skipped   int
+}
+
+func (f *fakeDatagramSource) Next() (*model.CapturedDatagram, error) {
+	if len(f.datagrams) == 0 {
+		return nil, io.EOF
+	}
+	d := f.datagrams[0]
+	f.datagrams = f.datagrams[1:]
+	return d, nil
+}
+
+func (f *fakeDatagramSource) Skipped() int {
+	return f.skipped
+}
+
+func capturedDatagram(t *testing.T, ts time.Time, srcPort, dstPort int, p interface{ MarshalBinary() ([]byte, error) }) *model.CapturedDatagram {
+	t.Helper()
+	b, err := p.MarshalBinary()
+	require.NoError(t, err)
+	return &model.CapturedDatagram{
+		Timestamp: ts,
+		Src:       &net.UDPAddr{IP: net.IPv4(2, 0, 0, 10), Port: srcPort},
+		Dst:       &net.UDPAddr{IP: net.IPv4(2, 255, 255, 255), Port: dstPort},
+		Payload:   b,
+	}
+}
+
+func newPcapTestSource(t *testing.T, start time.Time) *fakeDatagramSource {
+	artDMX := &packet.ArtDMXPacket{Sequence: 1, SubUni: 1, Length: 4}
+	artDMX.Data[0], artDMX.Data[1] = 10, 20
+	sacnDMX := &sacn.DataPacket{CID: [16]byte{1}, SourceName: "console", Priority: 100, Universe: 2, Sequence: 1, StartCode: sacn.StartCodeDMX, Data: []byte{30, 40, 50}}
+	sacnPriority := &sacn.DataPacket{CID: [16]byte{1}, Priority: 100, Universe: 2, Sequence: 2, StartCode: sacn.StartCodePerAddressPriority, Data: []byte{100, 100, 100}}
+
+	other := capturedDatagram(t, start, 5000, 5001, artDMX)
+	garbage := capturedDatagram(t, start.Add(5*time.Millisecond), 6454, 6454, artDMX)
+	garbage.Payload = []byte("not art-net")
+
+	return &fakeDatagramSource{
+		skipped: 3, // UDP以外のパケット
+		datagrams: []*model.CapturedDatagram{
+			capturedDatagram(t, start, 6454, 6454, artDMX),
+			other, // 対象外のポート
+			garbage,
+			capturedDatagram(t, start.Add(10*time.Millisecond), 6454, 6454, packet.NewArtPollPacket()),
+			capturedDatagram(t, start.Add(20*time.Millisecond), 50000, sacn.DefaultPort, sacnDMX),
+			capturedDatagram(t, start.Add(30*time.Millisecond), 50000, sacn.DefaultPort, sacnPriority),
+		},
+	}
+}
+
+func TestPcapImportUseCase_Convert(t *testing.T) {
+	repo := infrastructure.NewCaptureRepository(t.TempDir(), 0, 0)
+	uc := NewPcapImportUseCaseImpl(repo, nil, nil, logger.NewLogger("fatal"))
+	start := time.Date(2024, 5, 1, 19, 30, 0, 0, time.UTC)
+
+	result, err := uc.Convert(newPcapTestSource(t, start))
+	require.NoError(t, err)
+	assert.Equal(t, 5, result.Datagrams)
+	assert.Equal(t, 3, result.ArtNet)
+	assert.Equal(t, 2, result.SACN)
+	assert.Equal(t, 2, result.Frames)
+	assert.Equal(t, 5, result.Skipped)
+	require.Len(t, result.Files, 1)
+	assert.True(t, start.Equal(result.Files[0].StartedAt))
+
+	file, records, err := repo.Load(result.Files[0].Name)
+	require.NoError(t, err)
+	assert.True(t, start.Equal(file.StartedAt))
+	require.Len(t, records, 2)
+
+	// キャプチャした時刻の間隔を保つ
+	assert.Equal(t, time.Duration(0), records[0].Offset)
+	assert.Equal(t, model.ProtocolArtNet, records[0].Frame.Protocol)
+	assert.Equal(t, uint16(1), records[0].Frame.Universe)
+	assert.Equal(t, uint8(20), records[0].Frame.Data[1])
+	assert.Equal(t, "2.0.0.10", records[0].Frame.SourceIP.String())
+
+	assert.Equal(t, 20*time.Millisecond, records[1].Offset)
+	assert.Equal(t, model.ProtocolSACN, records[1].Frame.Protocol)
+	assert.Equal(t, uint16(2), records[1].Frame.Universe)
+	assert.Equal(t, uint8(100), records[1].Frame.Priority)
+	assert.Equal(t, uint8(50), records[1].Frame.Data[2])
+}
+
+func TestPcapImportUseCase_ConvertWithoutFrames(t *testing.T) {
+	repo := infrastructure.NewCaptureRepository(t.TempDir(), 0, 0)
+	uc := NewPcapImportUseCaseImpl(repo, nil, nil, logger.NewLogger("fatal"))
+
+	source := &fakeDatagramSource{datagrams: []*model.CapturedDatagram{
+		capturedDatagram(t, time.Now(), 6454, 6454, packet.NewArtPollPacket()),
+	}}
+	result, err := uc.Convert(source)
+	assert.ErrorIs(t, err, ErrNoDMXFrames)
+	assert.Empty(t, result.Files)
+
+	files, err := repo.List()
+	require.NoError(t, err)
+	assert.Empty(t, files)
+}
+
+func TestPcapImportUseCase_Replay(t *testing.T) {
+	artNetHandler, artNetWS, _ := newTestPacketHandler(t, &config.ArtNet{}, nil)
+	sacnBridge, sacnWS, _ := newTestSACNBridge(&config.SACN{})
+	uc := NewPcapImportUseCaseImpl(nil, artNetHandler, sacnBridge, logger.NewLogger("fatal"))
+
+	startedAt := time.Now()
+	result, err := uc.Replay(context.Background(), newPcapTestSource(t, time.Date(2024, 5, 1, 19, 30, 0, 0, time.UTC)))
+	require.NoError(t, err)
+
+	// 最後のパケットまでキャプチャした時刻の間隔で待つ
+	assert.GreaterOrEqual(t, time.Since(startedAt), 30*time.Millisecond)
+	assert.Equal(t, 5, result.Datagrams)
+	assert.Equal(t, 4, result.Frames)
+	assert.Equal(t, 5, result.Skipped)
+
+	require.Len(t, artNetWS.Messages("artnet/dmx_packet"), 1)
+	frame := artNetWS.Messages("artnet/dmx_packet")[0].Data.(*model.DMXFrame)
+	assert.Equal(t, uint16(1), frame.Universe)
+	// 受信時刻は再生した時刻とする
+	assert.False(t, frame.ReceivedAt.Before(startedAt))
+
+	require.Len(t, sacnWS.Messages("artnet/dmx_packet"), 1)
+	assert.Equal(t, uint16(2), sacnWS.Messages("artnet/dmx_packet")[0].Data.(*model.DMXFrame).Universe)
+}
+
+func TestPcapImportUseCase_ReplayCancel(t *testing.T) {
+	uc := NewPcapImportUseCaseImpl(nil, nil, nil, logger.NewLogger("fatal"))
+	start := time.Now()
+	artDMX := &packet.ArtDMXPacket{Length: 2}
+	source := &fakeDatagramSource{datagrams: []*model.CapturedDatagram{
+		capturedDatagram(t, start, 6454, 6454, artDMX),
+		capturedDatagram(t, start.Add(time.Hour), 6454, 6454, artDMX),
+	}}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	result, err := uc.Replay(ctx, source)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, 2, result.Datagrams)
+	assert.Equal(t, 1, result.ArtNet)
+}
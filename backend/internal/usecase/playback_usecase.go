@@ -0,0 +1,601 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/nasshu2916/dmx_viewer/internal/domain/model"
+	"github.com/nasshu2916/dmx_viewer/internal/domain/repository"
+	"github.com/nasshu2916/dmx_viewer/internal/infrastructure/artnet"
+	"github.com/nasshu2916/dmx_viewer/pkg/logger"
+)
+
+// PlaybackStatusInterval 再生中に再生状態を配信する間隔
+const PlaybackStatusInterval = 500 * time.Millisecond
+
+// MaxPlaybackFileSize 再生できるキャプチャファイルの最大サイズ
+// 再生中はすべてのレコードをメモリに保持するため、これより大きいファイルは再生しない（記録時の既定の分割サイズは100MB）
+const MaxPlaybackFileSize int64 = 256 * 1024 * 1024
+
+// playbackLoopGap ループ再生で末尾から先頭に戻るときの間隔（おおよそ1フレーム分）
+const playbackLoopGap = 25 * time.Millisecond
+
+var (
+	ErrAlreadyPlaying         = errors.New("playback is already running")
+	ErrNotPlaying             = errors.New("playback is not running")
+	ErrCaptureNotFound        = errors.New("capture file not found")
+	ErrEmptyCapture           = errors.New("capture file has no frames")
+	ErrCaptureTooLarge        = errors.New("capture file is too large to play back")
+	ErrInvalidPlaybackOptions = errors.New("invalid playback options")
+)
+
+// PlaybackUseCase キャプチャファイルを再生するインターフェース
+type PlaybackUseCase interface {
+	// 再生できるキャプチャファイルの一覧を取得する
+	Files() ([]model.CaptureFile, error)
+	// 再生を開始する
+	Start(opts model.PlaybackOptions) (model.PlaybackStatus, error)
+	// 再生を停止する
+	Stop() (model.PlaybackStatus, error)
+	// 再生を一時停止する
+	Pause() (model.PlaybackStatus, error)
+	// 一時停止した再生を再開する
+	Resume() (model.PlaybackStatus, error)
+	// 再生位置を変更する
+	Seek(position time.Duration) (model.PlaybackStatus, error)
+	// 一時停止して前後のフレームに移動する（負の値で前のフレームに戻る）
+	Step(frames int) (model.PlaybackStatus, error)
+	// 再生速度を変更する
+	SetSpeed(speed float64) (model.PlaybackStatus, error)
+	// 再生の状態を取得する
+	Status() model.PlaybackStatus
+}
+
+// PlaybackUseCaseImpl PlaybackUseCaseの実装
+//
+// 記録時の経過時間に合わせてフレームを ArtDMX で送信する（sACN で記録したフレームも ArtDMX で送信する）。
+// DryRun の場合はネットワークに送信せず、受信したフレームと同じ artnet/dmx_packet トピックにのみ配信する。
+// 記録したフレームは送信元ごとのものをそのまま出力するため、同じユニバースに複数の送信元がある場合はマージしない。
+type PlaybackUseCaseImpl struct {
+	mu           sync.Mutex
+	repo         repository.CaptureRepository
+	artNetWriter ArtNetWriter
+	netProvider  NetworkInterfaceProvider
+	wsUseCase    WebSocketUseCase
+	observer     FrameObserver // WebSocketにのみ配信するフレームを受け取る（nil の場合は渡さない）
+	merger       FrameMerger   // WebSocketにのみ配信するフレームをマージする（nil の場合はマージしない）
+	logger       *logger.Logger
+	now          func() time.Time
+	wake         chan struct{}
+	maxFileSize  int64 // 再生できるファイルの最大サイズ（MaxPlaybackFileSize）
+
+	state        model.PlaybackState
+	opts         model.PlaybackOptions
+	records      []*model.CaptureRecord
+	index        int                    // 次に出力するレコード
+	pending      []*model.CaptureRecord // シーク後に直前の状態を再現するため先に出力するレコード
+	resetMerger  bool                   // pending を出力する前にマージの送信元を削除する（再生位置を戻した場合）
+	anchorPos    time.Duration          // anchorAt の時点の再生位置
+	anchorAt     time.Time
+	sequences    map[uint16]uint8 // 出力ユニバースごとのシーケンス番号
+	framesSent   uint64
+	loops        uint64
+	lastError    string
+	lastStatusAt time.Time
+	review       bool // オフラインレビューモード
+}
+
+// NewPlaybackUseCaseImpl PlaybackUseCaseの新しいインスタンスを作成
+func NewPlaybackUseCaseImpl(repo repository.CaptureRepository, artNetWriter ArtNetWriter, netProvider NetworkInterfaceProvider, wsUseCase WebSocketUseCase, logger *logger.Logger) *PlaybackUseCaseImpl {
+	return &PlaybackUseCaseImpl{
+		repo:         repo,
+		artNetWriter: artNetWriter,
+		netProvider:  netProvider,
+		wsUseCase:    wsUseCase,
+		logger:       logger,
+		now:          time.Now,
+		wake:         make(chan struct{}, 1),
+		maxFileSize:  MaxPlaybackFileSize,
+		state:        model.PlaybackStateStopped,
+	}
+}
+
+// SetFrameObserver WebSocketにのみ配信する（ネットワークに送信しない）フレームを受け取る FrameObserver を設定する（再生を開始する前に呼ぶ）
+func (uc *PlaybackUseCaseImpl) SetFrameObserver(observer FrameObserver) {
+	uc.observer = observer
+}
+
+// SetFrameMerger WebSocketにのみ配信するフレームを、受信したフレームと同じくユニバースごとにマージする FrameMerger を設定する（再生を開始する前に呼ぶ）
+// 無通信の送信元は再生位置に対応する記録時の時刻で外し、シーク・前のフレームへの移動ではマージの状態を作り直す
+func (uc *PlaybackUseCaseImpl) SetFrameMerger(merger FrameMerger) {
+	uc.merger = merger
+}
+
+// EnableReviewMode オフラインレビューモードにする（再生を開始する前に呼ぶ）
+//
+// ネットワークに送信せず、記録したフレームを送信元・受信時刻を含めて記録時のまま配信する。
+// 末尾に達した場合は停止せず、最後のフレームで一時停止する。
+func (uc *PlaybackUseCaseImpl) EnableReviewMode() {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	uc.review = true
+}
+
+func (uc *PlaybackUseCaseImpl) Files() ([]model.CaptureFile, error) {
+	return uc.repo.List()
+}
+
+func (uc *PlaybackUseCaseImpl) Start(opts model.PlaybackOptions) (model.PlaybackStatus, error) {
+	if err := opts.Validate(); err != nil {
+		return uc.Status(), fmt.Errorf("%w: %v", ErrInvalidPlaybackOptions, err)
+	}
+	if uc.Status().State != model.PlaybackStateStopped {
+		return uc.Status(), ErrAlreadyPlaying
+	}
+
+	file, err := uc.repo.Get(opts.File)
+	if errors.Is(err, fs.ErrNotExist) {
+		return uc.Status(), fmt.Errorf("%w: %s", ErrCaptureNotFound, opts.File)
+	}
+	if err != nil {
+		return uc.Status(), err
+	}
+	if file.Size > uc.maxFileSize {
+		return uc.Status(), fmt.Errorf("%w: %s is %d bytes (maximum %d bytes)", ErrCaptureTooLarge, opts.File, file.Size, uc.maxFileSize)
+	}
+	file, records, err := uc.repo.Load(opts.File)
+	if errors.Is(err, fs.ErrNotExist) {
+		return uc.Status(), fmt.Errorf("%w: %s", ErrCaptureNotFound, opts.File)
+	}
+	if err != nil {
+		return uc.Status(), err
+	}
+	if len(records) == 0 {
+		return uc.Status(), fmt.Errorf("%w: %s", ErrEmptyCapture, opts.File)
+	}
+
+	uc.mu.Lock()
+	if uc.state != model.PlaybackStateStopped {
+		status := uc.statusLocked(uc.now())
+		uc.mu.Unlock()
+		return status, ErrAlreadyPlaying
+	}
+	uc.state = model.PlaybackStatePlaying
+	uc.opts = opts
+	uc.opts.File = file.Name
+	uc.opts.DryRun = opts.DryRun || uc.review
+	uc.records = records
+	uc.sequences = make(map[uint16]uint8)
+	uc.framesSent, uc.loops = 0, 0
+	uc.lastError = ""
+	uc.seekLocked(opts.Start, uc.now())
+	status := uc.statusLocked(uc.now())
+	uc.mu.Unlock()
+
+	uc.logger.Info("Playback started", "file", file.Name, "frames", len(records), "speed", opts.Speed, "loop", opts.Loop, "dryRun", opts.DryRun)
+	uc.notify(status)
+	return status, nil
+}
+
+func (uc *PlaybackUseCaseImpl) Stop() (model.PlaybackStatus, error) {
+	uc.mu.Lock()
+	if uc.state == model.PlaybackStateStopped {
+		status := uc.statusLocked(uc.now())
+		uc.mu.Unlock()
+		return status, ErrNotPlaying
+	}
+	now := uc.now()
+	uc.anchorPos = uc.positionLocked(now)
+	uc.state = model.PlaybackStateStopped
+	uc.pending = nil
+	status := uc.statusLocked(now)
+	uc.mu.Unlock()
+
+	uc.logger.Info("Playback stopped", "file", status.File, "framesSent", status.FramesSent)
+	uc.notify(status)
+	return status, nil
+}
+
+func (uc *PlaybackUseCaseImpl) Pause() (model.PlaybackStatus, error) {
+	uc.mu.Lock()
+	now := uc.now()
+	switch uc.state {
+	case model.PlaybackStateStopped:
+		status := uc.statusLocked(now)
+		uc.mu.Unlock()
+		return status, ErrNotPlaying
+	case model.PlaybackStatePlaying:
+		uc.anchorPos = uc.positionLocked(now)
+		uc.anchorAt = now
+		uc.state = model.PlaybackStatePaused
+	}
+	status := uc.statusLocked(now)
+	uc.mu.Unlock()
+
+	uc.notify(status)
+	return status, nil
+}
+
+func (uc *PlaybackUseCaseImpl) Resume() (model.PlaybackStatus, error) {
+	uc.mu.Lock()
+	now := uc.now()
+	switch uc.state {
+	case model.PlaybackStateStopped:
+		status := uc.statusLocked(now)
+		uc.mu.Unlock()
+		return status, ErrNotPlaying
+	case model.PlaybackStatePaused:
+		uc.anchorAt = now
+		uc.state = model.PlaybackStatePlaying
+	}
+	status := uc.statusLocked(now)
+	uc.mu.Unlock()
+
+	uc.notify(status)
+	return status, nil
+}
+
+func (uc *PlaybackUseCaseImpl) Seek(position time.Duration) (model.PlaybackStatus, error) {
+	uc.mu.Lock()
+	now := uc.now()
+	if uc.state == model.PlaybackStateStopped {
+		status := uc.statusLocked(now)
+		uc.mu.Unlock()
+		return status, ErrNotPlaying
+	}
+	uc.seekLocked(position, now)
+	status := uc.statusLocked(now)
+	uc.mu.Unlock()
+
+	uc.notify(status)
+	return status, nil
+}
+
+func (uc *PlaybackUseCaseImpl) Step(frames int) (model.PlaybackStatus, error) {
+	uc.mu.Lock()
+	now := uc.now()
+	if uc.state == model.PlaybackStateStopped {
+		status := uc.statusLocked(now)
+		uc.mu.Unlock()
+		return status, ErrNotPlaying
+	}
+	uc.stepLocked(frames, now)
+	status := uc.statusLocked(now)
+	uc.mu.Unlock()
+
+	uc.notify(status)
+	return status, nil
+}
+
+func (uc *PlaybackUseCaseImpl) SetSpeed(speed float64) (model.PlaybackStatus, error) {
+	if speed < model.MinPlaybackSpeed || speed > model.MaxPlaybackSpeed {
+		return uc.Status(), fmt.Errorf("%w: speed %g is out of range (%g-%g)", ErrInvalidPlaybackOptions, speed, model.MinPlaybackSpeed, model.MaxPlaybackSpeed)
+	}
+
+	uc.mu.Lock()
+	now := uc.now()
+	if uc.state == model.PlaybackStateStopped {
+		status := uc.statusLocked(now)
+		uc.mu.Unlock()
+		return status, ErrNotPlaying
+	}
+	// 変更した時点の再生位置から新しい速度で進める
+	uc.anchorPos = uc.positionLocked(now)
+	uc.anchorAt = now
+	uc.opts.Speed = speed
+	status := uc.statusLocked(now)
+	uc.mu.Unlock()
+
+	uc.notify(status)
+	return status, nil
+}
+
+func (uc *PlaybackUseCaseImpl) Status() model.PlaybackStatus {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	return uc.statusLocked(uc.now())
+}
+
+// StartPlayer 再生中のフレームを記録時の間隔で出力する
+func (uc *PlaybackUseCaseImpl) StartPlayer(ctx context.Context) {
+	timer := time.NewTimer(PlaybackStatusInterval)
+	defer timer.Stop()
+
+	for {
+		wait := uc.step()
+		if wait < 0 {
+			// 再生していない間は操作を待つ
+			select {
+			case <-uc.wake:
+				continue
+			case <-ctx.Done():
+				uc.logger.Info("Playback player stopped.")
+				return
+			}
+		}
+
+		timer.Reset(wait)
+		select {
+		case <-timer.C:
+		case <-uc.wake:
+		case <-ctx.Done():
+			uc.logger.Info("Playback player stopped.")
+			return
+		}
+	}
+}
+
+// step 再生位置までのフレームを出力し、次に出力するまでの待ち時間を返す（再生していない場合は負の値）
+func (uc *PlaybackUseCaseImpl) step() time.Duration {
+	uc.mu.Lock()
+	if uc.state == model.PlaybackStateStopped {
+		uc.mu.Unlock()
+		return -1
+	}
+	now := uc.now()
+	due := uc.pending
+	reset := uc.resetMerger
+	uc.pending = nil
+	uc.resetMerger = false
+
+	wait := time.Duration(-1)
+	finished := false
+	if uc.state == model.PlaybackStatePlaying {
+		position := uc.positionLocked(now)
+		for uc.index < len(uc.records) && uc.records[uc.index].Offset <= position {
+			due = append(due, uc.records[uc.index])
+			uc.index++
+		}
+		if uc.index >= len(uc.records) {
+			if uc.opts.Loop {
+				uc.index = 0
+				uc.anchorPos = 0
+				uc.anchorAt = now.Add(playbackLoopGap)
+				uc.loops++
+			} else {
+				uc.anchorPos = uc.durationLocked()
+				uc.anchorAt = now
+				uc.state = model.PlaybackStateStopped
+				if uc.review {
+					uc.state = model.PlaybackStatePaused
+				}
+				finished = true
+			}
+		}
+		if !finished {
+			next := uc.records[uc.index].Offset - uc.positionLocked(now)
+			wait = min(max(time.Duration(float64(next)/uc.opts.Speed), 0), PlaybackStatusInterval)
+		}
+	}
+	frames := uc.outputFramesLocked(due, now)
+	merger := uc.merger
+	uc.mu.Unlock()
+
+	if merger != nil && reset {
+		merger.Reset()
+	}
+	uc.output(frames)
+
+	uc.mu.Lock()
+	publish := finished || now.Sub(uc.lastStatusAt) >= PlaybackStatusInterval
+	status := uc.statusLocked(now)
+	recordedAt := uc.recordedTimeLocked(uc.positionLocked(now))
+	uc.mu.Unlock()
+	if merger != nil {
+		merger.Sweep(recordedAt)
+	}
+	if finished {
+		uc.logger.Info("Playback finished", "file", status.File, "framesSent", status.FramesSent)
+	}
+	if publish {
+		uc.broadcastStatus(status)
+	}
+	return wait
+}
+
+// seekLocked 再生位置を変更し、その位置までの最後のフレームを送信元・ユニバースごとに出力する
+func (uc *PlaybackUseCaseImpl) seekLocked(position time.Duration, now time.Time) {
+	position = min(max(position, 0), uc.durationLocked())
+	uc.index = sort.Search(len(uc.records), func(i int) bool { return uc.records[i].Offset > position })
+	uc.anchorPos = position
+	uc.anchorAt = now
+	uc.pending = latestRecords(uc.records[:uc.index])
+	uc.resetMerger = true
+}
+
+// stepLocked 一時停止した状態で前後のフレームに移動する
+// 先に進む場合は間のフレームを順に出力し、戻る場合は移動先までの状態を再現する
+func (uc *PlaybackUseCaseImpl) stepLocked(frames int, now time.Time) {
+	target := min(max(uc.index-1+frames, 0), len(uc.records)-1)
+	if target >= uc.index {
+		uc.pending = append(uc.pending, uc.records[uc.index:target+1]...)
+	} else {
+		uc.pending = latestRecords(uc.records[:target+1])
+		uc.resetMerger = true
+	}
+	uc.index = target + 1
+	uc.state = model.PlaybackStatePaused
+	uc.anchorPos = uc.records[target].Offset
+	uc.anchorAt = now
+}
+
+// latestRecords 送信元・ユニバースごとの最後のレコードを最初に現れた順に返す
+func latestRecords(records []*model.CaptureRecord) []*model.CaptureRecord {
+	latest := make(map[recorderKey]*model.CaptureRecord)
+	var keys []recorderKey
+	for _, record := range records {
+		key := recorderKey{protocol: record.Frame.Protocol, source: frameSourceKey(record.Frame), universe: record.Frame.Universe}
+		if _, ok := latest[key]; !ok {
+			keys = append(keys, key)
+		}
+		latest[key] = record
+	}
+	result := make([]*model.CaptureRecord, 0, len(keys))
+	for _, key := range keys {
+		result = append(result, latest[key])
+	}
+	return result
+}
+
+// positionLocked 現在の再生位置（ループ再生で先頭に戻る間は負の値になる）
+func (uc *PlaybackUseCaseImpl) positionLocked(now time.Time) time.Duration {
+	if uc.state != model.PlaybackStatePlaying {
+		return uc.anchorPos
+	}
+	return uc.anchorPos + time.Duration(float64(now.Sub(uc.anchorAt))*uc.opts.Speed)
+}
+
+// recordedTimeLocked 再生位置に対応する記録時の時刻
+func (uc *PlaybackUseCaseImpl) recordedTimeLocked(position time.Duration) time.Time {
+	first := uc.records[0]
+	return first.Frame.ReceivedAt.Add(position - first.Offset)
+}
+
+func (uc *PlaybackUseCaseImpl) durationLocked() time.Duration {
+	if len(uc.records) == 0 {
+		return 0
+	}
+	return uc.records[len(uc.records)-1].Offset
+}
+
+// outputFramesLocked 記録したフレームを出力するユニバースのフレームに変換する
+func (uc *PlaybackUseCaseImpl) outputFramesLocked(records []*model.CaptureRecord, now time.Time) []*model.DMXFrame {
+	frames := make([]*model.DMXFrame, 0, len(records))
+	for _, record := range records {
+		if record.Frame.StartCode != model.StartCodeDMX {
+			continue
+		}
+		universe := uc.opts.Remap.Apply(record.Frame.Universe)
+		if uc.review {
+			// レビューモードでは受信したときのフレームをそのまま配信する
+			frame := *record.Frame
+			frame.Universe = universe
+			frames = append(frames, &frame)
+			continue
+		}
+		if universe > model.MaxUniverse {
+			uc.logger.Debug("Skipping playback frame outside Art-Net universe range", "universe", universe)
+			continue
+		}
+		uc.sequences[universe] = nextArtNetSequence(uc.sequences[universe])
+		frames = append(frames, &model.DMXFrame{
+			Protocol:   model.ProtocolArtNet,
+			Universe:   universe,
+			StartCode:  model.StartCodeDMX,
+			Length:     record.Frame.Length,
+			Data:       record.Frame.Data,
+			Sequence:   uc.sequences[universe],
+			ReceivedAt: now,
+		})
+	}
+	return frames
+}
+
+// output フレームを ArtDMX で送信する（DryRun の場合は WebSocket にのみ配信する）
+func (uc *PlaybackUseCaseImpl) output(frames []*model.DMXFrame) {
+	if len(frames) == 0 {
+		return
+	}
+	uc.mu.Lock()
+	dryRun, review := uc.opts.DryRun, uc.review
+	uc.mu.Unlock()
+
+	var sent uint64
+	var lastErr error
+	for _, frame := range frames {
+		var err error
+		if dryRun {
+			err = uc.broadcastFrame(frame, !review)
+		} else {
+			err = uc.sendFrame(frame)
+		}
+		if err != nil {
+			lastErr = err
+			uc.logger.Debug("Failed to output playback frame", "universe", frame.Universe, "error", err)
+			continue
+		}
+		sent++
+	}
+
+	uc.mu.Lock()
+	uc.framesSent += sent
+	if lastErr != nil {
+		uc.lastError = lastErr.Error()
+	}
+	uc.mu.Unlock()
+}
+
+func (uc *PlaybackUseCaseImpl) sendFrame(frame *model.DMXFrame) error {
+	data, err := artnet.MarshalDMXFrame(frame)
+	if err != nil {
+		return err
+	}
+	return uc.artNetWriter.SendToWriteChan(data, artNetBroadcastAddr(uc.netProvider))
+}
+
+// broadcastFrame フレームを artnet/dmx_packet とユニバースごとのトピックに配信する（local の場合は自身が送信したフレームとして配信する）
+func (uc *PlaybackUseCaseImpl) broadcastFrame(frame *model.DMXFrame, local bool) error {
+	if iface := uc.netProvider.LocalInterface(); local && iface != nil {
+		frame.SourceIP = iface.IP
+		frame.SourcePort = artnet.DefaultPort
+	}
+	if uc.observer != nil {
+		uc.observer.ObserveFrame(frame)
+	}
+	if err := publishDMXFrame(uc.wsUseCase, frame); err != nil {
+		return err
+	}
+	if uc.merger != nil {
+		return uc.merger.MergeFrame(frame)
+	}
+	return nil
+}
+
+// notify 操作による状態の変化を配信し、再生処理に反映させる
+func (uc *PlaybackUseCaseImpl) notify(status model.PlaybackStatus) {
+	select {
+	case uc.wake <- struct{}{}:
+	default:
+	}
+	uc.broadcastStatus(status)
+}
+
+// broadcastStatus 再生状態を playback/status トピックに配信する
+func (uc *PlaybackUseCaseImpl) broadcastStatus(status model.PlaybackStatus) {
+	uc.mu.Lock()
+	uc.lastStatusAt = uc.now()
+	uc.mu.Unlock()
+
+	msg := model.NewWebSocketMessage("playback_status", status)
+	if err := uc.wsUseCase.BroadcastToTopic("playback/status", msg); err != nil {
+		uc.logger.Debug("Failed to broadcast playback status", "error", err)
+	}
+}
+
+func (uc *PlaybackUseCaseImpl) statusLocked(now time.Time) model.PlaybackStatus {
+	speed := uc.opts.Speed
+	if speed == 0 {
+		speed = 1
+	}
+	return model.PlaybackStatus{
+		State:      uc.state,
+		File:       uc.opts.File,
+		Review:     uc.review,
+		PositionMs: max(uc.positionLocked(now), 0).Milliseconds(),
+		DurationMs: uc.durationLocked().Milliseconds(),
+		Speed:      speed,
+		Loop:       uc.opts.Loop,
+		DryRun:     uc.opts.DryRun,
+		Remap:      uc.opts.Remap,
+		Frames:     len(uc.records),
+		FrameIndex: uc.index - 1,
+		FramesSent: uc.framesSent,
+		Loops:      uc.loops,
+		LastError:  uc.lastError,
+	}
+}
@@ -0,0 +1,424 @@
+package usecase
+
+import (
+	"context"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jsimonetti/go-artnet/packet"
+	"github.com/nasshu2916/dmx_viewer/internal/domain/model"
+	"github.com/nasshu2916/dmx_viewer/internal/infrastructure"
+	"github.com/nasshu2916/dmx_viewer/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeTestCapture 指定した経過時間のフレームを記録したキャプチャファイルを作成し、ファイル名を返す
+func writeTestCapture(t *testing.T, repo *infrastructure.CaptureRepositoryImpl, frames ...*model.DMXFrame) string {
+	t.Helper()
+	start := frames[0].ReceivedAt
+	require.NoError(t, repo.Open(start))
+	for _, frame := range frames {
+		require.NoError(t, repo.Append(frame))
+	}
+	require.NoError(t, repo.Close())
+	files := repo.Files()
+	require.Len(t, files, 1)
+	return files[0].Name
+}
+
+func newTestPlayback(t *testing.T, frames ...*model.DMXFrame) (*PlaybackUseCaseImpl, string, *fakeArtNetWriter, *fakeWebSocketUseCase, *time.Time) {
+	t.Helper()
+	repo := infrastructure.NewCaptureRepository(t.TempDir(), 0, 0)
+	name := writeTestCapture(t, repo, frames...)
+
+	writer := &fakeArtNetWriter{}
+	ws := newFakeWebSocketUseCase()
+	netProvider := &fakeNetworkInterfaceProvider{
+		iface: model.NewNetworkInterface("eth0", net.IPv4(2, 0, 0, 1), net.CIDRMask(8, 32), nil),
+	}
+	playback := NewPlaybackUseCaseImpl(repo, writer, netProvider, ws, logger.NewLogger("fatal"))
+	clock := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	playback.now = func() time.Time { return clock }
+	return playback, name, writer, ws, &clock
+}
+
+func newPlaybackTestFrames(universe uint16, offsets ...time.Duration) []*model.DMXFrame {
+	start := time.Date(2023, 6, 1, 20, 0, 0, 0, time.UTC)
+	frames := make([]*model.DMXFrame, 0, len(offsets))
+	for i, offset := range offsets {
+		frames = append(frames, newRecorderTestFrame(start, offset, uint8(i+1)))
+		frames[i].Universe = universe
+	}
+	return frames
+}
+
+func sentArtDMX(t *testing.T, writer *fakeArtNetWriter) []*packet.ArtDMXPacket {
+	t.Helper()
+	writer.mu.Lock()
+	defer writer.mu.Unlock()
+	var dmx []*packet.ArtDMXPacket
+	for _, p := range writer.packets {
+		if d, ok := p.(*packet.ArtDMXPacket); ok {
+			dmx = append(dmx, d)
+		}
+	}
+	return dmx
+}
+
+func TestPlaybackUseCase_Timing(t *testing.T) {
+	frames := newPlaybackTestFrames(1, 0, 100*time.Millisecond, 200*time.Millisecond)
+	playback, name, writer, ws, clock := newTestPlayback(t, frames...)
+
+	status, err := playback.Start(model.PlaybackOptions{File: name, Speed: 2})
+	require.NoError(t, err)
+	assert.Equal(t, model.PlaybackStatePlaying, status.State)
+	assert.Equal(t, int64(200), status.DurationMs)
+	assert.Equal(t, 3, status.Frames)
+
+	// 2倍速のため記録時の半分の間隔で出力する
+	assert.Equal(t, 50*time.Millisecond, playback.step())
+	require.Len(t, sentArtDMX(t, writer), 1)
+	*clock = clock.Add(49 * time.Millisecond)
+	playback.step()
+	require.Len(t, sentArtDMX(t, writer), 1)
+	*clock = clock.Add(time.Millisecond)
+	playback.step()
+	require.Len(t, sentArtDMX(t, writer), 2)
+
+	*clock = clock.Add(50 * time.Millisecond)
+	assert.Less(t, playback.step(), time.Duration(0))
+	packets := sentArtDMX(t, writer)
+	require.Len(t, packets, 3)
+	for i, p := range packets {
+		assert.Equal(t, uint8(1), p.SubUni)
+		assert.Equal(t, uint8(i+1), p.Sequence)
+		assert.Equal(t, uint8(i+1), p.Data[0])
+	}
+	writer.mu.Lock()
+	assert.Equal(t, "2.255.255.255:6454", writer.addrs[0].String())
+	writer.mu.Unlock()
+
+	// 末尾に達したら停止し、状態を配信する
+	status = playback.Status()
+	assert.Equal(t, model.PlaybackStateStopped, status.State)
+	assert.Equal(t, int64(200), status.PositionMs)
+	assert.Equal(t, uint64(3), status.FramesSent)
+	messages := ws.Messages("playback/status")
+	require.NotEmpty(t, messages)
+	last := messages[len(messages)-1]
+	assert.Equal(t, "playback_status", last.Type)
+	assert.Equal(t, model.PlaybackStateStopped, last.Data.(model.PlaybackStatus).State)
+}
+
+func TestPlaybackUseCase_DryRunRemap(t *testing.T) {
+	frames := newPlaybackTestFrames(1, 0)
+	playback, name, writer, ws, _ := newTestPlayback(t, frames...)
+
+	_, err := playback.Start(model.PlaybackOptions{File: name, Speed: 1, DryRun: true, Remap: model.UniverseRemap{1: 10}})
+	require.NoError(t, err)
+	playback.step()
+
+	// DryRun ではネットワークに送信せず WebSocket にのみ配信する
+	assert.Empty(t, sentArtDMX(t, writer))
+	messages := ws.Messages("artnet/dmx_packet")
+	require.Len(t, messages, 1)
+	frame := messages[0].Data.(*model.DMXFrame)
+	assert.Equal(t, uint16(10), frame.Universe)
+	assert.Equal(t, uint8(1), frame.Data[0])
+	assert.Equal(t, "2.0.0.1", frame.SourceIP.String())
+}
+
+func TestPlaybackUseCase_SeekAndLoop(t *testing.T) {
+	frames := newPlaybackTestFrames(1, 0, 100*time.Millisecond, 200*time.Millisecond)
+	playback, name, writer, _, clock := newTestPlayback(t, frames...)
+
+	_, err := playback.Start(model.PlaybackOptions{File: name, Speed: 1, Loop: true})
+	require.NoError(t, err)
+	_, err = playback.Pause()
+	require.NoError(t, err)
+
+	// シークした位置より前の最後のフレームを出力して状態を再現する
+	status, err := playback.Seek(150 * time.Millisecond)
+	require.NoError(t, err)
+	assert.Equal(t, int64(150), status.PositionMs)
+	playback.step()
+	packets := sentArtDMX(t, writer)
+	require.Len(t, packets, 1)
+	assert.Equal(t, uint8(2), packets[0].Data[0])
+
+	// 一時停止中は再生位置が進まない
+	*clock = clock.Add(time.Second)
+	assert.Equal(t, int64(150), playback.Status().PositionMs)
+
+	_, err = playback.Resume()
+	require.NoError(t, err)
+	*clock = clock.Add(50 * time.Millisecond)
+	playback.step()
+	require.Len(t, sentArtDMX(t, writer), 2)
+
+	// ループ再生では末尾の後に先頭から繰り返す
+	status = playback.Status()
+	assert.Equal(t, model.PlaybackStatePlaying, status.State)
+	assert.Equal(t, uint64(1), status.Loops)
+	*clock = clock.Add(playbackLoopGap)
+	playback.step()
+	packets = sentArtDMX(t, writer)
+	require.Len(t, packets, 3)
+	assert.Equal(t, uint8(1), packets[2].Data[0])
+
+	_, err = playback.Stop()
+	require.NoError(t, err)
+	assert.Less(t, playback.step(), time.Duration(0))
+	require.Len(t, sentArtDMX(t, writer), 3)
+}
+
+func TestPlaybackUseCase_SetSpeed(t *testing.T) {
+	frames := newPlaybackTestFrames(1, 0, time.Second)
+	playback, name, _, _, clock := newTestPlayback(t, frames...)
+
+	_, err := playback.Start(model.PlaybackOptions{File: name, Speed: 1})
+	require.NoError(t, err)
+	*clock = clock.Add(200 * time.Millisecond)
+	status, err := playback.SetSpeed(4)
+	require.NoError(t, err)
+	assert.Equal(t, 4.0, status.Speed)
+
+	// 変更した時点の位置から新しい速度で進む
+	*clock = clock.Add(100 * time.Millisecond)
+	assert.Equal(t, int64(600), playback.Status().PositionMs)
+
+	_, err = playback.SetSpeed(100)
+	assert.ErrorIs(t, err, ErrInvalidPlaybackOptions)
+}
+
+func TestPlaybackUseCase_Errors(t *testing.T) {
+	frames := newPlaybackTestFrames(1, 0, time.Second)
+	playback, name, _, _, _ := newTestPlayback(t, frames...)
+
+	_, err := playback.Pause()
+	assert.ErrorIs(t, err, ErrNotPlaying)
+	_, err = playback.Stop()
+	assert.ErrorIs(t, err, ErrNotPlaying)
+	_, err = playback.Seek(0)
+	assert.ErrorIs(t, err, ErrNotPlaying)
+
+	_, err = playback.Start(model.PlaybackOptions{File: "missing.dmxcap", Speed: 1})
+	assert.ErrorIs(t, err, ErrCaptureNotFound)
+	// 保存先のディレクトリの外のファイルは再生しない
+	_, err = playback.Start(model.PlaybackOptions{File: filepath.Join("..", name), Speed: 1})
+	assert.ErrorIs(t, err, ErrCaptureNotFound)
+	_, err = playback.Start(model.PlaybackOptions{File: name, Speed: 0})
+	assert.ErrorIs(t, err, ErrInvalidPlaybackOptions)
+	_, err = playback.Start(model.PlaybackOptions{File: name, Speed: 1, Remap: model.UniverseRemap{1: 40000}})
+	assert.ErrorIs(t, err, ErrInvalidPlaybackOptions)
+
+	// すべてのレコードを読み込むため、大きすぎるファイルは再生しない
+	playback.maxFileSize = 16
+	_, err = playback.Start(model.PlaybackOptions{File: name, Speed: 1})
+	assert.ErrorIs(t, err, ErrCaptureTooLarge)
+	assert.Equal(t, model.PlaybackStateStopped, playback.Status().State)
+	playback.maxFileSize = MaxPlaybackFileSize
+
+	_, err = playback.Start(model.PlaybackOptions{File: name, Speed: 1})
+	require.NoError(t, err)
+	_, err = playback.Start(model.PlaybackOptions{File: name, Speed: 1})
+	assert.ErrorIs(t, err, ErrAlreadyPlaying)
+
+	files, err := playback.Files()
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Equal(t, name, files[0].Name)
+}
+
+func TestPlaybackUseCase_StartPlayer(t *testing.T) {
+	frames := newPlaybackTestFrames(3, 0, 10*time.Millisecond, 20*time.Millisecond)
+	playback, name, writer, _, _ := newTestPlayback(t, frames...)
+	playback.now = time.Now
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go playback.StartPlayer(ctx)
+
+	_, err := playback.Start(model.PlaybackOptions{File: name, Speed: 1})
+	require.NoError(t, err)
+	assert.Eventually(t, func() bool {
+		return playback.Status().State == model.PlaybackStateStopped
+	}, time.Second, 5*time.Millisecond)
+	assert.Len(t, sentArtDMX(t, writer), 3)
+}
+
+func TestPlaybackUseCase_Step(t *testing.T) {
+	frames := newPlaybackTestFrames(1, 0, 100*time.Millisecond, 200*time.Millisecond, 300*time.Millisecond)
+	frames[1].Universe = 2
+	playback, name, writer, _, _ := newTestPlayback(t, frames...)
+
+	_, err := playback.Step(1)
+	assert.ErrorIs(t, err, ErrNotPlaying)
+
+	_, err = playback.Start(model.PlaybackOptions{File: name, Speed: 1})
+	require.NoError(t, err)
+	playback.step()
+	require.Len(t, sentArtDMX(t, writer), 1)
+
+	// 先に進む場合は間のフレームを順に出力して一時停止する
+	status, err := playback.Step(2)
+	require.NoError(t, err)
+	assert.Equal(t, model.PlaybackStatePaused, status.State)
+	assert.Equal(t, int64(200), status.PositionMs)
+	assert.Equal(t, 2, status.FrameIndex)
+	playback.step()
+	packets := sentArtDMX(t, writer)
+	require.Len(t, packets, 3)
+	assert.Equal(t, uint8(2), packets[1].SubUni)
+	assert.Equal(t, uint8(3), packets[2].Data[0])
+
+	// 戻る場合は移動先までの送信元・ユニバースごとの状態を再現する
+	status, err = playback.Step(-2)
+	require.NoError(t, err)
+	assert.Equal(t, 0, status.FrameIndex)
+	playback.step()
+	packets = sentArtDMX(t, writer)
+	require.Len(t, packets, 4)
+	assert.Equal(t, uint8(1), packets[3].Data[0])
+
+	// 範囲外には移動しない
+	status, err = playback.Step(-5)
+	require.NoError(t, err)
+	assert.Equal(t, 0, status.FrameIndex)
+	status, err = playback.Step(10)
+	require.NoError(t, err)
+	assert.Equal(t, 3, status.FrameIndex)
+	assert.Equal(t, int64(300), status.PositionMs)
+}
+
+func TestPlaybackUseCase_ReviewMode(t *testing.T) {
+	frames := newPlaybackTestFrames(1, 0, 100*time.Millisecond)
+	frames[1].Protocol = model.ProtocolSACN
+	frames[1].SourceCID = "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+	frames[1].Priority = 150
+	playback, name, writer, ws, clock := newTestPlayback(t, frames...)
+	playback.EnableReviewMode()
+
+	// レビューモードでは DryRun を指定しなくてもネットワークに送信しない
+	status, err := playback.Start(model.PlaybackOptions{File: name, Speed: 1})
+	require.NoError(t, err)
+	assert.True(t, status.Review)
+	assert.True(t, status.DryRun)
+	*clock = clock.Add(100 * time.Millisecond)
+	playback.step()
+	assert.Empty(t, sentArtDMX(t, writer))
+
+	// 記録したときの送信元・受信時刻のまま配信する
+	messages := ws.Messages("artnet/dmx_packet")
+	require.Len(t, messages, 2)
+	artNet := messages[0].Data.(*model.DMXFrame)
+	assert.Equal(t, "2.0.0.10", artNet.SourceIP.String())
+	assert.Equal(t, frames[0].ReceivedAt.UnixNano(), artNet.ReceivedAt.UnixNano())
+	sacnFrame := messages[1].Data.(*model.DMXFrame)
+	assert.Equal(t, model.ProtocolSACN, sacnFrame.Protocol)
+	assert.Equal(t, frames[1].SourceCID, sacnFrame.SourceCID)
+	assert.Equal(t, uint8(150), sacnFrame.Priority)
+
+	// 末尾に達した場合は停止せず一時停止する
+	status = playback.Status()
+	assert.Equal(t, model.PlaybackStatePaused, status.State)
+	assert.Equal(t, int64(100), status.PositionMs)
+	_, err = playback.Seek(0)
+	require.NoError(t, err)
+}
+
+func TestPlaybackUseCase_ReviewModeMergesFrames(t *testing.T) {
+	frames := newPlaybackTestFrames(1, 0, 100*time.Millisecond)
+	frames[1].Protocol = model.ProtocolSACN
+	frames[1].Universe = 2
+	frames[1].SourceCID = "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+	frames[1].Priority = 150
+	playback, name, _, ws, clock := newTestPlayback(t, frames...)
+
+	l := logger.NewLogger("fatal")
+	live := NewLiveUniverses()
+	merger := NewUniverseMerger(model.MergeModeHTP, ws, live, l)
+	sacnBridge := NewSACNBridgeUseCaseImpl(ws, NewSACNSourceTracker(l), nil, nil, live, nil, nil, l)
+	playback.SetFrameMerger(NewProtocolFrameMerger(merger, sacnBridge))
+	playback.EnableReviewMode()
+
+	_, err := playback.Start(model.PlaybackOptions{File: name, Speed: 1})
+	require.NoError(t, err)
+	*clock = clock.Add(100 * time.Millisecond)
+	playback.step()
+
+	// 受信したフレームと同じくマージして配信し、ライブの値にも反映する
+	artNetMerged := ws.Messages("artnet/dmx_merged")
+	require.Len(t, artNetMerged, 1)
+	assert.Equal(t, uint16(1), artNetMerged[0].Data.(*model.MergedFrame).Universe)
+	sacnMerged := ws.Messages("sacn/dmx_merged")
+	require.Len(t, sacnMerged, 1)
+	merged := sacnMerged[0].Data.(*model.SACNMergedFrame)
+	assert.Equal(t, uint16(2), merged.Universe)
+	assert.Equal(t, frames[1].Data, merged.Data)
+
+	universes := live.Universes()
+	require.Len(t, universes, 2)
+	for _, u := range universes {
+		if u.Protocol == model.ProtocolSACN {
+			assert.Equal(t, frames[1].Data, u.Data)
+		} else {
+			assert.Equal(t, frames[0].Data, u.Data)
+		}
+	}
+}
+
+func TestPlaybackUseCase_ReviewModeSweepsByPlaybackPosition(t *testing.T) {
+	frames := newPlaybackTestFrames(1, 0, 100*time.Millisecond, 20*time.Second)
+	frames[1].Protocol = model.ProtocolSACN
+	frames[1].Universe = 2
+	frames[1].SourceCID = "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+	frames[1].Priority = 100
+	frames[2].SourceIP = net.IPv4(2, 0, 0, 20)
+	playback, name, _, ws, clock := newTestPlayback(t, frames...)
+
+	l := logger.NewLogger("fatal")
+	live := NewLiveUniverses()
+	merger := NewUniverseMerger(model.MergeModeHTP, ws, live, l)
+	tracker := NewSACNSourceTracker(l)
+	sacnBridge := NewSACNBridgeUseCaseImpl(ws, tracker, nil, nil, live, nil, nil, l)
+	playback.SetFrameMerger(NewProtocolFrameMerger(merger, sacnBridge))
+	playback.EnableReviewMode()
+
+	_, err := playback.Start(model.PlaybackOptions{File: name, Speed: 1})
+	require.NoError(t, err)
+	*clock = clock.Add(100 * time.Millisecond)
+	playback.step()
+	_, err = playback.Pause()
+	require.NoError(t, err)
+
+	// 送信元のタイムアウトより長く一時停止しても、再生位置が進まない間は送信元を外さない
+	*clock = clock.Add(30 * time.Second)
+	merger.Sweep()
+	sacnBridge.Sweep()
+	playback.step()
+	require.Len(t, merger.GetMergeStates(), 1)
+	assert.Equal(t, []string{"2.0.0.10"}, merger.GetMergeStates()[0].Sources)
+	assert.Equal(t, 1, tracker.SourceCount(2))
+	assert.Len(t, ws.Messages("artnet/dmx_merged"), 1)
+	assert.Len(t, ws.Messages("sacn/dmx_merged"), 1)
+
+	// 記録時の時刻でタイムアウトした送信元は外す
+	_, err = playback.Seek(20 * time.Second)
+	require.NoError(t, err)
+	playback.step()
+	assert.Equal(t, []string{"2.0.0.20"}, merger.GetMergeStates()[0].Sources)
+	assert.Equal(t, 0, tracker.SourceCount(2))
+
+	// 前のフレームに戻ると、その時点でまだ送信していない送信元はマージしない
+	_, err = playback.Step(-1)
+	require.NoError(t, err)
+	playback.step()
+	assert.Equal(t, []string{"2.0.0.10"}, merger.GetMergeStates()[0].Sources)
+	assert.Equal(t, 1, tracker.SourceCount(2))
+	sacnMerged := ws.Messages("sacn/dmx_merged")
+	assert.Equal(t, frames[1].Data, sacnMerged[len(sacnMerged)-1].Data.(*model.SACNMergedFrame).Data)
+}
@@ -0,0 +1,358 @@
+package usecase
+
+import (
+	"context"
+	"net"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/nasshu2916/dmx_viewer/internal/domain/model"
+	"github.com/nasshu2916/dmx_viewer/internal/infrastructure/artnet"
+	"github.com/nasshu2916/dmx_viewer/internal/infrastructure/sacn"
+	"github.com/nasshu2916/dmx_viewer/pkg/logger"
+)
+
+// DefaultBridgeRefreshRate 入力がない間も出力を再送する既定の頻度（Hz）
+const DefaultBridgeRefreshRate = 44
+
+// bridgeTerminateCount 出力を停止するときに送信終了を通知するパケット数（E1.31 6.2.6）
+const bridgeTerminateCount = 3
+
+// SACNSender sACNのデータパケットを送信するためのインターフェース
+type SACNSender interface {
+	Send(p *sacn.DataPacket) error
+}
+
+// SACNOutputOptions ブリッジがsACNで出力するときの送信元の情報
+type SACNOutputOptions struct {
+	CID        [16]byte
+	SourceName string
+	Priority   uint8
+}
+
+// bridgeKey プロトコルとユニバースの組
+type bridgeKey struct {
+	protocol string
+	universe uint16
+}
+
+// bridgeInput 入力ユニバースを送信している送信元ごとの最新フレーム
+type bridgeInput struct {
+	frame    *model.DMXFrame
+	lastSeen time.Time
+}
+
+// bridgeRouteState 1経路分の転送状態
+type bridgeRouteState struct {
+	route            model.BridgeRoute
+	sources          map[string]*bridgeInput // Art-Net は送信元IP、sACN はCIDをキーとする
+	length           uint16
+	data             [512]uint8
+	sequence         uint8
+	active           bool
+	sentSinceRefresh bool // 前回の再送確認以降に入力により送信したか
+	stats            model.BridgeRouteStats
+}
+
+// bridgePacket 送信待ちの出力パケット
+type bridgePacket struct {
+	state     *bridgeRouteState
+	artNet    []byte
+	sacn      *sacn.DataPacket
+	keepAlive bool
+}
+
+// ProtocolBridge Art-Net と sACN の間でユニバースを転送する
+//
+// 入力ユニバースの送信元ごとのフレームをマージし（Art-Net は HTP、sACN は優先度）、
+// 出力ユニバースに変換してもう一方のプロトコルで送信する。入力が変化しない間も refreshRate で再送する。
+type ProtocolBridge struct {
+	mu              sync.Mutex
+	routes          map[bridgeKey]*bridgeRouteState // 入力側のプロトコルとユニバースをキーとする
+	outputs         map[bridgeKey]struct{}          // 出力側のプロトコルとユニバース
+	order           []*bridgeRouteState
+	artNetWriter    ArtNetWriter
+	netProvider     NetworkInterfaceProvider
+	sacnSender      SACNSender
+	sacnOutput      SACNOutputOptions
+	refreshInterval time.Duration
+	logger          *logger.Logger
+	now             func() time.Time
+}
+
+// NewProtocolBridge ProtocolBridgeの新しいインスタンスを作成（refreshRate が0以下の場合は既定値を使用する）
+func NewProtocolBridge(routes []model.BridgeRoute, artNetWriter ArtNetWriter, netProvider NetworkInterfaceProvider, sacnSender SACNSender, sacnOutput SACNOutputOptions, refreshRate int, logger *logger.Logger) *ProtocolBridge {
+	if refreshRate <= 0 {
+		refreshRate = DefaultBridgeRefreshRate
+	}
+	b := &ProtocolBridge{
+		routes:          make(map[bridgeKey]*bridgeRouteState),
+		outputs:         make(map[bridgeKey]struct{}),
+		artNetWriter:    artNetWriter,
+		netProvider:     netProvider,
+		sacnSender:      sacnSender,
+		sacnOutput:      sacnOutput,
+		refreshInterval: time.Second / time.Duration(refreshRate),
+		logger:          logger,
+		now:             time.Now,
+	}
+	for _, route := range routes {
+		state := &bridgeRouteState{
+			route:   route,
+			sources: make(map[string]*bridgeInput),
+			stats:   model.BridgeRouteStats{BridgeRoute: route},
+		}
+		b.routes[bridgeKey{route.Direction.InputProtocol(), route.Input}] = state
+		b.outputs[bridgeKey{route.Direction.OutputProtocol(), route.Output}] = struct{}{}
+		b.order = append(b.order, state)
+	}
+	return b
+}
+
+// ObserveFrame 入力ユニバースのフレームを受け取り、マージした結果を出力ユニバースに送信する
+func (b *ProtocolBridge) ObserveFrame(frame *model.DMXFrame) {
+	if frame.StartCode != model.StartCodeDMX {
+		return
+	}
+
+	b.mu.Lock()
+	state, ok := b.routes[bridgeKey{frame.Protocol, frame.Universe}]
+	if !ok || b.isOwnOutputLocked(frame) {
+		b.mu.Unlock()
+		return
+	}
+	now := b.now()
+	state.sources[frameSourceKey(frame)] = &bridgeInput{frame: frame, lastSeen: now}
+	state.stats.FramesIn++
+	b.dropStaleLocked(state, now)
+	b.mergeLocked(state)
+	if !state.active {
+		state.active = true
+		b.logger.Info("Protocol bridge route active", "direction", state.route.Direction, "input", state.route.Input, "output", state.route.Output)
+	}
+	pkt := b.outputPacketLocked(state, false)
+	b.mu.Unlock()
+
+	b.sendPackets([]bridgePacket{pkt})
+}
+
+// StartRefresh 一定間隔で入力がなかった経路の出力を再送し、入力が途絶えた経路の出力を停止する
+func (b *ProtocolBridge) StartRefresh(ctx context.Context) {
+	if len(b.order) == 0 {
+		return
+	}
+	ticker := time.NewTicker(b.refreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			b.Refresh()
+		case <-ctx.Done():
+			b.logger.Info("Protocol bridge refresh stopped.")
+			return
+		}
+	}
+}
+
+// Refresh 前回の確認以降に送信していない経路の出力を再送する
+// 入力の送信元がすべて途絶えた経路は出力を停止する（sACN の場合は送信終了を通知する）
+func (b *ProtocolBridge) Refresh() {
+	b.mu.Lock()
+	now := b.now()
+	var packets []bridgePacket
+	for _, state := range b.order {
+		if !state.active {
+			continue
+		}
+		if b.dropStaleLocked(state, now) {
+			if len(state.sources) == 0 {
+				state.active = false
+				packets = append(packets, b.terminatePacketsLocked(state)...)
+				b.logger.Info("Protocol bridge route inactive", "direction", state.route.Direction, "input", state.route.Input, "output", state.route.Output)
+				continue
+			}
+			b.mergeLocked(state)
+			packets = append(packets, b.outputPacketLocked(state, false))
+		} else if !state.sentSinceRefresh {
+			packets = append(packets, b.outputPacketLocked(state, true))
+		}
+		state.sentSinceRefresh = false
+	}
+	b.mu.Unlock()
+
+	b.sendPackets(packets)
+}
+
+// BridgeStats 経路ごとの転送状況を取得する
+func (b *ProtocolBridge) BridgeStats() []model.BridgeRouteStats {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	stats := make([]model.BridgeRouteStats, 0, len(b.order))
+	for _, state := range b.order {
+		s := state.stats
+		s.Sources = len(state.sources)
+		s.Active = state.active
+		stats = append(stats, s)
+	}
+	return stats
+}
+
+// isOwnOutputLocked 自身が出力したフレームかどうか（ループを防ぐため入力として扱わない）
+func (b *ProtocolBridge) isOwnOutputLocked(frame *model.DMXFrame) bool {
+	if _, ok := b.outputs[bridgeKey{frame.Protocol, frame.Universe}]; !ok {
+		return false
+	}
+	if frame.Protocol == model.ProtocolSACN {
+		return frame.SourceCID == sacn.FormatCID(b.sacnOutput.CID)
+	}
+	iface := b.netProvider.LocalInterface()
+	return iface != nil && frame.SourceIP.Equal(iface.IP)
+}
+
+// dropStaleLocked 一定時間受信しなかった送信元を削除し、削除したかどうかを返す
+func (b *ProtocolBridge) dropStaleLocked(state *bridgeRouteState, now time.Time) bool {
+	timeout := model.MergeSourceTimeout
+	if state.route.Direction.InputProtocol() == model.ProtocolSACN {
+		timeout = sacn.SourceLossTimeout
+	}
+	dropped := false
+	for key, input := range state.sources {
+		if now.Sub(input.lastSeen) >= timeout {
+			delete(state.sources, key)
+			dropped = true
+		}
+	}
+	return dropped
+}
+
+// mergeLocked 送信元ごとのフレームをマージして出力データを更新する
+func (b *ProtocolBridge) mergeLocked(state *bridgeRouteState) {
+	keys := make([]string, 0, len(state.sources))
+	for key := range state.sources {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	if state.route.Direction.InputProtocol() == model.ProtocolSACN {
+		inputs := make([]model.SACNMergeInput, 0, len(keys))
+		for _, key := range keys {
+			input := state.sources[key]
+			inputs = append(inputs, model.SACNMergeInput{CID: key, Frame: input.frame, LastSeen: input.lastSeen})
+		}
+		merged := model.MergeSACNFrames(state.route.Input, inputs)
+		state.length, state.data = merged.Length, merged.Data
+		return
+	}
+
+	inputs := make([]model.MergeInput, 0, len(keys))
+	for _, key := range keys {
+		input := state.sources[key]
+		inputs = append(inputs, model.MergeInput{SourceIP: key, Frame: input.frame, LastSeen: input.lastSeen})
+	}
+	merged := model.MergeFrames(state.route.Input, inputs, model.MergeModeHTP, "")
+	state.length, state.data = merged.Length, merged.Data
+}
+
+// outputPacketLocked 出力ユニバースのパケットを作成する
+func (b *ProtocolBridge) outputPacketLocked(state *bridgeRouteState, keepAlive bool) bridgePacket {
+	state.sentSinceRefresh = true
+	pkt := bridgePacket{state: state, keepAlive: keepAlive}
+
+	if state.route.Direction.OutputProtocol() == model.ProtocolSACN {
+		pkt.sacn = b.sacnPacketLocked(state, 0)
+		return pkt
+	}
+
+	state.sequence = nextArtNetSequence(state.sequence)
+	data, err := artnet.MarshalDMXFrame(&model.DMXFrame{
+		Universe: state.route.Output,
+		Sequence: state.sequence,
+		Length:   state.length,
+		Data:     state.data,
+	})
+	if err != nil {
+		b.logger.Error("Failed to marshal bridged ArtDMX packet", "error", err)
+		return pkt
+	}
+	pkt.artNet = data
+	return pkt
+}
+
+// terminatePacketsLocked 出力を停止するときに送信するパケットを作成する
+func (b *ProtocolBridge) terminatePacketsLocked(state *bridgeRouteState) []bridgePacket {
+	if state.route.Direction.OutputProtocol() != model.ProtocolSACN {
+		// Art-Net は送信を止めるだけでよい
+		return nil
+	}
+	packets := make([]bridgePacket, 0, bridgeTerminateCount)
+	for i := 0; i < bridgeTerminateCount; i++ {
+		packets = append(packets, bridgePacket{state: state, sacn: b.sacnPacketLocked(state, sacn.OptionStreamTerminated)})
+	}
+	return packets
+}
+
+func (b *ProtocolBridge) sacnPacketLocked(state *bridgeRouteState, options uint8) *sacn.DataPacket {
+	state.sequence++
+	data := make([]byte, state.length)
+	copy(data, state.data[:state.length])
+	return &sacn.DataPacket{
+		CID:        b.sacnOutput.CID,
+		SourceName: b.sacnOutput.SourceName,
+		Priority:   b.sacnOutput.Priority,
+		Sequence:   state.sequence,
+		Options:    options,
+		Universe:   state.route.Output,
+		StartCode:  sacn.StartCodeDMX,
+		Data:       data,
+	}
+}
+
+// sendPackets 出力パケットを送信し、転送状況に反映する
+func (b *ProtocolBridge) sendPackets(packets []bridgePacket) {
+	for _, pkt := range packets {
+		var err error
+		switch {
+		case pkt.sacn != nil:
+			err = b.sacnSender.Send(pkt.sacn)
+		case pkt.artNet != nil:
+			err = b.artNetWriter.SendToWriteChan(pkt.artNet, artNetBroadcastAddr(b.netProvider))
+		default:
+			continue
+		}
+
+		b.mu.Lock()
+		if err != nil {
+			pkt.state.stats.SendErrors++
+		} else {
+			pkt.state.stats.FramesOut++
+			if pkt.keepAlive {
+				pkt.state.stats.KeepAlives++
+			}
+		}
+		b.mu.Unlock()
+		if err != nil {
+			b.logger.Debug("Failed to send bridged frame", "direction", pkt.state.route.Direction, "output", pkt.state.route.Output, "error", err)
+		}
+	}
+}
+
+// artNetBroadcastAddr 選択したインターフェースのディレクテッドブロードキャストアドレス
+func artNetBroadcastAddr(provider NetworkInterfaceProvider) net.Addr {
+	broadcastIP := net.IPv4bcast
+	if iface := provider.LocalInterface(); iface != nil {
+		broadcastIP = iface.Broadcast
+	}
+	return &net.UDPAddr{IP: broadcastIP, Port: artnet.DefaultPort}
+}
+
+// nextArtNetSequence ArtDMX のシーケンス番号は 1-255 を循環させる（0 はシーケンス番号なしを示す）
+func nextArtNetSequence(sequence uint8) uint8 {
+	sequence++
+	if sequence == 0 {
+		sequence = 1
+	}
+	return sequence
+}
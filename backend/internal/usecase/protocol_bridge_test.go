@@ -0,0 +1,150 @@
+package usecase
+
+import (
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jsimonetti/go-artnet/packet"
+	"github.com/nasshu2916/dmx_viewer/internal/domain/model"
+	"github.com/nasshu2916/dmx_viewer/internal/infrastructure/sacn"
+	"github.com/nasshu2916/dmx_viewer/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSACNSender 送信されたデータパケットを記録するSACNSender
+type fakeSACNSender struct {
+	mu      sync.Mutex
+	packets []*sacn.DataPacket
+}
+
+func (f *fakeSACNSender) Send(p *sacn.DataPacket) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.packets = append(f.packets, p)
+	return nil
+}
+
+func (f *fakeSACNSender) Packets() []*sacn.DataPacket {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]*sacn.DataPacket(nil), f.packets...)
+}
+
+var testBridgeCID = [16]byte{0xb0, 0x01}
+
+func newTestProtocolBridge(routes []model.BridgeRoute) (*ProtocolBridge, *fakeArtNetWriter, *fakeSACNSender) {
+	writer := &fakeArtNetWriter{}
+	sender := &fakeSACNSender{}
+	netProvider := &fakeNetworkInterfaceProvider{
+		iface: model.NewNetworkInterface("eth0", net.IPv4(2, 0, 0, 1), net.CIDRMask(8, 32), nil),
+	}
+	output := SACNOutputOptions{CID: testBridgeCID, SourceName: "Bridge", Priority: 150}
+	bridge := NewProtocolBridge(routes, writer, netProvider, sender, output, 0, logger.NewLogger("fatal"))
+	return bridge, writer, sender
+}
+
+func newBridgeTestFrame(protocol string, universe uint16, source string, values ...uint8) *model.DMXFrame {
+	frame := &model.DMXFrame{Protocol: protocol, Universe: universe, SourceIP: net.ParseIP(source), Length: uint16(len(values)), Priority: 100}
+	if protocol == model.ProtocolSACN {
+		frame.SourceCID = source
+	}
+	copy(frame.Data[:], values)
+	return frame
+}
+
+func TestProtocolBridge_ArtNetToSACN(t *testing.T) {
+	routes := []model.BridgeRoute{{Direction: model.BridgeArtNetToSACN, Input: 0, Output: 1}}
+	bridge, _, sender := newTestProtocolBridge(routes)
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := start
+	bridge.now = func() time.Time { return clock }
+
+	// 2つの送信元は HTP でマージして出力する
+	bridge.ObserveFrame(newBridgeTestFrame(model.ProtocolArtNet, 0, "2.0.0.10", 100, 0))
+	bridge.ObserveFrame(newBridgeTestFrame(model.ProtocolArtNet, 0, "2.0.0.11", 50, 200, 30))
+	// 経路のないユニバースは転送しない
+	bridge.ObserveFrame(newBridgeTestFrame(model.ProtocolArtNet, 5, "2.0.0.10", 1))
+
+	packets := sender.Packets()
+	require.Len(t, packets, 2)
+	out := packets[1]
+	assert.Equal(t, uint16(1), out.Universe)
+	assert.Equal(t, uint8(150), out.Priority)
+	assert.Equal(t, "Bridge", out.SourceName)
+	assert.Equal(t, testBridgeCID, out.CID)
+	assert.Equal(t, []byte{100, 200, 30}, out.Data)
+	assert.Equal(t, packets[0].Sequence+1, out.Sequence)
+
+	// 入力による送信があった周期は再送しない
+	bridge.Refresh()
+	assert.Len(t, sender.Packets(), 2)
+	// 入力がない周期は同じ内容を再送する
+	bridge.Refresh()
+	packets = sender.Packets()
+	require.Len(t, packets, 3)
+	assert.Equal(t, []byte{100, 200, 30}, packets[2].Data)
+
+	stats := bridge.BridgeStats()
+	require.Len(t, stats, 1)
+	assert.Equal(t, uint64(2), stats[0].FramesIn)
+	assert.Equal(t, uint64(3), stats[0].FramesOut)
+	assert.Equal(t, uint64(1), stats[0].KeepAlives)
+	assert.Equal(t, 2, stats[0].Sources)
+	assert.True(t, stats[0].Active)
+
+	// すべての送信元が途絶えると送信終了を通知して出力を止める
+	clock = start.Add(model.MergeSourceTimeout)
+	bridge.Refresh()
+	packets = sender.Packets()
+	require.Len(t, packets, 3+bridgeTerminateCount)
+	for _, p := range packets[3:] {
+		assert.True(t, p.StreamTerminated())
+	}
+	bridge.Refresh()
+	assert.Len(t, sender.Packets(), 3+bridgeTerminateCount)
+	assert.False(t, bridge.BridgeStats()[0].Active)
+}
+
+func TestProtocolBridge_SACNToArtNet(t *testing.T) {
+	routes := []model.BridgeRoute{{Direction: model.BridgeSACNToArtNet, Input: 1, Output: 0x0203}}
+	bridge, writer, _ := newTestProtocolBridge(routes)
+
+	// 優先度の高い送信元の値を出力する
+	low := newBridgeTestFrame(model.ProtocolSACN, 1, "cid-a", 255, 255, 255)
+	high := newBridgeTestFrame(model.ProtocolSACN, 1, "cid-b", 10, 20, 30)
+	high.Priority = 150
+	bridge.ObserveFrame(low)
+	bridge.ObserveFrame(high)
+
+	require.Len(t, writer.packets, 2)
+	dmx, ok := writer.packets[1].(*packet.ArtDMXPacket)
+	require.True(t, ok)
+	assert.Equal(t, uint8(0x02), dmx.Net)
+	assert.Equal(t, uint8(0x03), dmx.SubUni)
+	assert.Equal(t, uint16(4), dmx.Length) // 偶数に切り上げる
+	assert.Equal(t, []byte{10, 20, 30, 0}, dmx.Data[:4])
+	assert.Equal(t, uint8(2), dmx.Sequence)
+	assert.Equal(t, "2.255.255.255:6454", writer.addrs[1].String())
+}
+
+func TestProtocolBridge_IgnoresOwnOutput(t *testing.T) {
+	routes := []model.BridgeRoute{
+		{Direction: model.BridgeArtNetToSACN, Input: 0, Output: 1},
+		{Direction: model.BridgeSACNToArtNet, Input: 1, Output: 0},
+	}
+	bridge, writer, sender := newTestProtocolBridge(routes)
+
+	// 自身が出力したフレームを入力として転送しない
+	own := newBridgeTestFrame(model.ProtocolSACN, 1, sacn.FormatCID(testBridgeCID), 1)
+	bridge.ObserveFrame(own)
+	bridge.ObserveFrame(newBridgeTestFrame(model.ProtocolArtNet, 0, "2.0.0.1", 1))
+	assert.Empty(t, writer.packets)
+	assert.Empty(t, sender.Packets())
+
+	// 他の送信元のフレームは転送する
+	bridge.ObserveFrame(newBridgeTestFrame(model.ProtocolArtNet, 0, "2.0.0.10", 1))
+	assert.Len(t, sender.Packets(), 1)
+}
@@ -0,0 +1,286 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/nasshu2916/dmx_viewer/internal/domain/model"
+	"github.com/nasshu2916/dmx_viewer/internal/domain/repository"
+	"github.com/nasshu2916/dmx_viewer/pkg/logger"
+)
+
+// CaptureFlushInterval 記録中のキャプチャファイルをディスクに書き出す間隔
+const CaptureFlushInterval = 1 * time.Second
+
+var (
+	ErrAlreadyRecording = errors.New("recorder is already recording")
+	ErrNotRecording     = errors.New("recorder is not recording")
+)
+
+// RecorderUseCase 受信したDMXフレームの記録を開始・停止するインターフェース
+type RecorderUseCase interface {
+	// 記録を開始する
+	Start(mode model.RecordMode) (model.RecorderStatus, error)
+	// 記録を停止する
+	Stop() (model.RecorderStatus, error)
+	// 記録の状態を取得する
+	Status() model.RecorderStatus
+}
+
+// recorderKey 変化の有無を判定する単位（プロトコル・送信元・ユニバース）
+type recorderKey struct {
+	protocol string
+	source   string
+	universe uint16
+}
+
+// recorderQueueSize 書き出しを待つフレームの最大数
+const recorderQueueSize = 4096
+
+// queuedFrame 書き出しを待つフレーム
+type queuedFrame struct {
+	generation uint64 // 記録を開始するごとに増やし、停止した記録のフレームを破棄する
+	frame      *model.DMXFrame
+}
+
+// RecorderUseCaseImpl RecorderUseCaseの実装
+// FrameObserver として送信元ごとのフレームを受け取り、記録中であればキャプチャファイルに追記する
+//
+// 受信処理を止めないよう、ファイルへの書き込み・ローテーション・ディスクへの書き出しは StartFlusher のゴルーチンで行う。
+// ioMu はファイルの操作を、mu は状態を保護する（ioMu → mu の順に取得する）。
+type RecorderUseCaseImpl struct {
+	ioMu sync.Mutex
+	repo repository.CaptureRepository
+	open bool // ファイルを作成してから閉じるまで
+
+	mu         sync.Mutex
+	queue      chan queuedFrame
+	generation uint64
+	recording  bool
+	mode       model.RecordMode
+	startedAt  time.Time
+	frames     uint64
+	skipped    uint64
+	dropped    uint64
+	lastError  string
+	last       map[recorderKey]*model.DMXFrame // 変化したフレームのみを記録する場合の直前のフレーム
+	logger     *logger.Logger
+	now        func() time.Time
+}
+
+// NewRecorderUseCaseImpl RecorderUseCaseの新しいインスタンスを作成
+func NewRecorderUseCaseImpl(repo repository.CaptureRepository, logger *logger.Logger) *RecorderUseCaseImpl {
+	return &RecorderUseCaseImpl{
+		repo:   repo,
+		queue:  make(chan queuedFrame, recorderQueueSize),
+		mode:   model.RecordModeAll,
+		logger: logger,
+		now:    time.Now,
+	}
+}
+
+func (uc *RecorderUseCaseImpl) Start(mode model.RecordMode) (model.RecorderStatus, error) {
+	uc.ioMu.Lock()
+	defer uc.ioMu.Unlock()
+
+	if uc.open {
+		return uc.Status(), ErrAlreadyRecording
+	}
+	startedAt := uc.now()
+	if err := uc.repo.Open(startedAt); err != nil {
+		uc.mu.Lock()
+		uc.lastError = err.Error()
+		uc.mu.Unlock()
+		return uc.Status(), err
+	}
+	uc.open = true
+
+	uc.mu.Lock()
+	uc.generation++
+	uc.recording = true
+	uc.mode = mode
+	uc.startedAt = startedAt
+	uc.frames, uc.skipped, uc.dropped = 0, 0, 0
+	uc.lastError = ""
+	uc.last = make(map[recorderKey]*model.DMXFrame)
+	uc.mu.Unlock()
+	uc.logger.Info("Recording started", "mode", mode, "file", uc.currentFile())
+	return uc.Status(), nil
+}
+
+func (uc *RecorderUseCaseImpl) Stop() (model.RecorderStatus, error) {
+	uc.ioMu.Lock()
+	defer uc.ioMu.Unlock()
+
+	if !uc.open {
+		return uc.Status(), ErrNotRecording
+	}
+	if err := uc.stop(); err != nil {
+		return uc.Status(), err
+	}
+	status := uc.Status()
+	uc.logger.Info("Recording stopped", "frames", status.Frames, "dropped", status.Dropped, "files", len(status.Files))
+	return status, nil
+}
+
+func (uc *RecorderUseCaseImpl) Status() model.RecorderStatus {
+	// キャプチャファイルの一覧はリポジトリのロックを取得するため、状態のロックの外で取得する
+	files := uc.repo.Files()
+	if files == nil {
+		files = []model.CaptureFile{}
+	}
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	return model.RecorderStatus{
+		Recording: uc.recording,
+		Mode:      uc.mode,
+		StartedAt: uc.startedAt,
+		Frames:    uc.frames,
+		Skipped:   uc.skipped,
+		Dropped:   uc.dropped,
+		Files:     files,
+		LastError: uc.lastError,
+	}
+}
+
+// ObserveFrame 記録中であればフレームの複製を書き出しのキューに追加する
+// キューに空きがない場合は書き出さずに破棄する
+func (uc *RecorderUseCaseImpl) ObserveFrame(frame *model.DMXFrame) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	if !uc.recording {
+		return
+	}
+	var key recorderKey
+	if uc.mode == model.RecordModeChanged {
+		key = recorderKey{protocol: frame.Protocol, source: frameSourceKey(frame), universe: frame.Universe}
+		if last, ok := uc.last[key]; ok && sameFrameContent(last, frame) {
+			uc.skipped++
+			return
+		}
+	}
+
+	frame = frame.Clone()
+	select {
+	case uc.queue <- queuedFrame{generation: uc.generation, frame: frame}:
+		uc.frames++
+		if uc.mode == model.RecordModeChanged {
+			uc.last[key] = frame
+		}
+	default:
+		uc.dropped++
+	}
+}
+
+// StartFlusher キューのフレームを書き出し、記録中のキャプチャファイルを一定間隔でディスクに書き出す
+func (uc *RecorderUseCaseImpl) StartFlusher(ctx context.Context) {
+	ticker := time.NewTicker(CaptureFlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case queued := <-uc.queue:
+			uc.ioMu.Lock()
+			uc.write(queued)
+			uc.ioMu.Unlock()
+		case <-ticker.C:
+			uc.flush()
+		case <-ctx.Done():
+			uc.logger.Info("Recorder flusher stopped.")
+			return
+		}
+	}
+}
+
+// write フレームをキャプチャファイルに追記する（ioMu を保持して呼ぶ）
+func (uc *RecorderUseCaseImpl) write(queued queuedFrame) {
+	uc.mu.Lock()
+	current := queued.generation == uc.generation
+	uc.mu.Unlock()
+	if !uc.open || !current {
+		return
+	}
+	if err := uc.repo.Append(queued.frame); err != nil {
+		// 書き込めなくなった場合（ディスクフル等）は記録を停止する
+		uc.logger.Error("Failed to write capture record, recording stopped", "error", err)
+		uc.mu.Lock()
+		uc.lastError = err.Error()
+		uc.recording = false
+		uc.last = nil
+		uc.mu.Unlock()
+		uc.open = false
+		if err := uc.repo.Close(); err != nil {
+			uc.logger.Error("Failed to close capture file", "error", err)
+		}
+	}
+}
+
+// drain キューに残っているフレームを書き出す（ioMu を保持して呼ぶ）
+func (uc *RecorderUseCaseImpl) drain() {
+	for {
+		select {
+		case queued := <-uc.queue:
+			uc.write(queued)
+		default:
+			return
+		}
+	}
+}
+
+func (uc *RecorderUseCaseImpl) flush() {
+	uc.ioMu.Lock()
+	defer uc.ioMu.Unlock()
+
+	if !uc.open {
+		return
+	}
+	uc.drain()
+	if !uc.open {
+		return
+	}
+	if err := uc.repo.Flush(); err != nil {
+		uc.logger.Warn("Failed to flush capture file", "error", err)
+		uc.mu.Lock()
+		uc.lastError = err.Error()
+		uc.mu.Unlock()
+	}
+}
+
+// stop キューに残っているフレームを書き出してファイルを閉じる（ioMu を保持して呼ぶ）
+func (uc *RecorderUseCaseImpl) stop() error {
+	uc.mu.Lock()
+	uc.recording = false
+	uc.last = nil
+	uc.mu.Unlock()
+
+	uc.drain()
+	if !uc.open {
+		// 書き込みに失敗してすでに閉じている
+		return nil
+	}
+	uc.open = false
+	if err := uc.repo.Close(); err != nil {
+		uc.mu.Lock()
+		uc.lastError = err.Error()
+		uc.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// currentFile 記録中のキャプチャファイルのパス
+func (uc *RecorderUseCaseImpl) currentFile() string {
+	files := uc.repo.Files()
+	if len(files) == 0 {
+		return ""
+	}
+	return files[len(files)-1].Path
+}
+
+// sameFrameContent 2つのフレームのレベル・スロット数・優先度が同じかどうか
+func sameFrameContent(a, b *model.DMXFrame) bool {
+	return a.StartCode == b.StartCode && a.Length == b.Length && a.Priority == b.Priority && a.Data == b.Data
+}
@@ -0,0 +1,117 @@
+package usecase
+
+import (
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/nasshu2916/dmx_viewer/internal/domain/model"
+	"github.com/nasshu2916/dmx_viewer/internal/infrastructure"
+	"github.com/nasshu2916/dmx_viewer/internal/infrastructure/capture"
+	"github.com/nasshu2916/dmx_viewer/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readCaptureFile(t *testing.T, path string) []*model.CaptureRecord {
+	t.Helper()
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+	r, err := capture.NewReader(f)
+	require.NoError(t, err)
+	records, err := r.ReadAll()
+	require.NoError(t, err)
+	return records
+}
+
+func newRecorderTestFrame(start time.Time, offset time.Duration, values ...uint8) *model.DMXFrame {
+	frame := &model.DMXFrame{Protocol: model.ProtocolArtNet, Universe: 1, SourceIP: net.IPv4(2, 0, 0, 10), Length: uint16(len(values)), ReceivedAt: start.Add(offset)}
+	copy(frame.Data[:], values)
+	return frame
+}
+
+func TestRecorderUseCase_StartStop(t *testing.T) {
+	recorder := NewRecorderUseCaseImpl(infrastructure.NewCaptureRepository(t.TempDir(), 0, 0), logger.NewLogger("fatal"))
+	start := time.Now()
+	recorder.now = func() time.Time { return start }
+
+	// 記録していない間のフレームは書き込まない
+	recorder.ObserveFrame(newRecorderTestFrame(start, 0, 1))
+	_, err := recorder.Stop()
+	assert.ErrorIs(t, err, ErrNotRecording)
+
+	status, err := recorder.Start(model.RecordModeAll)
+	require.NoError(t, err)
+	assert.True(t, status.Recording)
+	require.Len(t, status.Files, 1)
+	_, err = recorder.Start(model.RecordModeAll)
+	assert.ErrorIs(t, err, ErrAlreadyRecording)
+
+	recorder.ObserveFrame(newRecorderTestFrame(start, time.Millisecond, 1, 2))
+	recorder.ObserveFrame(newRecorderTestFrame(start, 2*time.Millisecond, 1, 2))
+	recorder.flush()
+
+	// 書き出した内容は記録中でも読み込める
+	records := readCaptureFile(t, status.Files[0].Path)
+	require.Len(t, records, 2)
+	assert.Equal(t, 2*time.Millisecond, records[1].Offset)
+
+	status, err = recorder.Stop()
+	require.NoError(t, err)
+	assert.False(t, status.Recording)
+	assert.Equal(t, uint64(2), status.Frames)
+}
+
+func TestRecorderUseCase_ChangedOnlyAndRotation(t *testing.T) {
+	// ファイルヘッダーと1レコードで上限を超えるサイズ
+	repo := infrastructure.NewCaptureRepository(t.TempDir(), 90, 0)
+	recorder := NewRecorderUseCaseImpl(repo, logger.NewLogger("fatal"))
+	start := time.Now()
+
+	_, err := recorder.Start(model.RecordModeChanged)
+	require.NoError(t, err)
+	recorder.ObserveFrame(newRecorderTestFrame(start, time.Millisecond, 1, 2))
+	recorder.ObserveFrame(newRecorderTestFrame(start, 2*time.Millisecond, 1, 2)) // 変化なし
+	recorder.ObserveFrame(newRecorderTestFrame(start, 3*time.Millisecond, 1, 3))
+	other := newRecorderTestFrame(start, 4*time.Millisecond, 1, 3)
+	other.SourceIP = net.IPv4(2, 0, 0, 11) // 別の送信元は別に判定する
+	recorder.ObserveFrame(other)
+
+	status, err := recorder.Stop()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), status.Frames)
+	assert.Equal(t, uint64(1), status.Skipped)
+
+	// 1ファイルに1レコードずつローテーションする
+	require.Len(t, status.Files, 3)
+	for _, file := range status.Files {
+		info, err := os.Stat(file.Path)
+		require.NoError(t, err)
+		assert.Equal(t, file.Size, info.Size())
+		assert.Len(t, readCaptureFile(t, file.Path), 1)
+	}
+	assert.Equal(t, "2.0.0.11", readCaptureFile(t, status.Files[2].Path)[0].Frame.SourceIP.String())
+}
+
+func TestRecorderUseCase_DropsWhenQueueIsFull(t *testing.T) {
+	recorder := NewRecorderUseCaseImpl(infrastructure.NewCaptureRepository(t.TempDir(), 0, 0), logger.NewLogger("fatal"))
+	start := time.Now()
+	recorder.now = func() time.Time { return start }
+
+	_, err := recorder.Start(model.RecordModeAll)
+	require.NoError(t, err)
+	for i := 0; i < recorderQueueSize+2; i++ {
+		recorder.ObserveFrame(newRecorderTestFrame(start, time.Duration(i)*time.Millisecond, 1))
+	}
+	status := recorder.Status()
+	assert.Equal(t, uint64(recorderQueueSize), status.Frames)
+	assert.Equal(t, uint64(2), status.Dropped)
+
+	// 停止するときにキューに残っているフレームを書き出す
+	status, err = recorder.Stop()
+	require.NoError(t, err)
+	require.Len(t, status.Files, 1)
+	assert.Len(t, readCaptureFile(t, status.Files[0].Path), recorderQueueSize)
+}
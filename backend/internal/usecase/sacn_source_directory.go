@@ -0,0 +1,130 @@
+package usecase
+
+import (
+	"context"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/nasshu2916/dmx_viewer/internal/domain/model"
+	"github.com/nasshu2916/dmx_viewer/internal/domain/repository"
+	"github.com/nasshu2916/dmx_viewer/internal/infrastructure/sacn"
+	"github.com/nasshu2916/dmx_viewer/pkg/logger"
+)
+
+// SACNSourceSweepInterval 期限切れのsACN送信元を確認する間隔
+const SACNSourceSweepInterval = 1 * time.Second
+
+// SACNSourceObserver ユニバースディスカバリーで通知された送信元を受け取るインターフェース
+type SACNSourceObserver interface {
+	ObserveDiscovery(p *sacn.DiscoveryPacket, addr net.Addr) error
+}
+
+// SACNSourceDirectory ユニバースディスカバリーで検出したsACN送信元の一覧を管理するインターフェース
+type SACNSourceDirectory interface {
+	SACNSourceObserver
+	// 一定間隔で期限切れの送信元を削除する
+	StartSweeper(ctx context.Context)
+	// 現在の送信元一覧を取得する
+	GetSources() []*model.SACNSource
+}
+
+// SACNSourceDirectoryImpl SACNSourceDirectoryの実装
+type SACNSourceDirectoryImpl struct {
+	mu          sync.Mutex // 受信と期限切れの削除を直列化する
+	sourceRepo  repository.SACNSourceRepository
+	wsUseCase   WebSocketUseCase
+	expireAfter time.Duration
+	logger      *logger.Logger
+	now         func() time.Time
+}
+
+// NewSACNSourceDirectoryImpl SACNSourceDirectoryの新しいインスタンスを作成（expireAfter が0以下の場合は既定値を使用する）
+func NewSACNSourceDirectoryImpl(sourceRepo repository.SACNSourceRepository, wsUseCase WebSocketUseCase, expireAfter time.Duration, logger *logger.Logger) *SACNSourceDirectoryImpl {
+	if expireAfter <= 0 {
+		expireAfter = model.SACNSourceExpireAfter
+	}
+	return &SACNSourceDirectoryImpl{
+		sourceRepo:  sourceRepo,
+		wsUseCase:   wsUseCase,
+		expireAfter: expireAfter,
+		logger:      logger,
+		now:         time.Now,
+	}
+}
+
+// ObserveDiscovery ユニバースディスカバリーの内容を送信元一覧に反映し、変化があれば一覧を配信する
+func (d *SACNSourceDirectoryImpl) ObserveDiscovery(p *sacn.DiscoveryPacket, addr net.Addr) error {
+	var ip net.IP
+	if udpAddr, ok := addr.(*net.UDPAddr); ok {
+		ip = udpAddr.IP
+	}
+	cid := sacn.FormatCID(p.CID)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	prev, ok := d.sourceRepo.Get(cid)
+	if !ok {
+		prev = &model.SACNSource{CID: cid}
+	}
+	source := prev.WithDiscoveryPage(p.SourceName, ip, p.Page, p.LastPage, p.Universes, d.now())
+	d.sourceRepo.Save(source)
+
+	if ok && source.SameAnnouncement(prev) {
+		return nil
+	}
+	if !ok {
+		d.logger.Info("sACN source discovered", "cid", cid, "source", p.SourceName, "ip", ip, "universes", source.Universes)
+	}
+	return d.broadcastSources()
+}
+
+func (d *SACNSourceDirectoryImpl) StartSweeper(ctx context.Context) {
+	ticker := time.NewTicker(SACNSourceSweepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := d.Sweep(); err != nil {
+				d.logger.Debug("Failed to broadcast sACN source list", "error", err)
+			}
+		case <-ctx.Done():
+			d.logger.Info("sACN source directory sweeper stopped.")
+			return
+		}
+	}
+}
+
+// Sweep ユニバースディスカバリーが途絶えた送信元を削除し、変化があれば一覧を配信する
+func (d *SACNSourceDirectoryImpl) Sweep() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := d.now()
+	changed := false
+	for _, source := range d.sourceRepo.All() {
+		if now.Sub(source.LastSeen) < d.expireAfter {
+			continue
+		}
+		d.sourceRepo.Delete(source.CID)
+		d.logger.Info("sACN source expired", "cid", source.CID, "source", source.SourceName, "last_seen", source.LastSeen)
+		changed = true
+	}
+
+	if !changed {
+		return nil
+	}
+	return d.broadcastSources()
+}
+
+func (d *SACNSourceDirectoryImpl) GetSources() []*model.SACNSource {
+	return d.sourceRepo.All()
+}
+
+// broadcastSources すべての送信元を sacn/sources トピックに配信する
+func (d *SACNSourceDirectoryImpl) broadcastSources() error {
+	msg := model.NewWebSocketMessage("sacn_sources", d.sourceRepo.All())
+	return d.wsUseCase.BroadcastToTopic("sacn/sources", msg)
+}
@@ -0,0 +1,61 @@
+package usecase
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/nasshu2916/dmx_viewer/internal/domain/model"
+	"github.com/nasshu2916/dmx_viewer/internal/infrastructure"
+	"github.com/nasshu2916/dmx_viewer/internal/infrastructure/sacn"
+	"github.com/nasshu2916/dmx_viewer/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSACNSourceDirectory_ObserveDiscovery(t *testing.T) {
+	ws := newFakeWebSocketUseCase()
+	d := NewSACNSourceDirectoryImpl(infrastructure.NewSACNSourceRepository(), ws, 0, logger.NewLogger("fatal"))
+	addr := &net.UDPAddr{IP: net.IPv4(10, 0, 0, 5), Port: sacn.DefaultPort}
+	cid := [16]byte{0x01}
+
+	// 複数ページに分かれたユニバース一覧をまとめる
+	require.NoError(t, d.ObserveDiscovery(&sacn.DiscoveryPacket{CID: cid, SourceName: "Console", Page: 0, LastPage: 1, Universes: []uint16{1, 2}}, addr))
+	require.NoError(t, d.ObserveDiscovery(&sacn.DiscoveryPacket{CID: cid, SourceName: "Console", Page: 1, LastPage: 1, Universes: []uint16{600}}, addr))
+	// 内容が変わらない場合は配信しない
+	require.NoError(t, d.ObserveDiscovery(&sacn.DiscoveryPacket{CID: cid, SourceName: "Console", Page: 0, LastPage: 1, Universes: []uint16{1, 2}}, addr))
+
+	sources := d.GetSources()
+	require.Len(t, sources, 1)
+	assert.Equal(t, "01000000-0000-0000-0000-000000000000", sources[0].CID)
+	assert.Equal(t, "Console", sources[0].SourceName)
+	assert.True(t, sources[0].IPAddress.Equal(net.IPv4(10, 0, 0, 5)))
+	assert.Equal(t, []uint16{1, 2, 600}, sources[0].Universes)
+
+	messages := ws.Messages("sacn/sources")
+	require.Len(t, messages, 2)
+	assert.Equal(t, "sacn_sources", messages[0].Type)
+
+	// ページ数が減った場合は範囲外のページを破棄する
+	require.NoError(t, d.ObserveDiscovery(&sacn.DiscoveryPacket{CID: cid, SourceName: "Console", Page: 0, LastPage: 0, Universes: []uint16{1}}, addr))
+	assert.Equal(t, []uint16{1}, d.GetSources()[0].Universes)
+}
+
+func TestSACNSourceDirectory_Sweep(t *testing.T) {
+	ws := newFakeWebSocketUseCase()
+	d := NewSACNSourceDirectoryImpl(infrastructure.NewSACNSourceRepository(), ws, 0, logger.NewLogger("fatal"))
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	d.now = func() time.Time { return start }
+	addr := &net.UDPAddr{IP: net.IPv4(10, 0, 0, 5), Port: sacn.DefaultPort}
+
+	require.NoError(t, d.ObserveDiscovery(&sacn.DiscoveryPacket{CID: [16]byte{1}, Universes: []uint16{1}}, addr))
+
+	d.now = func() time.Time { return start.Add(model.SACNSourceExpireAfter - time.Second) }
+	require.NoError(t, d.Sweep())
+	assert.Len(t, d.GetSources(), 1)
+
+	d.now = func() time.Time { return start.Add(model.SACNSourceExpireAfter) }
+	require.NoError(t, d.Sweep())
+	assert.Empty(t, d.GetSources())
+	assert.Len(t, ws.Messages("sacn/sources"), 2)
+}
@@ -0,0 +1,221 @@
+package usecase
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/nasshu2916/dmx_viewer/internal/domain/model"
+	"github.com/nasshu2916/dmx_viewer/internal/infrastructure/sacn"
+	"github.com/nasshu2916/dmx_viewer/pkg/logger"
+)
+
+// SACNSweepInterval 無通信のsACN送信元を確認する間隔
+const SACNSweepInterval = 500 * time.Millisecond
+
+// sacnSequenceWindow 直前のシーケンス番号からこの範囲内で戻ったパケットは古いものとして破棄する（E1.31 6.7.2）
+const sacnSequenceWindow = 20
+
+// sacnSource 1ユニバースを送信している1送信元の状態
+type sacnSource struct {
+	sequence uint8
+	lastSeen time.Time
+
+	frame                  *model.DMXFrame // 最後に受信したレベル（START Code 0x00）
+	addressPriority        *[512]uint8     // 最後に受信したアドレスごとの優先度（START Code 0xDD）
+	addressPriorityUpdated time.Time
+}
+
+// SACNSourceTracker ユニバース・送信元（CID）ごとにsACNのシーケンス番号・送信終了・レベル・優先度を追跡する
+type SACNSourceTracker struct {
+	mu        sync.Mutex
+	universes map[uint16]map[[16]byte]*sacnSource
+	logger    *logger.Logger
+	now       func() time.Time
+}
+
+// NewSACNSourceTracker SACNSourceTrackerの新しいインスタンスを作成
+func NewSACNSourceTracker(logger *logger.Logger) *SACNSourceTracker {
+	return &SACNSourceTracker{
+		universes: make(map[uint16]map[[16]byte]*sacnSource),
+		logger:    logger,
+		now:       time.Now,
+	}
+}
+
+// Accept データパケットのシーケンス番号・送信終了を送信元の状態に反映し、内容を使用するべきパケットかどうかを返す
+//
+// 送信終了の通知を受けた場合は送信元を削除して false を返す。シーケンス番号が古いパケットも false を返す。
+func (t *SACNSourceTracker) Accept(p *sacn.DataPacket) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	sources, ok := t.universes[p.Universe]
+	if !ok {
+		sources = make(map[[16]byte]*sacnSource)
+		t.universes[p.Universe] = sources
+	}
+
+	if p.StreamTerminated() {
+		if _, ok := sources[p.CID]; ok {
+			t.logger.Info("sACN source terminated stream", "universe", p.Universe, "source", p.SourceName)
+		}
+		t.removeSourceLocked(p.Universe, p.CID)
+		return false
+	}
+
+	source, ok := sources[p.CID]
+	if ok {
+		if diff := int8(p.Sequence - source.sequence); diff <= 0 && diff > -sacnSequenceWindow {
+			return false
+		}
+	} else {
+		source = &sacnSource{}
+		sources[p.CID] = source
+		t.logger.Info("sACN source appeared", "universe", p.Universe, "source", p.SourceName, "priority", p.Priority)
+	}
+	source.sequence = p.Sequence
+	source.lastSeen = t.now()
+	return true
+}
+
+// UpdateLevels Accept で受け入れたデータパケットのレベルを保存し、アドレスごとの優先度を付加したフレームを返す
+func (t *SACNSourceTracker) UpdateLevels(cid [16]byte, dmx *model.DMXFrame) *model.DMXFrame {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	source, ok := t.universes[dmx.Universe][cid]
+	if !ok {
+		return dmx
+	}
+	if source.addressPriority != nil && t.now().Sub(source.addressPriorityUpdated) < sacn.SourceLossTimeout {
+		priorities := *source.addressPriority
+		dmx.ChannelPriority = &priorities
+	}
+	source.frame = dmx
+	return dmx
+}
+
+// UpdateRecordedLevels 記録したフレームのレベルを、パケットを受信したときと同じく送信元の最新のレベルとして保存する
+// 記録したフレームはシーク・コマ送りで前後するため、シーケンス番号は確認しない
+func (t *SACNSourceTracker) UpdateRecordedLevels(cid [16]byte, dmx *model.DMXFrame) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	sources, ok := t.universes[dmx.Universe]
+	if !ok {
+		sources = make(map[[16]byte]*sacnSource)
+		t.universes[dmx.Universe] = sources
+	}
+	source, ok := sources[cid]
+	if !ok {
+		source = &sacnSource{}
+		sources[cid] = source
+	}
+	source.sequence = dmx.Sequence
+	source.lastSeen = t.now()
+	source.frame = dmx
+	if dmx.ChannelPriority != nil {
+		source.addressPriority = dmx.ChannelPriority
+		source.addressPriorityUpdated = source.lastSeen
+	}
+}
+
+// UpdateAddressPriority Accept で受け入れたアドレスごとの優先度（START Code 0xDD）を保存する
+func (t *SACNSourceTracker) UpdateAddressPriority(p *sacn.DataPacket) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	source, ok := t.universes[p.Universe][p.CID]
+	if !ok {
+		return
+	}
+	// 送信されていないアドレスは優先度0（出力しない）として扱う
+	var priorities [512]uint8
+	copy(priorities[:], p.Data)
+	source.addressPriority = &priorities
+	source.addressPriorityUpdated = t.now()
+	if source.frame != nil {
+		source.frame = source.frame.Clone()
+		source.frame.ChannelPriority = &priorities
+	}
+}
+
+// Merge ユニバースを送信中の送信元のレベルを優先度に従ってマージする（レベルを受信した送信元がない場合はnil）
+func (t *SACNSourceTracker) Merge(universe uint16) *model.SACNMergedFrame {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	inputs := make([]model.SACNMergeInput, 0, len(t.universes[universe]))
+	for cid, source := range t.universes[universe] {
+		if source.frame == nil {
+			continue
+		}
+		frame := source.frame
+		// アドレスごとの優先度が途絶えた場合はユニバースの優先度に戻す
+		if frame.ChannelPriority != nil && now.Sub(source.addressPriorityUpdated) >= sacn.SourceLossTimeout {
+			frame = frame.Clone()
+			frame.ChannelPriority = nil
+		}
+		inputs = append(inputs, model.SACNMergeInput{CID: sacn.FormatCID(cid), Frame: frame, LastSeen: source.lastSeen})
+	}
+	if len(inputs) == 0 {
+		return nil
+	}
+	sort.Slice(inputs, func(i, j int) bool { return inputs[i].CID < inputs[j].CID })
+	return model.MergeSACNFrames(universe, inputs)
+}
+
+// Sweep 2.5秒間データを受信していない送信元を切断されたとみなして削除し、送信元が変化したユニバースを返す
+func (t *SACNSourceTracker) Sweep() []uint16 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	var changed []uint16
+	for universe, sources := range t.universes {
+		dropped := false
+		for cid, source := range sources {
+			if now.Sub(source.lastSeen) >= sacn.SourceLossTimeout {
+				t.logger.Info("sACN source lost", "universe", universe, "last_seen", source.lastSeen)
+				t.removeSourceLocked(universe, cid)
+				dropped = true
+			}
+		}
+		if dropped {
+			changed = append(changed, universe)
+		}
+	}
+	sort.Slice(changed, func(i, j int) bool { return changed[i] < changed[j] })
+	return changed
+}
+
+// Reset すべての送信元を削除し、送信元がいたユニバースを返す
+func (t *SACNSourceTracker) Reset() []uint16 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	changed := make([]uint16, 0, len(t.universes))
+	for universe := range t.universes {
+		changed = append(changed, universe)
+	}
+	clear(t.universes)
+	sort.Slice(changed, func(i, j int) bool { return changed[i] < changed[j] })
+	return changed
+}
+
+// SourceCount ユニバースを送信中の送信元の数を返す
+func (t *SACNSourceTracker) SourceCount(universe uint16) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return len(t.universes[universe])
+}
+
+func (t *SACNSourceTracker) removeSourceLocked(universe uint16, cid [16]byte) {
+	sources := t.universes[universe]
+	delete(sources, cid)
+	if len(sources) == 0 {
+		delete(t.universes, universe)
+	}
+}
@@ -0,0 +1,211 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"net"
+	"time"
+
+	"github.com/nasshu2916/dmx_viewer/internal/config"
+	"github.com/nasshu2916/dmx_viewer/internal/domain/model"
+	"github.com/nasshu2916/dmx_viewer/internal/infrastructure/sacn"
+	"github.com/nasshu2916/dmx_viewer/pkg/logger"
+)
+
+// SACNUniverseJoiner sACNのユニバースのマルチキャストグループに参加するためのインターフェース
+type SACNUniverseJoiner interface {
+	JoinUniverse(universe uint16) error
+}
+
+// SACNBridgeUseCase sACN受信機とWebSocketの橋渡しを行うビジネスロジック
+type SACNBridgeUseCase interface {
+	// sACN受信機からのパケットをWebSocketに転送する処理を開始
+	StartPacketForwarding(ctx context.Context, receiver *sacn.Receiver)
+	// 一定間隔で無通信の送信元を削除する
+	StartSweeper(ctx context.Context)
+	// 受信したsACNパケットを処理する
+	HandlePacket(received model.ReceivedData) error
+}
+
+// SACNBridgeUseCaseImpl SACNBridgeUseCaseの実装
+type SACNBridgeUseCaseImpl struct {
+	wsUseCase WebSocketUseCase
+	tracker   *SACNSourceTracker
+	sources   SACNSourceObserver
+	frames    FrameObserver    // 送信元ごとのフレームを受け取る（nilの場合は使用しない）
+	universes UniverseObserver // マージしたデータを受け取る（nilの場合は使用しない）
+	joiner    SACNUniverseJoiner
+	config    *config.SACN
+	logger    *logger.Logger
+}
+
+// NewSACNBridgeUseCaseImpl SACNBridgeUseCaseの新しいインスタンスを作成
+func NewSACNBridgeUseCaseImpl(wsUseCase WebSocketUseCase, tracker *SACNSourceTracker, sources SACNSourceObserver, frames FrameObserver, universes UniverseObserver, joiner SACNUniverseJoiner, cfg *config.SACN, logger *logger.Logger) *SACNBridgeUseCaseImpl {
+	return &SACNBridgeUseCaseImpl{
+		wsUseCase: wsUseCase,
+		tracker:   tracker,
+		sources:   sources,
+		frames:    frames,
+		universes: universes,
+		joiner:    joiner,
+		config:    cfg,
+		logger:    logger,
+	}
+}
+
+// StartPacketForwarding sACN受信機からのパケットをWebSocketに転送する処理を開始
+func (uc *SACNBridgeUseCaseImpl) StartPacketForwarding(ctx context.Context, receiver *sacn.Receiver) {
+	defer func() {
+		if r := recover(); r != nil {
+			uc.logger.Error("Panic occurred in sACN packet forwarding", "panic", r)
+		}
+	}()
+
+	receivedChan := receiver.ReceivedChan()
+
+	uc.logger.Info("Started sACN packet forwarding to WebSocket")
+
+	for {
+		select {
+		case <-ctx.Done():
+			uc.logger.Info("sACN packet forwarding stopped due to context cancellation")
+			return
+
+		case receivedData, ok := <-receivedChan:
+			if !ok {
+				uc.logger.Info("sACN packet channel closed, stopping packet forwarding")
+				return
+			}
+			if err := uc.HandlePacket(receivedData); err != nil {
+				uc.logger.Debug("Failed to handle sACN packet", "error", err, "addr", receivedData.Addr)
+			}
+		}
+	}
+}
+
+// StartSweeper 一定間隔で無通信の送信元を削除し、マージ結果を配信し直す
+func (uc *SACNBridgeUseCaseImpl) StartSweeper(ctx context.Context) {
+	ticker := time.NewTicker(SACNSweepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			uc.Sweep()
+		case <-ctx.Done():
+			uc.logger.Info("sACN source sweeper stopped.")
+			return
+		}
+	}
+}
+
+// Sweep 無通信の送信元を削除し、送信元が変化したユニバースのマージ結果を配信し直す
+func (uc *SACNBridgeUseCaseImpl) Sweep() {
+	uc.broadcastMergedAll(uc.tracker.Sweep())
+}
+
+// Reset すべての送信元を削除し、送信元がいたユニバースに空のフレームを配信する
+func (uc *SACNBridgeUseCaseImpl) Reset() {
+	uc.broadcastMergedAll(uc.tracker.Reset())
+}
+
+func (uc *SACNBridgeUseCaseImpl) broadcastMergedAll(universes []uint16) {
+	for _, universe := range universes {
+		if err := uc.broadcastMerged(universe); err != nil {
+			uc.logger.Debug("Failed to broadcast sACN merged frame", "universe", universe, "error", err)
+		}
+	}
+}
+
+func (uc *SACNBridgeUseCaseImpl) HandlePacket(received model.ReceivedData) error {
+	p, err := sacn.Unmarshal(received.Data)
+	if err != nil {
+		if errors.Is(err, sacn.ErrUnsupportedVector) {
+			return nil
+		}
+		return err
+	}
+
+	switch p := p.(type) {
+	case *sacn.DataPacket:
+		return uc.handleDataPacket(p, received)
+	case *sacn.DiscoveryPacket:
+		return uc.handleDiscoveryPacket(p, received.Addr)
+	case *sacn.SyncPacket:
+		// 同期パケットは受信順に表示するため使用しない
+	}
+	return nil
+}
+
+func (uc *SACNBridgeUseCaseImpl) handleDataPacket(p *sacn.DataPacket, received model.ReceivedData) error {
+	if !uc.tracker.Accept(p) {
+		if p.StreamTerminated() {
+			return uc.broadcastMerged(p.Universe)
+		}
+		return nil
+	}
+
+	switch p.StartCode {
+	case sacn.StartCodeDMX:
+		dmxData, err := sacn.NewDMXFrame(received.Addr, p, received.ReceivedAt)
+		if err != nil {
+			return err
+		}
+		dmxData = uc.tracker.UpdateLevels(p.CID, dmxData)
+		if uc.frames != nil {
+			uc.frames.ObserveFrame(dmxData)
+		}
+
+		if err := publishDMXFrame(uc.wsUseCase, dmxData); err != nil {
+			return err
+		}
+	case sacn.StartCodePerAddressPriority:
+		uc.tracker.UpdateAddressPriority(p)
+	default:
+		// その他の代替START Codeのデータはレベルではないため表示しない
+		return nil
+	}
+	return uc.broadcastMerged(p.Universe)
+}
+
+// MergeFrame 記録したsACNのフレームを受信したレベルと同じく優先度に従ってマージし、sacn/dmx_merged トピックに配信する
+func (uc *SACNBridgeUseCaseImpl) MergeFrame(frame *model.DMXFrame) error {
+	if frame.StartCode != model.StartCodeDMX {
+		return nil
+	}
+	cid, err := sacn.ParseCID(frame.SourceCID)
+	if err != nil {
+		return err
+	}
+	uc.tracker.UpdateRecordedLevels(cid, frame)
+	return uc.broadcastMerged(frame.Universe)
+}
+
+// broadcastMerged 優先度に従ってマージしたユニバースのデータを sacn/dmx_merged トピックに配信する
+func (uc *SACNBridgeUseCaseImpl) broadcastMerged(universe uint16) error {
+	merged := uc.tracker.Merge(universe)
+	if merged == nil {
+		// 送信元がいなくなったユニバースは空のフレームを配信する
+		merged = model.MergeSACNFrames(universe, nil)
+	}
+	if uc.universes != nil {
+		uc.universes.ObserveUniverse(model.ProtocolSACN, universe, &merged.Data)
+	}
+	msg := model.NewWebSocketMessage("sacn_dmx_merged", merged)
+	return uc.wsUseCase.BroadcastToTopic("sacn/dmx_merged", msg)
+}
+
+// handleDiscoveryPacket 送信元一覧を更新し、通知されたユニバースのマルチキャストグループに参加する
+func (uc *SACNBridgeUseCaseImpl) handleDiscoveryPacket(p *sacn.DiscoveryPacket, addr net.Addr) error {
+	if uc.config.JoinDiscovered && uc.joiner != nil {
+		for _, universe := range p.Universes {
+			if universe < sacn.MinUniverse || universe > sacn.MaxUniverse {
+				continue
+			}
+			if err := uc.joiner.JoinUniverse(universe); err != nil {
+				uc.logger.Warn("Failed to join discovered sACN universe", "universe", universe, "source", p.SourceName, "error", err)
+			}
+		}
+	}
+	return uc.sources.ObserveDiscovery(p, addr)
+}
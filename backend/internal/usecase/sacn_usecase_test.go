@@ -0,0 +1,161 @@
+package usecase
+
+import (
+	"encoding/json"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/nasshu2916/dmx_viewer/internal/config"
+	"github.com/nasshu2916/dmx_viewer/internal/domain/model"
+	"github.com/nasshu2916/dmx_viewer/internal/infrastructure"
+	"github.com/nasshu2916/dmx_viewer/internal/infrastructure/sacn"
+	"github.com/nasshu2916/dmx_viewer/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeSACNJoiner struct {
+	joined []uint16
+}
+
+func (f *fakeSACNJoiner) JoinUniverse(universe uint16) error {
+	f.joined = append(f.joined, universe)
+	return nil
+}
+
+func newTestSACNBridge(cfg *config.SACN) (*SACNBridgeUseCaseImpl, *fakeWebSocketUseCase, *fakeSACNJoiner) {
+	ws := newFakeWebSocketUseCase()
+	joiner := &fakeSACNJoiner{}
+	l := logger.NewLogger("fatal")
+	directory := NewSACNSourceDirectoryImpl(infrastructure.NewSACNSourceRepository(), ws, 0, l)
+	return NewSACNBridgeUseCaseImpl(ws, NewSACNSourceTracker(l), directory, nil, nil, joiner, cfg, l), ws, joiner
+}
+
+func sacnReceived(t *testing.T, p interface{ MarshalBinary() ([]byte, error) }) model.ReceivedData {
+	t.Helper()
+	b, err := p.MarshalBinary()
+	require.NoError(t, err)
+	return model.ReceivedData{Data: b, Addr: &net.UDPAddr{IP: net.IPv4(10, 0, 0, 5), Port: sacn.DefaultPort}}
+}
+
+func TestSACNSourceTracker_StreamTerminated(t *testing.T) {
+	tracker := NewSACNSourceTracker(logger.NewLogger("fatal"))
+	primary := [16]byte{1}
+	backup := [16]byte{2}
+
+	assert.True(t, tracker.Accept(&sacn.DataPacket{CID: backup, Priority: 50, Universe: 1, Sequence: 1}))
+	assert.True(t, tracker.Accept(&sacn.DataPacket{CID: primary, Priority: 100, Universe: 1, Sequence: 1}))
+	assert.Equal(t, 2, tracker.SourceCount(1))
+
+	assert.False(t, tracker.Accept(&sacn.DataPacket{CID: primary, Priority: 100, Universe: 1, Sequence: 2, Options: sacn.OptionStreamTerminated}))
+	assert.Equal(t, 1, tracker.SourceCount(1))
+}
+
+func TestSACNSourceTracker_Sequence(t *testing.T) {
+	tracker := NewSACNSourceTracker(logger.NewLogger("fatal"))
+	cid := [16]byte{1}
+
+	tests := []struct {
+		sequence uint8
+		want     bool
+	}{
+		{sequence: 250, want: true},
+		{sequence: 251, want: true},
+		{sequence: 251, want: false}, // 重複
+		{sequence: 240, want: false}, // 古いパケット
+		{sequence: 3, want: true},    // 255から0への折り返し
+		{sequence: 200, want: true},  // 20以上戻った場合は送信元の再起動とみなす
+	}
+	for _, tt := range tests {
+		got := tracker.Accept(&sacn.DataPacket{CID: cid, Priority: 100, Universe: 1, Sequence: tt.sequence})
+		assert.Equal(t, tt.want, got, "sequence %d", tt.sequence)
+	}
+}
+
+func TestSACNSourceTracker_SourceLoss(t *testing.T) {
+	tracker := NewSACNSourceTracker(logger.NewLogger("fatal"))
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tracker.now = func() time.Time { return start }
+
+	assert.True(t, tracker.Accept(&sacn.DataPacket{CID: [16]byte{1}, Priority: 200, Universe: 1, Sequence: 1}))
+
+	tracker.now = func() time.Time { return start.Add(sacn.SourceLossTimeout) }
+	assert.True(t, tracker.Accept(&sacn.DataPacket{CID: [16]byte{2}, Priority: 100, Universe: 1, Sequence: 1}))
+
+	assert.Equal(t, []uint16{1}, tracker.Sweep())
+	assert.Equal(t, 1, tracker.SourceCount(1))
+	assert.Empty(t, tracker.Sweep())
+}
+
+func TestSACNBridge_HandleDataPacket(t *testing.T) {
+	bridge, ws, _ := newTestSACNBridge(&config.SACN{})
+
+	received := sacnReceived(t, &sacn.DataPacket{CID: [16]byte{1}, SourceName: "Console", Priority: 120, Sequence: 9, Universe: 40000, Data: []byte{1, 2, 3}})
+	require.NoError(t, bridge.HandlePacket(received))
+	// 代替START Codeのパケットは配信しない
+	received = sacnReceived(t, &sacn.DataPacket{CID: [16]byte{1}, Priority: 120, Sequence: 10, Universe: 40000, StartCode: 0x17, Data: []byte{100}})
+	require.NoError(t, bridge.HandlePacket(received))
+
+	assert.Len(t, ws.Messages("sacn/dmx_merged"), 1)
+	messages := ws.Messages("artnet/dmx_packet")
+	require.Len(t, messages, 1)
+	assert.Equal(t, messages, ws.Messages("sacn/dmx/40000"))
+	dmx := messages[0].Data.(*model.DMXFrame)
+	assert.Equal(t, model.ProtocolSACN, dmx.Protocol)
+	assert.Equal(t, uint16(40000), dmx.Universe)
+	assert.Equal(t, uint8(120), dmx.Priority)
+	assert.Equal(t, "Console", dmx.SourceName)
+	assert.Equal(t, uint16(3), dmx.Length)
+	assert.Equal(t, []byte{1, 2, 3}, dmx.Data[:3])
+
+	payload, err := json.Marshal(dmx)
+	require.NoError(t, err)
+	assert.Contains(t, string(payload), `"Protocol":"sacn"`)
+}
+
+func TestSACNBridge_JoinsDiscoveredUniverses(t *testing.T) {
+	discovery := &sacn.DiscoveryPacket{CID: [16]byte{1}, Universes: []uint16{1, 5}}
+
+	bridge, ws, joiner := newTestSACNBridge(&config.SACN{JoinDiscovered: true})
+	require.NoError(t, bridge.HandlePacket(sacnReceived(t, discovery)))
+	assert.Equal(t, []uint16{1, 5}, joiner.joined)
+	assert.Len(t, ws.Messages("sacn/sources"), 1)
+
+	bridge, _, joiner = newTestSACNBridge(&config.SACN{JoinDiscovered: false})
+	require.NoError(t, bridge.HandlePacket(sacnReceived(t, discovery)))
+	assert.Empty(t, joiner.joined)
+}
+
+func TestSACNBridge_PerAddressPriority(t *testing.T) {
+	bridge, ws, _ := newTestSACNBridge(&config.SACN{})
+	primary := [16]byte{1}
+	backup := [16]byte{2}
+
+	// バックアップ卓はユニバースの優先度が高いが、アドレスごとの優先度で1chのみを出力する
+	require.NoError(t, bridge.HandlePacket(sacnReceived(t, &sacn.DataPacket{CID: primary, SourceName: "Primary", Priority: 100, Sequence: 1, Universe: 1, Data: []byte{10, 20, 30}})))
+	require.NoError(t, bridge.HandlePacket(sacnReceived(t, &sacn.DataPacket{CID: backup, SourceName: "Backup", Priority: 150, Sequence: 1, Universe: 1, StartCode: sacn.StartCodePerAddressPriority, Data: []byte{150, 0, 0}})))
+	require.NoError(t, bridge.HandlePacket(sacnReceived(t, &sacn.DataPacket{CID: backup, SourceName: "Backup", Priority: 150, Sequence: 2, Universe: 1, Data: []byte{1, 2, 3}})))
+
+	packets := ws.Messages("artnet/dmx_packet")
+	require.Len(t, packets, 2)
+	backupFrame := packets[1].Data.(*model.DMXFrame)
+	require.NotNil(t, backupFrame.ChannelPriority)
+	assert.Equal(t, uint8(150), backupFrame.ChannelPriority[0])
+
+	messages := ws.Messages("sacn/dmx_merged")
+	merged := messages[len(messages)-1].Data.(*model.SACNMergedFrame)
+	require.Len(t, merged.Sources, 2)
+	assert.Equal(t, "Primary", merged.Sources[0].SourceName)
+	assert.True(t, merged.Sources[1].PerAddressPriority)
+	assert.Equal(t, []uint8{1, 20, 30}, merged.Data[:3])
+	assert.Equal(t, []int16{1, 0, 0, -1}, merged.Winners[:4])
+	assert.Equal(t, []uint8{150, 100, 100, 0}, merged.Priorities[:4])
+
+	// 送信終了後は残った送信元のみでマージする
+	require.NoError(t, bridge.HandlePacket(sacnReceived(t, &sacn.DataPacket{CID: backup, Priority: 150, Sequence: 3, Universe: 1, Options: sacn.OptionStreamTerminated})))
+	messages = ws.Messages("sacn/dmx_merged")
+	merged = messages[len(messages)-1].Data.(*model.SACNMergedFrame)
+	require.Len(t, merged.Sources, 1)
+	assert.Equal(t, []uint8{10, 20, 30}, merged.Data[:3])
+}
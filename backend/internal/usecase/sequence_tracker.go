@@ -0,0 +1,116 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/nasshu2916/dmx_viewer/internal/domain/model"
+	"github.com/nasshu2916/dmx_viewer/pkg/logger"
+)
+
+const (
+	// SequenceStatsInterval シーケンス統計を配信する間隔
+	SequenceStatsInterval = 1 * time.Second
+	// SequenceStatsIdleTimeout この時間受信のない送信元・ユニバースは統計から削除する
+	SequenceStatsIdleTimeout = 10 * time.Minute
+)
+
+// SequenceTracker 送信元・ユニバースごとにArtDMXのシーケンス番号を追跡する
+type SequenceTracker struct {
+	mu        sync.Mutex
+	stats     map[string]*model.SequenceStats // "送信元IP/ユニバース" をキーとする
+	updated   bool                            // 前回の配信以降に更新があったか
+	wsUseCase WebSocketUseCase
+	logger    *logger.Logger
+	now       func() time.Time
+}
+
+// NewSequenceTracker SequenceTrackerの新しいインスタンスを作成
+func NewSequenceTracker(wsUseCase WebSocketUseCase, logger *logger.Logger) *SequenceTracker {
+	return &SequenceTracker{
+		stats:     make(map[string]*model.SequenceStats),
+		wsUseCase: wsUseCase,
+		logger:    logger,
+		now:       time.Now,
+	}
+}
+
+// Observe 受信したDMXデータのシーケンス番号を統計に反映する
+func (t *SequenceTracker) Observe(dmx *model.DMXFrame) {
+	sourceIP := dmx.SourceIP.String()
+	universe := dmx.Universe
+	key := fmt.Sprintf("%s/%d", sourceIP, universe)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	stats, ok := t.stats[key]
+	if !ok {
+		stats = &model.SequenceStats{SourceIP: sourceIP, Universe: universe}
+		t.stats[key] = stats
+	}
+	stats.Observe(dmx.Sequence, t.now())
+	t.updated = true
+}
+
+// Snapshot 送信元IP・ユニバース順に並べた統計のコピーを返す
+func (t *SequenceTracker) Snapshot() []model.SequenceStats {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.snapshotLocked()
+}
+
+func (t *SequenceTracker) snapshotLocked() []model.SequenceStats {
+	result := make([]model.SequenceStats, 0, len(t.stats))
+	for _, stats := range t.stats {
+		result = append(result, *stats)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].SourceIP != result[j].SourceIP {
+			return result[i].SourceIP < result[j].SourceIP
+		}
+		return result[i].Universe < result[j].Universe
+	})
+	return result
+}
+
+// StartStatsBroadcast 一定間隔で統計を artnet/stats トピックに配信する（更新がない場合は配信しない）
+func (t *SequenceTracker) StartStatsBroadcast(ctx context.Context) {
+	ticker := time.NewTicker(SequenceStatsInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := t.broadcastStats(); err != nil {
+				t.logger.Debug("Failed to broadcast sequence stats", "error", err)
+			}
+		case <-ctx.Done():
+			t.logger.Info("Sequence stats broadcast stopped.")
+			return
+		}
+	}
+}
+
+func (t *SequenceTracker) broadcastStats() error {
+	t.mu.Lock()
+	now := t.now()
+	for key, stats := range t.stats {
+		if now.Sub(stats.LastSeen) >= SequenceStatsIdleTimeout {
+			delete(t.stats, key)
+			t.updated = true
+		}
+	}
+	if !t.updated {
+		t.mu.Unlock()
+		return nil
+	}
+	t.updated = false
+	snapshot := t.snapshotLocked()
+	t.mu.Unlock()
+
+	msg := model.NewWebSocketMessage("artnet_sequence_stats", snapshot)
+	return t.wsUseCase.BroadcastToTopic("artnet/stats", msg)
+}
@@ -0,0 +1,64 @@
+package usecase
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/nasshu2916/dmx_viewer/internal/domain/model"
+	"github.com/nasshu2916/dmx_viewer/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSequenceTracker_PerSourceAndUniverse(t *testing.T) {
+	ws := newFakeWebSocketUseCase()
+	tracker := NewSequenceTracker(ws, logger.NewLogger("fatal"))
+
+	srcA := net.IPv4(2, 0, 0, 10)
+	srcB := net.IPv4(2, 0, 0, 11)
+	for _, seq := range []uint8{1, 2, 4} {
+		tracker.Observe(&model.DMXFrame{Sequence: seq, Universe: 1, SourceIP: srcA})
+	}
+	for _, seq := range []uint8{10, 11, 12} {
+		tracker.Observe(&model.DMXFrame{Sequence: seq, Universe: 2, SourceIP: srcA})
+	}
+	for _, seq := range []uint8{1, 1} {
+		tracker.Observe(&model.DMXFrame{Sequence: seq, Universe: 1, SourceIP: srcB})
+	}
+
+	snapshot := tracker.Snapshot()
+	require.Len(t, snapshot, 3)
+	assert.Equal(t, "2.0.0.10", snapshot[0].SourceIP)
+	assert.Equal(t, uint16(1), snapshot[0].Universe)
+	assert.Equal(t, uint64(1), snapshot[0].Lost)
+	assert.Equal(t, uint16(2), snapshot[1].Universe)
+	assert.Equal(t, uint64(0), snapshot[1].Lost)
+	assert.Equal(t, "2.0.0.11", snapshot[2].SourceIP)
+	assert.Equal(t, uint64(1), snapshot[2].Duplicates)
+
+	// 更新があった場合のみ配信する
+	require.NoError(t, tracker.broadcastStats())
+	require.NoError(t, tracker.broadcastStats())
+	messages := ws.Messages("artnet/stats")
+	require.Len(t, messages, 1)
+	assert.Equal(t, "artnet_sequence_stats", messages[0].Type)
+}
+
+func TestSequenceTracker_RemovesIdleSources(t *testing.T) {
+	ws := newFakeWebSocketUseCase()
+	tracker := NewSequenceTracker(ws, logger.NewLogger("fatal"))
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tracker.now = func() time.Time { return start }
+
+	tracker.Observe(&model.DMXFrame{Sequence: 1, SourceIP: net.IPv4(2, 0, 0, 10)})
+	require.NoError(t, tracker.broadcastStats())
+
+	tracker.now = func() time.Time { return start.Add(SequenceStatsIdleTimeout) }
+	require.NoError(t, tracker.broadcastStats())
+	assert.Empty(t, tracker.Snapshot())
+
+	messages := ws.Messages("artnet/stats")
+	require.Len(t, messages, 2)
+	assert.Empty(t, messages[1].Data)
+}
@@ -0,0 +1,286 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/nasshu2916/dmx_viewer/internal/domain/model"
+	"github.com/nasshu2916/dmx_viewer/internal/domain/repository"
+	"github.com/nasshu2916/dmx_viewer/pkg/logger"
+)
+
+// SnapshotDriftInterval 固定したスナップショットと現在の出力の差分を確認する間隔
+const SnapshotDriftInterval = 1 * time.Second
+
+var (
+	ErrInvalidSnapshotName = errors.New("invalid snapshot name")
+	ErrSnapshotExists      = errors.New("snapshot already exists")
+	ErrSnapshotNotFound    = errors.New("snapshot not found")
+	ErrNoPinnedSnapshot    = errors.New("no snapshot is pinned")
+)
+
+// SnapshotUseCase 名前を付けたスナップショットの保存・比較を行うインターフェース
+type SnapshotUseCase interface {
+	// 現在の出力を名前を付けて保存する
+	Create(name string) (model.SnapshotInfo, error)
+	// 保存済みのスナップショットの一覧
+	List() ([]model.SnapshotInfo, error)
+	// スナップショットを取得する
+	Get(name string) (*model.Snapshot, error)
+	// スナップショットを削除する（固定している場合は固定を解除する）
+	Delete(name string) error
+	// 2つのスナップショットを比較する（to が "live" の場合は現在の出力と比較する）
+	Diff(from, to string) (model.SnapshotDiff, error)
+	// スナップショットを固定し、現在の出力との差分を snapshot/drift トピックに配信する
+	Pin(name string) (model.SnapshotDiff, error)
+	// スナップショットの固定を解除する
+	Unpin() error
+	// 固定したスナップショットと現在の出力の差分
+	Drift() (model.SnapshotDiff, error)
+}
+
+// LiveUniverses UniverseObserver としてマージしたユニバースの出力を受け取り、現在の出力として保持する
+type LiveUniverses struct {
+	mu        sync.Mutex
+	universes map[model.UniverseKey]*[512]uint8
+}
+
+// NewLiveUniverses LiveUniversesの新しいインスタンスを作成
+func NewLiveUniverses() *LiveUniverses {
+	return &LiveUniverses{universes: make(map[model.UniverseKey]*[512]uint8)}
+}
+
+// ObserveUniverse 現在の出力を更新する
+func (l *LiveUniverses) ObserveUniverse(protocol string, universe uint16, data *[512]uint8) {
+	key := model.UniverseKey{Protocol: protocol, Universe: universe}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	current, ok := l.universes[key]
+	if !ok {
+		current = new([512]uint8)
+		l.universes[key] = current
+	}
+	*current = *data
+}
+
+// Universes 受信したすべてのユニバースの現在の出力（順不同）
+func (l *LiveUniverses) Universes() []model.SnapshotUniverse {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	universes := make([]model.SnapshotUniverse, 0, len(l.universes))
+	for key, data := range l.universes {
+		universes = append(universes, model.SnapshotUniverse{Protocol: key.Protocol, Universe: key.Universe, Data: *data})
+	}
+	return universes
+}
+
+// SnapshotUseCaseImpl SnapshotUseCaseの実装
+type SnapshotUseCaseImpl struct {
+	mu          sync.Mutex
+	repo        repository.SnapshotRepository
+	live        *LiveUniverses
+	merge       MergeUseCase
+	sacnSources SACNSourceDirectory
+	nodes       NodeLivenessUseCase
+	wsUseCase   WebSocketUseCase
+	pinned      *model.Snapshot
+	lastDrift   *model.SnapshotDiff // 最後に配信した差分（変化がない間は配信しない）
+	logger      *logger.Logger
+	now         func() time.Time
+}
+
+// NewSnapshotUseCaseImpl SnapshotUseCaseの新しいインスタンスを作成
+func NewSnapshotUseCaseImpl(repo repository.SnapshotRepository, live *LiveUniverses, merge MergeUseCase, sacnSources SACNSourceDirectory, nodes NodeLivenessUseCase, wsUseCase WebSocketUseCase, logger *logger.Logger) *SnapshotUseCaseImpl {
+	return &SnapshotUseCaseImpl{
+		repo:        repo,
+		live:        live,
+		merge:       merge,
+		sacnSources: sacnSources,
+		nodes:       nodes,
+		wsUseCase:   wsUseCase,
+		logger:      logger,
+		now:         time.Now,
+	}
+}
+
+func (uc *SnapshotUseCaseImpl) Create(name string) (model.SnapshotInfo, error) {
+	if err := model.ValidateSnapshotName(name); err != nil {
+		return model.SnapshotInfo{}, fmt.Errorf("%w: %v", ErrInvalidSnapshotName, err)
+	}
+	snapshot := uc.liveSnapshot()
+	snapshot.Name = name
+	err := uc.repo.Create(snapshot)
+	if errors.Is(err, fs.ErrExist) {
+		return model.SnapshotInfo{}, fmt.Errorf("%w: %s", ErrSnapshotExists, name)
+	}
+	if err != nil {
+		return model.SnapshotInfo{}, err
+	}
+	uc.logger.Info("Snapshot saved", "name", name, "universes", len(snapshot.Universes))
+	return model.SnapshotInfo{Name: name, CreatedAt: snapshot.CreatedAt, Universes: len(snapshot.Universes)}, nil
+}
+
+func (uc *SnapshotUseCaseImpl) List() ([]model.SnapshotInfo, error) {
+	snapshots, err := uc.repo.List()
+	if err != nil {
+		return nil, err
+	}
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	for i := range snapshots {
+		snapshots[i].Pinned = uc.pinned != nil && uc.pinned.Name == snapshots[i].Name
+	}
+	return snapshots, nil
+}
+
+func (uc *SnapshotUseCaseImpl) Get(name string) (*model.Snapshot, error) {
+	snapshot, err := uc.repo.Get(name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrSnapshotNotFound, name)
+	}
+	return snapshot, err
+}
+
+func (uc *SnapshotUseCaseImpl) Delete(name string) error {
+	err := uc.repo.Delete(name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("%w: %s", ErrSnapshotNotFound, name)
+	}
+	if err != nil {
+		return err
+	}
+
+	uc.mu.Lock()
+	unpinned := uc.pinned != nil && uc.pinned.Name == name
+	if unpinned {
+		uc.unpinLocked()
+	}
+	uc.mu.Unlock()
+	if unpinned {
+		uc.broadcastDrift(model.SnapshotDiff{To: model.SnapshotLiveName})
+	}
+	return nil
+}
+
+func (uc *SnapshotUseCaseImpl) Diff(from, to string) (model.SnapshotDiff, error) {
+	fromSnapshot, err := uc.Get(from)
+	if err != nil {
+		return model.SnapshotDiff{}, err
+	}
+	var toSnapshot *model.Snapshot
+	if to == "" || to == model.SnapshotLiveName {
+		toSnapshot = uc.liveSnapshot()
+	} else if toSnapshot, err = uc.Get(to); err != nil {
+		return model.SnapshotDiff{}, err
+	}
+	return model.DiffSnapshots(fromSnapshot, toSnapshot), nil
+}
+
+func (uc *SnapshotUseCaseImpl) Pin(name string) (model.SnapshotDiff, error) {
+	snapshot, err := uc.Get(name)
+	if err != nil {
+		return model.SnapshotDiff{}, err
+	}
+
+	uc.mu.Lock()
+	uc.pinned = snapshot
+	uc.lastDrift = nil
+	uc.mu.Unlock()
+	uc.logger.Info("Snapshot pinned", "name", name)
+
+	return uc.publishDrift(), nil
+}
+
+func (uc *SnapshotUseCaseImpl) Unpin() error {
+	uc.mu.Lock()
+	if uc.pinned == nil {
+		uc.mu.Unlock()
+		return ErrNoPinnedSnapshot
+	}
+	uc.unpinLocked()
+	uc.mu.Unlock()
+
+	// 固定を解除したことを From が空の差分で通知する
+	uc.broadcastDrift(model.SnapshotDiff{To: model.SnapshotLiveName})
+	return nil
+}
+
+func (uc *SnapshotUseCaseImpl) Drift() (model.SnapshotDiff, error) {
+	uc.mu.Lock()
+	pinned := uc.pinned
+	uc.mu.Unlock()
+	if pinned == nil {
+		return model.SnapshotDiff{}, ErrNoPinnedSnapshot
+	}
+	return model.DiffSnapshots(pinned, uc.liveSnapshot()), nil
+}
+
+// StartDriftPublisher 一定間隔で固定したスナップショットと現在の出力の差分を確認し、変化した場合に配信する
+func (uc *SnapshotUseCaseImpl) StartDriftPublisher(ctx context.Context) {
+	ticker := time.NewTicker(SnapshotDriftInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			uc.publishDrift()
+		}
+	}
+}
+
+// publishDrift 差分が前回の配信から変化していれば snapshot/drift トピックに配信する
+func (uc *SnapshotUseCaseImpl) publishDrift() model.SnapshotDiff {
+	uc.mu.Lock()
+	pinned := uc.pinned
+	uc.mu.Unlock()
+	if pinned == nil {
+		return model.SnapshotDiff{}
+	}
+	diff := model.DiffSnapshots(pinned, uc.liveSnapshot())
+
+	uc.mu.Lock()
+	if uc.pinned != pinned || (uc.lastDrift != nil && reflect.DeepEqual(*uc.lastDrift, diff)) {
+		uc.mu.Unlock()
+		return diff
+	}
+	uc.lastDrift = &diff
+	uc.mu.Unlock()
+
+	uc.broadcastDrift(diff)
+	return diff
+}
+
+func (uc *SnapshotUseCaseImpl) broadcastDrift(diff model.SnapshotDiff) {
+	msg := model.NewWebSocketMessage("snapshot_drift", diff)
+	if err := uc.wsUseCase.BroadcastToTopic("snapshot/drift", msg); err != nil {
+		uc.logger.Debug("Failed to broadcast snapshot drift", "error", err)
+	}
+}
+
+func (uc *SnapshotUseCaseImpl) unpinLocked() {
+	uc.logger.Info("Snapshot unpinned", "name", uc.pinned.Name)
+	uc.pinned = nil
+	uc.lastDrift = nil
+}
+
+// liveSnapshot 現在の出力と送信元・ノードの一覧からスナップショットを作成する
+func (uc *SnapshotUseCaseImpl) liveSnapshot() *model.Snapshot {
+	snapshot := &model.Snapshot{
+		Name:          model.SnapshotLiveName,
+		CreatedAt:     uc.now(),
+		ArtNetSources: uc.merge.GetMergeStates(),
+		SACNSources:   uc.sacnSources.GetSources(),
+		Nodes:         uc.nodes.GetNodes(),
+		Universes:     uc.live.Universes(),
+	}
+	snapshot.SortUniverses()
+	return snapshot
+}
@@ -0,0 +1,166 @@
+package usecase
+
+import (
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nasshu2916/dmx_viewer/internal/domain/model"
+	"github.com/nasshu2916/dmx_viewer/internal/infrastructure"
+	"github.com/nasshu2916/dmx_viewer/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestSnapshotUseCase(t *testing.T) (*SnapshotUseCaseImpl, *UniverseMerger, *fakeWebSocketUseCase) {
+	t.Helper()
+	l := logger.NewLogger("fatal")
+	ws := newFakeWebSocketUseCase()
+	live := NewLiveUniverses()
+	merger := NewUniverseMerger(model.MergeModeHTP, ws, live, l)
+	uc := NewSnapshotUseCaseImpl(
+		infrastructure.NewSnapshotRepository(t.TempDir()),
+		live,
+		merger,
+		NewSACNSourceDirectoryImpl(infrastructure.NewSACNSourceRepository(), ws, time.Minute, l),
+		NewNodeLivenessUseCaseImpl(infrastructure.NewArtNetNodeRepository(), ws, model.NewNodeLivenessPolicy(time.Second, time.Minute), l),
+		ws,
+		l,
+	)
+	// 作成日時で並べるため、呼ばれるたびに1秒進める
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	uc.now = func() time.Time {
+		now = now.Add(time.Second)
+		return now
+	}
+	return uc, merger, ws
+}
+
+func pushMergerFrame(t *testing.T, merger *UniverseMerger, universe uint16, values ...uint8) {
+	t.Helper()
+	frame := &model.DMXFrame{Protocol: model.ProtocolArtNet, Universe: universe, SourceIP: net.IPv4(2, 0, 0, 10), Length: 512}
+	copy(frame.Data[:], values)
+	require.NoError(t, merger.Push(frame))
+}
+
+func TestSnapshotUseCase_CreateAndDiff(t *testing.T) {
+	uc, merger, _ := newTestSnapshotUseCase(t)
+
+	pushMergerFrame(t, merger, 1, 255, 128)
+	info, err := uc.Create("cue 12")
+	require.NoError(t, err)
+	assert.Equal(t, 1, info.Universes)
+	_, err = uc.Create("cue 12")
+	assert.ErrorIs(t, err, ErrSnapshotExists)
+	_, err = uc.Create("../cue")
+	assert.ErrorIs(t, err, ErrInvalidSnapshotName)
+
+	snapshot, err := uc.Get("cue 12")
+	require.NoError(t, err)
+	require.Len(t, snapshot.ArtNetSources, 1)
+	assert.Equal(t, []string{"2.0.0.10"}, snapshot.ArtNetSources[0].Sources)
+	assert.Equal(t, uint8(128), snapshot.Universes[0].Data[1])
+
+	pushMergerFrame(t, merger, 1, 255, 0)
+	uc.live.ObserveUniverse(model.ProtocolSACN, 3, &[512]uint8{9})
+	_, err = uc.Create("cue 13")
+	require.NoError(t, err)
+
+	diff, err := uc.Diff("cue 12", "cue 13")
+	require.NoError(t, err)
+	assert.Equal(t, []model.ChannelDiff{
+		{Protocol: model.ProtocolArtNet, Universe: 1, Channel: 2, From: 128, To: 0},
+		{Protocol: model.ProtocolSACN, Universe: 3, Channel: 1, From: 0, To: 9},
+	}, diff.Changes)
+	assert.Equal(t, []model.UniverseKey{{Protocol: model.ProtocolSACN, Universe: 3}}, diff.OnlyInTo)
+
+	pushMergerFrame(t, merger, 1, 0, 0)
+	diff, err = uc.Diff("cue 13", "")
+	require.NoError(t, err)
+	assert.Equal(t, model.SnapshotLiveName, diff.To)
+	assert.Equal(t, []model.ChannelDiff{{Protocol: model.ProtocolArtNet, Universe: 1, Channel: 1, From: 255, To: 0}}, diff.Changes)
+
+	_, err = uc.Diff("cue 99", "")
+	assert.ErrorIs(t, err, ErrSnapshotNotFound)
+
+	snapshots, err := uc.List()
+	require.NoError(t, err)
+	require.Len(t, snapshots, 2)
+	assert.Equal(t, "cue 12", snapshots[0].Name)
+
+	require.NoError(t, uc.Delete("cue 12"))
+	assert.ErrorIs(t, uc.Delete("cue 12"), ErrSnapshotNotFound)
+}
+
+func TestSnapshotUseCase_ConcurrentCreateDoesNotOverwrite(t *testing.T) {
+	uc, _, _ := newTestSnapshotUseCase(t)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	uc.now = func() time.Time { return now }
+
+	const n = 8
+	errs := make(chan error, n)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := uc.Create("cue 1")
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	// 同じ名前で同時に作成しても1件のみ成功し、残りは既存として失敗する
+	created := 0
+	for err := range errs {
+		if err == nil {
+			created++
+			continue
+		}
+		assert.ErrorIs(t, err, ErrSnapshotExists)
+	}
+	assert.Equal(t, 1, created)
+}
+
+func TestSnapshotUseCase_Drift(t *testing.T) {
+	uc, merger, ws := newTestSnapshotUseCase(t)
+
+	_, err := uc.Drift()
+	assert.ErrorIs(t, err, ErrNoPinnedSnapshot)
+	assert.ErrorIs(t, uc.Unpin(), ErrNoPinnedSnapshot)
+
+	pushMergerFrame(t, merger, 0, 100)
+	_, err = uc.Create("base")
+	require.NoError(t, err)
+
+	diff, err := uc.Pin("base")
+	require.NoError(t, err)
+	assert.Empty(t, diff.Changes)
+	require.Len(t, ws.Messages("snapshot/drift"), 1)
+
+	// 変化がない間は配信しない
+	uc.publishDrift()
+	assert.Len(t, ws.Messages("snapshot/drift"), 1)
+
+	pushMergerFrame(t, merger, 0, 90)
+	uc.publishDrift()
+	messages := ws.Messages("snapshot/drift")
+	require.Len(t, messages, 2)
+	drift := messages[1].Data.(model.SnapshotDiff)
+	assert.Equal(t, "base", drift.From)
+	assert.Equal(t, []model.ChannelDiff{{Protocol: model.ProtocolArtNet, Universe: 0, Channel: 1, From: 100, To: 90}}, drift.Changes)
+
+	snapshots, err := uc.List()
+	require.NoError(t, err)
+	assert.True(t, snapshots[0].Pinned)
+
+	// 固定したスナップショットを削除すると固定を解除する
+	require.NoError(t, uc.Delete("base"))
+	messages = ws.Messages("snapshot/drift")
+	require.Len(t, messages, 3)
+	assert.Empty(t, messages[2].Data.(model.SnapshotDiff).From)
+	_, err = uc.Drift()
+	assert.ErrorIs(t, err, ErrNoPinnedSnapshot)
+}
@@ -0,0 +1,26 @@
+package usecase
+
+import (
+	"github.com/nasshu2916/dmx_viewer/internal/domain/model"
+	"github.com/nasshu2916/dmx_viewer/internal/domain/repository"
+)
+
+// TimeCodeUseCase 受信したタイムコードの参照を提供するインターフェース
+type TimeCodeUseCase interface {
+	// 最後に受信したタイムコードを取得する（未受信の場合はfalse）
+	GetCurrentTimeCode() (*model.TimeCode, bool)
+}
+
+// TimeCodeUseCaseImpl TimeCodeUseCaseの実装
+type TimeCodeUseCaseImpl struct {
+	timeCodeRepo repository.TimeCodeRepository
+}
+
+// NewTimeCodeUseCaseImpl TimeCodeUseCaseの新しいインスタンスを作成
+func NewTimeCodeUseCaseImpl(timeCodeRepo repository.TimeCodeRepository) *TimeCodeUseCaseImpl {
+	return &TimeCodeUseCaseImpl{timeCodeRepo: timeCodeRepo}
+}
+
+func (u *TimeCodeUseCaseImpl) GetCurrentTimeCode() (*model.TimeCode, bool) {
+	return u.timeCodeRepo.Latest()
+}
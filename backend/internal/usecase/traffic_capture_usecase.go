@@ -0,0 +1,382 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/jsimonetti/go-artnet/packet/code"
+	"github.com/nasshu2916/dmx_viewer/internal/domain/model"
+	"github.com/nasshu2916/dmx_viewer/internal/domain/repository"
+	"github.com/nasshu2916/dmx_viewer/internal/infrastructure/artnet"
+	"github.com/nasshu2916/dmx_viewer/pkg/logger"
+)
+
+var (
+	ErrAlreadyCapturingTraffic      = errors.New("traffic capture is already running")
+	ErrNotCapturingTraffic          = errors.New("traffic capture is not running")
+	ErrInvalidTrafficCaptureOptions = errors.New("invalid traffic capture options")
+	ErrTrafficCaptureNotFound       = errors.New("pcap file not found")
+)
+
+// TrafficCaptureUseCase 受信したパケットの pcap ファイルへの書き出しを開始・停止するインターフェース
+type TrafficCaptureUseCase interface {
+	// 書き出しを開始する
+	Start(opts model.TrafficCaptureOptions) (model.TrafficCaptureStatus, error)
+	// 書き出しを停止する
+	Stop() (model.TrafficCaptureStatus, error)
+	// 書き出しの状態を取得する
+	Status() model.TrafficCaptureStatus
+	// 書き出した pcap ファイルの一覧
+	Files() ([]model.CaptureFile, error)
+	// 書き出した pcap ファイルの情報を取得する
+	File(name string) (model.CaptureFile, error)
+}
+
+// trafficFilter 解析済みの書き出すパケットの条件
+type trafficFilter struct {
+	sources   []net.IP
+	opCodes   map[code.OpCode]struct{}
+	universes map[uint16]struct{}
+}
+
+// newTrafficFilter 条件を解析する
+func newTrafficFilter(f model.TrafficCaptureFilter) (trafficFilter, error) {
+	var filter trafficFilter
+	for _, s := range f.SourceIPs {
+		ip := net.ParseIP(s)
+		if ip == nil {
+			return filter, fmt.Errorf("invalid source IP %q", s)
+		}
+		filter.sources = append(filter.sources, ip)
+	}
+	if len(f.OpCodes) > 0 {
+		filter.opCodes = make(map[code.OpCode]struct{}, len(f.OpCodes))
+		for _, s := range f.OpCodes {
+			op, err := artnet.ParseOpCode(s)
+			if err != nil {
+				return filter, err
+			}
+			filter.opCodes[op] = struct{}{}
+		}
+	}
+	if f.Universes != "" {
+		universes, err := model.ParseUniverseList(f.Universes)
+		if err != nil {
+			return filter, err
+		}
+		filter.universes = make(map[uint16]struct{}, len(universes))
+		for _, u := range universes {
+			filter.universes[u] = struct{}{}
+		}
+	}
+	return filter, nil
+}
+
+// match データグラムが条件に一致するか
+func (f trafficFilter) match(datagram *model.CapturedDatagram) bool {
+	if len(f.sources) > 0 {
+		found := false
+		for _, ip := range f.sources {
+			if ip.Equal(datagram.Src.IP) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if f.opCodes == nil && f.universes == nil {
+		return true
+	}
+
+	header, ok := artnet.PeekHeader(datagram.Payload)
+	if !ok {
+		return false
+	}
+	if f.opCodes != nil {
+		if _, ok := f.opCodes[header.OpCode]; !ok {
+			return false
+		}
+	}
+	if f.universes != nil {
+		if !header.HasUniverse {
+			return false
+		}
+		if _, ok := f.universes[header.Universe]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// trafficCaptureQueueSize 受信処理から書き出し処理に渡すデータグラムのキューの長さ
+const trafficCaptureQueueSize = 4096
+
+// queuedDatagram 書き出しを待つデータグラム
+type queuedDatagram struct {
+	generation uint64 // 書き出しを開始するごとに増やし、停止した書き出しのデータグラムを破棄する
+	datagram   *model.CapturedDatagram
+}
+
+// TrafficCaptureUseCaseImpl TrafficCaptureUseCaseの実装
+// artnet.DatagramObserver として受信したデータグラムを受け取り、書き出し中であれば条件に一致するものを書き出す
+//
+// 受信処理を止めないよう、ファイルへの書き込みは StartFlusher のゴルーチンで行う。
+// ioMu はファイルの操作を、mu は状態を保護する（ioMu → mu の順に取得する）。
+type TrafficCaptureUseCaseImpl struct {
+	ioMu        sync.Mutex
+	repo        repository.TrafficCaptureRepository
+	netProvider NetworkInterfaceProvider // MACアドレスを決めるためのブロードキャストアドレスを取得する
+	open        bool                     // ファイルを作成してから閉じるまで
+
+	mu         sync.Mutex
+	queue      chan queuedDatagram
+	generation uint64
+	capturing  bool
+	opts       model.TrafficCaptureOptions
+	filter     trafficFilter
+	startedAt  time.Time
+	stopsAt    time.Time
+	packets    uint64
+	filtered   uint64
+	dropped    uint64
+	file       *model.CaptureFile
+	lastError  string
+	logger     *logger.Logger
+	now        func() time.Time
+}
+
+// NewTrafficCaptureUseCaseImpl TrafficCaptureUseCaseの新しいインスタンスを作成
+func NewTrafficCaptureUseCaseImpl(repo repository.TrafficCaptureRepository, netProvider NetworkInterfaceProvider, logger *logger.Logger) *TrafficCaptureUseCaseImpl {
+	return &TrafficCaptureUseCaseImpl{
+		repo:        repo,
+		netProvider: netProvider,
+		queue:       make(chan queuedDatagram, trafficCaptureQueueSize),
+		logger:      logger,
+		now:         time.Now,
+	}
+}
+
+func (uc *TrafficCaptureUseCaseImpl) Start(opts model.TrafficCaptureOptions) (model.TrafficCaptureStatus, error) {
+	if opts.MaxDuration == 0 {
+		opts.MaxDuration = model.DefaultTrafficCaptureDuration
+	}
+	if opts.MaxDuration < 0 || opts.MaxDuration > model.MaxTrafficCaptureDuration {
+		return model.TrafficCaptureStatus{}, fmt.Errorf("%w: max duration must be positive and at most %s", ErrInvalidTrafficCaptureOptions, model.MaxTrafficCaptureDuration)
+	}
+	filter, err := newTrafficFilter(opts.Filter)
+	if err != nil {
+		return model.TrafficCaptureStatus{}, fmt.Errorf("%w: %v", ErrInvalidTrafficCaptureOptions, err)
+	}
+
+	uc.ioMu.Lock()
+	defer uc.ioMu.Unlock()
+
+	uc.mu.Lock()
+	capturing := uc.capturing
+	status := uc.statusLocked()
+	uc.mu.Unlock()
+	if capturing {
+		return status, ErrAlreadyCapturingTraffic
+	}
+
+	var broadcast net.IP
+	if iface := uc.netProvider.LocalInterface(); iface != nil {
+		broadcast = iface.Broadcast
+	}
+	now := uc.now()
+	file, err := uc.repo.Create(now, broadcast)
+
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	if err != nil {
+		return uc.statusLocked(), err
+	}
+	uc.open = true
+	uc.generation++
+	uc.capturing = true
+	uc.opts = opts
+	uc.filter = filter
+	uc.startedAt = now
+	uc.stopsAt = now.Add(opts.MaxDuration)
+	uc.packets = 0
+	uc.filtered = 0
+	uc.dropped = 0
+	uc.file = &file
+	uc.lastError = ""
+	uc.logger.Info("Traffic capture started", "file", file.Path, "maxDuration", opts.MaxDuration)
+	return uc.statusLocked(), nil
+}
+
+func (uc *TrafficCaptureUseCaseImpl) Stop() (model.TrafficCaptureStatus, error) {
+	uc.ioMu.Lock()
+	defer uc.ioMu.Unlock()
+
+	uc.mu.Lock()
+	capturing := uc.capturing
+	status := uc.statusLocked()
+	uc.mu.Unlock()
+	if !capturing {
+		return status, ErrNotCapturingTraffic
+	}
+	err := uc.stop()
+	return uc.Status(), err
+}
+
+func (uc *TrafficCaptureUseCaseImpl) Status() model.TrafficCaptureStatus {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	return uc.statusLocked()
+}
+
+func (uc *TrafficCaptureUseCaseImpl) Files() ([]model.CaptureFile, error) {
+	return uc.repo.List()
+}
+
+func (uc *TrafficCaptureUseCaseImpl) File(name string) (model.CaptureFile, error) {
+	file, err := uc.repo.Get(name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return file, fmt.Errorf("%w: %s", ErrTrafficCaptureNotFound, name)
+	}
+	return file, err
+}
+
+// ObserveDatagram 書き出し中であれば条件に一致するデータグラムを書き出しのキューに追加する
+// キューに空きがない場合は書き出さずに破棄する
+func (uc *TrafficCaptureUseCaseImpl) ObserveDatagram(datagram *model.CapturedDatagram) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	// 最大の時間に達した書き出しは StartFlusher のゴルーチンで停止する
+	if !uc.capturing || !datagram.Timestamp.Before(uc.stopsAt) {
+		return
+	}
+	if !uc.filter.match(datagram) {
+		uc.filtered++
+		return
+	}
+	select {
+	case uc.queue <- queuedDatagram{generation: uc.generation, datagram: datagram}:
+		uc.packets++
+	default:
+		uc.dropped++
+	}
+}
+
+// StartFlusher キューのデータグラムを書き出し、一定間隔で書き出し中の pcap ファイルをディスクに書き出す
+// 最大の時間に達した書き出しを停止する
+func (uc *TrafficCaptureUseCaseImpl) StartFlusher(ctx context.Context) {
+	ticker := time.NewTicker(CaptureFlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case queued := <-uc.queue:
+			uc.ioMu.Lock()
+			uc.write(queued)
+			uc.ioMu.Unlock()
+		case <-ticker.C:
+			uc.flush()
+		}
+	}
+}
+
+// write データグラムを書き出す（ioMu を保持して呼ぶ）
+func (uc *TrafficCaptureUseCaseImpl) write(queued queuedDatagram) {
+	uc.mu.Lock()
+	current := queued.generation == uc.generation
+	uc.mu.Unlock()
+	if !uc.open || !current {
+		return
+	}
+	if err := uc.repo.Write(queued.datagram); err != nil {
+		uc.mu.Lock()
+		uc.lastError = err.Error()
+		uc.mu.Unlock()
+		uc.logger.Error("Failed to write pcap file", "error", err)
+	}
+}
+
+// drain キューに残っているデータグラムを書き出す（ioMu を保持して呼ぶ）
+func (uc *TrafficCaptureUseCaseImpl) drain() {
+	for {
+		select {
+		case queued := <-uc.queue:
+			uc.write(queued)
+		default:
+			return
+		}
+	}
+}
+
+func (uc *TrafficCaptureUseCaseImpl) flush() {
+	uc.ioMu.Lock()
+	defer uc.ioMu.Unlock()
+
+	uc.mu.Lock()
+	capturing, expired := uc.capturing, !uc.now().Before(uc.stopsAt)
+	uc.mu.Unlock()
+	if !capturing {
+		return
+	}
+	if expired {
+		if err := uc.stop(); err != nil {
+			uc.logger.Error("Failed to stop traffic capture", "error", err)
+		}
+		return
+	}
+	uc.drain()
+	if err := uc.repo.Flush(); err != nil {
+		uc.mu.Lock()
+		uc.lastError = err.Error()
+		uc.mu.Unlock()
+		uc.logger.Error("Failed to flush pcap file", "error", err)
+	}
+}
+
+// stop キューに残っているデータグラムを書き出してファイルを閉じる（ioMu を保持して呼ぶ）
+func (uc *TrafficCaptureUseCaseImpl) stop() error {
+	uc.mu.Lock()
+	uc.capturing = false
+	uc.mu.Unlock()
+
+	uc.drain()
+	file, err := uc.repo.Close()
+	uc.open = false
+
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	uc.file = &file
+	if err != nil {
+		uc.lastError = err.Error()
+		return err
+	}
+	uc.logger.Info("Traffic capture stopped", "file", file.Path, "packets", uc.packets, "dropped", uc.dropped)
+	return nil
+}
+
+func (uc *TrafficCaptureUseCaseImpl) statusLocked() model.TrafficCaptureStatus {
+	status := model.TrafficCaptureStatus{
+		Capturing: uc.capturing,
+		Filter:    uc.opts.Filter,
+		StartedAt: uc.startedAt,
+		StopsAt:   uc.stopsAt,
+		Packets:   uc.packets,
+		Filtered:  uc.filtered,
+		Dropped:   uc.dropped,
+		LastError: uc.lastError,
+	}
+	if uc.file != nil {
+		file := *uc.file
+		status.File = &file
+	}
+	return status
+}
@@ -0,0 +1,152 @@
+package usecase
+
+import (
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/jsimonetti/go-artnet/packet"
+	"github.com/nasshu2916/dmx_viewer/internal/domain/model"
+	"github.com/nasshu2916/dmx_viewer/internal/infrastructure"
+	"github.com/nasshu2916/dmx_viewer/internal/infrastructure/pcap"
+	"github.com/nasshu2916/dmx_viewer/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTrafficTestDatagram(t *testing.T, ts time.Time, src net.IP, p interface{ MarshalBinary() ([]byte, error) }) *model.CapturedDatagram {
+	t.Helper()
+	d := capturedDatagram(t, ts, 6454, 6454, p)
+	d.Src.IP = src
+	return d
+}
+
+func readPcapFile(t *testing.T, path string) []*model.CapturedDatagram {
+	t.Helper()
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+	r, err := pcap.NewReader(f)
+	require.NoError(t, err)
+	var datagrams []*model.CapturedDatagram
+	for {
+		d, err := r.Next()
+		if err != nil {
+			return datagrams
+		}
+		datagrams = append(datagrams, d)
+	}
+}
+
+func TestTrafficCaptureUseCase_Filter(t *testing.T) {
+	dir := t.TempDir()
+	uc := NewTrafficCaptureUseCaseImpl(infrastructure.NewTrafficCaptureRepository(dir), &fakeNetworkInterfaceProvider{}, logger.NewLogger("fatal"))
+	start := time.Now()
+	uc.now = func() time.Time { return start }
+
+	// 記録していない間は書き出さない
+	uc.ObserveDatagram(newTrafficTestDatagram(t, start, net.IPv4(2, 0, 0, 10), &packet.ArtDMXPacket{SubUni: 1}))
+	_, err := uc.Stop()
+	assert.ErrorIs(t, err, ErrNotCapturingTraffic)
+
+	status, err := uc.Start(model.TrafficCaptureOptions{Filter: model.TrafficCaptureFilter{
+		SourceIPs: []string{"2.0.0.10"},
+		OpCodes:   []string{"ArtDmx"},
+		Universes: "1-2",
+	}})
+	require.NoError(t, err)
+	assert.True(t, status.Capturing)
+	assert.Equal(t, start.Add(model.DefaultTrafficCaptureDuration), status.StopsAt)
+	_, err = uc.Start(model.TrafficCaptureOptions{})
+	assert.ErrorIs(t, err, ErrAlreadyCapturingTraffic)
+
+	src, other := net.IPv4(2, 0, 0, 10), net.IPv4(2, 0, 0, 11)
+	uc.ObserveDatagram(newTrafficTestDatagram(t, start, src, &packet.ArtDMXPacket{SubUni: 1, Length: 2}))
+	uc.ObserveDatagram(newTrafficTestDatagram(t, start, other, &packet.ArtDMXPacket{SubUni: 1, Length: 2})) // 送信元が異なる
+	uc.ObserveDatagram(newTrafficTestDatagram(t, start, src, &packet.ArtDMXPacket{SubUni: 3, Length: 2}))   // ユニバースが異なる
+	uc.ObserveDatagram(newTrafficTestDatagram(t, start, src, packet.NewArtPollPacket()))                    // オペコードが異なる
+	uc.ObserveDatagram(newTrafficTestDatagram(t, start, src, &packet.ArtDMXPacket{SubUni: 2, Length: 2}))
+
+	status, err = uc.Stop()
+	require.NoError(t, err)
+	assert.False(t, status.Capturing)
+	assert.Equal(t, uint64(2), status.Packets)
+	assert.Equal(t, uint64(3), status.Filtered)
+	require.NotNil(t, status.File)
+
+	datagrams := readPcapFile(t, status.File.Path)
+	require.Len(t, datagrams, 2)
+	assert.Equal(t, "2.0.0.10:6454", datagrams[0].Src.String())
+	assert.Equal(t, "2.255.255.255:6454", datagrams[0].Dst.String())
+
+	files, err := uc.Files()
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Equal(t, status.File.Name, files[0].Name)
+	file, err := uc.File(files[0].Name)
+	require.NoError(t, err)
+	assert.Equal(t, status.File.Size, file.Size)
+	_, err = uc.File("../secret.pcap")
+	assert.ErrorIs(t, err, ErrTrafficCaptureNotFound)
+}
+
+func TestTrafficCaptureUseCase_MaxDuration(t *testing.T) {
+	uc := NewTrafficCaptureUseCaseImpl(infrastructure.NewTrafficCaptureRepository(t.TempDir()), &fakeNetworkInterfaceProvider{}, logger.NewLogger("fatal"))
+	start := time.Now()
+	now := start
+	uc.now = func() time.Time { return now }
+
+	_, err := uc.Start(model.TrafficCaptureOptions{MaxDuration: 2 * time.Hour})
+	assert.ErrorIs(t, err, ErrInvalidTrafficCaptureOptions)
+	_, err = uc.Start(model.TrafficCaptureOptions{Filter: model.TrafficCaptureFilter{OpCodes: []string{"unknown"}}})
+	assert.ErrorIs(t, err, ErrInvalidTrafficCaptureOptions)
+	_, err = uc.Start(model.TrafficCaptureOptions{Filter: model.TrafficCaptureFilter{SourceIPs: []string{"host"}}})
+	assert.ErrorIs(t, err, ErrInvalidTrafficCaptureOptions)
+
+	_, err = uc.Start(model.TrafficCaptureOptions{MaxDuration: 10 * time.Second})
+	require.NoError(t, err)
+	uc.ObserveDatagram(newTrafficTestDatagram(t, start.Add(time.Second), net.IPv4(2, 0, 0, 10), packet.NewArtPollPacket()))
+
+	// 最大の時間に達したパケットは書き出さず、定期的な書き出しで停止する
+	uc.ObserveDatagram(newTrafficTestDatagram(t, start.Add(10*time.Second), net.IPv4(2, 0, 0, 10), packet.NewArtPollPacket()))
+	now = start.Add(10 * time.Second)
+	uc.flush()
+	status := uc.Status()
+	assert.False(t, status.Capturing)
+	assert.Equal(t, uint64(1), status.Packets)
+	require.NotNil(t, status.File)
+	assert.Len(t, readPcapFile(t, status.File.Path), 1)
+
+	// パケットを受信しない場合も定期的な書き出しで停止する
+	start = now.Add(time.Second)
+	now = start
+	_, err = uc.Start(model.TrafficCaptureOptions{MaxDuration: 10 * time.Second})
+	require.NoError(t, err)
+	now = start.Add(5 * time.Second)
+	uc.flush()
+	assert.True(t, uc.Status().Capturing)
+	now = start.Add(10 * time.Second)
+	uc.flush()
+	assert.False(t, uc.Status().Capturing)
+}
+
+func TestTrafficCaptureUseCase_DropsWhenQueueIsFull(t *testing.T) {
+	uc := NewTrafficCaptureUseCaseImpl(infrastructure.NewTrafficCaptureRepository(t.TempDir()), &fakeNetworkInterfaceProvider{}, logger.NewLogger("fatal"))
+	start := time.Now()
+	uc.now = func() time.Time { return start }
+
+	_, err := uc.Start(model.TrafficCaptureOptions{})
+	require.NoError(t, err)
+	for i := 0; i < trafficCaptureQueueSize+2; i++ {
+		uc.ObserveDatagram(newTrafficTestDatagram(t, start, net.IPv4(2, 0, 0, 10), packet.NewArtPollPacket()))
+	}
+	status := uc.Status()
+	assert.Equal(t, uint64(trafficCaptureQueueSize), status.Packets)
+	assert.Equal(t, uint64(2), status.Dropped)
+
+	// 停止するときにキューに残っているデータグラムを書き出す
+	status, err = uc.Stop()
+	require.NoError(t, err)
+	assert.Len(t, readPcapFile(t, status.File.Path), trafficCaptureQueueSize)
+}
@@ -0,0 +1,106 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/nasshu2916/dmx_viewer/internal/domain/model"
+	"github.com/nasshu2916/dmx_viewer/internal/domain/repository"
+)
+
+// HistoryPruneInterval 保持期間を過ぎた履歴を削除する間隔
+const HistoryPruneInterval = 1 * time.Second
+
+var ErrInvalidHistoryQuery = errors.New("invalid history query")
+
+// UniverseHistoryUseCase ユニバースの出力の履歴を取得するインターフェース
+type UniverseHistoryUseCase interface {
+	// 1チャンネルの履歴を取得する
+	ChannelHistory(query model.ChannelHistoryQuery) (model.ChannelHistory, error)
+}
+
+// UniverseHistoryUseCaseImpl UniverseHistoryUseCaseの実装
+// UniverseObserver としてマージしたユニバースの出力を受け取り、履歴に追加する
+type UniverseHistoryUseCaseImpl struct {
+	repo   repository.UniverseHistoryRepository
+	policy model.HistoryPolicy
+	now    func() time.Time
+}
+
+// NewUniverseHistoryUseCaseImpl UniverseHistoryUseCaseの新しいインスタンスを作成
+func NewUniverseHistoryUseCaseImpl(repo repository.UniverseHistoryRepository, policy model.HistoryPolicy) *UniverseHistoryUseCaseImpl {
+	return &UniverseHistoryUseCaseImpl{
+		repo:   repo,
+		policy: policy,
+		now:    time.Now,
+	}
+}
+
+// ObserveUniverse マージしたユニバースの出力を受信した時刻の履歴として追加する
+func (uc *UniverseHistoryUseCaseImpl) ObserveUniverse(protocol string, universe uint16, data *[512]uint8) {
+	uc.repo.Append(protocol, universe, uc.now(), data)
+}
+
+func (uc *UniverseHistoryUseCaseImpl) ChannelHistory(query model.ChannelHistoryQuery) (model.ChannelHistory, error) {
+	if query.Protocol == "" {
+		query.Protocol = model.ProtocolArtNet
+	}
+	switch query.Protocol {
+	case model.ProtocolArtNet:
+		if query.Universe > model.MaxUniverse {
+			return model.ChannelHistory{}, fmt.Errorf("%w: universe must be between 0 and %d", ErrInvalidHistoryQuery, model.MaxUniverse)
+		}
+	case model.ProtocolSACN:
+		if query.Universe < 1 || query.Universe > model.MaxSACNUniverse {
+			return model.ChannelHistory{}, fmt.Errorf("%w: universe must be between 1 and %d", ErrInvalidHistoryQuery, model.MaxSACNUniverse)
+		}
+	default:
+		return model.ChannelHistory{}, fmt.Errorf("%w: unknown protocol %q", ErrInvalidHistoryQuery, query.Protocol)
+	}
+	if query.Channel < 1 || query.Channel > 512 {
+		return model.ChannelHistory{}, fmt.Errorf("%w: channel must be between 1 and 512", ErrInvalidHistoryQuery)
+	}
+	if query.To.IsZero() {
+		query.To = uc.now()
+	}
+	if query.From.IsZero() {
+		query.From = query.To.Add(-uc.policy.Retention)
+	}
+	if !query.From.Before(query.To) {
+		return model.ChannelHistory{}, fmt.Errorf("%w: from must be before to", ErrInvalidHistoryQuery)
+	}
+	if query.Step < 0 || (query.Step > 0 && query.Step < time.Millisecond) {
+		return model.ChannelHistory{}, fmt.Errorf("%w: step must be at least 1ms", ErrInvalidHistoryQuery)
+	}
+	if query.Step > 0 && query.To.Sub(query.From)/query.Step > model.MaxHistoryPoints {
+		return model.ChannelHistory{}, fmt.Errorf("%w: too many points (max %d)", ErrInvalidHistoryQuery, model.MaxHistoryPoints)
+	}
+
+	points := uc.repo.Query(query.Protocol, query.Universe, query.Channel-1, query.From, query.To)
+	return model.ChannelHistory{
+		Protocol: query.Protocol,
+		Universe: query.Universe,
+		Channel:  query.Channel,
+		From:     query.From,
+		To:       query.To,
+		StepMs:   query.Step.Milliseconds(),
+		Points:   model.DownsampleChannelHistory(points, query.Step),
+	}, nil
+}
+
+// StartPruner 一定間隔で保持期間を過ぎた履歴を削除する
+func (uc *UniverseHistoryUseCaseImpl) StartPruner(ctx context.Context) {
+	ticker := time.NewTicker(HistoryPruneInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			uc.repo.Prune(uc.now())
+		}
+	}
+}
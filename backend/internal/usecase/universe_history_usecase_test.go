@@ -0,0 +1,112 @@
+package usecase
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nasshu2916/dmx_viewer/internal/domain/model"
+	"github.com/nasshu2916/dmx_viewer/internal/infrastructure"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestUniverseHistory(start time.Time) (*UniverseHistoryUseCaseImpl, *time.Time) {
+	policy := model.NewHistoryPolicy(time.Minute, 10*time.Minute, time.Hour)
+	uc := NewUniverseHistoryUseCaseImpl(infrastructure.NewUniverseHistoryRepository(policy), policy)
+	now := start
+	uc.now = func() time.Time { return now }
+	return uc, &now
+}
+
+func TestUniverseHistoryUseCase_Tiers(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	uc, now := newTestUniverseHistory(start)
+
+	// 20分間、500ms ごとに経過した分数をチャンネル1に出力する
+	var data [512]uint8
+	for at := start; at.Before(start.Add(20 * time.Minute)); at = at.Add(500 * time.Millisecond) {
+		*now = at
+		data[0] = uint8(at.Sub(start) / time.Minute)
+		uc.ObserveUniverse(model.ProtocolArtNet, 1, &data)
+	}
+	*now = start.Add(20 * time.Minute)
+	uc.repo.Prune(*now)
+
+	history, err := uc.ChannelHistory(model.ChannelHistoryQuery{Universe: 1, Channel: 1})
+	require.NoError(t, err)
+	assert.Equal(t, model.ProtocolArtNet, history.Protocol)
+	assert.Equal(t, start.Add(-40*time.Minute), history.From)
+
+	// 10分より前は10秒、直近1分までは1秒ごと、直近1分は受信したフレームそのまま
+	require.Len(t, history.Points, 60+540+120)
+	assert.Equal(t, start, history.Points[0].Time)
+	assert.Equal(t, start.Add(10*time.Minute), history.Points[60].Time)
+	assert.Equal(t, start.Add(19*time.Minute), history.Points[600].Time)
+	assert.Equal(t, start.Add(19*time.Minute+500*time.Millisecond), history.Points[601].Time)
+	assert.Equal(t, model.ChannelHistoryPoint{Time: start.Add(10 * time.Second), Min: 0, Max: 0, Avg: 0, Count: 20}, history.Points[1])
+
+	history, err = uc.ChannelHistory(model.ChannelHistoryQuery{Universe: 1, Channel: 1, From: start, Step: time.Minute})
+	require.NoError(t, err)
+	assert.Equal(t, int64(60000), history.StepMs)
+	require.Len(t, history.Points, 20)
+	for i, p := range history.Points {
+		assert.Equal(t, start.Add(time.Duration(i)*time.Minute), p.Time)
+		assert.Equal(t, uint8(i), p.Min)
+		assert.Equal(t, uint8(i), p.Max)
+		assert.InDelta(t, float64(i), p.Avg, 1e-9)
+		assert.Equal(t, uint32(120), p.Count)
+	}
+
+	// 範囲外・受信していないユニバースは空
+	history, err = uc.ChannelHistory(model.ChannelHistoryQuery{Universe: 1, Channel: 1, From: start.Add(-time.Hour), To: start})
+	require.NoError(t, err)
+	assert.Empty(t, history.Points)
+	history, err = uc.ChannelHistory(model.ChannelHistoryQuery{Protocol: model.ProtocolSACN, Universe: 1, Channel: 1})
+	require.NoError(t, err)
+	assert.Empty(t, history.Points)
+}
+
+func TestUniverseHistoryUseCase_Prune(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	uc, now := newTestUniverseHistory(start)
+
+	var data [512]uint8
+	data[511] = 200
+	uc.ObserveUniverse(model.ProtocolSACN, 5, &data)
+
+	*now = start.Add(30 * time.Minute)
+	uc.repo.Prune(*now)
+	history, err := uc.ChannelHistory(model.ChannelHistoryQuery{Protocol: model.ProtocolSACN, Universe: 5, Channel: 512})
+	require.NoError(t, err)
+	assert.Equal(t, []model.ChannelHistoryPoint{{Time: start, Min: 200, Max: 200, Avg: 200, Count: 1}}, history.Points)
+
+	*now = start.Add(time.Hour + 10*time.Second)
+	uc.repo.Prune(*now)
+	history, err = uc.ChannelHistory(model.ChannelHistoryQuery{Protocol: model.ProtocolSACN, Universe: 5, Channel: 512})
+	require.NoError(t, err)
+	assert.Empty(t, history.Points)
+}
+
+func TestUniverseHistoryUseCase_InvalidQuery(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	uc, _ := newTestUniverseHistory(start)
+
+	tests := []struct {
+		name  string
+		query model.ChannelHistoryQuery
+	}{
+		{name: "Unknown protocol", query: model.ChannelHistoryQuery{Protocol: "dmx", Channel: 1}},
+		{name: "Art-Net universe", query: model.ChannelHistoryQuery{Universe: model.MaxUniverse + 1, Channel: 1}},
+		{name: "sACN universe", query: model.ChannelHistoryQuery{Protocol: model.ProtocolSACN, Universe: 0, Channel: 1}},
+		{name: "Channel", query: model.ChannelHistoryQuery{Channel: 513}},
+		{name: "Range", query: model.ChannelHistoryQuery{Channel: 1, From: start, To: start}},
+		{name: "Step", query: model.ChannelHistoryQuery{Channel: 1, Step: time.Microsecond}},
+		{name: "Too many points", query: model.ChannelHistoryQuery{Channel: 1, Step: time.Millisecond}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := uc.ChannelHistory(tt.query)
+			assert.ErrorIs(t, err, ErrInvalidHistoryQuery)
+		})
+	}
+}
@@ -0,0 +1,257 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/nasshu2916/dmx_viewer/internal/domain/model"
+	"github.com/nasshu2916/dmx_viewer/pkg/logger"
+)
+
+// MergeSweepInterval 無通信の送信元を確認する間隔
+const MergeSweepInterval = 1 * time.Second
+
+// MergeUseCase ユニバースごとのマージ設定を参照・変更するインターフェース
+type MergeUseCase interface {
+	// すべてのユニバースのマージ状態を取得する
+	GetMergeStates() []model.UniverseMergeState
+	// ユニバースのマージモードを変更する（SOURCE の場合は source で送信元を指定する）
+	SetMergeMode(universe uint16, mode model.MergeMode, source string) (model.UniverseMergeState, error)
+}
+
+// universeMergeState 1ユニバース分のマージ状態
+type universeMergeState struct {
+	sources  map[string]*model.MergeInput // 送信元IPをキーとする
+	mode     model.MergeMode
+	selected string
+	conflict bool
+}
+
+// UniverseMerger 同じユニバースを送信する複数の送信元のフレームを保持し、マージしたデータを配信する
+type UniverseMerger struct {
+	mu          sync.Mutex
+	universes   map[uint16]*universeMergeState
+	defaultMode model.MergeMode
+	wsUseCase   WebSocketUseCase
+	observer    UniverseObserver // マージしたデータを受け取る（nilの場合は使用しない）
+	logger      *logger.Logger
+	now         func() time.Time
+}
+
+// NewUniverseMerger UniverseMergerの新しいインスタンスを作成
+func NewUniverseMerger(defaultMode model.MergeMode, wsUseCase WebSocketUseCase, observer UniverseObserver, logger *logger.Logger) *UniverseMerger {
+	return &UniverseMerger{
+		universes:   make(map[uint16]*universeMergeState),
+		defaultMode: defaultMode,
+		wsUseCase:   wsUseCase,
+		observer:    observer,
+		logger:      logger,
+		now:         time.Now,
+	}
+}
+
+// Push 送信元の最新フレームを更新し、マージしたデータを artnet/dmx_merged トピックに配信する
+func (m *UniverseMerger) Push(dmx *model.DMXFrame) error {
+	universe := dmx.Universe
+	sourceIP := dmx.SourceIP.String()
+
+	m.mu.Lock()
+	now := m.now()
+	state := m.stateLocked(universe)
+	state.sources[sourceIP] = &model.MergeInput{SourceIP: sourceIP, Frame: dmx, LastSeen: now}
+	m.dropStaleLocked(state, now)
+	merged := m.mergeLocked(universe, state)
+	conflict := m.updateConflictLocked(state, merged, now)
+	m.mu.Unlock()
+
+	m.broadcastConflict(conflict)
+	return m.publishMerged(merged)
+}
+
+// StartSweeper 一定間隔で無通信の送信元をマージ対象から外す
+func (m *UniverseMerger) StartSweeper(ctx context.Context) {
+	ticker := time.NewTicker(MergeSweepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			m.Sweep()
+		case <-ctx.Done():
+			m.logger.Info("Universe merge sweeper stopped.")
+			return
+		}
+	}
+}
+
+// Sweep 10秒間データを受信していない送信元を削除し、マージ結果を配信し直して競合の解消を通知する
+// 送信元がなくなったユニバースはすべてのチャンネルが0のデータを配信する
+func (m *UniverseMerger) Sweep() {
+	m.removeSources(m.dropStaleLocked)
+}
+
+// Reset すべての送信元をマージ対象から外す（マージモードの設定は残す）
+// 送信元がなくなったユニバースはすべてのチャンネルが0のデータを配信する
+func (m *UniverseMerger) Reset() {
+	m.removeSources(func(state *universeMergeState, _ time.Time) bool {
+		dropped := len(state.sources) > 0
+		clear(state.sources)
+		return dropped
+	})
+}
+
+// removeSources drop で送信元を削除したユニバースのマージ結果を配信し直して競合の解消を通知する
+func (m *UniverseMerger) removeSources(drop func(state *universeMergeState, now time.Time) bool) {
+	m.mu.Lock()
+	now := m.now()
+	var conflicts []*model.UniverseConflict
+	var mergedFrames []*model.MergedFrame
+	for universe, state := range m.universes {
+		if !drop(state, now) {
+			continue
+		}
+		merged := m.mergeLocked(universe, state)
+		mergedFrames = append(mergedFrames, merged)
+		if conflict := m.updateConflictLocked(state, merged, now); conflict != nil {
+			conflicts = append(conflicts, conflict)
+		}
+		// 設定を変更していないユニバースは送信元がなくなれば削除する
+		if len(state.sources) == 0 && state.mode == m.defaultMode {
+			delete(m.universes, universe)
+		}
+	}
+	m.mu.Unlock()
+
+	for _, conflict := range conflicts {
+		m.broadcastConflict(conflict)
+	}
+	for _, merged := range mergedFrames {
+		if err := m.publishMerged(merged); err != nil {
+			m.logger.Debug("Failed to broadcast merged frame", "universe", merged.Universe, "error", err)
+		}
+	}
+}
+
+// publishMerged マージしたデータをオブザーバーに渡し、artnet/dmx_merged トピックに配信する
+func (m *UniverseMerger) publishMerged(merged *model.MergedFrame) error {
+	if m.observer != nil {
+		m.observer.ObserveUniverse(model.ProtocolArtNet, merged.Universe, &merged.Data)
+	}
+	msg := model.NewWebSocketMessage("artnet_dmx_merged", merged)
+	return m.wsUseCase.BroadcastToTopic("artnet/dmx_merged", msg)
+}
+
+func (m *UniverseMerger) GetMergeStates() []model.UniverseMergeState {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	states := make([]model.UniverseMergeState, 0, len(m.universes))
+	for universe, state := range m.universes {
+		states = append(states, m.mergeStateLocked(universe, state))
+	}
+	sort.Slice(states, func(i, j int) bool { return states[i].Universe < states[j].Universe })
+	return states
+}
+
+func (m *UniverseMerger) SetMergeMode(universe uint16, mode model.MergeMode, source string) (model.UniverseMergeState, error) {
+	if universe > model.MaxUniverse {
+		return model.UniverseMergeState{}, fmt.Errorf("universe %d exceeds maximum %d", universe, model.MaxUniverse)
+	}
+	if mode == model.MergeModeSource && source == "" {
+		return model.UniverseMergeState{}, errors.New("source is required for SOURCE merge mode")
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	state := m.stateLocked(universe)
+	state.mode = mode
+	state.selected = ""
+	if mode == model.MergeModeSource {
+		state.selected = source
+	}
+	m.logger.Info("Merge mode updated", "universe", universe, "mode", mode, "source", state.selected)
+	return m.mergeStateLocked(universe, state), nil
+}
+
+func (m *UniverseMerger) stateLocked(universe uint16) *universeMergeState {
+	state, ok := m.universes[universe]
+	if !ok {
+		state = &universeMergeState{sources: make(map[string]*model.MergeInput), mode: m.defaultMode}
+		m.universes[universe] = state
+	}
+	return state
+}
+
+// dropStaleLocked 無通信の送信元を削除し、削除したかどうかを返す
+func (m *UniverseMerger) dropStaleLocked(state *universeMergeState, now time.Time) bool {
+	dropped := false
+	for sourceIP, in := range state.sources {
+		if now.Sub(in.LastSeen) >= model.MergeSourceTimeout {
+			delete(state.sources, sourceIP)
+			dropped = true
+		}
+	}
+	return dropped
+}
+
+func (m *UniverseMerger) mergeLocked(universe uint16, state *universeMergeState) *model.MergedFrame {
+	return model.MergeFrames(universe, sortedMergeInputs(state), state.mode, state.selected)
+}
+
+func (m *UniverseMerger) mergeStateLocked(universe uint16, state *universeMergeState) model.UniverseMergeState {
+	inputs := sortedMergeInputs(state)
+	sources := make([]string, 0, len(inputs))
+	for _, in := range inputs {
+		sources = append(sources, in.SourceIP)
+	}
+	return model.UniverseMergeState{
+		Universe: universe,
+		Mode:     state.mode,
+		Selected: state.selected,
+		Sources:  sources,
+		Conflict: state.conflict,
+	}
+}
+
+// updateConflictLocked 競合状態が変化した場合に通知内容を返す
+func (m *UniverseMerger) updateConflictLocked(state *universeMergeState, merged *model.MergedFrame, now time.Time) *model.UniverseConflict {
+	if merged.Conflict == state.conflict {
+		return nil
+	}
+	state.conflict = merged.Conflict
+	return &model.UniverseConflict{
+		Universe: merged.Universe,
+		Sources:  merged.Sources,
+		Active:   merged.Conflict,
+		At:       now,
+	}
+}
+
+func (m *UniverseMerger) broadcastConflict(conflict *model.UniverseConflict) {
+	if conflict == nil {
+		return
+	}
+	if conflict.Active {
+		m.logger.Warn("Universe conflict detected", "universe", conflict.Universe, "sources", conflict.Sources)
+	} else {
+		m.logger.Info("Universe conflict resolved", "universe", conflict.Universe, "sources", conflict.Sources)
+	}
+	msg := model.NewWebSocketMessage("universe_conflict", conflict)
+	if err := m.wsUseCase.BroadcastToTopic("artnet/conflicts", msg); err != nil {
+		m.logger.Debug("Failed to broadcast universe conflict", "error", err)
+	}
+}
+
+// sortedMergeInputs 送信元IP順に並べたマージ対象を返す
+func sortedMergeInputs(state *universeMergeState) []model.MergeInput {
+	inputs := make([]model.MergeInput, 0, len(state.sources))
+	for _, in := range state.sources {
+		inputs = append(inputs, *in)
+	}
+	sort.Slice(inputs, func(i, j int) bool { return inputs[i].SourceIP < inputs[j].SourceIP })
+	return inputs
+}
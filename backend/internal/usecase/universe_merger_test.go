@@ -0,0 +1,106 @@
+package usecase
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/nasshu2916/dmx_viewer/internal/domain/model"
+	"github.com/nasshu2916/dmx_viewer/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newMergeTestFrame(source net.IP, universe uint16, values ...uint8) *model.DMXFrame {
+	dmx := &model.DMXFrame{Universe: universe, SourceIP: source, Length: uint16(len(values))}
+	copy(dmx.Data[:], values)
+	return dmx
+}
+
+func TestUniverseMerger_ConflictAndSourceTimeout(t *testing.T) {
+	ws := newFakeWebSocketUseCase()
+	merger := NewUniverseMerger(model.MergeModeHTP, ws, nil, logger.NewLogger("fatal"))
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := start
+	merger.now = func() time.Time { return clock }
+
+	srcA := net.IPv4(2, 0, 0, 10)
+	srcB := net.IPv4(2, 0, 0, 11)
+
+	require.NoError(t, merger.Push(newMergeTestFrame(srcA, 1, 100, 0)))
+	assert.Empty(t, ws.Messages("artnet/conflicts"))
+
+	clock = start.Add(time.Second)
+	require.NoError(t, merger.Push(newMergeTestFrame(srcB, 1, 50, 200)))
+
+	conflicts := ws.Messages("artnet/conflicts")
+	require.Len(t, conflicts, 1)
+	conflict := conflicts[0].Data.(*model.UniverseConflict)
+	assert.True(t, conflict.Active)
+	assert.Equal(t, []string{"2.0.0.10", "2.0.0.11"}, conflict.Sources)
+
+	merged := ws.Messages("artnet/dmx_merged")
+	require.Len(t, merged, 2)
+	frame := merged[1].Data.(*model.MergedFrame)
+	assert.True(t, frame.Conflict)
+	assert.Equal(t, []uint8{100, 200}, frame.Data[:frame.Length])
+
+	// 10秒間受信しなかった送信元は外れ、競合が解消される
+	clock = start.Add(model.MergeSourceTimeout)
+	merger.Sweep()
+	conflicts = ws.Messages("artnet/conflicts")
+	require.Len(t, conflicts, 2)
+	resolved := conflicts[1].Data.(*model.UniverseConflict)
+	assert.False(t, resolved.Active)
+	assert.Equal(t, []string{"2.0.0.11"}, resolved.Sources)
+
+	states := merger.GetMergeStates()
+	require.Len(t, states, 1)
+	assert.Equal(t, []string{"2.0.0.11"}, states[0].Sources)
+	assert.False(t, states[0].Conflict)
+
+	// 残った送信元のみでマージし直して配信する
+	merged = ws.Messages("artnet/dmx_merged")
+	require.Len(t, merged, 3)
+	frame = merged[2].Data.(*model.MergedFrame)
+	assert.False(t, frame.Conflict)
+	assert.Equal(t, []uint8{50, 200}, frame.Data[:frame.Length])
+
+	// すべての送信元が外れたユニバースは削除され、すべてのチャンネルが0のデータを配信する
+	clock = start.Add(time.Second + model.MergeSourceTimeout)
+	merger.Sweep()
+	assert.Empty(t, merger.GetMergeStates())
+	merged = ws.Messages("artnet/dmx_merged")
+	require.Len(t, merged, 4)
+	frame = merged[3].Data.(*model.MergedFrame)
+	assert.Empty(t, frame.Sources)
+	assert.Equal(t, [512]uint8{}, frame.Data)
+
+	// 送信元が外れなければ配信しない
+	merger.Sweep()
+	assert.Len(t, ws.Messages("artnet/dmx_merged"), 4)
+}
+
+func TestUniverseMerger_SetMergeMode(t *testing.T) {
+	ws := newFakeWebSocketUseCase()
+	merger := NewUniverseMerger(model.MergeModeHTP, ws, nil, logger.NewLogger("fatal"))
+
+	_, err := merger.SetMergeMode(1, model.MergeModeSource, "")
+	assert.Error(t, err)
+	_, err = merger.SetMergeMode(model.MaxUniverse+1, model.MergeModeLTP, "")
+	assert.Error(t, err)
+
+	state, err := merger.SetMergeMode(1, model.MergeModeSource, "2.0.0.10")
+	require.NoError(t, err)
+	assert.Equal(t, model.MergeModeSource, state.Mode)
+	assert.Equal(t, "2.0.0.10", state.Selected)
+
+	require.NoError(t, merger.Push(newMergeTestFrame(net.IPv4(2, 0, 0, 10), 1, 10, 20)))
+	require.NoError(t, merger.Push(newMergeTestFrame(net.IPv4(2, 0, 0, 11), 1, 255, 255)))
+
+	merged := ws.Messages("artnet/dmx_merged")
+	frame := merged[len(merged)-1].Data.(*model.MergedFrame)
+	assert.Equal(t, model.MergeModeSource, frame.Mode)
+	assert.Equal(t, "2.0.0.10", frame.Selected)
+	assert.Equal(t, []uint8{10, 20}, frame.Data[:frame.Length])
+}
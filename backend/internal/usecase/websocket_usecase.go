@@ -5,12 +5,15 @@ import (
 
 	"github.com/nasshu2916/dmx_viewer/internal/domain/model"
 	"github.com/nasshu2916/dmx_viewer/internal/domain/repository"
+	"github.com/nasshu2916/dmx_viewer/internal/infrastructure/wsframe"
 	"github.com/nasshu2916/dmx_viewer/pkg/logger"
 )
 
 // WebSocketUseCase WebSocketに関連するビジネスロジックを定義するインターフェース
 type WebSocketUseCase interface {
 	BroadcastToTopic(topic string, message *model.WebSocketMessage) error
+	// 同じメッセージを複数のトピックにブロードキャストする（各表現への変換は必要な場合に1回のみ）
+	BroadcastToTopics(topics []string, message *model.WebSocketMessage) error
 }
 
 // WebSocketUseCaseImpl WebSocketUseCaseの実装
@@ -29,15 +32,45 @@ func NewWebSocketUseCaseImpl(wsRepo repository.WebSocketRepository, logger *logg
 
 // BroadcastToTopic 特定のトピックにメッセージをブロードキャストする
 func (uc *WebSocketUseCaseImpl) BroadcastToTopic(topic string, message *model.WebSocketMessage) error {
-	jsonData, err := json.Marshal(message)
-	if err != nil {
-		uc.logger.Error("Failed to marshal WebSocket message", "error", err, "topic", topic)
-		return err
+	return uc.BroadcastToTopics([]string{topic}, message)
+}
+
+// BroadcastToTopics 複数のトピックに同じメッセージをブロードキャストする
+// JSON とDMXフレームのバイナリ表現は、その表現を選択したクライアントが購読している場合にのみ1回だけ作成する
+func (uc *WebSocketUseCaseImpl) BroadcastToTopics(topics []string, message *model.WebSocketMessage) error {
+	var marshalErr error
+	text := func() []byte {
+		data, err := json.Marshal(message)
+		if err != nil {
+			uc.logger.Error("Failed to marshal WebSocket message", "error", err, "topics", topics)
+			marshalErr = err
+			return nil
+		}
+		return data
+	}
+	var binary func() []byte
+	if frame, ok := message.Data.(*model.DMXFrame); ok {
+		binary = func() []byte {
+			data, err := wsframe.MarshalDMXFrame(frame)
+			if err != nil {
+				uc.logger.Warn("Failed to encode DMX frame as binary, sending JSON instead", "error", err, "universe", frame.Universe)
+				return nil
+			}
+			return data
+		}
 	}
 
-	if err := uc.wsRepo.BroadcastToTopic(topic, jsonData); err != nil {
-		uc.logger.Error("Failed to broadcast WebSocket message", "error", err, "topic", topic)
+	if err := uc.wsRepo.BroadcastToTopics(topics, text, binary); err != nil {
+		uc.logger.Error("Failed to broadcast WebSocket message", "error", err, "topics", topics)
 		return err
 	}
-	return nil
+	// エンコードはブロードキャストが終わるまでに行われる
+	return marshalErr
+}
+
+// publishDMXFrame DMXフレームを全ユニバースのトピック（artnet/dmx_packet）と
+// ユニバースごとのトピック（artnet/dmx/{universe}、sacn/dmx/{universe}）に配信する
+func publishDMXFrame(wsUseCase WebSocketUseCase, frame *model.DMXFrame) error {
+	msg := model.NewWebSocketMessage("artnet_dmx_packet", frame)
+	return wsUseCase.BroadcastToTopics([]string{model.DMXPacketTopic, model.DMXUniverseTopic(frame.Protocol, frame.Universe)}, msg)
 }
@@ -0,0 +1,148 @@
+package usecase
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	gorillaws "github.com/gorilla/websocket"
+	"github.com/nasshu2916/dmx_viewer/internal/domain/model"
+	"github.com/nasshu2916/dmx_viewer/internal/infrastructure"
+	"github.com/nasshu2916/dmx_viewer/internal/infrastructure/wsframe"
+	"github.com/nasshu2916/dmx_viewer/internal/interface/handler/websocket"
+	"github.com/nasshu2916/dmx_viewer/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeWebSocketRepository ブロードキャストしたメッセージを記録するWebSocketRepository
+// JSON とバイナリ表現の両方を使うクライアントが購読しているものとしてエンコードする
+type fakeWebSocketRepository struct {
+	topics   []string
+	messages [][]byte
+	binaries [][]byte
+}
+
+func (f *fakeWebSocketRepository) BroadcastToTopic(topic string, message []byte) error {
+	return f.BroadcastToTopics([]string{topic}, func() []byte { return message }, nil)
+}
+
+func (f *fakeWebSocketRepository) BroadcastToTopics(topics []string, text, binary func() []byte) error {
+	message := text()
+	var binaryData []byte
+	if binary != nil {
+		binaryData = binary()
+	}
+	for _, topic := range topics {
+		f.topics = append(f.topics, topic)
+		f.messages = append(f.messages, message)
+		f.binaries = append(f.binaries, binaryData)
+	}
+	return nil
+}
+
+func (f *fakeWebSocketRepository) BroadcastToAll(message []byte) error {
+	return nil
+}
+
+func TestWebSocketUseCase_BroadcastDMXFrameWithBinary(t *testing.T) {
+	repo := &fakeWebSocketRepository{}
+	uc := NewWebSocketUseCaseImpl(repo, logger.NewLogger("fatal"))
+
+	frame := &model.DMXFrame{Protocol: model.ProtocolArtNet, Universe: 3, Length: 2, Data: [512]uint8{10, 20}, SourceIP: net.IPv4(2, 0, 0, 10)}
+	require.NoError(t, publishDMXFrame(uc, frame))
+
+	assert.Equal(t, []string{model.DMXPacketTopic, "artnet/dmx/3"}, repo.topics)
+	var msg model.WebSocketMessage
+	require.NoError(t, json.Unmarshal(repo.messages[0], &msg))
+	assert.Equal(t, "artnet_dmx_packet", msg.Type)
+
+	require.NotNil(t, repo.binaries[0])
+	assert.Equal(t, repo.binaries[0], repo.binaries[1])
+	decoded, err := wsframe.UnmarshalDMXFrame(repo.binaries[0])
+	require.NoError(t, err)
+	assert.Equal(t, uint16(3), decoded.Universe)
+	assert.Equal(t, []uint8{10, 20}, decoded.Data[:decoded.Length])
+}
+
+func TestWebSocketUseCase_BroadcastOtherMessagesAsJSONOnly(t *testing.T) {
+	repo := &fakeWebSocketRepository{}
+	uc := NewWebSocketUseCaseImpl(repo, logger.NewLogger("fatal"))
+
+	require.NoError(t, uc.BroadcastToTopic("artnet/nodes", model.NewWebSocketMessage("artnet_nodes", []string{})))
+	require.Len(t, repo.binaries, 1)
+	assert.Nil(t, repo.binaries[0])
+}
+
+// benchmarkBroadcastDMXFrame JSON とバイナリ表現を選択したクライアントがユニバースを購読している状態で、
+// ハブを経由してDMXフレームを配信する
+func benchmarkBroadcastDMXFrame(b *testing.B, jsonClients, binaryClients int) {
+	l := logger.NewLogger("fatal")
+	hub := websocket.NewHub(l)
+	go hub.Run()
+	server := httptest.NewServer(http.HandlerFunc(websocket.NewWebSocketHandler(hub, l).ServeWS))
+	defer server.Close()
+	uc := NewWebSocketUseCaseImpl(infrastructure.NewWebSocketRepositoryImpl(hub, l), l)
+	frame := &model.DMXFrame{Protocol: model.ProtocolArtNet, Universe: 1, Length: 512, SourceIP: net.IPv4(2, 0, 0, 10)}
+	for i := range frame.Data {
+		frame.Data[i] = uint8(i)
+	}
+
+	clients := jsonClients + binaryClients
+	received := make(chan struct{}, clients)
+	for i := 0; i < clients; i++ {
+		dialer := gorillaws.Dialer{}
+		if i >= jsonClients {
+			dialer.Subprotocols = []string{websocket.BinarySubprotocol}
+		}
+		conn, _, err := dialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+		require.NoError(b, err)
+		defer conn.Close()
+		require.NoError(b, conn.WriteJSON(map[string]string{"type": "subscribe", "topic": "artnet/dmx/1"}))
+		go func() {
+			first := true
+			for {
+				if _, _, err := conn.ReadMessage(); err != nil {
+					return
+				}
+				if first {
+					received <- struct{}{}
+					first = false
+				}
+			}
+		}()
+	}
+	// すべてのクライアントが購読を終えるまで配信する
+	for ready := 0; ready < clients; {
+		require.NoError(b, publishDMXFrame(uc, frame))
+		select {
+		case <-received:
+			ready++
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := publishDMXFrame(uc, frame); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkBroadcastDMXFrame_JSONClients(b *testing.B) {
+	benchmarkBroadcastDMXFrame(b, 4, 0)
+}
+
+func BenchmarkBroadcastDMXFrame_BinaryClients(b *testing.B) {
+	benchmarkBroadcastDMXFrame(b, 0, 4)
+}
+
+func BenchmarkBroadcastDMXFrame_MixedClients(b *testing.B) {
+	benchmarkBroadcastDMXFrame(b, 2, 2)
+}
@@ -9,12 +9,19 @@ import { useState, useCallback } from 'react'
 import { useWebSocket } from '@/contexts/WebSocketContext'
 import { useSelectionStore } from '@/stores/selectionStore'
 import { useArtNetStore } from '@/stores/artNetStore'
+import { useChannelHistory } from '@/hooks/useChannelHistory'
 
 function App() {
   const { isConnected } = useWebSocket()
   const { serverMessages } = useArtNetStore()
   const { selectedUniverse, selectedChannel } = useSelectionStore()
-  const { dmxHistory } = useArtNetStore()
+  const { dmxHistory, sacnMerged } = useArtNetStore()
+  // 選択中の送信元が出力に参加している sACN ユニバースのマージ結果
+  const selectedSACNMerged = selectedUniverse ? sacnMerged[selectedUniverse.universe] : undefined
+  const sacnMergedForSelection = selectedSACNMerged?.Sources.some(s => s.SourceIP === selectedUniverse?.address)
+    ? selectedSACNMerged
+    : undefined
+  useChannelHistory(selectedUniverse, selectedChannel, sacnMergedForSelection ? 'sacn' : 'artnet', 100)
 
   const [activeTab, setActiveTab] = useState<MobileTabKey>('viewer')
   const handleTabChange = useCallback((key: MobileTabKey) => {
@@ -42,6 +49,7 @@ function App() {
                 <h3 className="mb-3 text-lg font-semibold text-dmx-text-light">Status</h3>
                 <SelectedInfoDisplay
                   dmxHistory={dmxHistory}
+                  sacnMerged={sacnMergedForSelection}
                   selectedChannel={selectedChannel}
                   selectedUniverse={selectedUniverse}
                 />
@@ -66,6 +74,7 @@ function App() {
             <h3 className="mb-3 text-lg font-semibold text-dmx-text-light">Status</h3>
             <SelectedInfoDisplay
               dmxHistory={dmxHistory}
+              sacnMerged={sacnMergedForSelection}
               selectedChannel={selectedChannel}
               selectedUniverse={selectedUniverse}
             />
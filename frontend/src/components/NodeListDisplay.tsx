@@ -1,10 +1,11 @@
 import React from 'react'
 import type { ArtNet } from '@/types/artnet'
-import type { NodeListDisplayNode } from './NodeListDisplayContainer'
+import type { NodeListDisplayNode, NodeListDisplaySACNSource } from './NodeListDisplayContainer'
 import { useSelectionStore } from '@/stores/selectionStore'
 
 interface NodeListDisplayProps {
   nodes: NodeListDisplayNode[]
+  sacnSources?: NodeListDisplaySACNSource[]
 }
 
 interface NodeUniverseListProps {
@@ -55,14 +56,25 @@ const NodeUniverseList: React.FC<NodeUniverseListProps> = React.memo(
   }
 )
 
+const livenessClassName: Record<ArtNet.NodeLiveness, string> = {
+  unknown: 'text-gray-500',
+  online: 'text-green-400',
+  stale: 'text-yellow-400',
+  offline: 'text-red-400',
+}
+
 const NodeInfo: React.FC<{ node: ArtNet.ArtNetNode }> = React.memo(({ node }) => {
   const lastSeen = node.LastSeen ? new Date(node.LastSeen).toLocaleString() : 'Unknown'
+  const outputPorts = (node.Ports ?? []).filter(port => port.Output)
 
   return (
     <div>
       <div className="flex items-center justify-between text-sm">
         <span className="text-left font-bold">Name</span>
-        <span className="text-right">{node.ShortName || 'Unknown Node'}</span>
+        <span className="text-right">
+          {node.Liveness && <span className={livenessClassName[node.Liveness]}>● </span>}
+          {node.ShortName || 'Unknown Node'}
+        </span>
       </div>
       <div className="flex items-center justify-between text-sm">
         <span className="text-left font-bold">IP</span>
@@ -72,6 +84,24 @@ const NodeInfo: React.FC<{ node: ArtNet.ArtNetNode }> = React.memo(({ node }) =>
         <span className="text-left font-bold">MAC</span>
         <span className="text-right">{node.MacAddress}</span>
       </div>
+      {node.BindIndex !== undefined && node.BindIndex > 1 && (
+        <div className="flex items-center justify-between text-sm">
+          <span className="text-left font-bold">Bind Index</span>
+          <span className="text-right">{node.BindIndex}</span>
+        </div>
+      )}
+      {outputPorts.length > 0 && (
+        <div className="flex items-center justify-between text-sm">
+          <span className="text-left font-bold">Outputs</span>
+          <span className="flex gap-1 text-right">
+            {outputPorts.map(port => (
+              <span className={port.Healthy ? '' : 'text-red-400'} key={port.Index}>
+                {port.OutputUniverse}
+              </span>
+            ))}
+          </span>
+        </div>
+      )}
       <div className="flex items-center justify-between text-sm">
         <span className="text-left font-bold">Last Seen</span>
         <span className="text-right">{lastSeen}</span>
@@ -82,18 +112,64 @@ const NodeInfo: React.FC<{ node: ArtNet.ArtNetNode }> = React.memo(({ node }) =>
 
 NodeInfo.displayName = 'NodeInfo'
 
-const NodeListDisplay: React.FC<NodeListDisplayProps> = ({ nodes }) => {
+const SACNSourceInfo: React.FC<{ source: ArtNet.SACNSource }> = React.memo(({ source }) => {
+  const lastSeen = new Date(source.LastSeen).toLocaleString()
+
+  return (
+    <div>
+      <div className="flex items-center justify-between text-sm">
+        <span className="text-left font-bold">Name</span>
+        <span className="text-right">{source.SourceName || 'Unknown Source'}</span>
+      </div>
+      <div className="flex items-center justify-between text-sm">
+        <span className="text-left font-bold">IP</span>
+        <span className="text-right">{source.IPAddress}</span>
+      </div>
+      <div className="flex items-center justify-between text-sm">
+        <span className="text-left font-bold">CID</span>
+        <span className="truncate pl-2 text-right" title={source.CID}>
+          {source.CID}
+        </span>
+      </div>
+      <div className="flex items-center justify-between text-sm">
+        <span className="text-left font-bold">Universes</span>
+        <span className="text-right">{source.Universes.length > 0 ? source.Universes.join(', ') : 'None'}</span>
+      </div>
+      <div className="flex items-center justify-between text-sm">
+        <span className="text-left font-bold">Last Seen</span>
+        <span className="text-right">{lastSeen}</span>
+      </div>
+    </div>
+  )
+})
+
+SACNSourceInfo.displayName = 'SACNSourceInfo'
+
+const NodeListDisplay: React.FC<NodeListDisplayProps> = ({ nodes, sacnSources = [] }) => {
   return (
     <div className="p-4">
       <h2 className="mb-4 text-xl font-bold">ArtNet Nodes</h2>
       <ul>
         {nodes.map(node => (
-          <li className="mb-2 rounded border-2 border-gray-500 p-2" key={node.address}>
+          <li className="mb-2 rounded border-2 border-gray-500 p-2" key={node.key}>
             <NodeInfo node={node.info} />
             <NodeUniverseList address={node.address} universes={node.universes} />
           </li>
         ))}
       </ul>
+      {sacnSources.length > 0 && (
+        <>
+          <h2 className="mt-6 mb-4 text-xl font-bold">sACN Sources</h2>
+          <ul>
+            {sacnSources.map(source => (
+              <li className="mb-2 rounded border-2 border-gray-500 p-2" key={source.key}>
+                <SACNSourceInfo source={source.info} />
+                <NodeUniverseList address={source.address} universes={source.universes} />
+              </li>
+            ))}
+          </ul>
+        </>
+      )}
     </div>
   )
 }
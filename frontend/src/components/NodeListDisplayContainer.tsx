@@ -3,7 +3,15 @@ import NodeListDisplay from './NodeListDisplay'
 import type { ArtNet } from '@/types/artnet'
 import { useArtNetStore } from '@/stores'
 
+export type NodeListDisplaySACNSource = {
+  key: string
+  address: string
+  info: ArtNet.SACNSource
+  universes: ArtNet.Universe[]
+}
+
 export type NodeListDisplayNode = {
+  key: string
   address: string
   info: ArtNet.ArtNetNode
   universes: ArtNet.Universe[]
@@ -11,7 +19,7 @@ export type NodeListDisplayNode = {
 }
 
 const NodeListDisplayContainer: React.FC = () => {
-  const { artNetNodes, dmxData } = useArtNetStore()
+  const { artNetNodes, sacnSources, dmxData } = useArtNetStore()
   const receiveUniverseByNode = new Map<string, ArtNet.Universe[]>()
   for (const [address, universes] of Object.entries(dmxData)) {
     const universeNumbers: ArtNet.Universe[] = Object.keys(universes).map(Number) as ArtNet.Universe[]
@@ -21,25 +29,40 @@ const NodeListDisplayContainer: React.FC = () => {
   const displayNodes: NodeListDisplayNode[] = React.useMemo(() => {
     // ノードが存在しないがdmxDataにだけ存在するアドレス
     const missingAddresses = Array.from(receiveUniverseByNode.keys()).filter(
-      address => !artNetNodes.some(node => node.IPAddress === address)
+      address =>
+        !artNetNodes.some(node => node.IPAddress === address) &&
+        !sacnSources.some(source => source.IPAddress === address)
     )
     return [
       ...artNetNodes.map(node => ({
+        key: `${node.IPAddress}#${node.BindIndex || 1}`,
         address: node.IPAddress,
         info: node,
         universes: receiveUniverseByNode.get(node.IPAddress) || [],
         isUnknown: false,
       })),
       ...missingAddresses.map(address => ({
+        key: address,
         address,
         info: invalidNode(address),
         universes: receiveUniverseByNode.get(address) || [],
         isUnknown: true,
       })),
     ]
-  }, [artNetNodes, receiveUniverseByNode])
+  }, [artNetNodes, sacnSources, receiveUniverseByNode])
+
+  const displaySACNSources: NodeListDisplaySACNSource[] = React.useMemo(
+    () =>
+      sacnSources.map(source => ({
+        key: source.CID,
+        address: source.IPAddress,
+        info: source,
+        universes: receiveUniverseByNode.get(source.IPAddress) || [],
+      })),
+    [sacnSources, receiveUniverseByNode]
+  )
 
-  return <NodeListDisplay nodes={displayNodes} />
+  return <NodeListDisplay nodes={displayNodes} sacnSources={displaySACNSources} />
 }
 
 function invalidNode(address: string): ArtNet.ArtNetNode {
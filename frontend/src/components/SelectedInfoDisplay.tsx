@@ -3,39 +3,62 @@ import type { ArtNet } from '@/types/artnet'
 import type { DmxHistoryPoint } from '@/stores/artNetStore'
 import DmxHistoryChart from './DmxHistoryChart'
 import type { SelectedUniverse } from '@/types'
+import { getSACNChannelWinner } from '@/service/artnet'
 
 interface SelectedInfoDisplayProps {
   selectedUniverse: SelectedUniverse | null
   selectedChannel: ArtNet.DmxChannel | null
   dmxHistory: DmxHistoryPoint[]
+  sacnMerged?: ArtNet.SACNMergedFrame
 }
 
-const SelectedInfoDisplay = memo(({ selectedUniverse, selectedChannel, dmxHistory }: SelectedInfoDisplayProps) => {
-  const dmxValue = dmxHistory.length > 0 ? dmxHistory[dmxHistory.length - 1].value : null
+const SelectedInfoDisplay = memo(
+  ({ selectedUniverse, selectedChannel, dmxHistory, sacnMerged }: SelectedInfoDisplayProps) => {
+    const dmxValue = dmxHistory.length > 0 ? dmxHistory[dmxHistory.length - 1].value : null
+    const sacnWinner = sacnMerged && selectedChannel !== null ? getSACNChannelWinner(sacnMerged, selectedChannel) : null
 
-  return (
-    <div className="text-sm">
-      <div className="flex items-center justify-between py-1">
-        <span className="text-left font-bold">Address</span>
-        <span className="text-right">{selectedUniverse ? selectedUniverse.address : 'None'}</span>
+    return (
+      <div className="text-sm">
+        <div className="flex items-center justify-between py-1">
+          <span className="text-left font-bold">Address</span>
+          <span className="text-right">{selectedUniverse ? selectedUniverse.address : 'None'}</span>
+        </div>
+        <div className="flex items-center justify-between py-1">
+          <span className="text-left font-bold">Universe ID</span>
+          <span className="text-right">{selectedUniverse ? selectedUniverse.universe : 'None'}</span>
+        </div>
+        <div className="flex items-center justify-between py-1">
+          <span className="text-left font-bold">Selected Channel</span>
+          <span className="text-right">{selectedChannel !== null ? selectedChannel : 'None'}</span>
+        </div>
+        <div className="flex items-center justify-between py-1">
+          <span className="text-left font-bold">Dmx Value</span>
+          <span className="text-right">{dmxValue != null ? dmxValue : 'None'}</span>
+        </div>
+        {sacnMerged && (
+          <>
+            <div className="flex items-center justify-between py-1">
+              <span className="text-left font-bold">sACN Winner</span>
+              <span className="text-right">
+                {sacnWinner ? sacnWinner.source.SourceName || sacnWinner.source.SourceIP : 'None'}
+              </span>
+            </div>
+            <div className="flex items-center justify-between py-1">
+              <span className="text-left font-bold">sACN Priority</span>
+              <span className="text-right">
+                {sacnWinner
+                  ? `${sacnWinner.priority}${sacnWinner.source.PerAddressPriority ? ' (per-address)' : ''}`
+                  : 'None'}
+              </span>
+            </div>
+          </>
+        )}
+        <div className="pt-2">
+          <DmxHistoryChart history={dmxHistory} maxLength={100} />
+        </div>
       </div>
-      <div className="flex items-center justify-between py-1">
-        <span className="text-left font-bold">Universe ID</span>
-        <span className="text-right">{selectedUniverse ? selectedUniverse.universe : 'None'}</span>
-      </div>
-      <div className="flex items-center justify-between py-1">
-        <span className="text-left font-bold">Selected Channel</span>
-        <span className="text-right">{selectedChannel !== null ? selectedChannel : 'None'}</span>
-      </div>
-      <div className="flex items-center justify-between py-1">
-        <span className="text-left font-bold">Dmx Value</span>
-        <span className="text-right">{dmxValue != null ? dmxValue : 'None'}</span>
-      </div>
-      <div className="pt-2">
-        <DmxHistoryChart history={dmxHistory} maxLength={100} />
-      </div>
-    </div>
-  )
-})
+    )
+  }
+)
 
 export default SelectedInfoDisplay
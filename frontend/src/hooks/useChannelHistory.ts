@@ -0,0 +1,61 @@
+import { useEffect } from 'react'
+import type { ArtNet } from '@/types/artnet'
+import type { SelectedUniverse } from '@/types'
+import { useArtNetStore } from '@/stores/artNetStore'
+
+/** 初期表示で取得する履歴の期間 */
+const HISTORY_RANGE_MS = 60 * 60 * 1000
+
+/**
+ * 選択したチャンネルの直近1時間の履歴をサーバーから取得し、グラフの初期値とするフック
+ * サーバーはマージしたユニバースの出力を保持しているため、protocol で Art-Net / sACN を指定する
+ * @param selectedUniverse - 選択中のユニバース
+ * @param selectedChannel - 選択中のチャンネル（0始まり）
+ * @param protocol - ユニバースのプロトコル
+ * @param maxLength - グラフの点数
+ */
+export function useChannelHistory(
+  selectedUniverse: SelectedUniverse | null,
+  selectedChannel: ArtNet.DmxChannel | null,
+  protocol: 'artnet' | 'sacn',
+  maxLength: number
+) {
+  const address = selectedUniverse?.address
+  const universe = selectedUniverse?.universe
+
+  useEffect(() => {
+    const { setDmxHistory } = useArtNetStore.getState()
+    setDmxHistory([])
+    if (address === undefined || universe === undefined || selectedChannel === null) {
+      return
+    }
+
+    const controller = new AbortController()
+    const to = Date.now()
+    const params = new URLSearchParams({
+      protocol,
+      from: String(to - HISTORY_RANGE_MS),
+      to: String(to),
+      step: String(Math.ceil(HISTORY_RANGE_MS / maxLength)),
+    })
+    fetch(`/api/universes/${universe}/channels/${selectedChannel + 1}/history?${params}`, {
+      signal: controller.signal,
+    })
+      .then(res => (res.ok ? (res.json() as Promise<ArtNet.ChannelHistory>) : null))
+      .then(history => {
+        if (!history || history.Points.length === 0) {
+          return
+        }
+        // 取得中に受信した値は履歴の後ろにつなげる
+        const received = useArtNetStore.getState().dmxHistory
+        const points = history.Points.map(p => ({ value: Math.round(p.Avg), timestamp: Date.parse(p.Time) }))
+        setDmxHistory([...points, ...received].slice(-maxLength))
+      })
+      .catch(err => {
+        if (!controller.signal.aborted) {
+          console.warn('チャンネルの履歴を取得できませんでした', err)
+        }
+      })
+    return () => controller.abort()
+  }, [address, universe, selectedChannel, protocol, maxLength])
+}
@@ -7,6 +7,8 @@ export interface MessageHandler {
   onServerMessage?: (message: ServerMessage) => void
   onServerMessageHistory?: (messages: ServerMessage[]) => void
   onArtNetNodes?: (nodes: ArtNet.ArtNetNode[]) => void
+  onSACNDmxMerged?: (frame: ArtNet.SACNMergedFrame) => void
+  onSACNSources?: (sources: ArtNet.SACNSource[]) => void
   onUnknownMessage?: (type: string, data: unknown) => void
 }
 
@@ -26,6 +28,12 @@ export class MessageRouter {
     artnet_nodes: data => {
       this.handlers.onArtNetNodes?.(data as ArtNet.ArtNetNode[])
     },
+    sacn_dmx_merged: data => {
+      this.handlers.onSACNDmxMerged?.(data as ArtNet.SACNMergedFrame)
+    },
+    sacn_sources: data => {
+      this.handlers.onSACNSources?.(data as ArtNet.SACNSource[])
+    },
   }
 
   setHandlers(handlers: MessageHandler): void {
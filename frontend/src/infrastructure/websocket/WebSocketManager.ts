@@ -6,7 +6,7 @@ import { useSelectionStore } from '@/stores/selectionStore'
 import type { ArtNet } from '@/types/artnet'
 import { getUniverse } from '@/service/artnet'
 
-const DefaultSubscribeTopics = ['artnet/dmx_packet', 'artnet/nodes']
+const DefaultSubscribeTopics = ['artnet/dmx_packet', 'artnet/nodes', 'sacn/dmx_merged', 'sacn/sources']
 
 export interface WebSocketManager {
   // Connection state
@@ -72,6 +72,12 @@ export const useWebSocketManager = (config: WebSocketConfig): WebSocketManager =
       onArtNetNodes: nodes => {
         artNetStore.setArtNetNodes(nodes)
       },
+      onSACNDmxMerged: frame => {
+        artNetStore.updateSACNMerged(frame)
+      },
+      onSACNSources: sources => {
+        artNetStore.setSACNSources(sources)
+      },
     }
 
     messageRouter.setHandlers(messageHandlers)
@@ -1,5 +1,5 @@
 import { describe, it, expect } from 'vitest'
-import { getUniverse } from './artnet'
+import { getSACNChannelWinner, getUniverse } from './artnet'
 import type { ArtNet } from '@/types/artnet'
 
 describe('getUniverse', () => {
@@ -42,3 +42,29 @@ describe('getUniverse', () => {
     expect(getUniverse(packet)).toBe(65535) // (255 << 8) | 255 = 65280 + 255
   })
 })
+
+describe('getSACNChannelWinner', () => {
+  const source: ArtNet.SACNSourceInfo = {
+    CID: '01000000-0000-0000-0000-000000000000',
+    SourceName: 'Backup',
+    SourceIP: '10.0.0.2',
+    Priority: 150,
+    PerAddressPriority: true,
+  }
+  const frame: ArtNet.SACNMergedFrame = {
+    Universe: 1,
+    Sources: [source],
+    Length: 1,
+    Data: [255, 0] as ArtNet.DmxValue[],
+    Priorities: [150, 0],
+    Winners: [0, -1],
+  }
+
+  it('returns the winning source and priority', () => {
+    expect(getSACNChannelWinner(frame, 0)).toEqual({ source, priority: 150 })
+  })
+
+  it('returns null when no source outputs the channel', () => {
+    expect(getSACNChannelWinner(frame, 1)).toBeNull()
+  })
+})
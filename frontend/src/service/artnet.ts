@@ -7,3 +7,18 @@ import type { ArtNet } from '@/types/artnet'
 export function getUniverse(packet: ArtNet.ArtDMXPacket): ArtNet.Universe {
   return (packet.Net << 8) | packet.SubUni
 }
+
+/**
+ * sACN のマージ結果から、チャンネルの出力元と優先度を取得する
+ * 出力元がないチャンネルの場合は null を返す
+ */
+export function getSACNChannelWinner(
+  frame: ArtNet.SACNMergedFrame,
+  channel: ArtNet.DmxChannel
+): { source: ArtNet.SACNSourceInfo; priority: number } | null {
+  const index = frame.Winners[channel]
+  if (index === undefined || index < 0 || index >= frame.Sources.length) {
+    return null
+  }
+  return { source: frame.Sources[index], priority: frame.Priorities[channel] }
+}
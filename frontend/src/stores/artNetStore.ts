@@ -13,14 +13,19 @@ export interface ArtNetStore {
   serverMessages: ServerMessage[]
   artNetNodes: ArtNet.ArtNetNode[]
   dmxHistory: DmxHistoryPoint[]
+  sacnMerged: Record<ArtNet.Universe, ArtNet.SACNMergedFrame>
+  sacnSources: ArtNet.SACNSource[]
 
   // Actions
   updateDmxData: (address: string, universe: ArtNet.Universe, data: ArtNet.DmxValue[], receivedAt: Date) => void
   addServerMessage: (message: ServerMessage) => void
   setServerMessages: (messages: ServerMessage[]) => void
   setArtNetNodes: (nodes: ArtNet.ArtNetNode[]) => void
+  setSACNSources: (sources: ArtNet.SACNSource[]) => void
   clearData: () => void
   updateDmxHistory: (value: ArtNet.DmxValue, maxLength: number) => void
+  setDmxHistory: (history: DmxHistoryPoint[]) => void
+  updateSACNMerged: (frame: ArtNet.SACNMergedFrame) => void
 }
 
 export const useArtNetStore = create<ArtNetStore>(set => ({
@@ -28,6 +33,8 @@ export const useArtNetStore = create<ArtNetStore>(set => ({
   serverMessages: [],
   artNetNodes: [],
   dmxHistory: [],
+  sacnMerged: {},
+  sacnSources: [],
 
   updateDmxData: (address, universe, data, receivedAt) => {
     set(state => ({
@@ -63,12 +70,18 @@ export const useArtNetStore = create<ArtNetStore>(set => ({
     set({ artNetNodes: nodes })
   },
 
+  setSACNSources: sources => {
+    set({ sacnSources: sources })
+  },
+
   clearData: () => {
     set({
       dmxData: {},
       serverMessages: [],
       artNetNodes: [],
       dmxHistory: [],
+      sacnMerged: {},
+      sacnSources: [],
     })
   },
 
@@ -80,4 +93,21 @@ export const useArtNetStore = create<ArtNetStore>(set => ({
       return { dmxHistory: [...sliced, { value: value, timestamp: Date.now() }] }
     })
   },
+
+  setDmxHistory: history => {
+    set({ dmxHistory: history })
+  },
+
+  updateSACNMerged: frame => {
+    set(state => {
+      const sacnMerged = { ...state.sacnMerged }
+      if (frame.Sources.length > 0) {
+        sacnMerged[frame.Universe] = frame
+      } else {
+        // 送信元がいなくなったユニバースは削除する
+        delete sacnMerged[frame.Universe]
+      }
+      return { sacnMerged }
+    })
+  },
 }))
@@ -15,8 +15,21 @@ export namespace ArtNet {
     Length: number
     Data: DmxValue[]
     SourceIP: string
+    Synced?: boolean
+    Protocol?: Protocol
+    Priority?: number
+    SourceName?: string
+    ChannelPriority?: number[]
+    /** プロトコル共通のユニバース番号（Art-Net は15bitのポートアドレス） */
+    Universe?: number
+    StartCode?: number
+    SourceCID?: string
+    ReceivedAt?: string
   }
 
+  /** 受信したプロトコル */
+  export type Protocol = 'artnet' | 'sacn'
+
   export interface ArtNetNode {
     IPAddress: string
     ShortName: string
@@ -24,5 +37,147 @@ export namespace ArtNet {
     NodeReport: string
     MacAddress: string
     LastSeen?: string
+    Liveness?: NodeLiveness
+    BindIP?: string
+    BindIndex?: number
+    Firmware?: number
+    UBEAVersion?: number
+    Oem?: number
+    ESTACode?: number
+    Style?: string
+    NetSwitch?: number
+    SubSwitch?: number
+    NumPorts?: number
+    Ports?: ArtNetNodePort[]
+    Status1?: NodeStatus1
+    Status2?: NodeStatus2
+  }
+
+  export type NodeLiveness = 'unknown' | 'online' | 'stale' | 'offline'
+
+  export interface ArtNetNodePort {
+    Index: number
+    Protocol: string
+    Input: boolean
+    Output: boolean
+    InputUniverse: Universe
+    OutputUniverse: Universe
+    GoodInput: {
+      DataReceived: boolean
+      TestPackets: boolean
+      SIP: boolean
+      TextPackets: boolean
+      Disabled: boolean
+      ReceiveErrors: boolean
+    }
+    GoodOutput: {
+      DataTransmitted: boolean
+      TestPackets: boolean
+      SIP: boolean
+      TextPackets: boolean
+      Merging: boolean
+      ShortDetected: boolean
+      MergeLTP: boolean
+      SACN: boolean
+    }
+    Healthy: boolean
+  }
+
+  export interface NodeStatus1 {
+    Indicator: string
+    PortAddressFrom: string
+    BootROM: boolean
+    RDM: boolean
+    UBEA: boolean
+  }
+
+  export interface NodeStatus2 {
+    WebConfig: boolean
+    DHCP: boolean
+    DHCPCapable: boolean
+    PortAddress15Bit: boolean
+    SACNSwitchable: boolean
+    Squawking: boolean
+  }
+
+  export interface SequenceStats {
+    SourceIP: string
+    Universe: Universe
+    Received: number
+    Lost: number
+    Reordered: number
+    Duplicates: number
+    Unsequenced: number
+    LastSequence: number
+    LossPercent: number
+    LastSeen: string
+  }
+
+  export type MergeMode = 'HTP' | 'LTP' | 'SOURCE'
+
+  export interface MergedFrame {
+    Universe: Universe
+    Mode: MergeMode
+    Sources: string[]
+    Selected: string
+    Conflict: boolean
+    Length: number
+    Data: DmxValue[]
+  }
+
+  export interface UniverseConflict {
+    Universe: Universe
+    Sources: string[]
+    Active: boolean
+    At: string
+  }
+
+  export interface SACNSourceInfo {
+    CID: string
+    SourceName: string
+    SourceIP: string
+    Priority: number
+    PerAddressPriority: boolean
+  }
+
+  export interface SACNMergedFrame {
+    Universe: Universe
+    Sources: SACNSourceInfo[]
+    Length: number
+    Data: DmxValue[]
+    /** チャンネルごとの出力元の優先度 */
+    Priorities: number[]
+    /** チャンネルごとの出力元（Sources のインデックス。出力元がない場合は-1） */
+    Winners: number[]
+  }
+
+  /** サーバーが保持しているチャンネルの履歴の1区間 */
+  export interface ChannelHistoryPoint {
+    /** 区間の開始時刻（RFC 3339） */
+    Time: string
+    Min: DmxValue
+    Max: DmxValue
+    Avg: number
+  }
+
+  export interface ChannelHistory {
+    Protocol: 'artnet' | 'sacn'
+    Universe: Universe
+    /** 1-512 */
+    Channel: number
+    From: string
+    To: string
+    /** 0の場合は保持している解像度のまま */
+    StepMs: number
+    Points: ChannelHistoryPoint[]
+  }
+
+  export interface SACNSource {
+    CID: string
+    SourceName: string
+    IPAddress: string
+    /** ユニバースディスカバリーで通知された送信中のユニバース */
+    Universes: Universe[]
+    LastSeen: string
   }
 }
@@ -27,3 +27,13 @@ export interface ServerCommand {
   command: string
   data?: unknown
 }
+
+export interface ReviewCommand {
+  type: 'review_command'
+  command: 'play' | 'pause' | 'seek' | 'step' | 'speed' | 'status'
+  data?: {
+    positionMs?: number
+    frames?: number
+    speed?: number
+  }
+}